@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// authExempt lists the services callers may use without a token, so that
+// load balancers can check health.
+var authExempt = []string{"/grpc.health.v1.Health/"}
+
+// tokenAuth requires every call to carry one of a set of bearer tokens in
+// its authorization metadata. Without tokens it lets every call through.
+// The methods the servers of a cluster call on each other are only let
+// through for peers, whatever the tokens.
+type tokenAuth struct {
+	peers *peerAuth
+
+	mu     sync.RWMutex
+	tokens []string
+}
+
+func newTokenAuth(tokens []string, peers *peerAuth) *tokenAuth {
+	a := &tokenAuth{peers: peers}
+	a.setTokens(tokens)
+	return a
+}
+
+// setTokens replaces the accepted tokens.
+func (a *tokenAuth) setTokens(tokens []string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.tokens = append([]string(nil), tokens...)
+}
+
+func (a *tokenAuth) check(ctx context.Context, fullMethod string) error {
+	peer := a.peers.isPeer(ctx)
+	if internalMethod(fullMethod) && !peer {
+		return status.Errorf(codes.PermissionDenied, "Only the servers of the cluster may call %s.", methodName(fullMethod))
+	}
+	if peer {
+		return nil
+	}
+	for _, prefix := range authExempt {
+		if strings.HasPrefix(fullMethod, prefix) {
+			return nil
+		}
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if len(a.tokens) == 0 {
+		return nil
+	}
+	got, ok := bearerToken(ctx)
+	if !ok {
+		return status.Errorf(codes.Unauthenticated, "Missing bearer token.")
+	}
+	for _, token := range a.tokens {
+		if subtle.ConstantTimeCompare(got, []byte(token)) == 1 {
+			return nil
+		}
+	}
+	return status.Errorf(codes.Unauthenticated, "Invalid bearer token.")
+}
+
+// bearerToken returns the first bearer token in the authorization
+// metadata of ctx.
+func bearerToken(ctx context.Context) ([]byte, bool) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, v := range md.Get("authorization") {
+		const scheme = "bearer "
+		if len(v) <= len(scheme) || !strings.EqualFold(v[:len(scheme)], scheme) {
+			continue
+		}
+		return []byte(strings.TrimSpace(v[len(scheme):])), true
+	}
+	return nil, false
+}
+
+// peerAuth recognises the other servers of a cluster, by the cluster
+// token or by the common name of a verified TLS client certificate. A
+// peerAuth without either, or a nil one, recognises none.
+type peerAuth struct {
+	token       string
+	commonNames map[string]bool
+}
+
+func newPeerAuth(token string, commonNames []string) *peerAuth {
+	p := &peerAuth{token: token, commonNames: make(map[string]bool)}
+	for _, name := range commonNames {
+		p.commonNames[name] = true
+	}
+	return p
+}
+
+// isPeer reports whether the caller of ctx is a server of the cluster.
+func (p *peerAuth) isPeer(ctx context.Context) bool {
+	if p == nil {
+		return false
+	}
+	if p.token != "" {
+		if got, ok := bearerToken(ctx); ok && subtle.ConstantTimeCompare(got, []byte(p.token)) == 1 {
+			return true
+		}
+	}
+	if len(p.commonNames) > 0 {
+		if pr, ok := peer.FromContext(ctx); ok {
+			if tlsInfo, ok := pr.AuthInfo.(credentials.TLSInfo); ok {
+				if chains := tlsInfo.State.VerifiedChains; len(chains) > 0 && len(chains[0]) > 0 {
+					return p.commonNames[chains[0][0].Subject.CommonName]
+				}
+			}
+		}
+	}
+	return false
+}
+
+func (a *tokenAuth) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := a.check(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (a *tokenAuth) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := a.check(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestTokenAuthReservesInternalMethodsForPeers(t *testing.T) {
+	a := newTokenAuth([]string{"client"}, newPeerAuth("clust3r", nil))
+	bearer := func(token string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+	}
+	tests := []struct {
+		name, token, method string
+		want                codes.Code
+	}{
+		{"client calling BookInfo", "client", "/booksapp.BookInfo/GetBook", codes.OK},
+		{"client calling Replication", "client", "/booksapp.Replication/AppendEntries", codes.PermissionDenied},
+		{"peer calling Replication", "clust3r", "/booksapp.Replication/AppendEntries", codes.OK},
+		{"peer calling BookInfo", "clust3r", "/booksapp.BookInfo/AddBook", codes.OK},
+		{"stranger calling Replication", "guess", "/booksapp.Replication/RequestVote", codes.PermissionDenied},
+		{"client calling Shard", "client", "/booksapp.Shard/PutRecord", codes.PermissionDenied},
+		{"peer calling Shard", "clust3r", "/booksapp.Shard/scanRecords", codes.OK},
+		{"stranger calling BookInfo", "guess", "/booksapp.BookInfo/GetBook", codes.Unauthenticated},
+	}
+	for _, tt := range tests {
+		if got := status.Code(a.check(bearer(tt.token), tt.method)); got != tt.want {
+			t.Errorf("%s: check = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
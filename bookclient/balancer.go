@@ -0,0 +1,94 @@
+package bookclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/balancer/leastrequest"
+	"google.golang.org/grpc/balancer/roundrobin"
+	_ "google.golang.org/grpc/health" // client side health checking
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+)
+
+// Balancing policies, spreading calls over the addresses a target
+// resolves to.
+const (
+	// PickFirst sends every call to the first address that works.
+	PickFirst = "pick_first"
+	// RoundRobin sends calls to each address in turn.
+	RoundRobin = roundrobin.Name
+	// LeastRequest sends each call to whichever of two addresses picked
+	// at random has fewer calls in flight.
+	LeastRequest = "least_request"
+)
+
+// staticScheme is the resolver scheme of targets listing their addresses.
+const staticScheme = "bookinfo-static"
+
+// healthService is the service whose health decides whether a server
+// gets calls.
+const healthService = "booksapp.BookInfo"
+
+// Target returns what to dial to reach address, which is either one
+// target understood by gRPC, such as "localhost:50051" or
+// "dns:///books.internal:50051", or a comma separated list of host:port
+// addresses of replicas. A list is resolved statically, with the dial
+// options returned.
+func Target(address string) (string, []grpc.DialOption) {
+	if !strings.Contains(address, ",") {
+		return address, nil
+	}
+	var addrs []string
+	for _, addr := range strings.Split(address, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			addrs = append(addrs, addr)
+		}
+	}
+	r := StaticResolver(addrs...)
+	return r.Scheme() + ":///bookinfo", []grpc.DialOption{grpc.WithResolvers(r)}
+}
+
+// StaticResolver returns a resolver for the "bookinfo-static" scheme
+// resolving any target to addrs. Its UpdateState method changes the
+// addresses of connections already made, which lets tests add and remove
+// in-process servers.
+func StaticResolver(addrs ...string) *manual.Resolver {
+	r := manual.NewBuilderWithScheme(staticScheme)
+	state := resolver.State{}
+	for _, addr := range addrs {
+		state.Addresses = append(state.Addresses, resolver.Address{Addr: addr})
+	}
+	r.InitialState(state)
+	return r
+}
+
+// DialOptions returns the options connections used by a Client should be
+// dialed with to balance calls with policy. Except with PickFirst, servers
+// are health checked, and those not serving get no calls until they
+// recover.
+func DialOptions(policy string) ([]grpc.DialOption, error) {
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(ServiceConfig), &config); err != nil {
+		return nil, err
+	}
+	switch policy {
+	case "", PickFirst:
+		return []grpc.DialOption{grpc.WithDefaultServiceConfig(ServiceConfig)}, nil
+	case RoundRobin:
+		config["loadBalancingConfig"] = []interface{}{map[string]interface{}{RoundRobin: map[string]interface{}{}}}
+	case LeastRequest:
+		config["loadBalancingConfig"] = []interface{}{map[string]interface{}{
+			leastrequest.Name: map[string]interface{}{"choiceCount": 2}}}
+	default:
+		return nil, fmt.Errorf("bookclient: unknown balancing policy %q: want %s, %s or %s", policy, PickFirst, RoundRobin, LeastRequest)
+	}
+	config["healthCheckConfig"] = map[string]interface{}{"serviceName": healthService}
+	out, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	return []grpc.DialOption{grpc.WithDefaultServiceConfig(string(out))}, nil
+}
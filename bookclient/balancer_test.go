@@ -0,0 +1,214 @@
+package bookclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"google.golang.org/grpc/resolver"
+)
+
+func TestTarget(t *testing.T) {
+	tests := []struct {
+		address    string
+		wantTarget string
+		wantOpts   bool
+	}{
+		{"localhost:50051", "localhost:50051", false},
+		{"dns:///books.internal:50051", "dns:///books.internal:50051", false},
+		{"a:1, b:2,", staticScheme + ":///bookinfo", true},
+	}
+	for _, tt := range tests {
+		target, opts := Target(tt.address)
+		if target != tt.wantTarget || (len(opts) > 0) != tt.wantOpts {
+			t.Errorf("Target(%q) = %q with %d options, want %q with options %v", tt.address, target, len(opts), tt.wantTarget, tt.wantOpts)
+		}
+	}
+}
+
+func TestDialOptions(t *testing.T) {
+	for _, policy := range []string{"", PickFirst, RoundRobin, LeastRequest} {
+		if _, err := DialOptions(policy); err != nil {
+			t.Errorf("DialOptions(%q): %v", policy, err)
+		}
+	}
+	if _, err := DialOptions("random"); err == nil {
+		t.Error("DialOptions(random) succeeded, want an error")
+	}
+}
+
+// getCounts returns how many GetBook calls each replica has had.
+func getCounts(replicas []*replica) []int {
+	counts := make([]int, len(replicas))
+	for i, r := range replicas {
+		counts[i] = r.getCount()
+	}
+	return counts
+}
+
+// callUntil calls GetBook until done reports true of the calls each
+// replica got, and fails the test if that takes too long.
+func callUntil(t *testing.T, client *Client, replicas []*replica, done func(counts []int) bool) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for !done(getCounts(replicas)) {
+		if _, err := client.GetBook(ctx, &pb.BookID{Value: "1"}); err != nil {
+			t.Fatalf("GetBook: %v", err)
+		}
+		if ctx.Err() != nil {
+			t.Fatalf("calls did not settle; each replica got %v", getCounts(replicas))
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// spread makes n calls and returns how many each replica got.
+func spread(t *testing.T, client *Client, replicas []*replica, n int) []int {
+	t.Helper()
+	before := getCounts(replicas)
+	for i := 0; i < n; i++ {
+		if _, err := client.GetBook(context.Background(), &pb.BookID{Value: "1"}); err != nil {
+			t.Fatalf("GetBook: %v", err)
+		}
+	}
+	counts := getCounts(replicas)
+	for i := range counts {
+		counts[i] -= before[i]
+	}
+	return counts
+}
+
+// allCalled reports whether every replica got a call.
+func allCalled(counts []int) bool {
+	for _, n := range counts {
+		if n == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestRoundRobin(t *testing.T) {
+	replicas := startReplicas(t, 3)
+	conn, _ := dialReplicas(t, RoundRobin, replicas)
+	client := New(conn, Options{})
+	// Replicas get calls once connected.
+	callUntil(t, client, replicas, allCalled)
+
+	if counts := spread(t, client, replicas, 30); counts[0] != 10 || counts[1] != 10 || counts[2] != 10 {
+		t.Errorf("replicas got %v of 30 calls, want 10 each", counts)
+	}
+}
+
+func TestPickFirst(t *testing.T) {
+	replicas := startReplicas(t, 3)
+	conn, _ := dialReplicas(t, PickFirst, replicas)
+	client := New(conn, Options{})
+
+	counts := spread(t, client, replicas, 10)
+	if counts[0] != 10 {
+		t.Errorf("replicas got %v of 10 calls, want all on the first", counts)
+	}
+}
+
+// TestLeastRequest keeps every call to one replica in flight and checks
+// that the replica gets far fewer calls than its round robin share.
+func TestLeastRequest(t *testing.T) {
+	replicas := startReplicas(t, 4)
+	conn, _ := dialReplicas(t, LeastRequest, replicas)
+	client := New(conn, Options{})
+	callUntil(t, client, replicas, allCalled)
+
+	slow := replicas[0]
+	slow.mu.Lock()
+	slow.hold = make(chan struct{})
+	slow.mu.Unlock()
+	defer close(slow.hold)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	const calls = 100
+	before := getCounts(replicas)
+	for i := 0; i < calls; i++ {
+		held := slow.getCount()
+		done := make(chan error, 1)
+		go func() {
+			_, err := client.GetBook(ctx, &pb.BookID{Value: "1"})
+			done <- err
+		}()
+		// Wait for the call to finish, or to be held by the slow
+		// replica, so that the next one is picked knowing where this
+		// one went.
+		for waiting := true; waiting; {
+			select {
+			case err := <-done:
+				if err != nil {
+					t.Fatalf("GetBook: %v", err)
+				}
+				waiting = false
+			case <-time.After(time.Millisecond):
+				waiting = slow.getCount() == held
+			case <-ctx.Done():
+				t.Fatal("GetBook did not reach a replica")
+			}
+		}
+	}
+	counts := getCounts(replicas)
+	for i := range counts {
+		counts[i] -= before[i]
+	}
+	// Picking two replicas at random, the slow one is only called when
+	// picked twice: 1 in 16 calls, against 1 in 4 with round robin.
+	if counts[0] >= calls/5 {
+		t.Errorf("replicas got %v of %d calls, want the one with calls in flight to get fewer than %d", counts, calls, calls/5)
+	}
+}
+
+// TestUnhealthyReplica checks that a replica reporting NOT_SERVING stops
+// getting calls until it recovers, and that a replica the resolver drops
+// gets none.
+func TestUnhealthyReplica(t *testing.T) {
+	replicas := startReplicas(t, 3)
+	conn, res := dialReplicas(t, RoundRobin, replicas)
+	client := New(conn, Options{})
+	callUntil(t, client, replicas, allCalled)
+
+	sick := replicas[0]
+	sick.setServing(false)
+	// Calls already picked may still reach it, so wait until ten in a row
+	// go elsewhere.
+	last, streak := sick.getCount(), 0
+	callUntil(t, client, replicas, func(counts []int) bool {
+		if counts[0] != last {
+			last, streak = counts[0], 0
+		} else {
+			streak++
+		}
+		return streak > 10
+	})
+	if counts := spread(t, client, replicas, 30); counts[0] != 0 || counts[1] != 15 || counts[2] != 15 {
+		t.Errorf("replicas got %v of 30 calls with the first not serving, want 0, 15, 15", counts)
+	}
+
+	sick.setServing(true)
+	start := sick.getCount()
+	callUntil(t, client, replicas, func(counts []int) bool { return counts[0] > start })
+
+	// Dropping the replica from the resolver's addresses also stops its
+	// calls.
+	res.UpdateState(resolver.State{Addresses: []resolver.Address{{Addr: replicas[1].addr}, {Addr: replicas[2].addr}}})
+	last, streak = sick.getCount(), 0
+	callUntil(t, client, replicas, func(counts []int) bool {
+		if counts[0] != last {
+			last, streak = counts[0], 0
+		} else {
+			streak++
+		}
+		return streak > 10
+	})
+	if counts := spread(t, client, replicas, 30); counts[0] != 0 {
+		t.Errorf("replicas got %v of 30 calls with the first removed, want none on it", counts)
+	}
+}
@@ -0,0 +1,211 @@
+// Package bookclient is a BookInfo client that survives transient
+// failures. It bounds every call with its own deadline, retries the calls
+// that are safe to repeat with exponential backoff and jitter, and hedges
+// reads: when a read is slow it sends the same request again and keeps
+// the first answer.
+//
+// AddBook is made safe to retry with an idempotency key, which the server
+// uses to add the book only once however many attempts reach it. The
+// other writes are not retried.
+//
+// Streaming calls are retried by gRPC itself, following the service
+// config DialOptions installs, as long as no message has been received.
+// DialOptions also picks how calls are spread over several server
+// replicas, which Target lists.
+//
+// Optionally, GetBook answers repeated calls from a local cache, which
+// Watch keeps in sync with the server's change stream.
+package bookclient
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+// IdempotencyKey is the metadata key carrying the idempotency key of an
+// AddBook call.
+const IdempotencyKey = "idempotency-key"
+
+// ServiceConfig retries ListBooks, which the server answers with a
+// stream, when it fails before the first book arrives.
+const ServiceConfig = `{
+  "methodConfig": [{
+    "name": [{"service": "booksapp.BookInfo", "method": "listBooks"}],
+    "retryPolicy": {
+      "maxAttempts": 4,
+      "initialBackoff": "0.1s",
+      "maxBackoff": "2s",
+      "backoffMultiplier": 2,
+      "retryableStatusCodes": ["UNAVAILABLE"]
+    }
+  }]
+}`
+
+// Options tune how a Client retries.
+type Options struct {
+	// CallTimeout bounds each call, retries included, when the context
+	// has no earlier deadline. Zero leaves calls unbounded.
+	CallTimeout time.Duration
+	// AttemptTimeout bounds each attempt. An attempt running out of time
+	// is retried. Zero leaves attempts bounded only by the call.
+	AttemptTimeout time.Duration
+	// MaxAttempts is the most times a call is sent, hedges included.
+	MaxAttempts int
+	// InitialBackoff is the longest wait before the first retry; each
+	// retry may wait Multiplier times longer than the previous one, up to
+	// MaxBackoff. The actual wait is picked at random below that bound.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	// HedgeDelay is how long a read waits for an answer before it is sent
+	// again. Zero disables hedging.
+	HedgeDelay time.Duration
+	// CacheSize is how many books GetBook keeps to answer repeated calls
+	// locally, dropping the least recently used first. Zero disables the
+	// cache.
+	CacheSize int
+	// CacheTTL is how long a book is served from the cache, and so how
+	// stale it can get when another client changes it and Watch is not
+	// running. Zero means DefaultCacheTTL.
+	CacheTTL time.Duration
+}
+
+// DefaultOptions returns the options New uses for zero fields.
+func DefaultOptions() Options {
+	return Options{
+		CallTimeout:    5 * time.Second,
+		MaxAttempts:    4,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     2 * time.Second,
+		Multiplier:     2,
+		HedgeDelay:     300 * time.Millisecond,
+	}
+}
+
+// Client wraps a pb.BookInfoClient. The calls it does not retry go
+// straight to the wrapped client.
+type Client struct {
+	pb.BookInfoClient
+	opts  Options
+	cache *bookCache
+}
+
+// New returns a client calling the BookInfo service over cc. Fields of
+// opts left zero take their DefaultOptions values, except the timeouts,
+// HedgeDelay and CacheSize, which are off when zero.
+func New(cc grpc.ClientConnInterface, opts Options) *Client {
+	defaults := DefaultOptions()
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = defaults.MaxAttempts
+	}
+	if opts.InitialBackoff <= 0 {
+		opts.InitialBackoff = defaults.InitialBackoff
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = defaults.MaxBackoff
+	}
+	if opts.Multiplier < 1 {
+		opts.Multiplier = defaults.Multiplier
+	}
+	if opts.CacheTTL <= 0 {
+		opts.CacheTTL = DefaultCacheTTL
+	}
+	c := &Client{BookInfoClient: pb.NewBookInfoClient(cc), opts: opts}
+	if opts.CacheSize > 0 {
+		c.cache = newBookCache(opts.CacheSize, opts.CacheTTL)
+	}
+	return c
+}
+
+// GetBook fetches a book, from the cache if it holds it and otherwise
+// hedging and retrying. Cached books are served without their header and
+// trailer, which call options asking for them find empty.
+func (c *Client) GetBook(ctx context.Context, in *pb.BookID, opts ...grpc.CallOption) (*pb.Book, error) {
+	var generation uint64
+	if c.cache != nil {
+		var book *pb.Book
+		if book, generation = c.cache.get(in.Value); book != nil {
+			return book, nil
+		}
+	}
+	out, err := c.invoke(ctx, true, func(ctx context.Context, attemptOpts []grpc.CallOption) (proto.Message, error) {
+		return c.BookInfoClient.GetBook(ctx, in, callOptions(opts, attemptOpts)...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	book := out.(*pb.Book)
+	if c.cache != nil {
+		c.cache.put(book, generation)
+	}
+	return book, nil
+}
+
+// FormatCitation formats a citation, hedging and retrying.
+func (c *Client) FormatCitation(ctx context.Context, in *pb.FormatCitationRequest, opts ...grpc.CallOption) (*pb.Citation, error) {
+	out, err := c.invoke(ctx, true, func(ctx context.Context, attemptOpts []grpc.CallOption) (proto.Message, error) {
+		return c.BookInfoClient.FormatCitation(ctx, in, callOptions(opts, attemptOpts)...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*pb.Citation), nil
+}
+
+// AddBook adds a book, retrying. Unless ctx already carries one, every
+// call gets a new idempotency key shared by its attempts.
+func (c *Client) AddBook(ctx context.Context, in *pb.Book, opts ...grpc.CallOption) (*pb.BookID, error) {
+	if md, _ := metadata.FromOutgoingContext(ctx); len(md.Get(IdempotencyKey)) == 0 {
+		key, err := NewIdempotencyKey()
+		if err != nil {
+			return nil, err
+		}
+		ctx = metadata.AppendToOutgoingContext(ctx, IdempotencyKey, key)
+	}
+	out, err := c.invoke(ctx, false, func(ctx context.Context, attemptOpts []grpc.CallOption) (proto.Message, error) {
+		return c.BookInfoClient.AddBook(ctx, in, callOptions(opts, attemptOpts)...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*pb.BookID), nil
+}
+
+// UpdateBook replaces a book, within the call deadline but without
+// retrying. The book is dropped from the cache whatever the outcome, as a
+// call that failed may still have changed it.
+func (c *Client) UpdateBook(ctx context.Context, in *pb.Book, opts ...grpc.CallOption) (*pb.Book, error) {
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
+	if c.cache != nil {
+		defer c.cache.invalidate(in.Id)
+	}
+	return c.BookInfoClient.UpdateBook(ctx, in, opts...)
+}
+
+// DeleteBook deletes a book, within the call deadline but without
+// retrying, and drops it from the cache.
+func (c *Client) DeleteBook(ctx context.Context, in *pb.BookID, opts ...grpc.CallOption) (*pb.Book, error) {
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
+	if c.cache != nil {
+		defer c.cache.invalidate(in.Value)
+	}
+	return c.BookInfoClient.DeleteBook(ctx, in, opts...)
+}
+
+// NewIdempotencyKey returns a random idempotency key.
+func NewIdempotencyKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,192 @@
+package bookclient
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultCacheTTL is how long cached books are served when Options give
+// a CacheSize but no CacheTTL.
+const DefaultCacheTTL = 30 * time.Second
+
+// CacheStats counts how GetBook calls were served by the cache.
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+	// Evictions counts the books dropped to make room for others.
+	Evictions uint64
+	// Invalidations counts the books dropped because they changed, or
+	// might have.
+	Invalidations uint64
+}
+
+type cacheEntry struct {
+	id      string
+	book    *pb.Book
+	expires time.Time
+}
+
+// bookCache keeps the most recently fetched books for up to ttl each.
+type bookCache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	entries map[string]*list.Element
+	// order holds the entries, most recently used first.
+	order *list.List
+	// generation changes on every invalidation, so that books fetched
+	// before it are not cached after it.
+	generation uint64
+	stats      CacheStats
+	now        func() time.Time
+}
+
+func newBookCache(size int, ttl time.Duration) *bookCache {
+	return &bookCache{
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+		now:     time.Now,
+	}
+}
+
+// get returns a copy of the cached book id, if it has not expired, and the
+// generation to pass to put when it has to be fetched.
+func (c *bookCache) get(id string) (*pb.Book, uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[id]; ok {
+		entry := e.Value.(*cacheEntry)
+		if c.now().Before(entry.expires) {
+			c.order.MoveToFront(e)
+			c.stats.Hits++
+			return proto.Clone(entry.book).(*pb.Book), c.generation
+		}
+		c.order.Remove(e)
+		delete(c.entries, id)
+	}
+	c.stats.Misses++
+	return nil, c.generation
+}
+
+// put caches book unless the cache was invalidated since generation.
+func (c *bookCache) put(book *pb.Book, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if generation != c.generation {
+		return
+	}
+	entry := &cacheEntry{id: book.Id, book: proto.Clone(book).(*pb.Book), expires: c.now().Add(c.ttl)}
+	if e, ok := c.entries[book.Id]; ok {
+		e.Value = entry
+		c.order.MoveToFront(e)
+		return
+	}
+	c.entries[book.Id] = c.order.PushFront(entry)
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).id)
+		c.stats.Evictions++
+	}
+}
+
+// invalidate drops book id.
+func (c *bookCache) invalidate(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	if e, ok := c.entries[id]; ok {
+		c.order.Remove(e)
+		delete(c.entries, id)
+		c.stats.Invalidations++
+	}
+}
+
+// clear drops every book.
+func (c *bookCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	c.stats.Invalidations += uint64(c.order.Len())
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+func (c *bookCache) snapshot() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// CacheStats returns the cache counters, all zero without a cache.
+func (c *Client) CacheStats() CacheStats {
+	if c.cache == nil {
+		return CacheStats{}
+	}
+	return c.cache.snapshot()
+}
+
+// Watch keeps the cache in sync with the server until ctx is done,
+// dropping books as soon as the server reports them changed, so that
+// changes made by other clients are seen without waiting for CacheTTL.
+// It reconnects when the stream breaks, dropping the whole cache since
+// changes may have been missed. Without a cache, or against a server
+// without WatchBooks, it returns at once.
+//
+// The cache is also dropped when a watch starts, but a change made while
+// the stream is being set up may still only be seen once the books it
+// touched expire.
+func (c *Client) Watch(ctx context.Context) error {
+	if c.cache == nil {
+		return nil
+	}
+	retries := 0
+	for {
+		opened, err := c.watch(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if status.Code(err) == codes.Unimplemented {
+			return err
+		}
+		c.cache.clear()
+		if opened {
+			retries = 0
+		} else if retries < 10 {
+			retries++
+		}
+		select {
+		case <-time.After(c.backoff(retries, nil)):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// watch applies the changes of one WatchBooks stream to the cache. It
+// reports whether the stream delivered any change before it broke.
+func (c *Client) watch(ctx context.Context) (bool, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := c.BookInfoClient.WatchBooks(ctx, &pb.WatchBooksRequest{})
+	if err != nil {
+		return false, err
+	}
+	c.cache.clear()
+	for received := false; ; received = true {
+		change, err := stream.Recv()
+		if err != nil {
+			return received, err
+		}
+		c.cache.invalidate(change.Id)
+	}
+}
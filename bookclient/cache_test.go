@@ -0,0 +1,203 @@
+package bookclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// clock is a time the tests move by hand.
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time { return c.t }
+
+func TestCacheTTL(t *testing.T) {
+	clock := &clock{t: time.Unix(1700000000, 0)}
+	c := newBookCache(10, time.Minute)
+	c.now = clock.now
+
+	_, generation := c.get("1")
+	c.put(&pb.Book{Id: "1", Title: "Cached"}, generation)
+	clock.t = clock.t.Add(time.Minute - time.Nanosecond)
+	book, _ := c.get("1")
+	if book == nil || book.Title != "Cached" {
+		t.Fatalf("get before the TTL = %v, want the cached book", book)
+	}
+	// Callers get copies they may change.
+	book.Title = "Changed"
+	if book, _ := c.get("1"); book.Title != "Cached" {
+		t.Errorf("changing a book get returned changed the cache to %q", book.Title)
+	}
+
+	clock.t = clock.t.Add(time.Nanosecond)
+	if book, _ := c.get("1"); book != nil {
+		t.Errorf("get at the TTL = %v, want a miss", book)
+	}
+	want := CacheStats{Hits: 2, Misses: 2}
+	if got := c.snapshot(); got != want {
+		t.Errorf("stats = %+v, want %+v", got, want)
+	}
+	if len(c.entries) != 0 || c.order.Len() != 0 {
+		t.Errorf("expired book still held: %d entries, %d in order", len(c.entries), c.order.Len())
+	}
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := newBookCache(2, time.Minute)
+	for _, id := range []string{"1", "2"} {
+		_, generation := c.get(id)
+		c.put(&pb.Book{Id: id}, generation)
+	}
+	c.get("1")
+	_, generation := c.get("3")
+	c.put(&pb.Book{Id: "3"}, generation)
+
+	for id, want := range map[string]bool{"1": true, "2": false, "3": true} {
+		if _, ok := c.entries[id]; ok != want {
+			t.Errorf("book %s cached %v, want %v", id, ok, want)
+		}
+	}
+	if got := c.snapshot().Evictions; got != 1 {
+		t.Errorf("Evictions = %d, want 1", got)
+	}
+}
+
+// TestCachePutAfterInvalidate checks that a book fetched before an
+// invalidation, and so possibly stale, is not cached after it.
+func TestCachePutAfterInvalidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		invalidate func(c *bookCache)
+	}{
+		{"invalidate", func(c *bookCache) { c.invalidate("1") }},
+		{"invalidate another book", func(c *bookCache) { c.invalidate("2") }},
+		{"clear", func(c *bookCache) { c.clear() }},
+	}
+	for _, tt := range tests {
+		c := newBookCache(10, time.Minute)
+		_, generation := c.get("1")
+		// The book changes while it is being fetched.
+		tt.invalidate(c)
+		c.put(&pb.Book{Id: "1", Title: "Stale"}, generation)
+		if book, _ := c.get("1"); book != nil {
+			t.Errorf("%s: a book fetched before the invalidation was cached", tt.name)
+		}
+
+		_, generation = c.get("1")
+		c.put(&pb.Book{Id: "1", Title: "Fresh"}, generation)
+		if book, _ := c.get("1"); book == nil || book.Title != "Fresh" {
+			t.Errorf("%s: a book fetched after the invalidation was not cached: %v", tt.name, book)
+		}
+	}
+}
+
+func TestClientCacheWrites(t *testing.T) {
+	srv := newFakeServer(&pb.Book{Id: "1", Title: "First"}, &pb.Book{Id: "2", Title: "Second"})
+	client := New(dial(t, serve(t, srv)), Options{CacheSize: 10, CacheTTL: time.Hour})
+	ctx := context.Background()
+
+	get := func(id string) (*pb.Book, error) {
+		t.Helper()
+		return client.GetBook(ctx, &pb.BookID{Value: id})
+	}
+	get("1")
+	get("2")
+	if book, _ := get("1"); book.Title != "First" || srv.getCount() != 2 {
+		t.Fatalf("second GetBook = %v after %d calls to the server, want First from the cache", book, srv.getCount())
+	}
+
+	if _, err := client.UpdateBook(ctx, &pb.Book{Id: "1", Title: "Updated"}); err != nil {
+		t.Fatal(err)
+	}
+	if book, _ := get("1"); book.Title != "Updated" {
+		t.Errorf("GetBook after UpdateBook = %q, want Updated", book.Title)
+	}
+
+	// An update whose answer is lost may still have changed the book.
+	srv.failWrites = true
+	if _, err := client.UpdateBook(ctx, &pb.Book{Id: "1", Title: "Lost"}); status.Code(err) != codes.Unavailable {
+		t.Fatalf("UpdateBook = %v, want Unavailable", err)
+	}
+	if book, _ := get("1"); book.Title != "Lost" {
+		t.Errorf("GetBook after a failed UpdateBook = %q, want Lost", book.Title)
+	}
+
+	if _, err := client.DeleteBook(ctx, &pb.BookID{Value: "1"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := get("1"); status.Code(err) != codes.NotFound {
+		t.Errorf("GetBook after DeleteBook = %v, want NotFound", err)
+	}
+
+	// The other book stayed cached throughout.
+	calls := srv.getCount()
+	if book, _ := get("2"); book.Title != "Second" || srv.getCount() != calls {
+		t.Errorf("GetBook of an untouched book = %v, called the server %v", book, srv.getCount() != calls)
+	}
+}
+
+func TestWatchInvalidates(t *testing.T) {
+	srv := newFakeServer(&pb.Book{Id: "1", Title: "First"}, &pb.Book{Id: "2", Title: "Second"})
+	client := New(dial(t, serve(t, srv)), Options{CacheSize: 10, CacheTTL: time.Hour})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	watchCtx, stopWatch := context.WithCancel(ctx)
+	watched := make(chan error, 1)
+	go func() { watched <- client.Watch(watchCtx) }()
+	select {
+	case <-srv.watching:
+	case <-ctx.Done():
+		t.Fatal("Watch did not open a stream")
+	}
+
+	for _, id := range []string{"1", "2"} {
+		if _, err := client.GetBook(ctx, &pb.BookID{Value: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Another client updates book 1, and the server reports it.
+	srv.setBook(&pb.Book{Id: "1", Title: "Changed elsewhere"})
+	invalidations := client.CacheStats().Invalidations
+	srv.changes <- &pb.BookChange{Type: pb.ChangeType_UPDATED, Id: "1"}
+	for client.CacheStats().Invalidations == invalidations {
+		if ctx.Err() != nil {
+			t.Fatal("Watch did not invalidate the changed book")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	calls := srv.getCount()
+	if book, _ := client.GetBook(ctx, &pb.BookID{Value: "1"}); book.Title != "Changed elsewhere" {
+		t.Errorf("GetBook after the change = %q, want the changed book", book.Title)
+	}
+	if book, _ := client.GetBook(ctx, &pb.BookID{Value: "2"}); book.Title != "Second" || srv.getCount() != calls+1 {
+		t.Errorf("GetBook of the unchanged book went to the server")
+	}
+
+	stopWatch()
+	if err := <-watched; !errors.Is(err, context.Canceled) {
+		t.Errorf("Watch returned %v, want context.Canceled", err)
+	}
+}
+
+func TestWatchUnsupported(t *testing.T) {
+	srv := newFakeServer()
+	srv.noWatch = true
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	uncached := New(dial(t, serve(t, srv)), Options{})
+	if err := uncached.Watch(ctx); err != nil {
+		t.Errorf("Watch without a cache = %v, want nil", err)
+	}
+	cached := New(dial(t, serve(t, srv)), Options{CacheSize: 10})
+	if err := cached.Watch(ctx); status.Code(err) != codes.Unimplemented {
+		t.Errorf("Watch against a server without WatchBooks = %v, want Unimplemented", err)
+	}
+}
@@ -0,0 +1,137 @@
+package bookclient
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// attemptFunc sends one attempt of a call, with opts added to its call
+// options.
+type attemptFunc func(ctx context.Context, opts []grpc.CallOption) (proto.Message, error)
+
+// callContext applies the call timeout to ctx.
+func (c *Client) callContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.opts.CallTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, c.opts.CallTimeout)
+}
+
+type attemptResult struct {
+	msg     proto.Message
+	err     error
+	trailer metadata.MD
+}
+
+// invoke sends attempts of a call until one succeeds, one fails with an
+// error not worth retrying, MaxAttempts have been sent or the call runs
+// out of time. With hedge set, another attempt is sent every HedgeDelay
+// while none has answered; the first answer wins and the others are
+// cancelled.
+func (c *Client) invoke(ctx context.Context, hedge bool, attempt attemptFunc) (proto.Message, error) {
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
+
+	results := make(chan attemptResult, c.opts.MaxAttempts)
+	sent, inFlight := 0, 0
+	send := func() {
+		sent++
+		inFlight++
+		go func() {
+			attemptCtx, cancel := ctx, context.CancelFunc(func() {})
+			if c.opts.AttemptTimeout > 0 {
+				attemptCtx, cancel = context.WithTimeout(ctx, c.opts.AttemptTimeout)
+			}
+			defer cancel()
+			var trailer metadata.MD
+			msg, err := attempt(attemptCtx, []grpc.CallOption{grpc.Trailer(&trailer)})
+			results <- attemptResult{msg: msg, err: err, trailer: trailer}
+		}()
+	}
+
+	send()
+	var hedgeTimer, backoffTimer <-chan time.Time
+	retries := 0
+	for {
+		if hedge && c.opts.HedgeDelay > 0 && hedgeTimer == nil && inFlight > 0 && sent < c.opts.MaxAttempts {
+			hedgeTimer = time.After(c.opts.HedgeDelay)
+		}
+		select {
+		case r := <-results:
+			inFlight--
+			if r.err == nil {
+				return r.msg, nil
+			}
+			if !retryable(ctx, r.err) {
+				return nil, r.err
+			}
+			if inFlight > 0 {
+				// A hedge may still succeed.
+				continue
+			}
+			if sent >= c.opts.MaxAttempts {
+				return nil, r.err
+			}
+			wait := c.backoff(retries, r.trailer)
+			if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+				return nil, r.err
+			}
+			retries++
+			hedgeTimer = nil
+			backoffTimer = time.After(wait)
+		case <-hedgeTimer:
+			hedgeTimer = nil
+			send()
+		case <-backoffTimer:
+			backoffTimer = nil
+			send()
+		case <-ctx.Done():
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+	}
+}
+
+// retryable reports whether a call failing with err may succeed if sent
+// again. Deadlines are only retried when they are an attempt's, not the
+// call's.
+func retryable(ctx context.Context, err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.ResourceExhausted:
+		return true
+	case codes.DeadlineExceeded:
+		return ctx.Err() == nil
+	}
+	return false
+}
+
+// backoff returns how long to wait before the retry following retries
+// earlier ones: a random time below the exponential bound ("full
+// jitter"), or longer if the server's retry-after trailer asks for it.
+func (c *Client) backoff(retries int, trailer metadata.MD) time.Duration {
+	bound := float64(c.opts.InitialBackoff) * math.Pow(c.opts.Multiplier, float64(retries))
+	if bound > float64(c.opts.MaxBackoff) {
+		bound = float64(c.opts.MaxBackoff)
+	}
+	wait := time.Duration(rand.Int63n(int64(bound) + 1))
+	if v := trailer.Get("retry-after-ms"); len(v) > 0 {
+		if ms, err := strconv.ParseInt(v[0], 10, 64); err == nil && time.Duration(ms)*time.Millisecond > wait {
+			wait = time.Duration(ms) * time.Millisecond
+		}
+	}
+	return wait
+}
+
+// callOptions joins the options of a call and of one of its attempts
+// without sharing opts' backing array between concurrent attempts.
+func callOptions(opts, attemptOpts []grpc.CallOption) []grpc.CallOption {
+	return append(append([]grpc.CallOption(nil), opts...), attemptOpts...)
+}
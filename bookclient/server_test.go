@@ -0,0 +1,229 @@
+package bookclient
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/resolver/manual"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+// fakeServer is an in-memory BookInfo server counting the calls it gets.
+type fakeServer struct {
+	pb.UnimplementedBookInfoServer
+
+	mu    sync.Mutex
+	books map[string]*pb.Book
+	gets  int
+	// failWrites makes UpdateBook fail after it has changed the book, as
+	// a call whose answer is lost does.
+	failWrites bool
+	// noWatch makes WatchBooks unimplemented.
+	noWatch bool
+	// hold, if set, keeps GetBook calls in flight until it is closed.
+	hold chan struct{}
+
+	// changes are sent to the WatchBooks stream, which signals watching
+	// when it opens.
+	changes  chan *pb.BookChange
+	watching chan struct{}
+}
+
+func newFakeServer(books ...*pb.Book) *fakeServer {
+	s := &fakeServer{
+		books:    make(map[string]*pb.Book),
+		changes:  make(chan *pb.BookChange),
+		watching: make(chan struct{}, 1),
+	}
+	for _, b := range books {
+		s.books[b.Id] = b
+	}
+	return s
+}
+
+func (s *fakeServer) getCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.gets
+}
+
+// setBook changes a book behind the clients' backs.
+func (s *fakeServer) setBook(b *pb.Book) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.books[b.Id] = b
+}
+
+func (s *fakeServer) GetBook(ctx context.Context, in *pb.BookID) (*pb.Book, error) {
+	s.mu.Lock()
+	s.gets++
+	hold := s.hold
+	s.mu.Unlock()
+	if hold != nil {
+		select {
+		case <-hold:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.books[in.Value]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "Book %s not found.", in.Value)
+	}
+	return proto.Clone(b).(*pb.Book), nil
+}
+
+func (s *fakeServer) UpdateBook(ctx context.Context, in *pb.Book) (*pb.Book, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.books[in.Id]; !ok {
+		return nil, status.Errorf(codes.NotFound, "Book %s not found.", in.Id)
+	}
+	s.books[in.Id] = proto.Clone(in).(*pb.Book)
+	if s.failWrites {
+		return nil, status.Errorf(codes.Unavailable, "Connection lost.")
+	}
+	return in, nil
+}
+
+func (s *fakeServer) DeleteBook(ctx context.Context, in *pb.BookID) (*pb.Book, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.books[in.Value]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "Book %s not found.", in.Value)
+	}
+	delete(s.books, in.Value)
+	return b, nil
+}
+
+func (s *fakeServer) WatchBooks(in *pb.WatchBooksRequest, stream pb.BookInfo_WatchBooksServer) error {
+	if s.noWatch {
+		return status.Errorf(codes.Unimplemented, "method WatchBooks not implemented")
+	}
+	select {
+	case s.watching <- struct{}{}:
+	default:
+	}
+	for {
+		select {
+		case change := <-s.changes:
+			if err := stream.Send(change); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
+
+// serve runs srv on an in-memory listener until the test ends and
+// returns its listener.
+func serve(t *testing.T, srv pb.BookInfoServer) *bufconn.Listener {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	pb.RegisterBookInfoServer(s, srv)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+	return lis
+}
+
+// dial connects to a single in-memory server.
+func dial(t *testing.T, lis *bufconn.Listener, opts ...grpc.DialOption) *grpc.ClientConn {
+	t.Helper()
+	opts = append([]grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}, opts...)
+	conn, err := grpc.Dial("passthrough:///bufconn", opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// replica is one of several in-memory servers a client balances over.
+type replica struct {
+	*fakeServer
+	addr   string
+	lis    *bufconn.Listener
+	health *health.Server
+}
+
+// setServing sets whether the replica reports BookInfo as serving.
+func (r *replica) setServing(serving bool) {
+	st := healthpb.HealthCheckResponse_SERVING
+	if !serving {
+		st = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	r.health.SetServingStatus(healthService, st)
+}
+
+// startReplicas runs n in-memory servers, each holding book "1" and
+// reporting BookInfo as serving, until the test ends.
+func startReplicas(t *testing.T, n int) []*replica {
+	t.Helper()
+	replicas := make([]*replica, n)
+	for i := range replicas {
+		r := &replica{
+			fakeServer: newFakeServer(&pb.Book{Id: "1", Title: "Replicated"}),
+			addr:       fmt.Sprintf("replica-%d", i),
+			health:     health.NewServer(),
+		}
+		r.setServing(true)
+		r.lis = bufconn.Listen(1 << 20)
+		s := grpc.NewServer()
+		pb.RegisterBookInfoServer(s, r.fakeServer)
+		healthpb.RegisterHealthServer(s, r.health)
+		go s.Serve(r.lis)
+		t.Cleanup(s.Stop)
+		replicas[i] = r
+	}
+	return replicas
+}
+
+// dialReplicas connects to replicas balancing with policy. The returned
+// resolver changes the addresses the connection uses.
+func dialReplicas(t *testing.T, policy string, replicas []*replica) (*grpc.ClientConn, *manual.Resolver) {
+	t.Helper()
+	listeners := make(map[string]*bufconn.Listener)
+	var addrs []string
+	for _, r := range replicas {
+		listeners[r.addr] = r.lis
+		addrs = append(addrs, r.addr)
+	}
+	balancing, err := DialOptions(policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := StaticResolver(addrs...)
+	opts := append(balancing,
+		grpc.WithResolvers(res),
+		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return listeners[addr].DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.Dial(res.Scheme()+":///bookinfo", opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn, res
+}
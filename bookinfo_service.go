@@ -1,35 +1,287 @@
 package main
 
 import (
-    "context"
-    pb "github.com/marcoc22/tutorial3/booksapp"
-    "github.com/gofrs/uuid"
-    "google.golang.org/grpc/codes"
-    "google.golang.org/grpc/status"
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gofrs/uuid"
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"github.com/marcoc22/tutorial3/citation"
+	"github.com/marcoc22/tutorial3/store"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
+// booksCollection is the store collection books are kept in.
+const booksCollection = "books"
+
+var tracer = otel.Tracer("github.com/marcoc22/tutorial3")
+
 type server struct {
-    bookMap map[string]*pb.Book
+	store store.Store
+	// counts keeps the number of books in store, by language and work.
+	counts *bookCounts
+
+	// mu serializes writes, so that checking whether a book exists and
+	// changing it happen atomically.
+	mu sync.Mutex
+
+	// added remembers the books added by calls with idempotency keys.
+	added *idempotencyCache
+	// changes carries every change to the books to WatchBooks. When the
+	// store is replicated, the changes are published as each node applies
+	// them rather than by the handlers, so that watchers of every node see
+	// the writes made through the others.
+	changes    *changeFeed
+	replicated bool
+}
+
+// newServer returns a server of the books in s, which counts keeps
+// counts of (see countBooks).
+func newServer(s store.Store, counts *bookCounts) *server {
+	return &server{store: s, counts: counts, added: newIdempotencyCache(), changes: newChangeFeed()}
+}
+
+// storeSpan starts a span covering a single store operation on a book.
+func storeSpan(ctx context.Context, op, id string) trace.Span {
+	_, span := tracer.Start(ctx, "store."+op,
+		trace.WithAttributes(attribute.String("book.id", id)))
+	return span
+}
+
+// endSpan records err, if any, on span and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil && err != store.ErrNotFound {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (s *server) getBook(ctx context.Context, id string) (*pb.Book, error) {
+	span := storeSpan(ctx, "Get", id)
+	book := &pb.Book{}
+	err := store.GetMessage(s.store, booksCollection, id, book)
+	endSpan(span, err)
+	if err != nil {
+		return nil, storeError(err, id)
+	}
+	return book, nil
+}
+
+func (s *server) putBook(ctx context.Context, book *pb.Book) error {
+	span := storeSpan(ctx, "Put", book.Id)
+	err := store.PutMessage(s.store, booksCollection, book.Id, book)
+	endSpan(span, err)
+	if err != nil {
+		return storeError(err, book.Id)
+	}
+	return nil
+}
+
+func (s *server) deleteBook(ctx context.Context, id string) error {
+	span := storeSpan(ctx, "Delete", id)
+	err := s.store.Delete(booksCollection, id)
+	endSpan(span, err)
+	if err != nil {
+		return storeError(err, id)
+	}
+	return nil
+}
+
+// storeError converts a store error about book id to a gRPC status.
+func storeError(err error, id string) error {
+	return recordStatus(err, "Book", id)
+}
+
+// recordStatus converts a store error about the record of kind, such as
+// Book or Author, with id to a gRPC status.
+func recordStatus(err error, kind, id string) error {
+	if err == store.ErrNotFound {
+		return status.Errorf(codes.NotFound, "%s %s does not exist.", kind, id)
+	}
+	if errors.Is(err, store.ErrUnavailable) {
+		return status.Errorf(codes.Unavailable, "%s %s cannot be changed now: %v", kind, id, err)
+	}
+	return status.Errorf(codes.Internal, "Error while accessing %s %s: %v", kind, id, err)
 }
 
 func (s *server) AddBook(ctx context.Context, in *pb.Book) (*pb.BookID, error) {
-    out, err := uuid.NewV4()
-    if err != nil {
-        return nil, status.Errorf(codes.Internal,
-            "Error while generating Book ID", err)
-    }
-    in.Id = out.String()
-    if s.bookMap == nil {
-        s.bookMap = make(map[string]*pb.Book)
-    }
-    s.bookMap[in.Id] = in
-    return &pb.BookID{Value: in.Id}, status.New(codes.OK, "").Err()
+	add := func() (string, error) {
+		out, err := uuid.NewV4()
+		if err != nil {
+			return "", status.Errorf(codes.Internal,
+				"Error while generating Book ID: %v", err)
+		}
+		in.Id = out.String()
+		// Hold off deletions of the authors, publisher and work referenced.
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if err := s.resolveReferences(ctx, in); err != nil {
+			return "", err
+		}
+		if err := s.assignWork(in); err != nil {
+			return "", err
+		}
+		if err := s.putBook(ctx, in); err != nil {
+			return "", err
+		}
+		s.publishChange(&pb.BookChange{Type: pb.ChangeType_ADDED, Id: in.Id, Book: in})
+		return in.Id, nil
+	}
+	var id string
+	var err error
+	if key := keyFromContext(ctx); key != "" {
+		id, err = s.added.do(key, add)
+	} else {
+		id, err = add()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &pb.BookID{Value: id}, status.New(codes.OK, "").Err()
 }
 
 func (s *server) GetBook(ctx context.Context, in *pb.BookID) (*pb.Book, error) {
-    value, exists := s.bookMap[in.Value]
-    if exists {
-        return value, status.New(codes.OK, "").Err()
-    }
-    return nil, status.Errorf(codes.NotFound, "Book does not exist.", in.Value)
+	return s.getBook(ctx, in.Value)
+}
+
+func (s *server) UpdateBook(ctx context.Context, in *pb.Book) (*pb.Book, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, err := s.getBook(ctx, in.Id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.resolveReferences(ctx, in); err != nil {
+		return nil, err
+	}
+	if err := s.assignWork(in); err != nil {
+		return nil, err
+	}
+	if err := s.putBook(ctx, in); err != nil {
+		return nil, err
+	}
+	s.publishChange(&pb.BookChange{Type: pb.ChangeType_UPDATED, Id: in.Id, Book: in})
+	if old.WorkId != in.WorkId {
+		s.releaseWork(old.WorkId)
+	}
+	return in, status.New(codes.OK, "").Err()
+}
+
+func (s *server) DeleteBook(ctx context.Context, in *pb.BookID) (*pb.Book, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	book, err := s.getBook(ctx, in.Value)
+	if err != nil {
+		return nil, err
+	}
+	copies, err := s.countCopies(in.Value)
+	if err != nil {
+		return nil, err
+	}
+	if copies > 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "Book %s has %d copies.", in.Value, copies)
+	}
+	if err := s.deleteBook(ctx, in.Value); err != nil {
+		return nil, err
+	}
+	s.publishChange(&pb.BookChange{Type: pb.ChangeType_DELETED, Id: in.Value})
+	s.releaseWork(book.WorkId)
+	return book, status.New(codes.OK, "").Err()
+}
+
+func (s *server) ListBooks(in *pb.ListBooksRequest, stream pb.BookInfo_ListBooksServer) error {
+	if in.FromYear != 0 && in.ToYear != 0 && in.FromYear > in.ToYear {
+		return status.Errorf(codes.InvalidArgument, "from_year %d is after to_year %d.", in.FromYear, in.ToYear)
+	}
+	query := strings.ToLower(in.Query)
+	_, span := tracer.Start(stream.Context(), "store.Scan")
+	defer span.End()
+	err := s.scanBooks(func(book *pb.Book) error {
+		if !matchesQuery(book, query) || !matchesFilters(book, in) {
+			return nil
+		}
+		return stream.Send(book)
+	})
+	if _, ok := status.FromError(err); !ok {
+		return status.Errorf(codes.Internal, "Error while listing books: %v", err)
+	}
+	return err
+}
+
+// scanBooks calls fn with every book, in ID order, and stops at the first
+// error fn returns.
+func (s *server) scanBooks(fn func(book *pb.Book) error) error {
+	return s.store.Scan(booksCollection, func(id string, value []byte) error {
+		book := &pb.Book{}
+		if err := proto.Unmarshal(value, book); err != nil {
+			return status.Errorf(codes.DataLoss, "Book %s is corrupt: %v", id, err)
+		}
+		return fn(book)
+	})
+}
+
+func (s *server) FormatCitation(ctx context.Context, in *pb.FormatCitationRequest) (*pb.Citation, error) {
+	contentType, ok := citation.ContentTypes[in.Style]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "Unsupported citation style %v.", in.Style)
+	}
+	book, err := s.getBook(ctx, in.Id)
+	if err != nil {
+		return nil, err
+	}
+	text, err := citation.Format(book, in.Style)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Error while citing Book %s: %v", in.Id, err)
+	}
+	return &pb.Citation{Text: text, ContentType: contentType}, status.New(codes.OK, "").Err()
+}
+
+// matchesQuery reports whether any field of book contains query, which
+// must be lower case.
+func matchesQuery(book *pb.Book, query string) bool {
+	if query == "" {
+		return true
+	}
+	found := false
+	book.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Kind() == protoreflect.StringKind && !fd.IsList() &&
+			strings.Contains(strings.ToLower(v.String()), query) {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// matchesFilters reports whether book passes the field filters of req.
+func matchesFilters(book *pb.Book, req *pb.ListBooksRequest) bool {
+	if !equalOrEmpty(req.Author, book.Author) ||
+		!equalOrEmpty(req.Publisher, book.Publisher) ||
+		!equalOrEmpty(req.Language, book.Language) {
+		return false
+	}
+	if req.FromYear == 0 && req.ToYear == 0 {
+		return true
+	}
+	year, err := strconv.Atoi(book.Copyright)
+	if err != nil {
+		return false
+	}
+	return (req.FromYear == 0 || int32(year) >= req.FromYear) &&
+		(req.ToYear == 0 || int32(year) <= req.ToYear)
+}
+
+func equalOrEmpty(filter, value string) bool {
+	return filter == "" || strings.EqualFold(filter, value)
 }
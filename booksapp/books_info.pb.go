@@ -29,6 +29,285 @@ const (
 // of the legacy proto package is being used.
 const _ = proto.ProtoPackageIsVersion4
 
+type ContributorRole int32
+
+const (
+	ContributorRole_CONTRIBUTOR_ROLE_UNSPECIFIED ContributorRole = 0
+	ContributorRole_AUTHOR                       ContributorRole = 1
+	ContributorRole_EDITOR                       ContributorRole = 2
+	ContributorRole_TRANSLATOR                   ContributorRole = 3
+	ContributorRole_ILLUSTRATOR                  ContributorRole = 4
+)
+
+// Enum value maps for ContributorRole.
+var (
+	ContributorRole_name = map[int32]string{
+		0: "CONTRIBUTOR_ROLE_UNSPECIFIED",
+		1: "AUTHOR",
+		2: "EDITOR",
+		3: "TRANSLATOR",
+		4: "ILLUSTRATOR",
+	}
+	ContributorRole_value = map[string]int32{
+		"CONTRIBUTOR_ROLE_UNSPECIFIED": 0,
+		"AUTHOR":                       1,
+		"EDITOR":                       2,
+		"TRANSLATOR":                   3,
+		"ILLUSTRATOR":                  4,
+	}
+)
+
+func (x ContributorRole) Enum() *ContributorRole {
+	p := new(ContributorRole)
+	*p = x
+	return p
+}
+
+func (x ContributorRole) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ContributorRole) Descriptor() protoreflect.EnumDescriptor {
+	return file_books_info_proto_enumTypes[0].Descriptor()
+}
+
+func (ContributorRole) Type() protoreflect.EnumType {
+	return &file_books_info_proto_enumTypes[0]
+}
+
+func (x ContributorRole) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ContributorRole.Descriptor instead.
+func (ContributorRole) EnumDescriptor() ([]byte, []int) {
+	return file_books_info_proto_rawDescGZIP(), []int{0}
+}
+
+type ImportFormat int32
+
+const (
+	ImportFormat_IMPORT_FORMAT_UNSPECIFIED ImportFormat = 0
+	// MARC21 records in ISO 2709 transmission format.
+	ImportFormat_MARC21 ImportFormat = 1
+	// MARC21 records in the MARCXML schema.
+	ImportFormat_MARCXML ImportFormat = 2
+	// An ONIX for Books 3.0 feed. Products are matched to books by ISBN:
+	// notification type 05 deletes the book, others add or replace it.
+	ImportFormat_ONIX ImportFormat = 3
+)
+
+// Enum value maps for ImportFormat.
+var (
+	ImportFormat_name = map[int32]string{
+		0: "IMPORT_FORMAT_UNSPECIFIED",
+		1: "MARC21",
+		2: "MARCXML",
+		3: "ONIX",
+	}
+	ImportFormat_value = map[string]int32{
+		"IMPORT_FORMAT_UNSPECIFIED": 0,
+		"MARC21":                    1,
+		"MARCXML":                   2,
+		"ONIX":                      3,
+	}
+)
+
+func (x ImportFormat) Enum() *ImportFormat {
+	p := new(ImportFormat)
+	*p = x
+	return p
+}
+
+func (x ImportFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ImportFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_books_info_proto_enumTypes[1].Descriptor()
+}
+
+func (ImportFormat) Type() protoreflect.EnumType {
+	return &file_books_info_proto_enumTypes[1]
+}
+
+func (x ImportFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ImportFormat.Descriptor instead.
+func (ImportFormat) EnumDescriptor() ([]byte, []int) {
+	return file_books_info_proto_rawDescGZIP(), []int{1}
+}
+
+type CitationStyle int32
+
+const (
+	CitationStyle_CITATION_STYLE_UNSPECIFIED CitationStyle = 0
+	CitationStyle_BIBTEX                     CitationStyle = 1
+	CitationStyle_RIS                        CitationStyle = 2
+	// A CSL-JSON item object.
+	CitationStyle_CSL_JSON CitationStyle = 3
+	// APA 7th edition reference list entry.
+	CitationStyle_APA CitationStyle = 4
+	// MLA 9th edition works cited entry.
+	CitationStyle_MLA CitationStyle = 5
+	// Chicago 17th edition bibliography entry.
+	CitationStyle_CHICAGO CitationStyle = 6
+)
+
+// Enum value maps for CitationStyle.
+var (
+	CitationStyle_name = map[int32]string{
+		0: "CITATION_STYLE_UNSPECIFIED",
+		1: "BIBTEX",
+		2: "RIS",
+		3: "CSL_JSON",
+		4: "APA",
+		5: "MLA",
+		6: "CHICAGO",
+	}
+	CitationStyle_value = map[string]int32{
+		"CITATION_STYLE_UNSPECIFIED": 0,
+		"BIBTEX":                     1,
+		"RIS":                        2,
+		"CSL_JSON":                   3,
+		"APA":                        4,
+		"MLA":                        5,
+		"CHICAGO":                    6,
+	}
+)
+
+func (x CitationStyle) Enum() *CitationStyle {
+	p := new(CitationStyle)
+	*p = x
+	return p
+}
+
+func (x CitationStyle) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CitationStyle) Descriptor() protoreflect.EnumDescriptor {
+	return file_books_info_proto_enumTypes[2].Descriptor()
+}
+
+func (CitationStyle) Type() protoreflect.EnumType {
+	return &file_books_info_proto_enumTypes[2]
+}
+
+func (x CitationStyle) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CitationStyle.Descriptor instead.
+func (CitationStyle) EnumDescriptor() ([]byte, []int) {
+	return file_books_info_proto_rawDescGZIP(), []int{2}
+}
+
+type ChangeType int32
+
+const (
+	ChangeType_CHANGE_TYPE_UNSPECIFIED ChangeType = 0
+	ChangeType_ADDED                   ChangeType = 1
+	ChangeType_UPDATED                 ChangeType = 2
+	ChangeType_DELETED                 ChangeType = 3
+)
+
+// Enum value maps for ChangeType.
+var (
+	ChangeType_name = map[int32]string{
+		0: "CHANGE_TYPE_UNSPECIFIED",
+		1: "ADDED",
+		2: "UPDATED",
+		3: "DELETED",
+	}
+	ChangeType_value = map[string]int32{
+		"CHANGE_TYPE_UNSPECIFIED": 0,
+		"ADDED":                   1,
+		"UPDATED":                 2,
+		"DELETED":                 3,
+	}
+)
+
+func (x ChangeType) Enum() *ChangeType {
+	p := new(ChangeType)
+	*p = x
+	return p
+}
+
+func (x ChangeType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ChangeType) Descriptor() protoreflect.EnumDescriptor {
+	return file_books_info_proto_enumTypes[3].Descriptor()
+}
+
+func (ChangeType) Type() protoreflect.EnumType {
+	return &file_books_info_proto_enumTypes[3]
+}
+
+func (x ChangeType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ChangeType.Descriptor instead.
+func (ChangeType) EnumDescriptor() ([]byte, []int) {
+	return file_books_info_proto_rawDescGZIP(), []int{3}
+}
+
+type EditionOrder int32
+
+const (
+	EditionOrder_EDITION_ORDER_UNSPECIFIED EditionOrder = 0
+	// By edition number, read from Edition text such as "9th" or "Second".
+	EditionOrder_EDITION_NUMBER EditionOrder = 1
+	// By the year in Copyright.
+	EditionOrder_COPYRIGHT_YEAR EditionOrder = 2
+)
+
+// Enum value maps for EditionOrder.
+var (
+	EditionOrder_name = map[int32]string{
+		0: "EDITION_ORDER_UNSPECIFIED",
+		1: "EDITION_NUMBER",
+		2: "COPYRIGHT_YEAR",
+	}
+	EditionOrder_value = map[string]int32{
+		"EDITION_ORDER_UNSPECIFIED": 0,
+		"EDITION_NUMBER":            1,
+		"COPYRIGHT_YEAR":            2,
+	}
+)
+
+func (x EditionOrder) Enum() *EditionOrder {
+	p := new(EditionOrder)
+	*p = x
+	return p
+}
+
+func (x EditionOrder) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EditionOrder) Descriptor() protoreflect.EnumDescriptor {
+	return file_books_info_proto_enumTypes[4].Descriptor()
+}
+
+func (EditionOrder) Type() protoreflect.EnumType {
+	return &file_books_info_proto_enumTypes[4]
+}
+
+func (x EditionOrder) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EditionOrder.Descriptor instead.
+func (EditionOrder) EnumDescriptor() ([]byte, []int) {
+	return file_books_info_proto_rawDescGZIP(), []int{4}
+}
+
 type Book struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -42,6 +321,14 @@ type Book struct {
 	Pages     string `protobuf:"bytes,6,opt,name=Pages,json=pages,proto3" json:"Pages,omitempty"`
 	Author    string `protobuf:"bytes,7,opt,name=Author,json=author,proto3" json:"Author,omitempty"`
 	Publisher string `protobuf:"bytes,8,opt,name=Publisher,json=publisher,proto3" json:"Publisher,omitempty"`
+	Isbn      string `protobuf:"bytes,9,opt,name=Isbn,json=isbn,proto3" json:"Isbn,omitempty"`
+	// Contributors and PublisherId reference authors and a publisher.
+	// Author and Publisher remain the text displayed and cited; when
+	// empty, they are filled in from the referenced names.
+	Contributors []*Contributor `protobuf:"bytes,10,rep,name=Contributors,json=contributors,proto3" json:"Contributors,omitempty"`
+	PublisherId  string         `protobuf:"bytes,11,opt,name=PublisherId,json=publisherId,proto3" json:"PublisherId,omitempty"`
+	// WorkId is the work the book is an edition or translation of.
+	WorkId string `protobuf:"bytes,12,opt,name=WorkId,json=workId,proto3" json:"WorkId,omitempty"`
 }
 
 func (x *Book) Reset() {
@@ -132,6 +419,90 @@ func (x *Book) GetPublisher() string {
 	return ""
 }
 
+func (x *Book) GetIsbn() string {
+	if x != nil {
+		return x.Isbn
+	}
+	return ""
+}
+
+func (x *Book) GetContributors() []*Contributor {
+	if x != nil {
+		return x.Contributors
+	}
+	return nil
+}
+
+func (x *Book) GetPublisherId() string {
+	if x != nil {
+		return x.PublisherId
+	}
+	return ""
+}
+
+func (x *Book) GetWorkId() string {
+	if x != nil {
+		return x.WorkId
+	}
+	return ""
+}
+
+type Contributor struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AuthorId string `protobuf:"bytes,1,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	// role is AUTHOR when unspecified.
+	Role ContributorRole `protobuf:"varint,2,opt,name=role,proto3,enum=booksapp.ContributorRole" json:"role,omitempty"`
+}
+
+func (x *Contributor) Reset() {
+	*x = Contributor{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_books_info_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Contributor) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Contributor) ProtoMessage() {}
+
+func (x *Contributor) ProtoReflect() protoreflect.Message {
+	mi := &file_books_info_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Contributor.ProtoReflect.Descriptor instead.
+func (*Contributor) Descriptor() ([]byte, []int) {
+	return file_books_info_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Contributor) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+func (x *Contributor) GetRole() ContributorRole {
+	if x != nil {
+		return x.Role
+	}
+	return ContributorRole_CONTRIBUTOR_ROLE_UNSPECIFIED
+}
+
 type BookID struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -143,7 +514,7 @@ type BookID struct {
 func (x *BookID) Reset() {
 	*x = BookID{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_books_info_proto_msgTypes[1]
+		mi := &file_books_info_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -156,7 +527,7 @@ func (x *BookID) String() string {
 func (*BookID) ProtoMessage() {}
 
 func (x *BookID) ProtoReflect() protoreflect.Message {
-	mi := &file_books_info_proto_msgTypes[1]
+	mi := &file_books_info_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -169,7 +540,7 @@ func (x *BookID) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BookID.ProtoReflect.Descriptor instead.
 func (*BookID) Descriptor() ([]byte, []int) {
-	return file_books_info_proto_rawDescGZIP(), []int{1}
+	return file_books_info_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *BookID) GetValue() string {
@@ -179,117 +550,2066 @@ func (x *BookID) GetValue() string {
 	return ""
 }
 
-var File_books_info_proto protoreflect.FileDescriptor
+// ListBooksRequest selects the books listBooks streams, always in ID
+// order. Empty fields do not filter.
+type ListBooksRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-var file_books_info_proto_rawDesc = []byte{
-	0x0a, 0x10, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x12, 0x08, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x22, 0xcc, 0x01, 0x0a,
-	0x04, 0x42, 0x6f, 0x6f, 0x6b, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x54, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x45,
-	0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x65, 0x64,
-	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x43, 0x6f, 0x70, 0x79, 0x72, 0x69, 0x67,
-	0x68, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x6f, 0x70, 0x79, 0x72, 0x69,
-	0x67, 0x68, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x4c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65, 0x18,
-	0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65, 0x12,
-	0x14, 0x0a, 0x05, 0x50, 0x61, 0x67, 0x65, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
-	0x70, 0x61, 0x67, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x18,
-	0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x12, 0x1c, 0x0a,
-	0x09, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x09, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72, 0x22, 0x1e, 0x0a, 0x06, 0x42,
-	0x6f, 0x6f, 0x6b, 0x49, 0x44, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x32, 0xc2, 0x01, 0x0a, 0x08,
-	0x42, 0x6f, 0x6f, 0x6b, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x2b, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x42,
-	0x6f, 0x6f, 0x6b, 0x12, 0x0e, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x42,
-	0x6f, 0x6f, 0x6b, 0x1a, 0x10, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x42,
-	0x6f, 0x6f, 0x6b, 0x49, 0x44, 0x12, 0x2b, 0x0a, 0x07, 0x67, 0x65, 0x74, 0x42, 0x6f, 0x6f, 0x6b,
-	0x12, 0x10, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x42, 0x6f, 0x6f, 0x6b,
-	0x49, 0x44, 0x1a, 0x0e, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x42, 0x6f,
-	0x6f, 0x6b, 0x12, 0x2c, 0x0a, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x42, 0x6f, 0x6f, 0x6b,
-	0x12, 0x0e, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x42, 0x6f, 0x6f, 0x6b,
-	0x1a, 0x0e, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x42, 0x6f, 0x6f, 0x6b,
-	0x12, 0x2e, 0x0a, 0x0a, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x6f, 0x6f, 0x6b, 0x12, 0x10,
-	0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x42, 0x6f, 0x6f, 0x6b, 0x49, 0x44,
-	0x1a, 0x0e, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x42, 0x6f, 0x6f, 0x6b,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	// Only books with a field containing query, ignoring case, are listed.
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// Only books whose author, publisher or language equals these, ignoring
+	// case, are listed.
+	Author    string `protobuf:"bytes,2,opt,name=author,proto3" json:"author,omitempty"`
+	Publisher string `protobuf:"bytes,3,opt,name=publisher,proto3" json:"publisher,omitempty"`
+	Language  string `protobuf:"bytes,4,opt,name=language,proto3" json:"language,omitempty"`
+	// Only books with a copyright year in [from_year, to_year] are listed.
+	// A zero bound is open.
+	FromYear int32 `protobuf:"varint,5,opt,name=from_year,json=fromYear,proto3" json:"from_year,omitempty"`
+	ToYear   int32 `protobuf:"varint,6,opt,name=to_year,json=toYear,proto3" json:"to_year,omitempty"`
 }
 
-var (
-	file_books_info_proto_rawDescOnce sync.Once
-	file_books_info_proto_rawDescData = file_books_info_proto_rawDesc
-)
+func (x *ListBooksRequest) Reset() {
+	*x = ListBooksRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_books_info_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
 
-func file_books_info_proto_rawDescGZIP() []byte {
-	file_books_info_proto_rawDescOnce.Do(func() {
-		file_books_info_proto_rawDescData = protoimpl.X.CompressGZIP(file_books_info_proto_rawDescData)
-	})
-	return file_books_info_proto_rawDescData
+func (x *ListBooksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-var file_books_info_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
-var file_books_info_proto_goTypes = []interface{}{
-	(*Book)(nil),   // 0: booksapp.Book
-	(*BookID)(nil), // 1: booksapp.BookID
+func (*ListBooksRequest) ProtoMessage() {}
+
+func (x *ListBooksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_books_info_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-var file_books_info_proto_depIdxs = []int32{
-	0, // 0: booksapp.BookInfo.addBook:input_type -> booksapp.Book
-	1, // 1: booksapp.BookInfo.getBook:input_type -> booksapp.BookID
-	0, // 2: booksapp.BookInfo.updateBook:input_type -> booksapp.Book
-	1, // 3: booksapp.BookInfo.deleteBook:input_type -> booksapp.BookID
-	1, // 4: booksapp.BookInfo.addBook:output_type -> booksapp.BookID
-	0, // 5: booksapp.BookInfo.getBook:output_type -> booksapp.Book
-	0, // 6: booksapp.BookInfo.updateBook:output_type -> booksapp.Book
-	0, // 7: booksapp.BookInfo.deleteBook:output_type -> booksapp.Book
-	4, // [4:8] is the sub-list for method output_type
-	0, // [0:4] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+
+// Deprecated: Use ListBooksRequest.ProtoReflect.Descriptor instead.
+func (*ListBooksRequest) Descriptor() ([]byte, []int) {
+	return file_books_info_proto_rawDescGZIP(), []int{3}
 }
 
-func init() { file_books_info_proto_init() }
-func file_books_info_proto_init() {
-	if File_books_info_proto != nil {
-		return
+func (x *ListBooksRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_books_info_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Book); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_books_info_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BookID); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
+	return ""
+}
+
+func (x *ListBooksRequest) GetAuthor() string {
+	if x != nil {
+		return x.Author
 	}
-	type x struct{}
-	out := protoimpl.TypeBuilder{
-		File: protoimpl.DescBuilder{
-			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
-			RawDescriptor: file_books_info_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   2,
+	return ""
+}
+
+func (x *ListBooksRequest) GetPublisher() string {
+	if x != nil {
+		return x.Publisher
+	}
+	return ""
+}
+
+func (x *ListBooksRequest) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *ListBooksRequest) GetFromYear() int32 {
+	if x != nil {
+		return x.FromYear
+	}
+	return 0
+}
+
+func (x *ListBooksRequest) GetToYear() int32 {
+	if x != nil {
+		return x.ToYear
+	}
+	return 0
+}
+
+type ImportChunk struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Format ImportFormat `protobuf:"varint,1,opt,name=format,proto3,enum=booksapp.ImportFormat" json:"format,omitempty"`
+	Data   []byte       `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (x *ImportChunk) Reset() {
+	*x = ImportChunk{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_books_info_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ImportChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportChunk) ProtoMessage() {}
+
+func (x *ImportChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_books_info_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportChunk.ProtoReflect.Descriptor instead.
+func (*ImportChunk) Descriptor() ([]byte, []int) {
+	return file_books_info_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ImportChunk) GetFormat() ImportFormat {
+	if x != nil {
+		return x.Format
+	}
+	return ImportFormat_IMPORT_FORMAT_UNSPECIFIED
+}
+
+func (x *ImportChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type ImportError struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// record is the position of the failing record in the file, from 1.
+	Record  int32  `protobuf:"varint,1,opt,name=record,proto3" json:"record,omitempty"`
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *ImportError) Reset() {
+	*x = ImportError{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_books_info_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ImportError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportError) ProtoMessage() {}
+
+func (x *ImportError) ProtoReflect() protoreflect.Message {
+	mi := &file_books_info_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportError.ProtoReflect.Descriptor instead.
+func (*ImportError) Descriptor() ([]byte, []int) {
+	return file_books_info_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ImportError) GetRecord() int32 {
+	if x != nil {
+		return x.Record
+	}
+	return 0
+}
+
+func (x *ImportError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ImportSummary struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Added   int32          `protobuf:"varint,1,opt,name=added,proto3" json:"added,omitempty"`
+	Errors  []*ImportError `protobuf:"bytes,2,rep,name=errors,proto3" json:"errors,omitempty"`
+	Updated int32          `protobuf:"varint,3,opt,name=updated,proto3" json:"updated,omitempty"`
+	Deleted int32          `protobuf:"varint,4,opt,name=deleted,proto3" json:"deleted,omitempty"`
+}
+
+func (x *ImportSummary) Reset() {
+	*x = ImportSummary{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_books_info_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ImportSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportSummary) ProtoMessage() {}
+
+func (x *ImportSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_books_info_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportSummary.ProtoReflect.Descriptor instead.
+func (*ImportSummary) Descriptor() ([]byte, []int) {
+	return file_books_info_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ImportSummary) GetAdded() int32 {
+	if x != nil {
+		return x.Added
+	}
+	return 0
+}
+
+func (x *ImportSummary) GetErrors() []*ImportError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+func (x *ImportSummary) GetUpdated() int32 {
+	if x != nil {
+		return x.Updated
+	}
+	return 0
+}
+
+func (x *ImportSummary) GetDeleted() int32 {
+	if x != nil {
+		return x.Deleted
+	}
+	return 0
+}
+
+type FormatCitationRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id    string        `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Style CitationStyle `protobuf:"varint,2,opt,name=style,proto3,enum=booksapp.CitationStyle" json:"style,omitempty"`
+}
+
+func (x *FormatCitationRequest) Reset() {
+	*x = FormatCitationRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_books_info_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FormatCitationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FormatCitationRequest) ProtoMessage() {}
+
+func (x *FormatCitationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_books_info_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FormatCitationRequest.ProtoReflect.Descriptor instead.
+func (*FormatCitationRequest) Descriptor() ([]byte, []int) {
+	return file_books_info_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *FormatCitationRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *FormatCitationRequest) GetStyle() CitationStyle {
+	if x != nil {
+		return x.Style
+	}
+	return CitationStyle_CITATION_STYLE_UNSPECIFIED
+}
+
+type Citation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Text string `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	// content_type is the media type of text, e.g. application/x-bibtex.
+	ContentType string `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+}
+
+func (x *Citation) Reset() {
+	*x = Citation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_books_info_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Citation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Citation) ProtoMessage() {}
+
+func (x *Citation) ProtoReflect() protoreflect.Message {
+	mi := &file_books_info_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Citation.ProtoReflect.Descriptor instead.
+func (*Citation) Descriptor() ([]byte, []int) {
+	return file_books_info_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Citation) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *Citation) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+type WatchBooksRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *WatchBooksRequest) Reset() {
+	*x = WatchBooksRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_books_info_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchBooksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchBooksRequest) ProtoMessage() {}
+
+func (x *WatchBooksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_books_info_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchBooksRequest.ProtoReflect.Descriptor instead.
+func (*WatchBooksRequest) Descriptor() ([]byte, []int) {
+	return file_books_info_proto_rawDescGZIP(), []int{9}
+}
+
+type BookChange struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type ChangeType `protobuf:"varint,1,opt,name=type,proto3,enum=booksapp.ChangeType" json:"type,omitempty"`
+	Id   string     `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	// book is the book as added or updated, and unset for deletions.
+	Book *Book `protobuf:"bytes,3,opt,name=book,proto3" json:"book,omitempty"`
+}
+
+func (x *BookChange) Reset() {
+	*x = BookChange{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_books_info_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BookChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BookChange) ProtoMessage() {}
+
+func (x *BookChange) ProtoReflect() protoreflect.Message {
+	mi := &file_books_info_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BookChange.ProtoReflect.Descriptor instead.
+func (*BookChange) Descriptor() ([]byte, []int) {
+	return file_books_info_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *BookChange) GetType() ChangeType {
+	if x != nil {
+		return x.Type
+	}
+	return ChangeType_CHANGE_TYPE_UNSPECIFIED
+}
+
+func (x *BookChange) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *BookChange) GetBook() *Book {
+	if x != nil {
+		return x.Book
+	}
+	return nil
+}
+
+type Author struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id   string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *Author) Reset() {
+	*x = Author{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_books_info_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Author) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Author) ProtoMessage() {}
+
+func (x *Author) ProtoReflect() protoreflect.Message {
+	mi := &file_books_info_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Author.ProtoReflect.Descriptor instead.
+func (*Author) Descriptor() ([]byte, []int) {
+	return file_books_info_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Author) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Author) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type AuthorID struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Value string `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *AuthorID) Reset() {
+	*x = AuthorID{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_books_info_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuthorID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthorID) ProtoMessage() {}
+
+func (x *AuthorID) ProtoReflect() protoreflect.Message {
+	mi := &file_books_info_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthorID.ProtoReflect.Descriptor instead.
+func (*AuthorID) Descriptor() ([]byte, []int) {
+	return file_books_info_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *AuthorID) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type Publisher struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id   string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *Publisher) Reset() {
+	*x = Publisher{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_books_info_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Publisher) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Publisher) ProtoMessage() {}
+
+func (x *Publisher) ProtoReflect() protoreflect.Message {
+	mi := &file_books_info_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Publisher.ProtoReflect.Descriptor instead.
+func (*Publisher) Descriptor() ([]byte, []int) {
+	return file_books_info_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Publisher) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Publisher) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type PublisherID struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Value string `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *PublisherID) Reset() {
+	*x = PublisherID{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_books_info_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PublisherID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublisherID) ProtoMessage() {}
+
+func (x *PublisherID) ProtoReflect() protoreflect.Message {
+	mi := &file_books_info_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublisherID.ProtoReflect.Descriptor instead.
+func (*PublisherID) Descriptor() ([]byte, []int) {
+	return file_books_info_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *PublisherID) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type ListAuthorsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Only authors whose name contains query, ignoring case, are listed.
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+}
+
+func (x *ListAuthorsRequest) Reset() {
+	*x = ListAuthorsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_books_info_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListAuthorsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuthorsRequest) ProtoMessage() {}
+
+func (x *ListAuthorsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_books_info_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuthorsRequest.ProtoReflect.Descriptor instead.
+func (*ListAuthorsRequest) Descriptor() ([]byte, []int) {
+	return file_books_info_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListAuthorsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+type ListPublishersRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Only publishers whose name contains query, ignoring case, are listed.
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+}
+
+func (x *ListPublishersRequest) Reset() {
+	*x = ListPublishersRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_books_info_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListPublishersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPublishersRequest) ProtoMessage() {}
+
+func (x *ListPublishersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_books_info_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPublishersRequest.ProtoReflect.Descriptor instead.
+func (*ListPublishersRequest) Descriptor() ([]byte, []int) {
+	return file_books_info_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListPublishersRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+type ListBooksByAuthorRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AuthorId string `protobuf:"bytes,1,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	// Only books crediting the author in role are listed, if set.
+	Role ContributorRole `protobuf:"varint,2,opt,name=role,proto3,enum=booksapp.ContributorRole" json:"role,omitempty"`
+}
+
+func (x *ListBooksByAuthorRequest) Reset() {
+	*x = ListBooksByAuthorRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_books_info_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListBooksByAuthorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBooksByAuthorRequest) ProtoMessage() {}
+
+func (x *ListBooksByAuthorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_books_info_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBooksByAuthorRequest.ProtoReflect.Descriptor instead.
+func (*ListBooksByAuthorRequest) Descriptor() ([]byte, []int) {
+	return file_books_info_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListBooksByAuthorRequest) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+func (x *ListBooksByAuthorRequest) GetRole() ContributorRole {
+	if x != nil {
+		return x.Role
+	}
+	return ContributorRole_CONTRIBUTOR_ROLE_UNSPECIFIED
+}
+
+type ListBooksByPublisherRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PublisherId string `protobuf:"bytes,1,opt,name=publisher_id,json=publisherId,proto3" json:"publisher_id,omitempty"`
+}
+
+func (x *ListBooksByPublisherRequest) Reset() {
+	*x = ListBooksByPublisherRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_books_info_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListBooksByPublisherRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBooksByPublisherRequest) ProtoMessage() {}
+
+func (x *ListBooksByPublisherRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_books_info_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBooksByPublisherRequest.ProtoReflect.Descriptor instead.
+func (*ListBooksByPublisherRequest) Descriptor() ([]byte, []int) {
+	return file_books_info_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ListBooksByPublisherRequest) GetPublisherId() string {
+	if x != nil {
+		return x.PublisherId
+	}
+	return ""
+}
+
+type LinkEntitiesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// dry_run counts what would be linked and created without changing
+	// anything.
+	DryRun bool `protobuf:"varint,1,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+}
+
+func (x *LinkEntitiesRequest) Reset() {
+	*x = LinkEntitiesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_books_info_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LinkEntitiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkEntitiesRequest) ProtoMessage() {}
+
+func (x *LinkEntitiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_books_info_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LinkEntitiesRequest.ProtoReflect.Descriptor instead.
+func (*LinkEntitiesRequest) Descriptor() ([]byte, []int) {
+	return file_books_info_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *LinkEntitiesRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type LinkEntitiesSummary struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BooksLinked       int32 `protobuf:"varint,1,opt,name=books_linked,json=booksLinked,proto3" json:"books_linked,omitempty"`
+	AuthorsCreated    int32 `protobuf:"varint,2,opt,name=authors_created,json=authorsCreated,proto3" json:"authors_created,omitempty"`
+	PublishersCreated int32 `protobuf:"varint,3,opt,name=publishers_created,json=publishersCreated,proto3" json:"publishers_created,omitempty"`
+}
+
+func (x *LinkEntitiesSummary) Reset() {
+	*x = LinkEntitiesSummary{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_books_info_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LinkEntitiesSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkEntitiesSummary) ProtoMessage() {}
+
+func (x *LinkEntitiesSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_books_info_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LinkEntitiesSummary.ProtoReflect.Descriptor instead.
+func (*LinkEntitiesSummary) Descriptor() ([]byte, []int) {
+	return file_books_info_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *LinkEntitiesSummary) GetBooksLinked() int32 {
+	if x != nil {
+		return x.BooksLinked
+	}
+	return 0
+}
+
+func (x *LinkEntitiesSummary) GetAuthorsCreated() int32 {
+	if x != nil {
+		return x.AuthorsCreated
+	}
+	return 0
+}
+
+func (x *LinkEntitiesSummary) GetPublishersCreated() int32 {
+	if x != nil {
+		return x.PublishersCreated
+	}
+	return 0
+}
+
+// Work is what the editions and translations of a title have in common.
+type Work struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id     string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title  string `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Author string `protobuf:"bytes,3,opt,name=author,proto3" json:"author,omitempty"`
+}
+
+func (x *Work) Reset() {
+	*x = Work{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_books_info_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Work) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Work) ProtoMessage() {}
+
+func (x *Work) ProtoReflect() protoreflect.Message {
+	mi := &file_books_info_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Work.ProtoReflect.Descriptor instead.
+func (*Work) Descriptor() ([]byte, []int) {
+	return file_books_info_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *Work) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Work) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Work) GetAuthor() string {
+	if x != nil {
+		return x.Author
+	}
+	return ""
+}
+
+type WorkID struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Value string `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *WorkID) Reset() {
+	*x = WorkID{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_books_info_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WorkID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkID) ProtoMessage() {}
+
+func (x *WorkID) ProtoReflect() protoreflect.Message {
+	mi := &file_books_info_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkID.ProtoReflect.Descriptor instead.
+func (*WorkID) Descriptor() ([]byte, []int) {
+	return file_books_info_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *WorkID) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type ListWorksRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Only works whose title or author contains query, ignoring case, are
+	// listed.
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+}
+
+func (x *ListWorksRequest) Reset() {
+	*x = ListWorksRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_books_info_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListWorksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWorksRequest) ProtoMessage() {}
+
+func (x *ListWorksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_books_info_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWorksRequest.ProtoReflect.Descriptor instead.
+func (*ListWorksRequest) Descriptor() ([]byte, []int) {
+	return file_books_info_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ListWorksRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+type ListEditionsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	WorkId string `protobuf:"bytes,1,opt,name=work_id,json=workId,proto3" json:"work_id,omitempty"`
+	// order defaults to EDITION_NUMBER. Books whose edition or year cannot
+	// be read come last; ties are broken by the other order, then by ID.
+	Order EditionOrder `protobuf:"varint,2,opt,name=order,proto3,enum=booksapp.EditionOrder" json:"order,omitempty"`
+}
+
+func (x *ListEditionsRequest) Reset() {
+	*x = ListEditionsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_books_info_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListEditionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEditionsRequest) ProtoMessage() {}
+
+func (x *ListEditionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_books_info_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEditionsRequest.ProtoReflect.Descriptor instead.
+func (*ListEditionsRequest) Descriptor() ([]byte, []int) {
+	return file_books_info_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ListEditionsRequest) GetWorkId() string {
+	if x != nil {
+		return x.WorkId
+	}
+	return ""
+}
+
+func (x *ListEditionsRequest) GetOrder() EditionOrder {
+	if x != nil {
+		return x.Order
+	}
+	return EditionOrder_EDITION_ORDER_UNSPECIFIED
+}
+
+type GroupWorksRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// dry_run counts what would be grouped and created without changing
+	// anything.
+	DryRun bool `protobuf:"varint,1,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+}
+
+func (x *GroupWorksRequest) Reset() {
+	*x = GroupWorksRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_books_info_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GroupWorksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupWorksRequest) ProtoMessage() {}
+
+func (x *GroupWorksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_books_info_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupWorksRequest.ProtoReflect.Descriptor instead.
+func (*GroupWorksRequest) Descriptor() ([]byte, []int) {
+	return file_books_info_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GroupWorksRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type GroupWorksSummary struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BooksGrouped int32 `protobuf:"varint,1,opt,name=books_grouped,json=booksGrouped,proto3" json:"books_grouped,omitempty"`
+	WorksCreated int32 `protobuf:"varint,2,opt,name=works_created,json=worksCreated,proto3" json:"works_created,omitempty"`
+}
+
+func (x *GroupWorksSummary) Reset() {
+	*x = GroupWorksSummary{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_books_info_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GroupWorksSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupWorksSummary) ProtoMessage() {}
+
+func (x *GroupWorksSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_books_info_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupWorksSummary.ProtoReflect.Descriptor instead.
+func (*GroupWorksSummary) Descriptor() ([]byte, []int) {
+	return file_books_info_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *GroupWorksSummary) GetBooksGrouped() int32 {
+	if x != nil {
+		return x.BooksGrouped
+	}
+	return 0
+}
+
+func (x *GroupWorksSummary) GetWorksCreated() int32 {
+	if x != nil {
+		return x.WorksCreated
+	}
+	return 0
+}
+
+var File_books_info_proto protoreflect.FileDescriptor
+
+var file_books_info_proto_rawDesc = []byte{
+	0x0a, 0x10, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x08, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x22, 0xd5, 0x02, 0x0a,
+	0x04, 0x42, 0x6f, 0x6f, 0x6b, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x54, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x45,
+	0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x65, 0x64,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x43, 0x6f, 0x70, 0x79, 0x72, 0x69, 0x67,
+	0x68, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x6f, 0x70, 0x79, 0x72, 0x69,
+	0x67, 0x68, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x4c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65, 0x12,
+	0x14, 0x0a, 0x05, 0x50, 0x61, 0x67, 0x65, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x70, 0x61, 0x67, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x12, 0x1c, 0x0a,
+	0x09, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x49,
+	0x73, 0x62, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x69, 0x73, 0x62, 0x6e, 0x12,
+	0x39, 0x0a, 0x0c, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x6f, 0x72, 0x73, 0x18,
+	0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70,
+	0x2e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x6f, 0x72, 0x52, 0x0c, 0x63, 0x6f,
+	0x6e, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x6f, 0x72, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x50, 0x75,
+	0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72, 0x49, 0x64, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06,
+	0x57, 0x6f, 0x72, 0x6b, 0x49, 0x64, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x77, 0x6f,
+	0x72, 0x6b, 0x49, 0x64, 0x22, 0x59, 0x0a, 0x0b, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x69, 0x62, 0x75,
+	0x74, 0x6f, 0x72, 0x12, 0x1b, 0x0a, 0x09, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x49, 0x64,
+	0x12, 0x2d, 0x0a, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19,
+	0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x69,
+	0x62, 0x75, 0x74, 0x6f, 0x72, 0x52, 0x6f, 0x6c, 0x65, 0x52, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x22,
+	0x1e, 0x0a, 0x06, 0x42, 0x6f, 0x6f, 0x6b, 0x49, 0x44, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22,
+	0xb0, 0x01, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x6f, 0x6f, 0x6b, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x75,
+	0x74, 0x68, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x75, 0x74, 0x68,
+	0x6f, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72,
+	0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x6c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65, 0x12, 0x1b, 0x0a, 0x09,
+	0x66, 0x72, 0x6f, 0x6d, 0x5f, 0x79, 0x65, 0x61, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x08, 0x66, 0x72, 0x6f, 0x6d, 0x59, 0x65, 0x61, 0x72, 0x12, 0x17, 0x0a, 0x07, 0x74, 0x6f, 0x5f,
+	0x79, 0x65, 0x61, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x74, 0x6f, 0x59, 0x65,
+	0x61, 0x72, 0x22, 0x51, 0x0a, 0x0b, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x43, 0x68, 0x75, 0x6e,
+	0x6b, 0x12, 0x2e, 0x0a, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x16, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x49, 0x6d, 0x70,
+	0x6f, 0x72, 0x74, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x52, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61,
+	0x74, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x3f, 0x0a, 0x0b, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x45,
+	0x72, 0x72, 0x6f, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x12, 0x18, 0x0a, 0x07,
+	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x88, 0x01, 0x0a, 0x0d, 0x49, 0x6d, 0x70, 0x6f, 0x72,
+	0x74, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x64, 0x64, 0x65,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x61, 0x64, 0x64, 0x65, 0x64, 0x12, 0x2d,
+	0x0a, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15,
+	0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74,
+	0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x12, 0x18, 0x0a,
+	0x07, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07,
+	0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x64, 0x22, 0x56, 0x0a, 0x15, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x43, 0x69, 0x74, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x2d, 0x0a, 0x05, 0x73, 0x74,
+	0x79, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x62, 0x6f, 0x6f, 0x6b,
+	0x73, 0x61, 0x70, 0x70, 0x2e, 0x43, 0x69, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x79,
+	0x6c, 0x65, 0x52, 0x05, 0x73, 0x74, 0x79, 0x6c, 0x65, 0x22, 0x41, 0x0a, 0x08, 0x43, 0x69, 0x74,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x6e,
+	0x74, 0x65, 0x6e, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x22, 0x13, 0x0a, 0x11,
+	0x57, 0x61, 0x74, 0x63, 0x68, 0x42, 0x6f, 0x6f, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x22, 0x6a, 0x0a, 0x0a, 0x42, 0x6f, 0x6f, 0x6b, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x12,
+	0x28, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e,
+	0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x54,
+	0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x22, 0x0a, 0x04, 0x62, 0x6f, 0x6f,
+	0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61,
+	0x70, 0x70, 0x2e, 0x42, 0x6f, 0x6f, 0x6b, 0x52, 0x04, 0x62, 0x6f, 0x6f, 0x6b, 0x22, 0x2c, 0x0a,
+	0x06, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x20, 0x0a, 0x08, 0x41,
+	0x75, 0x74, 0x68, 0x6f, 0x72, 0x49, 0x44, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x2f, 0x0a,
+	0x09, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x23,
+	0x0a, 0x0b, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72, 0x49, 0x44, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x22, 0x2a, 0x0a, 0x12, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x75, 0x74, 0x68, 0x6f,
+	0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x71, 0x75, 0x65,
+	0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x22,
+	0x2d, 0x0a, 0x15, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x71, 0x75, 0x65, 0x72,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x22, 0x66,
+	0x0a, 0x18, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x6f, 0x6f, 0x6b, 0x73, 0x42, 0x79, 0x41, 0x75, 0x74,
+	0x68, 0x6f, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x61, 0x75,
+	0x74, 0x68, 0x6f, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x61,
+	0x75, 0x74, 0x68, 0x6f, 0x72, 0x49, 0x64, 0x12, 0x2d, 0x0a, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70,
+	0x2e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x6f, 0x72, 0x52, 0x6f, 0x6c, 0x65,
+	0x52, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x22, 0x40, 0x0a, 0x1b, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x6f,
+	0x6f, 0x6b, 0x73, 0x42, 0x79, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68,
+	0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x75, 0x62,
+	0x6c, 0x69, 0x73, 0x68, 0x65, 0x72, 0x49, 0x64, 0x22, 0x2e, 0x0a, 0x13, 0x4c, 0x69, 0x6e, 0x6b,
+	0x45, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x17, 0x0a, 0x07, 0x64, 0x72, 0x79, 0x5f, 0x72, 0x75, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x06, 0x64, 0x72, 0x79, 0x52, 0x75, 0x6e, 0x22, 0x90, 0x01, 0x0a, 0x13, 0x4c, 0x69, 0x6e,
+	0x6b, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79,
+	0x12, 0x21, 0x0a, 0x0c, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x5f, 0x6c, 0x69, 0x6e, 0x6b, 0x65, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x4c, 0x69, 0x6e,
+	0x6b, 0x65, 0x64, 0x12, 0x27, 0x0a, 0x0f, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x73, 0x5f, 0x63,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x61, 0x75,
+	0x74, 0x68, 0x6f, 0x72, 0x73, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x12, 0x2d, 0x0a, 0x12,
+	0x70, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72, 0x73, 0x5f, 0x63, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x11, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x73,
+	0x68, 0x65, 0x72, 0x73, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x22, 0x44, 0x0a, 0x04, 0x57,
+	0x6f, 0x72, 0x6b, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x02, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x75, 0x74,
+	0x68, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x75, 0x74, 0x68, 0x6f,
+	0x72, 0x22, 0x1e, 0x0a, 0x06, 0x57, 0x6f, 0x72, 0x6b, 0x49, 0x44, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x22, 0x28, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x22, 0x5c, 0x0a, 0x13, 0x4c,
+	0x69, 0x73, 0x74, 0x45, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x77, 0x6f, 0x72, 0x6b, 0x49, 0x64, 0x12, 0x2c, 0x0a, 0x05, 0x6f,
+	0x72, 0x64, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x62, 0x6f, 0x6f,
+	0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x45, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x4f, 0x72, 0x64,
+	0x65, 0x72, 0x52, 0x05, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x22, 0x2c, 0x0a, 0x11, 0x47, 0x72, 0x6f,
+	0x75, 0x70, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17,
+	0x0a, 0x07, 0x64, 0x72, 0x79, 0x5f, 0x72, 0x75, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x06, 0x64, 0x72, 0x79, 0x52, 0x75, 0x6e, 0x22, 0x5d, 0x0a, 0x11, 0x47, 0x72, 0x6f, 0x75, 0x70,
+	0x57, 0x6f, 0x72, 0x6b, 0x73, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x23, 0x0a, 0x0d,
+	0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x65, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0c, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x65,
+	0x64, 0x12, 0x23, 0x0a, 0x0d, 0x77, 0x6f, 0x72, 0x6b, 0x73, 0x5f, 0x63, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x77, 0x6f, 0x72, 0x6b, 0x73, 0x43,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x2a, 0x6c, 0x0a, 0x0f, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x69,
+	0x62, 0x75, 0x74, 0x6f, 0x72, 0x52, 0x6f, 0x6c, 0x65, 0x12, 0x20, 0x0a, 0x1c, 0x43, 0x4f, 0x4e,
+	0x54, 0x52, 0x49, 0x42, 0x55, 0x54, 0x4f, 0x52, 0x5f, 0x52, 0x4f, 0x4c, 0x45, 0x5f, 0x55, 0x4e,
+	0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x41,
+	0x55, 0x54, 0x48, 0x4f, 0x52, 0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x45, 0x44, 0x49, 0x54, 0x4f,
+	0x52, 0x10, 0x02, 0x12, 0x0e, 0x0a, 0x0a, 0x54, 0x52, 0x41, 0x4e, 0x53, 0x4c, 0x41, 0x54, 0x4f,
+	0x52, 0x10, 0x03, 0x12, 0x0f, 0x0a, 0x0b, 0x49, 0x4c, 0x4c, 0x55, 0x53, 0x54, 0x52, 0x41, 0x54,
+	0x4f, 0x52, 0x10, 0x04, 0x2a, 0x50, 0x0a, 0x0c, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x46, 0x6f,
+	0x72, 0x6d, 0x61, 0x74, 0x12, 0x1d, 0x0a, 0x19, 0x49, 0x4d, 0x50, 0x4f, 0x52, 0x54, 0x5f, 0x46,
+	0x4f, 0x52, 0x4d, 0x41, 0x54, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45,
+	0x44, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x4d, 0x41, 0x52, 0x43, 0x32, 0x31, 0x10, 0x01, 0x12,
+	0x0b, 0x0a, 0x07, 0x4d, 0x41, 0x52, 0x43, 0x58, 0x4d, 0x4c, 0x10, 0x02, 0x12, 0x08, 0x0a, 0x04,
+	0x4f, 0x4e, 0x49, 0x58, 0x10, 0x03, 0x2a, 0x71, 0x0a, 0x0d, 0x43, 0x69, 0x74, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x53, 0x74, 0x79, 0x6c, 0x65, 0x12, 0x1e, 0x0a, 0x1a, 0x43, 0x49, 0x54, 0x41, 0x54,
+	0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x59, 0x4c, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43,
+	0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x42, 0x49, 0x42, 0x54, 0x45,
+	0x58, 0x10, 0x01, 0x12, 0x07, 0x0a, 0x03, 0x52, 0x49, 0x53, 0x10, 0x02, 0x12, 0x0c, 0x0a, 0x08,
+	0x43, 0x53, 0x4c, 0x5f, 0x4a, 0x53, 0x4f, 0x4e, 0x10, 0x03, 0x12, 0x07, 0x0a, 0x03, 0x41, 0x50,
+	0x41, 0x10, 0x04, 0x12, 0x07, 0x0a, 0x03, 0x4d, 0x4c, 0x41, 0x10, 0x05, 0x12, 0x0b, 0x0a, 0x07,
+	0x43, 0x48, 0x49, 0x43, 0x41, 0x47, 0x4f, 0x10, 0x06, 0x2a, 0x4e, 0x0a, 0x0a, 0x43, 0x68, 0x61,
+	0x6e, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1b, 0x0a, 0x17, 0x43, 0x48, 0x41, 0x4e, 0x47,
+	0x45, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49,
+	0x45, 0x44, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x41, 0x44, 0x44, 0x45, 0x44, 0x10, 0x01, 0x12,
+	0x0b, 0x0a, 0x07, 0x55, 0x50, 0x44, 0x41, 0x54, 0x45, 0x44, 0x10, 0x02, 0x12, 0x0b, 0x0a, 0x07,
+	0x44, 0x45, 0x4c, 0x45, 0x54, 0x45, 0x44, 0x10, 0x03, 0x2a, 0x55, 0x0a, 0x0c, 0x45, 0x64, 0x69,
+	0x74, 0x69, 0x6f, 0x6e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x12, 0x1d, 0x0a, 0x19, 0x45, 0x44, 0x49,
+	0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x4f, 0x52, 0x44, 0x45, 0x52, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45,
+	0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x12, 0x0a, 0x0e, 0x45, 0x44, 0x49, 0x54,
+	0x49, 0x4f, 0x4e, 0x5f, 0x4e, 0x55, 0x4d, 0x42, 0x45, 0x52, 0x10, 0x01, 0x12, 0x12, 0x0a, 0x0e,
+	0x43, 0x4f, 0x50, 0x59, 0x52, 0x49, 0x47, 0x48, 0x54, 0x5f, 0x59, 0x45, 0x41, 0x52, 0x10, 0x02,
+	0x32, 0x9c, 0x0c, 0x0a, 0x08, 0x42, 0x6f, 0x6f, 0x6b, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x2b, 0x0a,
+	0x07, 0x61, 0x64, 0x64, 0x42, 0x6f, 0x6f, 0x6b, 0x12, 0x0e, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73,
+	0x61, 0x70, 0x70, 0x2e, 0x42, 0x6f, 0x6f, 0x6b, 0x1a, 0x10, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73,
+	0x61, 0x70, 0x70, 0x2e, 0x42, 0x6f, 0x6f, 0x6b, 0x49, 0x44, 0x12, 0x2b, 0x0a, 0x07, 0x67, 0x65,
+	0x74, 0x42, 0x6f, 0x6f, 0x6b, 0x12, 0x10, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70,
+	0x2e, 0x42, 0x6f, 0x6f, 0x6b, 0x49, 0x44, 0x1a, 0x0e, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61,
+	0x70, 0x70, 0x2e, 0x42, 0x6f, 0x6f, 0x6b, 0x12, 0x2c, 0x0a, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x42, 0x6f, 0x6f, 0x6b, 0x12, 0x0e, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70,
+	0x2e, 0x42, 0x6f, 0x6f, 0x6b, 0x1a, 0x0e, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70,
+	0x2e, 0x42, 0x6f, 0x6f, 0x6b, 0x12, 0x2e, 0x0a, 0x0a, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42,
+	0x6f, 0x6f, 0x6b, 0x12, 0x10, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x42,
+	0x6f, 0x6f, 0x6b, 0x49, 0x44, 0x1a, 0x0e, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70,
+	0x2e, 0x42, 0x6f, 0x6f, 0x6b, 0x12, 0x39, 0x0a, 0x09, 0x6c, 0x69, 0x73, 0x74, 0x42, 0x6f, 0x6f,
+	0x6b, 0x73, 0x12, 0x1a, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x4c, 0x69,
+	0x73, 0x74, 0x42, 0x6f, 0x6f, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e,
+	0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x42, 0x6f, 0x6f, 0x6b, 0x30, 0x01,
+	0x12, 0x3f, 0x0a, 0x0b, 0x69, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x42, 0x6f, 0x6f, 0x6b, 0x73, 0x12,
+	0x15, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x49, 0x6d, 0x70, 0x6f, 0x72,
+	0x74, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x1a, 0x17, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70,
+	0x70, 0x2e, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x28,
+	0x01, 0x12, 0x45, 0x0a, 0x0e, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x43, 0x69, 0x74, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x1f, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x46,
+	0x6f, 0x72, 0x6d, 0x61, 0x74, 0x43, 0x69, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e,
+	0x43, 0x69, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x41, 0x0a, 0x0a, 0x77, 0x61, 0x74, 0x63,
+	0x68, 0x42, 0x6f, 0x6f, 0x6b, 0x73, 0x12, 0x1b, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70,
+	0x70, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x42, 0x6f, 0x6f, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x42,
+	0x6f, 0x6f, 0x6b, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x30, 0x01, 0x12, 0x2f, 0x0a, 0x09, 0x61,
+	0x64, 0x64, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x12, 0x10, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73,
+	0x61, 0x70, 0x70, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x1a, 0x10, 0x2e, 0x62, 0x6f, 0x6f,
+	0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x12, 0x31, 0x0a, 0x09,
+	0x67, 0x65, 0x74, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x12, 0x12, 0x2e, 0x62, 0x6f, 0x6f, 0x6b,
+	0x73, 0x61, 0x70, 0x70, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x49, 0x44, 0x1a, 0x10, 0x2e,
+	0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x12,
+	0x32, 0x0a, 0x0c, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x12,
+	0x10, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x6f,
+	0x72, 0x1a, 0x10, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x41, 0x75, 0x74,
+	0x68, 0x6f, 0x72, 0x12, 0x34, 0x0a, 0x0c, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x41, 0x75, 0x74,
+	0x68, 0x6f, 0x72, 0x12, 0x12, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x41,
+	0x75, 0x74, 0x68, 0x6f, 0x72, 0x49, 0x44, 0x1a, 0x10, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61,
+	0x70, 0x70, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x12, 0x3f, 0x0a, 0x0b, 0x6c, 0x69, 0x73,
+	0x74, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x73, 0x12, 0x1c, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73,
+	0x61, 0x70, 0x70, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70,
+	0x70, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x30, 0x01, 0x12, 0x38, 0x0a, 0x0c, 0x61, 0x64,
+	0x64, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72, 0x12, 0x13, 0x2e, 0x62, 0x6f, 0x6f,
+	0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72, 0x1a,
+	0x13, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69,
+	0x73, 0x68, 0x65, 0x72, 0x12, 0x3a, 0x0a, 0x0c, 0x67, 0x65, 0x74, 0x50, 0x75, 0x62, 0x6c, 0x69,
+	0x73, 0x68, 0x65, 0x72, 0x12, 0x15, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e,
+	0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72, 0x49, 0x44, 0x1a, 0x13, 0x2e, 0x62, 0x6f,
+	0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72,
+	0x12, 0x3b, 0x0a, 0x0f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73,
+	0x68, 0x65, 0x72, 0x12, 0x13, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x50,
+	0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72, 0x1a, 0x13, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73,
+	0x61, 0x70, 0x70, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72, 0x12, 0x3d, 0x0a,
+	0x0f, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72,
+	0x12, 0x15, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x50, 0x75, 0x62, 0x6c,
+	0x69, 0x73, 0x68, 0x65, 0x72, 0x49, 0x44, 0x1a, 0x13, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61,
+	0x70, 0x70, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72, 0x12, 0x48, 0x0a, 0x0e,
+	0x6c, 0x69, 0x73, 0x74, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72, 0x73, 0x12, 0x1f,
+	0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x75,
+	0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x13, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69,
+	0x73, 0x68, 0x65, 0x72, 0x30, 0x01, 0x12, 0x49, 0x0a, 0x11, 0x6c, 0x69, 0x73, 0x74, 0x42, 0x6f,
+	0x6f, 0x6b, 0x73, 0x42, 0x79, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x12, 0x22, 0x2e, 0x62, 0x6f,
+	0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x6f, 0x6f, 0x6b, 0x73,
+	0x42, 0x79, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x0e, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x42, 0x6f, 0x6f, 0x6b, 0x30,
+	0x01, 0x12, 0x4f, 0x0a, 0x14, 0x6c, 0x69, 0x73, 0x74, 0x42, 0x6f, 0x6f, 0x6b, 0x73, 0x42, 0x79,
+	0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72, 0x12, 0x25, 0x2e, 0x62, 0x6f, 0x6f, 0x6b,
+	0x73, 0x61, 0x70, 0x70, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x6f, 0x6f, 0x6b, 0x73, 0x42, 0x79,
+	0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x0e, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x42, 0x6f, 0x6f, 0x6b,
+	0x30, 0x01, 0x12, 0x4c, 0x0a, 0x0c, 0x6c, 0x69, 0x6e, 0x6b, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x69,
+	0x65, 0x73, 0x12, 0x1d, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x4c, 0x69,
+	0x6e, 0x6b, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1d, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x4c, 0x69, 0x6e,
+	0x6b, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79,
+	0x12, 0x2b, 0x0a, 0x07, 0x67, 0x65, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x12, 0x10, 0x2e, 0x62, 0x6f,
+	0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x49, 0x44, 0x1a, 0x0e, 0x2e,
+	0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x12, 0x2c, 0x0a,
+	0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x57, 0x6f, 0x72, 0x6b, 0x12, 0x0e, 0x2e, 0x62, 0x6f,
+	0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x1a, 0x0e, 0x2e, 0x62, 0x6f,
+	0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x12, 0x39, 0x0a, 0x09, 0x6c,
+	0x69, 0x73, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x12, 0x1a, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73,
+	0x61, 0x70, 0x70, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e,
+	0x57, 0x6f, 0x72, 0x6b, 0x30, 0x01, 0x12, 0x3f, 0x0a, 0x0c, 0x6c, 0x69, 0x73, 0x74, 0x45, 0x64,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1d, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70,
+	0x70, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x45, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70,
+	0x2e, 0x42, 0x6f, 0x6f, 0x6b, 0x30, 0x01, 0x12, 0x46, 0x0a, 0x0a, 0x67, 0x72, 0x6f, 0x75, 0x70,
+	0x57, 0x6f, 0x72, 0x6b, 0x73, 0x12, 0x1b, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70,
+	0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x47, 0x72,
+	0x6f, 0x75, 0x70, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_books_info_proto_rawDescOnce sync.Once
+	file_books_info_proto_rawDescData = file_books_info_proto_rawDesc
+)
+
+func file_books_info_proto_rawDescGZIP() []byte {
+	file_books_info_proto_rawDescOnce.Do(func() {
+		file_books_info_proto_rawDescData = protoimpl.X.CompressGZIP(file_books_info_proto_rawDescData)
+	})
+	return file_books_info_proto_rawDescData
+}
+
+var file_books_info_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_books_info_proto_msgTypes = make([]protoimpl.MessageInfo, 27)
+var file_books_info_proto_goTypes = []interface{}{
+	(ContributorRole)(0),                // 0: booksapp.ContributorRole
+	(ImportFormat)(0),                   // 1: booksapp.ImportFormat
+	(CitationStyle)(0),                  // 2: booksapp.CitationStyle
+	(ChangeType)(0),                     // 3: booksapp.ChangeType
+	(EditionOrder)(0),                   // 4: booksapp.EditionOrder
+	(*Book)(nil),                        // 5: booksapp.Book
+	(*Contributor)(nil),                 // 6: booksapp.Contributor
+	(*BookID)(nil),                      // 7: booksapp.BookID
+	(*ListBooksRequest)(nil),            // 8: booksapp.ListBooksRequest
+	(*ImportChunk)(nil),                 // 9: booksapp.ImportChunk
+	(*ImportError)(nil),                 // 10: booksapp.ImportError
+	(*ImportSummary)(nil),               // 11: booksapp.ImportSummary
+	(*FormatCitationRequest)(nil),       // 12: booksapp.FormatCitationRequest
+	(*Citation)(nil),                    // 13: booksapp.Citation
+	(*WatchBooksRequest)(nil),           // 14: booksapp.WatchBooksRequest
+	(*BookChange)(nil),                  // 15: booksapp.BookChange
+	(*Author)(nil),                      // 16: booksapp.Author
+	(*AuthorID)(nil),                    // 17: booksapp.AuthorID
+	(*Publisher)(nil),                   // 18: booksapp.Publisher
+	(*PublisherID)(nil),                 // 19: booksapp.PublisherID
+	(*ListAuthorsRequest)(nil),          // 20: booksapp.ListAuthorsRequest
+	(*ListPublishersRequest)(nil),       // 21: booksapp.ListPublishersRequest
+	(*ListBooksByAuthorRequest)(nil),    // 22: booksapp.ListBooksByAuthorRequest
+	(*ListBooksByPublisherRequest)(nil), // 23: booksapp.ListBooksByPublisherRequest
+	(*LinkEntitiesRequest)(nil),         // 24: booksapp.LinkEntitiesRequest
+	(*LinkEntitiesSummary)(nil),         // 25: booksapp.LinkEntitiesSummary
+	(*Work)(nil),                        // 26: booksapp.Work
+	(*WorkID)(nil),                      // 27: booksapp.WorkID
+	(*ListWorksRequest)(nil),            // 28: booksapp.ListWorksRequest
+	(*ListEditionsRequest)(nil),         // 29: booksapp.ListEditionsRequest
+	(*GroupWorksRequest)(nil),           // 30: booksapp.GroupWorksRequest
+	(*GroupWorksSummary)(nil),           // 31: booksapp.GroupWorksSummary
+}
+var file_books_info_proto_depIdxs = []int32{
+	6,  // 0: booksapp.Book.Contributors:type_name -> booksapp.Contributor
+	0,  // 1: booksapp.Contributor.role:type_name -> booksapp.ContributorRole
+	1,  // 2: booksapp.ImportChunk.format:type_name -> booksapp.ImportFormat
+	10, // 3: booksapp.ImportSummary.errors:type_name -> booksapp.ImportError
+	2,  // 4: booksapp.FormatCitationRequest.style:type_name -> booksapp.CitationStyle
+	3,  // 5: booksapp.BookChange.type:type_name -> booksapp.ChangeType
+	5,  // 6: booksapp.BookChange.book:type_name -> booksapp.Book
+	0,  // 7: booksapp.ListBooksByAuthorRequest.role:type_name -> booksapp.ContributorRole
+	4,  // 8: booksapp.ListEditionsRequest.order:type_name -> booksapp.EditionOrder
+	5,  // 9: booksapp.BookInfo.addBook:input_type -> booksapp.Book
+	7,  // 10: booksapp.BookInfo.getBook:input_type -> booksapp.BookID
+	5,  // 11: booksapp.BookInfo.updateBook:input_type -> booksapp.Book
+	7,  // 12: booksapp.BookInfo.deleteBook:input_type -> booksapp.BookID
+	8,  // 13: booksapp.BookInfo.listBooks:input_type -> booksapp.ListBooksRequest
+	9,  // 14: booksapp.BookInfo.importBooks:input_type -> booksapp.ImportChunk
+	12, // 15: booksapp.BookInfo.formatCitation:input_type -> booksapp.FormatCitationRequest
+	14, // 16: booksapp.BookInfo.watchBooks:input_type -> booksapp.WatchBooksRequest
+	16, // 17: booksapp.BookInfo.addAuthor:input_type -> booksapp.Author
+	17, // 18: booksapp.BookInfo.getAuthor:input_type -> booksapp.AuthorID
+	16, // 19: booksapp.BookInfo.updateAuthor:input_type -> booksapp.Author
+	17, // 20: booksapp.BookInfo.deleteAuthor:input_type -> booksapp.AuthorID
+	20, // 21: booksapp.BookInfo.listAuthors:input_type -> booksapp.ListAuthorsRequest
+	18, // 22: booksapp.BookInfo.addPublisher:input_type -> booksapp.Publisher
+	19, // 23: booksapp.BookInfo.getPublisher:input_type -> booksapp.PublisherID
+	18, // 24: booksapp.BookInfo.updatePublisher:input_type -> booksapp.Publisher
+	19, // 25: booksapp.BookInfo.deletePublisher:input_type -> booksapp.PublisherID
+	21, // 26: booksapp.BookInfo.listPublishers:input_type -> booksapp.ListPublishersRequest
+	22, // 27: booksapp.BookInfo.listBooksByAuthor:input_type -> booksapp.ListBooksByAuthorRequest
+	23, // 28: booksapp.BookInfo.listBooksByPublisher:input_type -> booksapp.ListBooksByPublisherRequest
+	24, // 29: booksapp.BookInfo.linkEntities:input_type -> booksapp.LinkEntitiesRequest
+	27, // 30: booksapp.BookInfo.getWork:input_type -> booksapp.WorkID
+	26, // 31: booksapp.BookInfo.updateWork:input_type -> booksapp.Work
+	28, // 32: booksapp.BookInfo.listWorks:input_type -> booksapp.ListWorksRequest
+	29, // 33: booksapp.BookInfo.listEditions:input_type -> booksapp.ListEditionsRequest
+	30, // 34: booksapp.BookInfo.groupWorks:input_type -> booksapp.GroupWorksRequest
+	7,  // 35: booksapp.BookInfo.addBook:output_type -> booksapp.BookID
+	5,  // 36: booksapp.BookInfo.getBook:output_type -> booksapp.Book
+	5,  // 37: booksapp.BookInfo.updateBook:output_type -> booksapp.Book
+	5,  // 38: booksapp.BookInfo.deleteBook:output_type -> booksapp.Book
+	5,  // 39: booksapp.BookInfo.listBooks:output_type -> booksapp.Book
+	11, // 40: booksapp.BookInfo.importBooks:output_type -> booksapp.ImportSummary
+	13, // 41: booksapp.BookInfo.formatCitation:output_type -> booksapp.Citation
+	15, // 42: booksapp.BookInfo.watchBooks:output_type -> booksapp.BookChange
+	16, // 43: booksapp.BookInfo.addAuthor:output_type -> booksapp.Author
+	16, // 44: booksapp.BookInfo.getAuthor:output_type -> booksapp.Author
+	16, // 45: booksapp.BookInfo.updateAuthor:output_type -> booksapp.Author
+	16, // 46: booksapp.BookInfo.deleteAuthor:output_type -> booksapp.Author
+	16, // 47: booksapp.BookInfo.listAuthors:output_type -> booksapp.Author
+	18, // 48: booksapp.BookInfo.addPublisher:output_type -> booksapp.Publisher
+	18, // 49: booksapp.BookInfo.getPublisher:output_type -> booksapp.Publisher
+	18, // 50: booksapp.BookInfo.updatePublisher:output_type -> booksapp.Publisher
+	18, // 51: booksapp.BookInfo.deletePublisher:output_type -> booksapp.Publisher
+	18, // 52: booksapp.BookInfo.listPublishers:output_type -> booksapp.Publisher
+	5,  // 53: booksapp.BookInfo.listBooksByAuthor:output_type -> booksapp.Book
+	5,  // 54: booksapp.BookInfo.listBooksByPublisher:output_type -> booksapp.Book
+	25, // 55: booksapp.BookInfo.linkEntities:output_type -> booksapp.LinkEntitiesSummary
+	26, // 56: booksapp.BookInfo.getWork:output_type -> booksapp.Work
+	26, // 57: booksapp.BookInfo.updateWork:output_type -> booksapp.Work
+	26, // 58: booksapp.BookInfo.listWorks:output_type -> booksapp.Work
+	5,  // 59: booksapp.BookInfo.listEditions:output_type -> booksapp.Book
+	31, // 60: booksapp.BookInfo.groupWorks:output_type -> booksapp.GroupWorksSummary
+	35, // [35:61] is the sub-list for method output_type
+	9,  // [9:35] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_books_info_proto_init() }
+func file_books_info_proto_init() {
+	if File_books_info_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_books_info_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Book); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_books_info_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Contributor); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_books_info_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BookID); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_books_info_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListBooksRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_books_info_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ImportChunk); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_books_info_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ImportError); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_books_info_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ImportSummary); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_books_info_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FormatCitationRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_books_info_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Citation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_books_info_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchBooksRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_books_info_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BookChange); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_books_info_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Author); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_books_info_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AuthorID); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_books_info_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Publisher); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_books_info_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PublisherID); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_books_info_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListAuthorsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_books_info_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListPublishersRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_books_info_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListBooksByAuthorRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_books_info_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListBooksByPublisherRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_books_info_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LinkEntitiesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_books_info_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LinkEntitiesSummary); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_books_info_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Work); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_books_info_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WorkID); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_books_info_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListWorksRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_books_info_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListEditionsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_books_info_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GroupWorksRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_books_info_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GroupWorksSummary); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_books_info_proto_rawDesc,
+			NumEnums:      5,
+			NumMessages:   27,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_books_info_proto_goTypes,
 		DependencyIndexes: file_books_info_proto_depIdxs,
+		EnumInfos:         file_books_info_proto_enumTypes,
 		MessageInfos:      file_books_info_proto_msgTypes,
 	}.Build()
 	File_books_info_proto = out.File
@@ -298,165 +2618,1142 @@ func file_books_info_proto_init() {
 	file_books_info_proto_depIdxs = nil
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConnInterface
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// BookInfoClient is the client API for BookInfo service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type BookInfoClient interface {
+	AddBook(ctx context.Context, in *Book, opts ...grpc.CallOption) (*BookID, error)
+	GetBook(ctx context.Context, in *BookID, opts ...grpc.CallOption) (*Book, error)
+	UpdateBook(ctx context.Context, in *Book, opts ...grpc.CallOption) (*Book, error)
+	DeleteBook(ctx context.Context, in *BookID, opts ...grpc.CallOption) (*Book, error)
+	ListBooks(ctx context.Context, in *ListBooksRequest, opts ...grpc.CallOption) (BookInfo_ListBooksClient, error)
+	// importBooks adds the books of a bibliographic file sent in chunks.
+	// The first chunk names the format.
+	ImportBooks(ctx context.Context, opts ...grpc.CallOption) (BookInfo_ImportBooksClient, error)
+	FormatCitation(ctx context.Context, in *FormatCitationRequest, opts ...grpc.CallOption) (*Citation, error)
+	// watchBooks streams every change made to the books from the time of
+	// the call. The server ends the stream of a watcher that falls behind.
+	WatchBooks(ctx context.Context, in *WatchBooksRequest, opts ...grpc.CallOption) (BookInfo_WatchBooksClient, error)
+	// Authors and publishers are referenced by the books' contributors
+	// and publisher_id. They cannot be deleted while referenced.
+	AddAuthor(ctx context.Context, in *Author, opts ...grpc.CallOption) (*Author, error)
+	GetAuthor(ctx context.Context, in *AuthorID, opts ...grpc.CallOption) (*Author, error)
+	UpdateAuthor(ctx context.Context, in *Author, opts ...grpc.CallOption) (*Author, error)
+	DeleteAuthor(ctx context.Context, in *AuthorID, opts ...grpc.CallOption) (*Author, error)
+	ListAuthors(ctx context.Context, in *ListAuthorsRequest, opts ...grpc.CallOption) (BookInfo_ListAuthorsClient, error)
+	AddPublisher(ctx context.Context, in *Publisher, opts ...grpc.CallOption) (*Publisher, error)
+	GetPublisher(ctx context.Context, in *PublisherID, opts ...grpc.CallOption) (*Publisher, error)
+	UpdatePublisher(ctx context.Context, in *Publisher, opts ...grpc.CallOption) (*Publisher, error)
+	DeletePublisher(ctx context.Context, in *PublisherID, opts ...grpc.CallOption) (*Publisher, error)
+	ListPublishers(ctx context.Context, in *ListPublishersRequest, opts ...grpc.CallOption) (BookInfo_ListPublishersClient, error)
+	// listBooksByAuthor and listBooksByPublisher stream the books
+	// referencing an author or publisher, in ID order.
+	ListBooksByAuthor(ctx context.Context, in *ListBooksByAuthorRequest, opts ...grpc.CallOption) (BookInfo_ListBooksByAuthorClient, error)
+	ListBooksByPublisher(ctx context.Context, in *ListBooksByPublisherRequest, opts ...grpc.CallOption) (BookInfo_ListBooksByPublisherClient, error)
+	// linkEntities links the books that only have Author and Publisher
+	// text to authors and publishers, creating those not found by name.
+	LinkEntities(ctx context.Context, in *LinkEntitiesRequest, opts ...grpc.CallOption) (*LinkEntitiesSummary, error)
+	// Works group the editions and translations of a title. Books added or
+	// updated without a WorkId join the work with the same normalized title
+	// and first author, which is created if there is none. A work is
+	// deleted with its last book.
+	GetWork(ctx context.Context, in *WorkID, opts ...grpc.CallOption) (*Work, error)
+	UpdateWork(ctx context.Context, in *Work, opts ...grpc.CallOption) (*Work, error)
+	ListWorks(ctx context.Context, in *ListWorksRequest, opts ...grpc.CallOption) (BookInfo_ListWorksClient, error)
+	ListEditions(ctx context.Context, in *ListEditionsRequest, opts ...grpc.CallOption) (BookInfo_ListEditionsClient, error)
+	// groupWorks puts the books without a WorkId, such as those stored
+	// before works existed, into works.
+	GroupWorks(ctx context.Context, in *GroupWorksRequest, opts ...grpc.CallOption) (*GroupWorksSummary, error)
+}
+
+type bookInfoClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBookInfoClient(cc grpc.ClientConnInterface) BookInfoClient {
+	return &bookInfoClient{cc}
+}
+
+func (c *bookInfoClient) AddBook(ctx context.Context, in *Book, opts ...grpc.CallOption) (*BookID, error) {
+	out := new(BookID)
+	err := c.cc.Invoke(ctx, "/booksapp.BookInfo/addBook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookInfoClient) GetBook(ctx context.Context, in *BookID, opts ...grpc.CallOption) (*Book, error) {
+	out := new(Book)
+	err := c.cc.Invoke(ctx, "/booksapp.BookInfo/getBook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookInfoClient) UpdateBook(ctx context.Context, in *Book, opts ...grpc.CallOption) (*Book, error) {
+	out := new(Book)
+	err := c.cc.Invoke(ctx, "/booksapp.BookInfo/updateBook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookInfoClient) DeleteBook(ctx context.Context, in *BookID, opts ...grpc.CallOption) (*Book, error) {
+	out := new(Book)
+	err := c.cc.Invoke(ctx, "/booksapp.BookInfo/deleteBook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookInfoClient) ListBooks(ctx context.Context, in *ListBooksRequest, opts ...grpc.CallOption) (BookInfo_ListBooksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BookInfo_serviceDesc.Streams[0], "/booksapp.BookInfo/listBooks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bookInfoListBooksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BookInfo_ListBooksClient interface {
+	Recv() (*Book, error)
+	grpc.ClientStream
+}
+
+type bookInfoListBooksClient struct {
+	grpc.ClientStream
+}
+
+func (x *bookInfoListBooksClient) Recv() (*Book, error) {
+	m := new(Book)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *bookInfoClient) ImportBooks(ctx context.Context, opts ...grpc.CallOption) (BookInfo_ImportBooksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BookInfo_serviceDesc.Streams[1], "/booksapp.BookInfo/importBooks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bookInfoImportBooksClient{stream}
+	return x, nil
+}
+
+type BookInfo_ImportBooksClient interface {
+	Send(*ImportChunk) error
+	CloseAndRecv() (*ImportSummary, error)
+	grpc.ClientStream
+}
+
+type bookInfoImportBooksClient struct {
+	grpc.ClientStream
+}
+
+func (x *bookInfoImportBooksClient) Send(m *ImportChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *bookInfoImportBooksClient) CloseAndRecv() (*ImportSummary, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ImportSummary)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *bookInfoClient) FormatCitation(ctx context.Context, in *FormatCitationRequest, opts ...grpc.CallOption) (*Citation, error) {
+	out := new(Citation)
+	err := c.cc.Invoke(ctx, "/booksapp.BookInfo/formatCitation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookInfoClient) WatchBooks(ctx context.Context, in *WatchBooksRequest, opts ...grpc.CallOption) (BookInfo_WatchBooksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BookInfo_serviceDesc.Streams[2], "/booksapp.BookInfo/watchBooks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bookInfoWatchBooksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BookInfo_WatchBooksClient interface {
+	Recv() (*BookChange, error)
+	grpc.ClientStream
+}
+
+type bookInfoWatchBooksClient struct {
+	grpc.ClientStream
+}
+
+func (x *bookInfoWatchBooksClient) Recv() (*BookChange, error) {
+	m := new(BookChange)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *bookInfoClient) AddAuthor(ctx context.Context, in *Author, opts ...grpc.CallOption) (*Author, error) {
+	out := new(Author)
+	err := c.cc.Invoke(ctx, "/booksapp.BookInfo/addAuthor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookInfoClient) GetAuthor(ctx context.Context, in *AuthorID, opts ...grpc.CallOption) (*Author, error) {
+	out := new(Author)
+	err := c.cc.Invoke(ctx, "/booksapp.BookInfo/getAuthor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookInfoClient) UpdateAuthor(ctx context.Context, in *Author, opts ...grpc.CallOption) (*Author, error) {
+	out := new(Author)
+	err := c.cc.Invoke(ctx, "/booksapp.BookInfo/updateAuthor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookInfoClient) DeleteAuthor(ctx context.Context, in *AuthorID, opts ...grpc.CallOption) (*Author, error) {
+	out := new(Author)
+	err := c.cc.Invoke(ctx, "/booksapp.BookInfo/deleteAuthor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookInfoClient) ListAuthors(ctx context.Context, in *ListAuthorsRequest, opts ...grpc.CallOption) (BookInfo_ListAuthorsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BookInfo_serviceDesc.Streams[3], "/booksapp.BookInfo/listAuthors", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bookInfoListAuthorsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BookInfo_ListAuthorsClient interface {
+	Recv() (*Author, error)
+	grpc.ClientStream
+}
+
+type bookInfoListAuthorsClient struct {
+	grpc.ClientStream
+}
+
+func (x *bookInfoListAuthorsClient) Recv() (*Author, error) {
+	m := new(Author)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *bookInfoClient) AddPublisher(ctx context.Context, in *Publisher, opts ...grpc.CallOption) (*Publisher, error) {
+	out := new(Publisher)
+	err := c.cc.Invoke(ctx, "/booksapp.BookInfo/addPublisher", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookInfoClient) GetPublisher(ctx context.Context, in *PublisherID, opts ...grpc.CallOption) (*Publisher, error) {
+	out := new(Publisher)
+	err := c.cc.Invoke(ctx, "/booksapp.BookInfo/getPublisher", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookInfoClient) UpdatePublisher(ctx context.Context, in *Publisher, opts ...grpc.CallOption) (*Publisher, error) {
+	out := new(Publisher)
+	err := c.cc.Invoke(ctx, "/booksapp.BookInfo/updatePublisher", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookInfoClient) DeletePublisher(ctx context.Context, in *PublisherID, opts ...grpc.CallOption) (*Publisher, error) {
+	out := new(Publisher)
+	err := c.cc.Invoke(ctx, "/booksapp.BookInfo/deletePublisher", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookInfoClient) ListPublishers(ctx context.Context, in *ListPublishersRequest, opts ...grpc.CallOption) (BookInfo_ListPublishersClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BookInfo_serviceDesc.Streams[4], "/booksapp.BookInfo/listPublishers", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bookInfoListPublishersClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BookInfo_ListPublishersClient interface {
+	Recv() (*Publisher, error)
+	grpc.ClientStream
+}
+
+type bookInfoListPublishersClient struct {
+	grpc.ClientStream
+}
+
+func (x *bookInfoListPublishersClient) Recv() (*Publisher, error) {
+	m := new(Publisher)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *bookInfoClient) ListBooksByAuthor(ctx context.Context, in *ListBooksByAuthorRequest, opts ...grpc.CallOption) (BookInfo_ListBooksByAuthorClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BookInfo_serviceDesc.Streams[5], "/booksapp.BookInfo/listBooksByAuthor", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bookInfoListBooksByAuthorClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BookInfo_ListBooksByAuthorClient interface {
+	Recv() (*Book, error)
+	grpc.ClientStream
+}
+
+type bookInfoListBooksByAuthorClient struct {
+	grpc.ClientStream
+}
+
+func (x *bookInfoListBooksByAuthorClient) Recv() (*Book, error) {
+	m := new(Book)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *bookInfoClient) ListBooksByPublisher(ctx context.Context, in *ListBooksByPublisherRequest, opts ...grpc.CallOption) (BookInfo_ListBooksByPublisherClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BookInfo_serviceDesc.Streams[6], "/booksapp.BookInfo/listBooksByPublisher", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bookInfoListBooksByPublisherClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BookInfo_ListBooksByPublisherClient interface {
+	Recv() (*Book, error)
+	grpc.ClientStream
+}
+
+type bookInfoListBooksByPublisherClient struct {
+	grpc.ClientStream
+}
+
+func (x *bookInfoListBooksByPublisherClient) Recv() (*Book, error) {
+	m := new(Book)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *bookInfoClient) LinkEntities(ctx context.Context, in *LinkEntitiesRequest, opts ...grpc.CallOption) (*LinkEntitiesSummary, error) {
+	out := new(LinkEntitiesSummary)
+	err := c.cc.Invoke(ctx, "/booksapp.BookInfo/linkEntities", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookInfoClient) GetWork(ctx context.Context, in *WorkID, opts ...grpc.CallOption) (*Work, error) {
+	out := new(Work)
+	err := c.cc.Invoke(ctx, "/booksapp.BookInfo/getWork", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookInfoClient) UpdateWork(ctx context.Context, in *Work, opts ...grpc.CallOption) (*Work, error) {
+	out := new(Work)
+	err := c.cc.Invoke(ctx, "/booksapp.BookInfo/updateWork", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookInfoClient) ListWorks(ctx context.Context, in *ListWorksRequest, opts ...grpc.CallOption) (BookInfo_ListWorksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BookInfo_serviceDesc.Streams[7], "/booksapp.BookInfo/listWorks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bookInfoListWorksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BookInfo_ListWorksClient interface {
+	Recv() (*Work, error)
+	grpc.ClientStream
+}
+
+type bookInfoListWorksClient struct {
+	grpc.ClientStream
+}
+
+func (x *bookInfoListWorksClient) Recv() (*Work, error) {
+	m := new(Work)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *bookInfoClient) ListEditions(ctx context.Context, in *ListEditionsRequest, opts ...grpc.CallOption) (BookInfo_ListEditionsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BookInfo_serviceDesc.Streams[8], "/booksapp.BookInfo/listEditions", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bookInfoListEditionsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BookInfo_ListEditionsClient interface {
+	Recv() (*Book, error)
+	grpc.ClientStream
+}
+
+type bookInfoListEditionsClient struct {
+	grpc.ClientStream
+}
+
+func (x *bookInfoListEditionsClient) Recv() (*Book, error) {
+	m := new(Book)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *bookInfoClient) GroupWorks(ctx context.Context, in *GroupWorksRequest, opts ...grpc.CallOption) (*GroupWorksSummary, error) {
+	out := new(GroupWorksSummary)
+	err := c.cc.Invoke(ctx, "/booksapp.BookInfo/groupWorks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BookInfoServer is the server API for BookInfo service.
+type BookInfoServer interface {
+	AddBook(context.Context, *Book) (*BookID, error)
+	GetBook(context.Context, *BookID) (*Book, error)
+	UpdateBook(context.Context, *Book) (*Book, error)
+	DeleteBook(context.Context, *BookID) (*Book, error)
+	ListBooks(*ListBooksRequest, BookInfo_ListBooksServer) error
+	// importBooks adds the books of a bibliographic file sent in chunks.
+	// The first chunk names the format.
+	ImportBooks(BookInfo_ImportBooksServer) error
+	FormatCitation(context.Context, *FormatCitationRequest) (*Citation, error)
+	// watchBooks streams every change made to the books from the time of
+	// the call. The server ends the stream of a watcher that falls behind.
+	WatchBooks(*WatchBooksRequest, BookInfo_WatchBooksServer) error
+	// Authors and publishers are referenced by the books' contributors
+	// and publisher_id. They cannot be deleted while referenced.
+	AddAuthor(context.Context, *Author) (*Author, error)
+	GetAuthor(context.Context, *AuthorID) (*Author, error)
+	UpdateAuthor(context.Context, *Author) (*Author, error)
+	DeleteAuthor(context.Context, *AuthorID) (*Author, error)
+	ListAuthors(*ListAuthorsRequest, BookInfo_ListAuthorsServer) error
+	AddPublisher(context.Context, *Publisher) (*Publisher, error)
+	GetPublisher(context.Context, *PublisherID) (*Publisher, error)
+	UpdatePublisher(context.Context, *Publisher) (*Publisher, error)
+	DeletePublisher(context.Context, *PublisherID) (*Publisher, error)
+	ListPublishers(*ListPublishersRequest, BookInfo_ListPublishersServer) error
+	// listBooksByAuthor and listBooksByPublisher stream the books
+	// referencing an author or publisher, in ID order.
+	ListBooksByAuthor(*ListBooksByAuthorRequest, BookInfo_ListBooksByAuthorServer) error
+	ListBooksByPublisher(*ListBooksByPublisherRequest, BookInfo_ListBooksByPublisherServer) error
+	// linkEntities links the books that only have Author and Publisher
+	// text to authors and publishers, creating those not found by name.
+	LinkEntities(context.Context, *LinkEntitiesRequest) (*LinkEntitiesSummary, error)
+	// Works group the editions and translations of a title. Books added or
+	// updated without a WorkId join the work with the same normalized title
+	// and first author, which is created if there is none. A work is
+	// deleted with its last book.
+	GetWork(context.Context, *WorkID) (*Work, error)
+	UpdateWork(context.Context, *Work) (*Work, error)
+	ListWorks(*ListWorksRequest, BookInfo_ListWorksServer) error
+	ListEditions(*ListEditionsRequest, BookInfo_ListEditionsServer) error
+	// groupWorks puts the books without a WorkId, such as those stored
+	// before works existed, into works.
+	GroupWorks(context.Context, *GroupWorksRequest) (*GroupWorksSummary, error)
+}
+
+// UnimplementedBookInfoServer can be embedded to have forward compatible implementations.
+type UnimplementedBookInfoServer struct {
+}
+
+func (*UnimplementedBookInfoServer) AddBook(context.Context, *Book) (*BookID, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddBook not implemented")
+}
+func (*UnimplementedBookInfoServer) GetBook(context.Context, *BookID) (*Book, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBook not implemented")
+}
+func (*UnimplementedBookInfoServer) UpdateBook(context.Context, *Book) (*Book, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateBook not implemented")
+}
+func (*UnimplementedBookInfoServer) DeleteBook(context.Context, *BookID) (*Book, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteBook not implemented")
+}
+func (*UnimplementedBookInfoServer) ListBooks(*ListBooksRequest, BookInfo_ListBooksServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListBooks not implemented")
+}
+func (*UnimplementedBookInfoServer) ImportBooks(BookInfo_ImportBooksServer) error {
+	return status.Errorf(codes.Unimplemented, "method ImportBooks not implemented")
+}
+func (*UnimplementedBookInfoServer) FormatCitation(context.Context, *FormatCitationRequest) (*Citation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FormatCitation not implemented")
+}
+func (*UnimplementedBookInfoServer) WatchBooks(*WatchBooksRequest, BookInfo_WatchBooksServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchBooks not implemented")
+}
+func (*UnimplementedBookInfoServer) AddAuthor(context.Context, *Author) (*Author, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddAuthor not implemented")
+}
+func (*UnimplementedBookInfoServer) GetAuthor(context.Context, *AuthorID) (*Author, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAuthor not implemented")
+}
+func (*UnimplementedBookInfoServer) UpdateAuthor(context.Context, *Author) (*Author, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateAuthor not implemented")
+}
+func (*UnimplementedBookInfoServer) DeleteAuthor(context.Context, *AuthorID) (*Author, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAuthor not implemented")
+}
+func (*UnimplementedBookInfoServer) ListAuthors(*ListAuthorsRequest, BookInfo_ListAuthorsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListAuthors not implemented")
+}
+func (*UnimplementedBookInfoServer) AddPublisher(context.Context, *Publisher) (*Publisher, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddPublisher not implemented")
+}
+func (*UnimplementedBookInfoServer) GetPublisher(context.Context, *PublisherID) (*Publisher, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPublisher not implemented")
+}
+func (*UnimplementedBookInfoServer) UpdatePublisher(context.Context, *Publisher) (*Publisher, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdatePublisher not implemented")
+}
+func (*UnimplementedBookInfoServer) DeletePublisher(context.Context, *PublisherID) (*Publisher, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeletePublisher not implemented")
+}
+func (*UnimplementedBookInfoServer) ListPublishers(*ListPublishersRequest, BookInfo_ListPublishersServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListPublishers not implemented")
+}
+func (*UnimplementedBookInfoServer) ListBooksByAuthor(*ListBooksByAuthorRequest, BookInfo_ListBooksByAuthorServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListBooksByAuthor not implemented")
+}
+func (*UnimplementedBookInfoServer) ListBooksByPublisher(*ListBooksByPublisherRequest, BookInfo_ListBooksByPublisherServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListBooksByPublisher not implemented")
+}
+func (*UnimplementedBookInfoServer) LinkEntities(context.Context, *LinkEntitiesRequest) (*LinkEntitiesSummary, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LinkEntities not implemented")
+}
+func (*UnimplementedBookInfoServer) GetWork(context.Context, *WorkID) (*Work, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWork not implemented")
+}
+func (*UnimplementedBookInfoServer) UpdateWork(context.Context, *Work) (*Work, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateWork not implemented")
+}
+func (*UnimplementedBookInfoServer) ListWorks(*ListWorksRequest, BookInfo_ListWorksServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListWorks not implemented")
+}
+func (*UnimplementedBookInfoServer) ListEditions(*ListEditionsRequest, BookInfo_ListEditionsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListEditions not implemented")
+}
+func (*UnimplementedBookInfoServer) GroupWorks(context.Context, *GroupWorksRequest) (*GroupWorksSummary, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GroupWorks not implemented")
+}
+
+func RegisterBookInfoServer(s *grpc.Server, srv BookInfoServer) {
+	s.RegisterService(&_BookInfo_serviceDesc, srv)
+}
+
+func _BookInfo_AddBook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Book)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookInfoServer).AddBook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/booksapp.BookInfo/AddBook",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookInfoServer).AddBook(ctx, req.(*Book))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookInfo_GetBook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BookID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookInfoServer).GetBook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/booksapp.BookInfo/GetBook",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookInfoServer).GetBook(ctx, req.(*BookID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookInfo_UpdateBook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Book)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookInfoServer).UpdateBook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/booksapp.BookInfo/UpdateBook",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookInfoServer).UpdateBook(ctx, req.(*Book))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookInfo_DeleteBook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BookID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookInfoServer).DeleteBook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/booksapp.BookInfo/DeleteBook",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookInfoServer).DeleteBook(ctx, req.(*BookID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookInfo_ListBooks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListBooksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BookInfoServer).ListBooks(m, &bookInfoListBooksServer{stream})
+}
+
+type BookInfo_ListBooksServer interface {
+	Send(*Book) error
+	grpc.ServerStream
+}
+
+type bookInfoListBooksServer struct {
+	grpc.ServerStream
+}
+
+func (x *bookInfoListBooksServer) Send(m *Book) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BookInfo_ImportBooks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(BookInfoServer).ImportBooks(&bookInfoImportBooksServer{stream})
+}
+
+type BookInfo_ImportBooksServer interface {
+	SendAndClose(*ImportSummary) error
+	Recv() (*ImportChunk, error)
+	grpc.ServerStream
+}
+
+type bookInfoImportBooksServer struct {
+	grpc.ServerStream
+}
+
+func (x *bookInfoImportBooksServer) SendAndClose(m *ImportSummary) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *bookInfoImportBooksServer) Recv() (*ImportChunk, error) {
+	m := new(ImportChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _BookInfo_FormatCitation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FormatCitationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookInfoServer).FormatCitation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/booksapp.BookInfo/FormatCitation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookInfoServer).FormatCitation(ctx, req.(*FormatCitationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookInfo_WatchBooks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchBooksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BookInfoServer).WatchBooks(m, &bookInfoWatchBooksServer{stream})
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion6
+type BookInfo_WatchBooksServer interface {
+	Send(*BookChange) error
+	grpc.ServerStream
+}
 
-// BookInfoClient is the client API for BookInfo service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type BookInfoClient interface {
-	AddBook(ctx context.Context, in *Book, opts ...grpc.CallOption) (*BookID, error)
-	GetBook(ctx context.Context, in *BookID, opts ...grpc.CallOption) (*Book, error)
-	UpdateBook(ctx context.Context, in *Book, opts ...grpc.CallOption) (*Book, error)
-	DeleteBook(ctx context.Context, in *BookID, opts ...grpc.CallOption) (*Book, error)
+type bookInfoWatchBooksServer struct {
+	grpc.ServerStream
 }
 
-type bookInfoClient struct {
-	cc grpc.ClientConnInterface
+func (x *bookInfoWatchBooksServer) Send(m *BookChange) error {
+	return x.ServerStream.SendMsg(m)
 }
 
-func NewBookInfoClient(cc grpc.ClientConnInterface) BookInfoClient {
-	return &bookInfoClient{cc}
+func _BookInfo_AddAuthor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Author)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookInfoServer).AddAuthor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/booksapp.BookInfo/AddAuthor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookInfoServer).AddAuthor(ctx, req.(*Author))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *bookInfoClient) AddBook(ctx context.Context, in *Book, opts ...grpc.CallOption) (*BookID, error) {
-	out := new(BookID)
-	err := c.cc.Invoke(ctx, "/booksapp.BookInfo/addBook", in, out, opts...)
-	if err != nil {
+func _BookInfo_GetAuthor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthorID)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(BookInfoServer).GetAuthor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/booksapp.BookInfo/GetAuthor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookInfoServer).GetAuthor(ctx, req.(*AuthorID))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *bookInfoClient) GetBook(ctx context.Context, in *BookID, opts ...grpc.CallOption) (*Book, error) {
-	out := new(Book)
-	err := c.cc.Invoke(ctx, "/booksapp.BookInfo/getBook", in, out, opts...)
-	if err != nil {
+func _BookInfo_UpdateAuthor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Author)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(BookInfoServer).UpdateAuthor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/booksapp.BookInfo/UpdateAuthor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookInfoServer).UpdateAuthor(ctx, req.(*Author))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *bookInfoClient) UpdateBook(ctx context.Context, in *Book, opts ...grpc.CallOption) (*Book, error) {
-	out := new(Book)
-	err := c.cc.Invoke(ctx, "/booksapp.BookInfo/updateBook", in, out, opts...)
-	if err != nil {
+func _BookInfo_DeleteAuthor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthorID)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(BookInfoServer).DeleteAuthor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/booksapp.BookInfo/DeleteAuthor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookInfoServer).DeleteAuthor(ctx, req.(*AuthorID))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *bookInfoClient) DeleteBook(ctx context.Context, in *BookID, opts ...grpc.CallOption) (*Book, error) {
-	out := new(Book)
-	err := c.cc.Invoke(ctx, "/booksapp.BookInfo/deleteBook", in, out, opts...)
-	if err != nil {
+func _BookInfo_ListAuthors_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListAuthorsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BookInfoServer).ListAuthors(m, &bookInfoListAuthorsServer{stream})
+}
+
+type BookInfo_ListAuthorsServer interface {
+	Send(*Author) error
+	grpc.ServerStream
+}
+
+type bookInfoListAuthorsServer struct {
+	grpc.ServerStream
+}
+
+func (x *bookInfoListAuthorsServer) Send(m *Author) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BookInfo_AddPublisher_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Publisher)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(BookInfoServer).AddPublisher(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/booksapp.BookInfo/AddPublisher",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookInfoServer).AddPublisher(ctx, req.(*Publisher))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// BookInfoServer is the server API for BookInfo service.
-type BookInfoServer interface {
-	AddBook(context.Context, *Book) (*BookID, error)
-	GetBook(context.Context, *BookID) (*Book, error)
-	UpdateBook(context.Context, *Book) (*Book, error)
-	DeleteBook(context.Context, *BookID) (*Book, error)
+func _BookInfo_GetPublisher_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublisherID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookInfoServer).GetPublisher(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/booksapp.BookInfo/GetPublisher",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookInfoServer).GetPublisher(ctx, req.(*PublisherID))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// UnimplementedBookInfoServer can be embedded to have forward compatible implementations.
-type UnimplementedBookInfoServer struct {
+func _BookInfo_UpdatePublisher_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Publisher)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookInfoServer).UpdatePublisher(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/booksapp.BookInfo/UpdatePublisher",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookInfoServer).UpdatePublisher(ctx, req.(*Publisher))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (*UnimplementedBookInfoServer) AddBook(context.Context, *Book) (*BookID, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method AddBook not implemented")
+func _BookInfo_DeletePublisher_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublisherID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookInfoServer).DeletePublisher(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/booksapp.BookInfo/DeletePublisher",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookInfoServer).DeletePublisher(ctx, req.(*PublisherID))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (*UnimplementedBookInfoServer) GetBook(context.Context, *BookID) (*Book, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetBook not implemented")
+
+func _BookInfo_ListPublishers_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListPublishersRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BookInfoServer).ListPublishers(m, &bookInfoListPublishersServer{stream})
 }
-func (*UnimplementedBookInfoServer) UpdateBook(context.Context, *Book) (*Book, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method UpdateBook not implemented")
+
+type BookInfo_ListPublishersServer interface {
+	Send(*Publisher) error
+	grpc.ServerStream
 }
-func (*UnimplementedBookInfoServer) DeleteBook(context.Context, *BookID) (*Book, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DeleteBook not implemented")
+
+type bookInfoListPublishersServer struct {
+	grpc.ServerStream
 }
 
-func RegisterBookInfoServer(s *grpc.Server, srv BookInfoServer) {
-	s.RegisterService(&_BookInfo_serviceDesc, srv)
+func (x *bookInfoListPublishersServer) Send(m *Publisher) error {
+	return x.ServerStream.SendMsg(m)
 }
 
-func _BookInfo_AddBook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Book)
+func _BookInfo_ListBooksByAuthor_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListBooksByAuthorRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BookInfoServer).ListBooksByAuthor(m, &bookInfoListBooksByAuthorServer{stream})
+}
+
+type BookInfo_ListBooksByAuthorServer interface {
+	Send(*Book) error
+	grpc.ServerStream
+}
+
+type bookInfoListBooksByAuthorServer struct {
+	grpc.ServerStream
+}
+
+func (x *bookInfoListBooksByAuthorServer) Send(m *Book) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BookInfo_ListBooksByPublisher_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListBooksByPublisherRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BookInfoServer).ListBooksByPublisher(m, &bookInfoListBooksByPublisherServer{stream})
+}
+
+type BookInfo_ListBooksByPublisherServer interface {
+	Send(*Book) error
+	grpc.ServerStream
+}
+
+type bookInfoListBooksByPublisherServer struct {
+	grpc.ServerStream
+}
+
+func (x *bookInfoListBooksByPublisherServer) Send(m *Book) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BookInfo_LinkEntities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LinkEntitiesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BookInfoServer).AddBook(ctx, in)
+		return srv.(BookInfoServer).LinkEntities(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/booksapp.BookInfo/AddBook",
+		FullMethod: "/booksapp.BookInfo/LinkEntities",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BookInfoServer).AddBook(ctx, req.(*Book))
+		return srv.(BookInfoServer).LinkEntities(ctx, req.(*LinkEntitiesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _BookInfo_GetBook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(BookID)
+func _BookInfo_GetWork_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorkID)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BookInfoServer).GetBook(ctx, in)
+		return srv.(BookInfoServer).GetWork(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/booksapp.BookInfo/GetBook",
+		FullMethod: "/booksapp.BookInfo/GetWork",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BookInfoServer).GetBook(ctx, req.(*BookID))
+		return srv.(BookInfoServer).GetWork(ctx, req.(*WorkID))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _BookInfo_UpdateBook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Book)
+func _BookInfo_UpdateWork_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Work)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BookInfoServer).UpdateBook(ctx, in)
+		return srv.(BookInfoServer).UpdateWork(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/booksapp.BookInfo/UpdateBook",
+		FullMethod: "/booksapp.BookInfo/UpdateWork",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BookInfoServer).UpdateBook(ctx, req.(*Book))
+		return srv.(BookInfoServer).UpdateWork(ctx, req.(*Work))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _BookInfo_DeleteBook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(BookID)
+func _BookInfo_ListWorks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListWorksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BookInfoServer).ListWorks(m, &bookInfoListWorksServer{stream})
+}
+
+type BookInfo_ListWorksServer interface {
+	Send(*Work) error
+	grpc.ServerStream
+}
+
+type bookInfoListWorksServer struct {
+	grpc.ServerStream
+}
+
+func (x *bookInfoListWorksServer) Send(m *Work) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BookInfo_ListEditions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListEditionsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BookInfoServer).ListEditions(m, &bookInfoListEditionsServer{stream})
+}
+
+type BookInfo_ListEditionsServer interface {
+	Send(*Book) error
+	grpc.ServerStream
+}
+
+type bookInfoListEditionsServer struct {
+	grpc.ServerStream
+}
+
+func (x *bookInfoListEditionsServer) Send(m *Book) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BookInfo_GroupWorks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GroupWorksRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BookInfoServer).DeleteBook(ctx, in)
+		return srv.(BookInfoServer).GroupWorks(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/booksapp.BookInfo/DeleteBook",
+		FullMethod: "/booksapp.BookInfo/GroupWorks",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BookInfoServer).DeleteBook(ctx, req.(*BookID))
+		return srv.(BookInfoServer).GroupWorks(ctx, req.(*GroupWorksRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -481,7 +3778,105 @@ var _BookInfo_serviceDesc = grpc.ServiceDesc{
 			MethodName: "deleteBook",
 			Handler:    _BookInfo_DeleteBook_Handler,
 		},
+		{
+			MethodName: "formatCitation",
+			Handler:    _BookInfo_FormatCitation_Handler,
+		},
+		{
+			MethodName: "addAuthor",
+			Handler:    _BookInfo_AddAuthor_Handler,
+		},
+		{
+			MethodName: "getAuthor",
+			Handler:    _BookInfo_GetAuthor_Handler,
+		},
+		{
+			MethodName: "updateAuthor",
+			Handler:    _BookInfo_UpdateAuthor_Handler,
+		},
+		{
+			MethodName: "deleteAuthor",
+			Handler:    _BookInfo_DeleteAuthor_Handler,
+		},
+		{
+			MethodName: "addPublisher",
+			Handler:    _BookInfo_AddPublisher_Handler,
+		},
+		{
+			MethodName: "getPublisher",
+			Handler:    _BookInfo_GetPublisher_Handler,
+		},
+		{
+			MethodName: "updatePublisher",
+			Handler:    _BookInfo_UpdatePublisher_Handler,
+		},
+		{
+			MethodName: "deletePublisher",
+			Handler:    _BookInfo_DeletePublisher_Handler,
+		},
+		{
+			MethodName: "linkEntities",
+			Handler:    _BookInfo_LinkEntities_Handler,
+		},
+		{
+			MethodName: "getWork",
+			Handler:    _BookInfo_GetWork_Handler,
+		},
+		{
+			MethodName: "updateWork",
+			Handler:    _BookInfo_UpdateWork_Handler,
+		},
+		{
+			MethodName: "groupWorks",
+			Handler:    _BookInfo_GroupWorks_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "listBooks",
+			Handler:       _BookInfo_ListBooks_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "importBooks",
+			Handler:       _BookInfo_ImportBooks_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "watchBooks",
+			Handler:       _BookInfo_WatchBooks_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "listAuthors",
+			Handler:       _BookInfo_ListAuthors_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "listPublishers",
+			Handler:       _BookInfo_ListPublishers_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "listBooksByAuthor",
+			Handler:       _BookInfo_ListBooksByAuthor_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "listBooksByPublisher",
+			Handler:       _BookInfo_ListBooksByPublisher_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "listWorks",
+			Handler:       _BookInfo_ListWorks_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "listEditions",
+			Handler:       _BookInfo_ListEditions_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "books_info.proto",
 }
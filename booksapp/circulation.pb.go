@@ -0,0 +1,2790 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.25.0
+// 	protoc        (unknown)
+// source: circulation.proto
+
+package booksapp
+
+import (
+	context "context"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+type CopyStatus int32
+
+const (
+	CopyStatus_COPY_STATUS_UNSPECIFIED CopyStatus = 0
+	CopyStatus_ON_SHELF                CopyStatus = 1
+	CopyStatus_ON_LOAN                 CopyStatus = 2
+	// ON_HOLD_SHELF copies are kept for the patron of a ready hold.
+	CopyStatus_ON_HOLD_SHELF CopyStatus = 3
+)
+
+// Enum value maps for CopyStatus.
+var (
+	CopyStatus_name = map[int32]string{
+		0: "COPY_STATUS_UNSPECIFIED",
+		1: "ON_SHELF",
+		2: "ON_LOAN",
+		3: "ON_HOLD_SHELF",
+	}
+	CopyStatus_value = map[string]int32{
+		"COPY_STATUS_UNSPECIFIED": 0,
+		"ON_SHELF":                1,
+		"ON_LOAN":                 2,
+		"ON_HOLD_SHELF":           3,
+	}
+)
+
+func (x CopyStatus) Enum() *CopyStatus {
+	p := new(CopyStatus)
+	*p = x
+	return p
+}
+
+func (x CopyStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CopyStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_circulation_proto_enumTypes[0].Descriptor()
+}
+
+func (CopyStatus) Type() protoreflect.EnumType {
+	return &file_circulation_proto_enumTypes[0]
+}
+
+func (x CopyStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CopyStatus.Descriptor instead.
+func (CopyStatus) EnumDescriptor() ([]byte, []int) {
+	return file_circulation_proto_rawDescGZIP(), []int{0}
+}
+
+type HoldStatus int32
+
+const (
+	HoldStatus_HOLD_STATUS_UNSPECIFIED HoldStatus = 0
+	// WAITING holds are queued for a copy.
+	HoldStatus_WAITING HoldStatus = 1
+	// READY holds have a copy on the hold shelf for the patron.
+	HoldStatus_READY HoldStatus = 2
+)
+
+// Enum value maps for HoldStatus.
+var (
+	HoldStatus_name = map[int32]string{
+		0: "HOLD_STATUS_UNSPECIFIED",
+		1: "WAITING",
+		2: "READY",
+	}
+	HoldStatus_value = map[string]int32{
+		"HOLD_STATUS_UNSPECIFIED": 0,
+		"WAITING":                 1,
+		"READY":                   2,
+	}
+)
+
+func (x HoldStatus) Enum() *HoldStatus {
+	p := new(HoldStatus)
+	*p = x
+	return p
+}
+
+func (x HoldStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (HoldStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_circulation_proto_enumTypes[1].Descriptor()
+}
+
+func (HoldStatus) Type() protoreflect.EnumType {
+	return &file_circulation_proto_enumTypes[1]
+}
+
+func (x HoldStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use HoldStatus.Descriptor instead.
+func (HoldStatus) EnumDescriptor() ([]byte, []int) {
+	return file_circulation_proto_rawDescGZIP(), []int{1}
+}
+
+type Copy struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Barcode  string `protobuf:"bytes,1,opt,name=barcode,proto3" json:"barcode,omitempty"`
+	BookId   string `protobuf:"bytes,2,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	Location string `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"`
+	// status, loan_id and hold_id are kept by the server.
+	Status CopyStatus `protobuf:"varint,4,opt,name=status,proto3,enum=booksapp.CopyStatus" json:"status,omitempty"`
+	LoanId string     `protobuf:"bytes,5,opt,name=loan_id,json=loanId,proto3" json:"loan_id,omitempty"`
+	HoldId string     `protobuf:"bytes,6,opt,name=hold_id,json=holdId,proto3" json:"hold_id,omitempty"`
+}
+
+func (x *Copy) Reset() {
+	*x = Copy{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_circulation_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Copy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Copy) ProtoMessage() {}
+
+func (x *Copy) ProtoReflect() protoreflect.Message {
+	mi := &file_circulation_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Copy.ProtoReflect.Descriptor instead.
+func (*Copy) Descriptor() ([]byte, []int) {
+	return file_circulation_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Copy) GetBarcode() string {
+	if x != nil {
+		return x.Barcode
+	}
+	return ""
+}
+
+func (x *Copy) GetBookId() string {
+	if x != nil {
+		return x.BookId
+	}
+	return ""
+}
+
+func (x *Copy) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *Copy) GetStatus() CopyStatus {
+	if x != nil {
+		return x.Status
+	}
+	return CopyStatus_COPY_STATUS_UNSPECIFIED
+}
+
+func (x *Copy) GetLoanId() string {
+	if x != nil {
+		return x.LoanId
+	}
+	return ""
+}
+
+func (x *Copy) GetHoldId() string {
+	if x != nil {
+		return x.HoldId
+	}
+	return ""
+}
+
+type Barcode struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Value string `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *Barcode) Reset() {
+	*x = Barcode{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_circulation_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Barcode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Barcode) ProtoMessage() {}
+
+func (x *Barcode) ProtoReflect() protoreflect.Message {
+	mi := &file_circulation_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Barcode.ProtoReflect.Descriptor instead.
+func (*Barcode) Descriptor() ([]byte, []int) {
+	return file_circulation_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Barcode) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type ListCopiesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Only the copies of book_id are listed, if set.
+	BookId string `protobuf:"bytes,1,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+}
+
+func (x *ListCopiesRequest) Reset() {
+	*x = ListCopiesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_circulation_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListCopiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCopiesRequest) ProtoMessage() {}
+
+func (x *ListCopiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_circulation_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCopiesRequest.ProtoReflect.Descriptor instead.
+func (*ListCopiesRequest) Descriptor() ([]byte, []int) {
+	return file_circulation_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListCopiesRequest) GetBookId() string {
+	if x != nil {
+		return x.BookId
+	}
+	return ""
+}
+
+type Patron struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id    string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name  string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Email string `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+}
+
+func (x *Patron) Reset() {
+	*x = Patron{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_circulation_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Patron) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Patron) ProtoMessage() {}
+
+func (x *Patron) ProtoReflect() protoreflect.Message {
+	mi := &file_circulation_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Patron.ProtoReflect.Descriptor instead.
+func (*Patron) Descriptor() ([]byte, []int) {
+	return file_circulation_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Patron) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Patron) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Patron) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+type PatronID struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Value string `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *PatronID) Reset() {
+	*x = PatronID{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_circulation_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PatronID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatronID) ProtoMessage() {}
+
+func (x *PatronID) ProtoReflect() protoreflect.Message {
+	mi := &file_circulation_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatronID.ProtoReflect.Descriptor instead.
+func (*PatronID) Descriptor() ([]byte, []int) {
+	return file_circulation_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PatronID) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type ListPatronsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Only patrons whose name contains query, ignoring case, are listed.
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+}
+
+func (x *ListPatronsRequest) Reset() {
+	*x = ListPatronsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_circulation_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListPatronsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPatronsRequest) ProtoMessage() {}
+
+func (x *ListPatronsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_circulation_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPatronsRequest.ProtoReflect.Descriptor instead.
+func (*ListPatronsRequest) Descriptor() ([]byte, []int) {
+	return file_circulation_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListPatronsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+type CheckoutRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Barcode  string `protobuf:"bytes,1,opt,name=barcode,proto3" json:"barcode,omitempty"`
+	PatronId string `protobuf:"bytes,2,opt,name=patron_id,json=patronId,proto3" json:"patron_id,omitempty"`
+}
+
+func (x *CheckoutRequest) Reset() {
+	*x = CheckoutRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_circulation_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CheckoutRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckoutRequest) ProtoMessage() {}
+
+func (x *CheckoutRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_circulation_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckoutRequest.ProtoReflect.Descriptor instead.
+func (*CheckoutRequest) Descriptor() ([]byte, []int) {
+	return file_circulation_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CheckoutRequest) GetBarcode() string {
+	if x != nil {
+		return x.Barcode
+	}
+	return ""
+}
+
+func (x *CheckoutRequest) GetPatronId() string {
+	if x != nil {
+		return x.PatronId
+	}
+	return ""
+}
+
+type Loan struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id         string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Barcode    string                 `protobuf:"bytes,2,opt,name=barcode,proto3" json:"barcode,omitempty"`
+	BookId     string                 `protobuf:"bytes,3,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	PatronId   string                 `protobuf:"bytes,4,opt,name=patron_id,json=patronId,proto3" json:"patron_id,omitempty"`
+	CheckedOut *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=checked_out,json=checkedOut,proto3" json:"checked_out,omitempty"`
+	Due        *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=due,proto3" json:"due,omitempty"`
+	Renewals   int32                  `protobuf:"varint,7,opt,name=renewals,proto3" json:"renewals,omitempty"`
+	// returned is unset while the copy is on loan.
+	Returned *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=returned,proto3" json:"returned,omitempty"`
+}
+
+func (x *Loan) Reset() {
+	*x = Loan{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_circulation_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Loan) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Loan) ProtoMessage() {}
+
+func (x *Loan) ProtoReflect() protoreflect.Message {
+	mi := &file_circulation_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Loan.ProtoReflect.Descriptor instead.
+func (*Loan) Descriptor() ([]byte, []int) {
+	return file_circulation_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Loan) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Loan) GetBarcode() string {
+	if x != nil {
+		return x.Barcode
+	}
+	return ""
+}
+
+func (x *Loan) GetBookId() string {
+	if x != nil {
+		return x.BookId
+	}
+	return ""
+}
+
+func (x *Loan) GetPatronId() string {
+	if x != nil {
+		return x.PatronId
+	}
+	return ""
+}
+
+func (x *Loan) GetCheckedOut() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CheckedOut
+	}
+	return nil
+}
+
+func (x *Loan) GetDue() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Due
+	}
+	return nil
+}
+
+func (x *Loan) GetRenewals() int32 {
+	if x != nil {
+		return x.Renewals
+	}
+	return 0
+}
+
+func (x *Loan) GetReturned() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Returned
+	}
+	return nil
+}
+
+type LoanID struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Value string `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *LoanID) Reset() {
+	*x = LoanID{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_circulation_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LoanID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoanID) ProtoMessage() {}
+
+func (x *LoanID) ProtoReflect() protoreflect.Message {
+	mi := &file_circulation_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoanID.ProtoReflect.Descriptor instead.
+func (*LoanID) Descriptor() ([]byte, []int) {
+	return file_circulation_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *LoanID) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type ReturnResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Loan *Loan `protobuf:"bytes,1,opt,name=loan,proto3" json:"loan,omitempty"`
+	// hold is the hold the copy is now kept for, if any.
+	Hold *Hold `protobuf:"bytes,2,opt,name=hold,proto3" json:"hold,omitempty"`
+}
+
+func (x *ReturnResponse) Reset() {
+	*x = ReturnResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_circulation_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReturnResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReturnResponse) ProtoMessage() {}
+
+func (x *ReturnResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_circulation_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReturnResponse.ProtoReflect.Descriptor instead.
+func (*ReturnResponse) Descriptor() ([]byte, []int) {
+	return file_circulation_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ReturnResponse) GetLoan() *Loan {
+	if x != nil {
+		return x.Loan
+	}
+	return nil
+}
+
+func (x *ReturnResponse) GetHold() *Hold {
+	if x != nil {
+		return x.Hold
+	}
+	return nil
+}
+
+type ListLoansRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Only the loans of patron_id are listed, if set.
+	PatronId string `protobuf:"bytes,1,opt,name=patron_id,json=patronId,proto3" json:"patron_id,omitempty"`
+	// include_returned lists ended loans too.
+	IncludeReturned bool `protobuf:"varint,2,opt,name=include_returned,json=includeReturned,proto3" json:"include_returned,omitempty"`
+}
+
+func (x *ListLoansRequest) Reset() {
+	*x = ListLoansRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_circulation_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListLoansRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLoansRequest) ProtoMessage() {}
+
+func (x *ListLoansRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_circulation_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLoansRequest.ProtoReflect.Descriptor instead.
+func (*ListLoansRequest) Descriptor() ([]byte, []int) {
+	return file_circulation_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListLoansRequest) GetPatronId() string {
+	if x != nil {
+		return x.PatronId
+	}
+	return ""
+}
+
+func (x *ListLoansRequest) GetIncludeReturned() bool {
+	if x != nil {
+		return x.IncludeReturned
+	}
+	return false
+}
+
+type ListOverdueRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListOverdueRequest) Reset() {
+	*x = ListOverdueRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_circulation_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListOverdueRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOverdueRequest) ProtoMessage() {}
+
+func (x *ListOverdueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_circulation_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOverdueRequest.ProtoReflect.Descriptor instead.
+func (*ListOverdueRequest) Descriptor() ([]byte, []int) {
+	return file_circulation_proto_rawDescGZIP(), []int{11}
+}
+
+type Hold struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id       string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	BookId   string                 `protobuf:"bytes,2,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	PatronId string                 `protobuf:"bytes,3,opt,name=patron_id,json=patronId,proto3" json:"patron_id,omitempty"`
+	Placed   *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=placed,proto3" json:"placed,omitempty"`
+	Status   HoldStatus             `protobuf:"varint,5,opt,name=status,proto3,enum=booksapp.HoldStatus" json:"status,omitempty"`
+	// barcode, ready and expires are set once a copy is kept for the
+	// hold. Unless the patron borrows it by expires, the hold is removed
+	// and the copy goes to the next patron.
+	Barcode string                 `protobuf:"bytes,6,opt,name=barcode,proto3" json:"barcode,omitempty"`
+	Ready   *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=ready,proto3" json:"ready,omitempty"`
+	Expires *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=expires,proto3" json:"expires,omitempty"`
+}
+
+func (x *Hold) Reset() {
+	*x = Hold{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_circulation_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Hold) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Hold) ProtoMessage() {}
+
+func (x *Hold) ProtoReflect() protoreflect.Message {
+	mi := &file_circulation_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Hold.ProtoReflect.Descriptor instead.
+func (*Hold) Descriptor() ([]byte, []int) {
+	return file_circulation_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Hold) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Hold) GetBookId() string {
+	if x != nil {
+		return x.BookId
+	}
+	return ""
+}
+
+func (x *Hold) GetPatronId() string {
+	if x != nil {
+		return x.PatronId
+	}
+	return ""
+}
+
+func (x *Hold) GetPlaced() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Placed
+	}
+	return nil
+}
+
+func (x *Hold) GetStatus() HoldStatus {
+	if x != nil {
+		return x.Status
+	}
+	return HoldStatus_HOLD_STATUS_UNSPECIFIED
+}
+
+func (x *Hold) GetBarcode() string {
+	if x != nil {
+		return x.Barcode
+	}
+	return ""
+}
+
+func (x *Hold) GetReady() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Ready
+	}
+	return nil
+}
+
+func (x *Hold) GetExpires() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Expires
+	}
+	return nil
+}
+
+type HoldID struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Value string `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *HoldID) Reset() {
+	*x = HoldID{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_circulation_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HoldID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HoldID) ProtoMessage() {}
+
+func (x *HoldID) ProtoReflect() protoreflect.Message {
+	mi := &file_circulation_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HoldID.ProtoReflect.Descriptor instead.
+func (*HoldID) Descriptor() ([]byte, []int) {
+	return file_circulation_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *HoldID) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type PlaceHoldRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BookId   string `protobuf:"bytes,1,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	PatronId string `protobuf:"bytes,2,opt,name=patron_id,json=patronId,proto3" json:"patron_id,omitempty"`
+}
+
+func (x *PlaceHoldRequest) Reset() {
+	*x = PlaceHoldRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_circulation_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PlaceHoldRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlaceHoldRequest) ProtoMessage() {}
+
+func (x *PlaceHoldRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_circulation_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlaceHoldRequest.ProtoReflect.Descriptor instead.
+func (*PlaceHoldRequest) Descriptor() ([]byte, []int) {
+	return file_circulation_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *PlaceHoldRequest) GetBookId() string {
+	if x != nil {
+		return x.BookId
+	}
+	return ""
+}
+
+func (x *PlaceHoldRequest) GetPatronId() string {
+	if x != nil {
+		return x.PatronId
+	}
+	return ""
+}
+
+type ListHoldsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Only the holds on book_id, or of patron_id, are listed, if set.
+	BookId   string `protobuf:"bytes,1,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	PatronId string `protobuf:"bytes,2,opt,name=patron_id,json=patronId,proto3" json:"patron_id,omitempty"`
+}
+
+func (x *ListHoldsRequest) Reset() {
+	*x = ListHoldsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_circulation_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListHoldsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListHoldsRequest) ProtoMessage() {}
+
+func (x *ListHoldsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_circulation_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListHoldsRequest.ProtoReflect.Descriptor instead.
+func (*ListHoldsRequest) Descriptor() ([]byte, []int) {
+	return file_circulation_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListHoldsRequest) GetBookId() string {
+	if x != nil {
+		return x.BookId
+	}
+	return ""
+}
+
+func (x *ListHoldsRequest) GetPatronId() string {
+	if x != nil {
+		return x.PatronId
+	}
+	return ""
+}
+
+// DeadLetter is a webhook event that could not be delivered.
+type DeadLetter struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// id is the event's delivery ID.
+	Id        string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	EventType string `protobuf:"bytes,2,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	// body is the JSON document that was POSTed.
+	Body     []byte                 `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+	Attempts int32                  `protobuf:"varint,4,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	Error    string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	Failed   *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=failed,proto3" json:"failed,omitempty"`
+}
+
+func (x *DeadLetter) Reset() {
+	*x = DeadLetter{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_circulation_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeadLetter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeadLetter) ProtoMessage() {}
+
+func (x *DeadLetter) ProtoReflect() protoreflect.Message {
+	mi := &file_circulation_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeadLetter.ProtoReflect.Descriptor instead.
+func (*DeadLetter) Descriptor() ([]byte, []int) {
+	return file_circulation_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DeadLetter) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetBody() []byte {
+	if x != nil {
+		return x.Body
+	}
+	return nil
+}
+
+func (x *DeadLetter) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *DeadLetter) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetFailed() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Failed
+	}
+	return nil
+}
+
+type DeadLetterID struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Value string `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *DeadLetterID) Reset() {
+	*x = DeadLetterID{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_circulation_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeadLetterID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeadLetterID) ProtoMessage() {}
+
+func (x *DeadLetterID) ProtoReflect() protoreflect.Message {
+	mi := &file_circulation_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeadLetterID.ProtoReflect.Descriptor instead.
+func (*DeadLetterID) Descriptor() ([]byte, []int) {
+	return file_circulation_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *DeadLetterID) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type ListDeadLettersRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListDeadLettersRequest) Reset() {
+	*x = ListDeadLettersRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_circulation_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListDeadLettersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeadLettersRequest) ProtoMessage() {}
+
+func (x *ListDeadLettersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_circulation_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeadLettersRequest.ProtoReflect.Descriptor instead.
+func (*ListDeadLettersRequest) Descriptor() ([]byte, []int) {
+	return file_circulation_proto_rawDescGZIP(), []int{18}
+}
+
+var File_circulation_proto protoreflect.FileDescriptor
+
+var file_circulation_proto_rawDesc = []byte{
+	0x0a, 0x11, 0x63, 0x69, 0x72, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x12, 0x08, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x1a, 0x1f, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xb5,
+	0x01, 0x0a, 0x04, 0x43, 0x6f, 0x70, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x62, 0x61, 0x72, 0x63, 0x6f,
+	0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x61, 0x72, 0x63, 0x6f, 0x64,
+	0x65, 0x12, 0x17, 0x0a, 0x07, 0x62, 0x6f, 0x6f, 0x6b, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x62, 0x6f, 0x6f, 0x6b, 0x49, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x6f,
+	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x6f,
+	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2c, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70,
+	0x70, 0x2e, 0x43, 0x6f, 0x70, 0x79, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x12, 0x17, 0x0a, 0x07, 0x6c, 0x6f, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6c, 0x6f, 0x61, 0x6e, 0x49, 0x64, 0x12, 0x17, 0x0a,
+	0x07, 0x68, 0x6f, 0x6c, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x68, 0x6f, 0x6c, 0x64, 0x49, 0x64, 0x22, 0x1f, 0x0a, 0x07, 0x42, 0x61, 0x72, 0x63, 0x6f, 0x64,
+	0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x2c, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x43,
+	0x6f, 0x70, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07,
+	0x62, 0x6f, 0x6f, 0x6b, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x62,
+	0x6f, 0x6f, 0x6b, 0x49, 0x64, 0x22, 0x42, 0x0a, 0x06, 0x50, 0x61, 0x74, 0x72, 0x6f, 0x6e, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x22, 0x20, 0x0a, 0x08, 0x50, 0x61, 0x74,
+	0x72, 0x6f, 0x6e, 0x49, 0x44, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x2a, 0x0a, 0x12, 0x4c,
+	0x69, 0x73, 0x74, 0x50, 0x61, 0x74, 0x72, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x14, 0x0a, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x22, 0x48, 0x0a, 0x0f, 0x43, 0x68, 0x65, 0x63, 0x6b,
+	0x6f, 0x75, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x62, 0x61,
+	0x72, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x61, 0x72,
+	0x63, 0x6f, 0x64, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x74, 0x72, 0x6f, 0x6e, 0x5f, 0x69,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x74, 0x72, 0x6f, 0x6e, 0x49,
+	0x64, 0x22, 0xa5, 0x02, 0x0a, 0x04, 0x4c, 0x6f, 0x61, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x62, 0x61,
+	0x72, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x61, 0x72,
+	0x63, 0x6f, 0x64, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x62, 0x6f, 0x6f, 0x6b, 0x5f, 0x69, 0x64, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x62, 0x6f, 0x6f, 0x6b, 0x49, 0x64, 0x12, 0x1b, 0x0a,
+	0x09, 0x70, 0x61, 0x74, 0x72, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x70, 0x61, 0x74, 0x72, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x3b, 0x0a, 0x0b, 0x63, 0x68,
+	0x65, 0x63, 0x6b, 0x65, 0x64, 0x5f, 0x6f, 0x75, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a, 0x63, 0x68, 0x65,
+	0x63, 0x6b, 0x65, 0x64, 0x4f, 0x75, 0x74, 0x12, 0x2c, 0x0a, 0x03, 0x64, 0x75, 0x65, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x03, 0x64, 0x75, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x6e, 0x65, 0x77, 0x61, 0x6c,
+	0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x72, 0x65, 0x6e, 0x65, 0x77, 0x61, 0x6c,
+	0x73, 0x12, 0x36, 0x0a, 0x08, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x65, 0x64, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x08, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x65, 0x64, 0x22, 0x1e, 0x0a, 0x06, 0x4c, 0x6f, 0x61,
+	0x6e, 0x49, 0x44, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x58, 0x0a, 0x0e, 0x52, 0x65, 0x74,
+	0x75, 0x72, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x22, 0x0a, 0x04, 0x6c,
+	0x6f, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x62, 0x6f, 0x6f, 0x6b,
+	0x73, 0x61, 0x70, 0x70, 0x2e, 0x4c, 0x6f, 0x61, 0x6e, 0x52, 0x04, 0x6c, 0x6f, 0x61, 0x6e, 0x12,
+	0x22, 0x0a, 0x04, 0x68, 0x6f, 0x6c, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e,
+	0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x48, 0x6f, 0x6c, 0x64, 0x52, 0x04, 0x68,
+	0x6f, 0x6c, 0x64, 0x22, 0x5a, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x4c, 0x6f, 0x61, 0x6e, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x74, 0x72, 0x6f,
+	0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x74, 0x72,
+	0x6f, 0x6e, 0x49, 0x64, 0x12, 0x29, 0x0a, 0x10, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f,
+	0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f,
+	0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x52, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x65, 0x64, 0x22,
+	0x14, 0x0a, 0x12, 0x4c, 0x69, 0x73, 0x74, 0x4f, 0x76, 0x65, 0x72, 0x64, 0x75, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xb0, 0x02, 0x0a, 0x04, 0x48, 0x6f, 0x6c, 0x64, 0x12, 0x0e,
+	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x17,
+	0x0a, 0x07, 0x62, 0x6f, 0x6f, 0x6b, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x62, 0x6f, 0x6f, 0x6b, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x74, 0x72, 0x6f,
+	0x6e, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x74, 0x72,
+	0x6f, 0x6e, 0x49, 0x64, 0x12, 0x32, 0x0a, 0x06, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x64, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x06, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x64, 0x12, 0x2c, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73,
+	0x61, 0x70, 0x70, 0x2e, 0x48, 0x6f, 0x6c, 0x64, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x62, 0x61, 0x72, 0x63, 0x6f, 0x64,
+	0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x61, 0x72, 0x63, 0x6f, 0x64, 0x65,
+	0x12, 0x30, 0x0a, 0x05, 0x72, 0x65, 0x61, 0x64, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x05, 0x72, 0x65, 0x61,
+	0x64, 0x79, 0x12, 0x34, 0x0a, 0x07, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x07, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x22, 0x1e, 0x0a, 0x06, 0x48, 0x6f, 0x6c, 0x64,
+	0x49, 0x44, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x48, 0x0a, 0x10, 0x50, 0x6c, 0x61, 0x63,
+	0x65, 0x48, 0x6f, 0x6c, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07,
+	0x62, 0x6f, 0x6f, 0x6b, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x62,
+	0x6f, 0x6f, 0x6b, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x74, 0x72, 0x6f, 0x6e, 0x5f,
+	0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x74, 0x72, 0x6f, 0x6e,
+	0x49, 0x64, 0x22, 0x48, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x48, 0x6f, 0x6c, 0x64, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x62, 0x6f, 0x6f, 0x6b, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x62, 0x6f, 0x6f, 0x6b, 0x49, 0x64, 0x12,
+	0x1b, 0x0a, 0x09, 0x70, 0x61, 0x74, 0x72, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x74, 0x72, 0x6f, 0x6e, 0x49, 0x64, 0x22, 0xb5, 0x01, 0x0a,
+	0x0a, 0x44, 0x65, 0x61, 0x64, 0x4c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x65,
+	0x76, 0x65, 0x6e, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x6f,
+	0x64, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x12, 0x1a,
+	0x0a, 0x08, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x08, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x12, 0x32, 0x0a, 0x06, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x06, 0x66, 0x61,
+	0x69, 0x6c, 0x65, 0x64, 0x22, 0x24, 0x0a, 0x0c, 0x44, 0x65, 0x61, 0x64, 0x4c, 0x65, 0x74, 0x74,
+	0x65, 0x72, 0x49, 0x44, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x18, 0x0a, 0x16, 0x4c, 0x69,
+	0x73, 0x74, 0x44, 0x65, 0x61, 0x64, 0x4c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x2a, 0x57, 0x0a, 0x0a, 0x43, 0x6f, 0x70, 0x79, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x12, 0x1b, 0x0a, 0x17, 0x43, 0x4f, 0x50, 0x59, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55,
+	0x53, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12,
+	0x0c, 0x0a, 0x08, 0x4f, 0x4e, 0x5f, 0x53, 0x48, 0x45, 0x4c, 0x46, 0x10, 0x01, 0x12, 0x0b, 0x0a,
+	0x07, 0x4f, 0x4e, 0x5f, 0x4c, 0x4f, 0x41, 0x4e, 0x10, 0x02, 0x12, 0x11, 0x0a, 0x0d, 0x4f, 0x4e,
+	0x5f, 0x48, 0x4f, 0x4c, 0x44, 0x5f, 0x53, 0x48, 0x45, 0x4c, 0x46, 0x10, 0x03, 0x2a, 0x41, 0x0a,
+	0x0a, 0x48, 0x6f, 0x6c, 0x64, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1b, 0x0a, 0x17, 0x48,
+	0x4f, 0x4c, 0x44, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45,
+	0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x57, 0x41, 0x49, 0x54,
+	0x49, 0x4e, 0x47, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x52, 0x45, 0x41, 0x44, 0x59, 0x10, 0x02,
+	0x32, 0xd4, 0x08, 0x0a, 0x0b, 0x43, 0x69, 0x72, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x29, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x43, 0x6f, 0x70, 0x79, 0x12, 0x0e, 0x2e, 0x62, 0x6f,
+	0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x43, 0x6f, 0x70, 0x79, 0x1a, 0x0e, 0x2e, 0x62, 0x6f,
+	0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x43, 0x6f, 0x70, 0x79, 0x12, 0x2c, 0x0a, 0x07, 0x67,
+	0x65, 0x74, 0x43, 0x6f, 0x70, 0x79, 0x12, 0x11, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70,
+	0x70, 0x2e, 0x42, 0x61, 0x72, 0x63, 0x6f, 0x64, 0x65, 0x1a, 0x0e, 0x2e, 0x62, 0x6f, 0x6f, 0x6b,
+	0x73, 0x61, 0x70, 0x70, 0x2e, 0x43, 0x6f, 0x70, 0x79, 0x12, 0x2c, 0x0a, 0x0a, 0x75, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x43, 0x6f, 0x70, 0x79, 0x12, 0x0e, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61,
+	0x70, 0x70, 0x2e, 0x43, 0x6f, 0x70, 0x79, 0x1a, 0x0e, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61,
+	0x70, 0x70, 0x2e, 0x43, 0x6f, 0x70, 0x79, 0x12, 0x2f, 0x0a, 0x0a, 0x64, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x43, 0x6f, 0x70, 0x79, 0x12, 0x11, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70,
+	0x2e, 0x42, 0x61, 0x72, 0x63, 0x6f, 0x64, 0x65, 0x1a, 0x0e, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73,
+	0x61, 0x70, 0x70, 0x2e, 0x43, 0x6f, 0x70, 0x79, 0x12, 0x3b, 0x0a, 0x0a, 0x6c, 0x69, 0x73, 0x74,
+	0x43, 0x6f, 0x70, 0x69, 0x65, 0x73, 0x12, 0x1b, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70,
+	0x70, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x6f, 0x70, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x43,
+	0x6f, 0x70, 0x79, 0x30, 0x01, 0x12, 0x2f, 0x0a, 0x09, 0x61, 0x64, 0x64, 0x50, 0x61, 0x74, 0x72,
+	0x6f, 0x6e, 0x12, 0x10, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x50, 0x61,
+	0x74, 0x72, 0x6f, 0x6e, 0x1a, 0x10, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e,
+	0x50, 0x61, 0x74, 0x72, 0x6f, 0x6e, 0x12, 0x31, 0x0a, 0x09, 0x67, 0x65, 0x74, 0x50, 0x61, 0x74,
+	0x72, 0x6f, 0x6e, 0x12, 0x12, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x50,
+	0x61, 0x74, 0x72, 0x6f, 0x6e, 0x49, 0x44, 0x1a, 0x10, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61,
+	0x70, 0x70, 0x2e, 0x50, 0x61, 0x74, 0x72, 0x6f, 0x6e, 0x12, 0x32, 0x0a, 0x0c, 0x75, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x50, 0x61, 0x74, 0x72, 0x6f, 0x6e, 0x12, 0x10, 0x2e, 0x62, 0x6f, 0x6f, 0x6b,
+	0x73, 0x61, 0x70, 0x70, 0x2e, 0x50, 0x61, 0x74, 0x72, 0x6f, 0x6e, 0x1a, 0x10, 0x2e, 0x62, 0x6f,
+	0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x50, 0x61, 0x74, 0x72, 0x6f, 0x6e, 0x12, 0x34, 0x0a,
+	0x0c, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x50, 0x61, 0x74, 0x72, 0x6f, 0x6e, 0x12, 0x12, 0x2e,
+	0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x50, 0x61, 0x74, 0x72, 0x6f, 0x6e, 0x49,
+	0x44, 0x1a, 0x10, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x50, 0x61, 0x74,
+	0x72, 0x6f, 0x6e, 0x12, 0x3f, 0x0a, 0x0b, 0x6c, 0x69, 0x73, 0x74, 0x50, 0x61, 0x74, 0x72, 0x6f,
+	0x6e, 0x73, 0x12, 0x1c, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x4c, 0x69,
+	0x73, 0x74, 0x50, 0x61, 0x74, 0x72, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x10, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x50, 0x61, 0x74, 0x72,
+	0x6f, 0x6e, 0x30, 0x01, 0x12, 0x35, 0x0a, 0x08, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x6f, 0x75, 0x74,
+	0x12, 0x19, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x43, 0x68, 0x65, 0x63,
+	0x6b, 0x6f, 0x75, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x62, 0x6f,
+	0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x4c, 0x6f, 0x61, 0x6e, 0x12, 0x39, 0x0a, 0x0a, 0x72,
+	0x65, 0x74, 0x75, 0x72, 0x6e, 0x43, 0x6f, 0x70, 0x79, 0x12, 0x11, 0x2e, 0x62, 0x6f, 0x6f, 0x6b,
+	0x73, 0x61, 0x70, 0x70, 0x2e, 0x42, 0x61, 0x72, 0x63, 0x6f, 0x64, 0x65, 0x1a, 0x18, 0x2e, 0x62,
+	0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x52, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x29, 0x0a, 0x05, 0x72, 0x65, 0x6e, 0x65, 0x77, 0x12,
+	0x10, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x4c, 0x6f, 0x61, 0x6e, 0x49,
+	0x44, 0x1a, 0x0e, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x4c, 0x6f, 0x61,
+	0x6e, 0x12, 0x39, 0x0a, 0x09, 0x6c, 0x69, 0x73, 0x74, 0x4c, 0x6f, 0x61, 0x6e, 0x73, 0x12, 0x1a,
+	0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4c, 0x6f,
+	0x61, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x62, 0x6f, 0x6f,
+	0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x4c, 0x6f, 0x61, 0x6e, 0x30, 0x01, 0x12, 0x3d, 0x0a, 0x0b,
+	0x6c, 0x69, 0x73, 0x74, 0x4f, 0x76, 0x65, 0x72, 0x64, 0x75, 0x65, 0x12, 0x1c, 0x2e, 0x62, 0x6f,
+	0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4f, 0x76, 0x65, 0x72, 0x64,
+	0x75, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x62, 0x6f, 0x6f, 0x6b,
+	0x73, 0x61, 0x70, 0x70, 0x2e, 0x4c, 0x6f, 0x61, 0x6e, 0x30, 0x01, 0x12, 0x37, 0x0a, 0x09, 0x70,
+	0x6c, 0x61, 0x63, 0x65, 0x48, 0x6f, 0x6c, 0x64, 0x12, 0x1a, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73,
+	0x61, 0x70, 0x70, 0x2e, 0x50, 0x6c, 0x61, 0x63, 0x65, 0x48, 0x6f, 0x6c, 0x64, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e,
+	0x48, 0x6f, 0x6c, 0x64, 0x12, 0x2e, 0x0a, 0x0a, 0x63, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x48, 0x6f,
+	0x6c, 0x64, 0x12, 0x10, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x48, 0x6f,
+	0x6c, 0x64, 0x49, 0x44, 0x1a, 0x0e, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e,
+	0x48, 0x6f, 0x6c, 0x64, 0x12, 0x39, 0x0a, 0x09, 0x6c, 0x69, 0x73, 0x74, 0x48, 0x6f, 0x6c, 0x64,
+	0x73, 0x12, 0x1a, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x4c, 0x69, 0x73,
+	0x74, 0x48, 0x6f, 0x6c, 0x64, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e,
+	0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x48, 0x6f, 0x6c, 0x64, 0x30, 0x01, 0x12,
+	0x4b, 0x0a, 0x0f, 0x6c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x61, 0x64, 0x4c, 0x65, 0x74, 0x74, 0x65,
+	0x72, 0x73, 0x12, 0x20, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x4c, 0x69,
+	0x73, 0x74, 0x44, 0x65, 0x61, 0x64, 0x4c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e,
+	0x44, 0x65, 0x61, 0x64, 0x4c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x30, 0x01, 0x12, 0x39, 0x0a, 0x09,
+	0x72, 0x65, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x12, 0x16, 0x2e, 0x62, 0x6f, 0x6f, 0x6b,
+	0x73, 0x61, 0x70, 0x70, 0x2e, 0x44, 0x65, 0x61, 0x64, 0x4c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x49,
+	0x44, 0x1a, 0x14, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x61, 0x70, 0x70, 0x2e, 0x44, 0x65, 0x61,
+	0x64, 0x4c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_circulation_proto_rawDescOnce sync.Once
+	file_circulation_proto_rawDescData = file_circulation_proto_rawDesc
+)
+
+func file_circulation_proto_rawDescGZIP() []byte {
+	file_circulation_proto_rawDescOnce.Do(func() {
+		file_circulation_proto_rawDescData = protoimpl.X.CompressGZIP(file_circulation_proto_rawDescData)
+	})
+	return file_circulation_proto_rawDescData
+}
+
+var file_circulation_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_circulation_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_circulation_proto_goTypes = []interface{}{
+	(CopyStatus)(0),                // 0: booksapp.CopyStatus
+	(HoldStatus)(0),                // 1: booksapp.HoldStatus
+	(*Copy)(nil),                   // 2: booksapp.Copy
+	(*Barcode)(nil),                // 3: booksapp.Barcode
+	(*ListCopiesRequest)(nil),      // 4: booksapp.ListCopiesRequest
+	(*Patron)(nil),                 // 5: booksapp.Patron
+	(*PatronID)(nil),               // 6: booksapp.PatronID
+	(*ListPatronsRequest)(nil),     // 7: booksapp.ListPatronsRequest
+	(*CheckoutRequest)(nil),        // 8: booksapp.CheckoutRequest
+	(*Loan)(nil),                   // 9: booksapp.Loan
+	(*LoanID)(nil),                 // 10: booksapp.LoanID
+	(*ReturnResponse)(nil),         // 11: booksapp.ReturnResponse
+	(*ListLoansRequest)(nil),       // 12: booksapp.ListLoansRequest
+	(*ListOverdueRequest)(nil),     // 13: booksapp.ListOverdueRequest
+	(*Hold)(nil),                   // 14: booksapp.Hold
+	(*HoldID)(nil),                 // 15: booksapp.HoldID
+	(*PlaceHoldRequest)(nil),       // 16: booksapp.PlaceHoldRequest
+	(*ListHoldsRequest)(nil),       // 17: booksapp.ListHoldsRequest
+	(*DeadLetter)(nil),             // 18: booksapp.DeadLetter
+	(*DeadLetterID)(nil),           // 19: booksapp.DeadLetterID
+	(*ListDeadLettersRequest)(nil), // 20: booksapp.ListDeadLettersRequest
+	(*timestamppb.Timestamp)(nil),  // 21: google.protobuf.Timestamp
+}
+var file_circulation_proto_depIdxs = []int32{
+	0,  // 0: booksapp.Copy.status:type_name -> booksapp.CopyStatus
+	21, // 1: booksapp.Loan.checked_out:type_name -> google.protobuf.Timestamp
+	21, // 2: booksapp.Loan.due:type_name -> google.protobuf.Timestamp
+	21, // 3: booksapp.Loan.returned:type_name -> google.protobuf.Timestamp
+	9,  // 4: booksapp.ReturnResponse.loan:type_name -> booksapp.Loan
+	14, // 5: booksapp.ReturnResponse.hold:type_name -> booksapp.Hold
+	21, // 6: booksapp.Hold.placed:type_name -> google.protobuf.Timestamp
+	1,  // 7: booksapp.Hold.status:type_name -> booksapp.HoldStatus
+	21, // 8: booksapp.Hold.ready:type_name -> google.protobuf.Timestamp
+	21, // 9: booksapp.Hold.expires:type_name -> google.protobuf.Timestamp
+	21, // 10: booksapp.DeadLetter.failed:type_name -> google.protobuf.Timestamp
+	2,  // 11: booksapp.Circulation.addCopy:input_type -> booksapp.Copy
+	3,  // 12: booksapp.Circulation.getCopy:input_type -> booksapp.Barcode
+	2,  // 13: booksapp.Circulation.updateCopy:input_type -> booksapp.Copy
+	3,  // 14: booksapp.Circulation.deleteCopy:input_type -> booksapp.Barcode
+	4,  // 15: booksapp.Circulation.listCopies:input_type -> booksapp.ListCopiesRequest
+	5,  // 16: booksapp.Circulation.addPatron:input_type -> booksapp.Patron
+	6,  // 17: booksapp.Circulation.getPatron:input_type -> booksapp.PatronID
+	5,  // 18: booksapp.Circulation.updatePatron:input_type -> booksapp.Patron
+	6,  // 19: booksapp.Circulation.deletePatron:input_type -> booksapp.PatronID
+	7,  // 20: booksapp.Circulation.listPatrons:input_type -> booksapp.ListPatronsRequest
+	8,  // 21: booksapp.Circulation.checkout:input_type -> booksapp.CheckoutRequest
+	3,  // 22: booksapp.Circulation.returnCopy:input_type -> booksapp.Barcode
+	10, // 23: booksapp.Circulation.renew:input_type -> booksapp.LoanID
+	12, // 24: booksapp.Circulation.listLoans:input_type -> booksapp.ListLoansRequest
+	13, // 25: booksapp.Circulation.listOverdue:input_type -> booksapp.ListOverdueRequest
+	16, // 26: booksapp.Circulation.placeHold:input_type -> booksapp.PlaceHoldRequest
+	15, // 27: booksapp.Circulation.cancelHold:input_type -> booksapp.HoldID
+	17, // 28: booksapp.Circulation.listHolds:input_type -> booksapp.ListHoldsRequest
+	20, // 29: booksapp.Circulation.listDeadLetters:input_type -> booksapp.ListDeadLettersRequest
+	19, // 30: booksapp.Circulation.redeliver:input_type -> booksapp.DeadLetterID
+	2,  // 31: booksapp.Circulation.addCopy:output_type -> booksapp.Copy
+	2,  // 32: booksapp.Circulation.getCopy:output_type -> booksapp.Copy
+	2,  // 33: booksapp.Circulation.updateCopy:output_type -> booksapp.Copy
+	2,  // 34: booksapp.Circulation.deleteCopy:output_type -> booksapp.Copy
+	2,  // 35: booksapp.Circulation.listCopies:output_type -> booksapp.Copy
+	5,  // 36: booksapp.Circulation.addPatron:output_type -> booksapp.Patron
+	5,  // 37: booksapp.Circulation.getPatron:output_type -> booksapp.Patron
+	5,  // 38: booksapp.Circulation.updatePatron:output_type -> booksapp.Patron
+	5,  // 39: booksapp.Circulation.deletePatron:output_type -> booksapp.Patron
+	5,  // 40: booksapp.Circulation.listPatrons:output_type -> booksapp.Patron
+	9,  // 41: booksapp.Circulation.checkout:output_type -> booksapp.Loan
+	11, // 42: booksapp.Circulation.returnCopy:output_type -> booksapp.ReturnResponse
+	9,  // 43: booksapp.Circulation.renew:output_type -> booksapp.Loan
+	9,  // 44: booksapp.Circulation.listLoans:output_type -> booksapp.Loan
+	9,  // 45: booksapp.Circulation.listOverdue:output_type -> booksapp.Loan
+	14, // 46: booksapp.Circulation.placeHold:output_type -> booksapp.Hold
+	14, // 47: booksapp.Circulation.cancelHold:output_type -> booksapp.Hold
+	14, // 48: booksapp.Circulation.listHolds:output_type -> booksapp.Hold
+	18, // 49: booksapp.Circulation.listDeadLetters:output_type -> booksapp.DeadLetter
+	18, // 50: booksapp.Circulation.redeliver:output_type -> booksapp.DeadLetter
+	31, // [31:51] is the sub-list for method output_type
+	11, // [11:31] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_circulation_proto_init() }
+func file_circulation_proto_init() {
+	if File_circulation_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_circulation_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Copy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_circulation_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Barcode); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_circulation_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListCopiesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_circulation_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Patron); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_circulation_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PatronID); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_circulation_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListPatronsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_circulation_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CheckoutRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_circulation_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Loan); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_circulation_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoanID); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_circulation_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReturnResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_circulation_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListLoansRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_circulation_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListOverdueRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_circulation_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Hold); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_circulation_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HoldID); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_circulation_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PlaceHoldRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_circulation_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListHoldsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_circulation_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeadLetter); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_circulation_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeadLetterID); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_circulation_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListDeadLettersRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_circulation_proto_rawDesc,
+			NumEnums:      2,
+			NumMessages:   19,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_circulation_proto_goTypes,
+		DependencyIndexes: file_circulation_proto_depIdxs,
+		EnumInfos:         file_circulation_proto_enumTypes,
+		MessageInfos:      file_circulation_proto_msgTypes,
+	}.Build()
+	File_circulation_proto = out.File
+	file_circulation_proto_rawDesc = nil
+	file_circulation_proto_goTypes = nil
+	file_circulation_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// CirculationClient is the client API for Circulation service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type CirculationClient interface {
+	AddCopy(ctx context.Context, in *Copy, opts ...grpc.CallOption) (*Copy, error)
+	GetCopy(ctx context.Context, in *Barcode, opts ...grpc.CallOption) (*Copy, error)
+	// updateCopy moves a copy to another location.
+	UpdateCopy(ctx context.Context, in *Copy, opts ...grpc.CallOption) (*Copy, error)
+	// deleteCopy withdraws a copy, which must be on the shelf.
+	DeleteCopy(ctx context.Context, in *Barcode, opts ...grpc.CallOption) (*Copy, error)
+	ListCopies(ctx context.Context, in *ListCopiesRequest, opts ...grpc.CallOption) (Circulation_ListCopiesClient, error)
+	AddPatron(ctx context.Context, in *Patron, opts ...grpc.CallOption) (*Patron, error)
+	GetPatron(ctx context.Context, in *PatronID, opts ...grpc.CallOption) (*Patron, error)
+	UpdatePatron(ctx context.Context, in *Patron, opts ...grpc.CallOption) (*Patron, error)
+	// deletePatron deletes a patron with no copies on loan, cancelling
+	// their holds.
+	DeletePatron(ctx context.Context, in *PatronID, opts ...grpc.CallOption) (*Patron, error)
+	ListPatrons(ctx context.Context, in *ListPatronsRequest, opts ...grpc.CallOption) (Circulation_ListPatronsClient, error)
+	// checkout lends a copy that is on the shelf, or on the hold shelf
+	// for the patron, unless the patron has the most loans allowed.
+	Checkout(ctx context.Context, in *CheckoutRequest, opts ...grpc.CallOption) (*Loan, error)
+	// returnCopy ends the loan of a copy. The copy goes to the hold shelf
+	// for the first patron waiting for the book, if any.
+	ReturnCopy(ctx context.Context, in *Barcode, opts ...grpc.CallOption) (*ReturnResponse, error)
+	// renew moves the due date of a loan a loan period past the renewal,
+	// unless the loan has been renewed the most times allowed or other
+	// patrons are waiting for the book.
+	Renew(ctx context.Context, in *LoanID, opts ...grpc.CallOption) (*Loan, error)
+	ListLoans(ctx context.Context, in *ListLoansRequest, opts ...grpc.CallOption) (Circulation_ListLoansClient, error)
+	// listOverdue streams the loans past their due date, most overdue
+	// first.
+	ListOverdue(ctx context.Context, in *ListOverdueRequest, opts ...grpc.CallOption) (Circulation_ListOverdueClient, error)
+	// placeHold queues a patron for a book none of whose copies is on the
+	// shelf.
+	PlaceHold(ctx context.Context, in *PlaceHoldRequest, opts ...grpc.CallOption) (*Hold, error)
+	// cancelHold removes a hold from its queue. A copy waiting on the hold
+	// shelf for it goes to the next patron in the queue.
+	CancelHold(ctx context.Context, in *HoldID, opts ...grpc.CallOption) (*Hold, error)
+	// listHolds streams holds in queue order.
+	ListHolds(ctx context.Context, in *ListHoldsRequest, opts ...grpc.CallOption) (Circulation_ListHoldsClient, error)
+	ListDeadLetters(ctx context.Context, in *ListDeadLettersRequest, opts ...grpc.CallOption) (Circulation_ListDeadLettersClient, error)
+	// redeliver sends a dead letter's event again and removes it. It is
+	// dead-lettered anew if delivery fails again.
+	Redeliver(ctx context.Context, in *DeadLetterID, opts ...grpc.CallOption) (*DeadLetter, error)
+}
+
+type circulationClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCirculationClient(cc grpc.ClientConnInterface) CirculationClient {
+	return &circulationClient{cc}
+}
+
+func (c *circulationClient) AddCopy(ctx context.Context, in *Copy, opts ...grpc.CallOption) (*Copy, error) {
+	out := new(Copy)
+	err := c.cc.Invoke(ctx, "/booksapp.Circulation/addCopy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *circulationClient) GetCopy(ctx context.Context, in *Barcode, opts ...grpc.CallOption) (*Copy, error) {
+	out := new(Copy)
+	err := c.cc.Invoke(ctx, "/booksapp.Circulation/getCopy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *circulationClient) UpdateCopy(ctx context.Context, in *Copy, opts ...grpc.CallOption) (*Copy, error) {
+	out := new(Copy)
+	err := c.cc.Invoke(ctx, "/booksapp.Circulation/updateCopy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *circulationClient) DeleteCopy(ctx context.Context, in *Barcode, opts ...grpc.CallOption) (*Copy, error) {
+	out := new(Copy)
+	err := c.cc.Invoke(ctx, "/booksapp.Circulation/deleteCopy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *circulationClient) ListCopies(ctx context.Context, in *ListCopiesRequest, opts ...grpc.CallOption) (Circulation_ListCopiesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Circulation_serviceDesc.Streams[0], "/booksapp.Circulation/listCopies", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &circulationListCopiesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Circulation_ListCopiesClient interface {
+	Recv() (*Copy, error)
+	grpc.ClientStream
+}
+
+type circulationListCopiesClient struct {
+	grpc.ClientStream
+}
+
+func (x *circulationListCopiesClient) Recv() (*Copy, error) {
+	m := new(Copy)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *circulationClient) AddPatron(ctx context.Context, in *Patron, opts ...grpc.CallOption) (*Patron, error) {
+	out := new(Patron)
+	err := c.cc.Invoke(ctx, "/booksapp.Circulation/addPatron", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *circulationClient) GetPatron(ctx context.Context, in *PatronID, opts ...grpc.CallOption) (*Patron, error) {
+	out := new(Patron)
+	err := c.cc.Invoke(ctx, "/booksapp.Circulation/getPatron", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *circulationClient) UpdatePatron(ctx context.Context, in *Patron, opts ...grpc.CallOption) (*Patron, error) {
+	out := new(Patron)
+	err := c.cc.Invoke(ctx, "/booksapp.Circulation/updatePatron", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *circulationClient) DeletePatron(ctx context.Context, in *PatronID, opts ...grpc.CallOption) (*Patron, error) {
+	out := new(Patron)
+	err := c.cc.Invoke(ctx, "/booksapp.Circulation/deletePatron", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *circulationClient) ListPatrons(ctx context.Context, in *ListPatronsRequest, opts ...grpc.CallOption) (Circulation_ListPatronsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Circulation_serviceDesc.Streams[1], "/booksapp.Circulation/listPatrons", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &circulationListPatronsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Circulation_ListPatronsClient interface {
+	Recv() (*Patron, error)
+	grpc.ClientStream
+}
+
+type circulationListPatronsClient struct {
+	grpc.ClientStream
+}
+
+func (x *circulationListPatronsClient) Recv() (*Patron, error) {
+	m := new(Patron)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *circulationClient) Checkout(ctx context.Context, in *CheckoutRequest, opts ...grpc.CallOption) (*Loan, error) {
+	out := new(Loan)
+	err := c.cc.Invoke(ctx, "/booksapp.Circulation/checkout", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *circulationClient) ReturnCopy(ctx context.Context, in *Barcode, opts ...grpc.CallOption) (*ReturnResponse, error) {
+	out := new(ReturnResponse)
+	err := c.cc.Invoke(ctx, "/booksapp.Circulation/returnCopy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *circulationClient) Renew(ctx context.Context, in *LoanID, opts ...grpc.CallOption) (*Loan, error) {
+	out := new(Loan)
+	err := c.cc.Invoke(ctx, "/booksapp.Circulation/renew", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *circulationClient) ListLoans(ctx context.Context, in *ListLoansRequest, opts ...grpc.CallOption) (Circulation_ListLoansClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Circulation_serviceDesc.Streams[2], "/booksapp.Circulation/listLoans", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &circulationListLoansClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Circulation_ListLoansClient interface {
+	Recv() (*Loan, error)
+	grpc.ClientStream
+}
+
+type circulationListLoansClient struct {
+	grpc.ClientStream
+}
+
+func (x *circulationListLoansClient) Recv() (*Loan, error) {
+	m := new(Loan)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *circulationClient) ListOverdue(ctx context.Context, in *ListOverdueRequest, opts ...grpc.CallOption) (Circulation_ListOverdueClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Circulation_serviceDesc.Streams[3], "/booksapp.Circulation/listOverdue", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &circulationListOverdueClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Circulation_ListOverdueClient interface {
+	Recv() (*Loan, error)
+	grpc.ClientStream
+}
+
+type circulationListOverdueClient struct {
+	grpc.ClientStream
+}
+
+func (x *circulationListOverdueClient) Recv() (*Loan, error) {
+	m := new(Loan)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *circulationClient) PlaceHold(ctx context.Context, in *PlaceHoldRequest, opts ...grpc.CallOption) (*Hold, error) {
+	out := new(Hold)
+	err := c.cc.Invoke(ctx, "/booksapp.Circulation/placeHold", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *circulationClient) CancelHold(ctx context.Context, in *HoldID, opts ...grpc.CallOption) (*Hold, error) {
+	out := new(Hold)
+	err := c.cc.Invoke(ctx, "/booksapp.Circulation/cancelHold", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *circulationClient) ListHolds(ctx context.Context, in *ListHoldsRequest, opts ...grpc.CallOption) (Circulation_ListHoldsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Circulation_serviceDesc.Streams[4], "/booksapp.Circulation/listHolds", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &circulationListHoldsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Circulation_ListHoldsClient interface {
+	Recv() (*Hold, error)
+	grpc.ClientStream
+}
+
+type circulationListHoldsClient struct {
+	grpc.ClientStream
+}
+
+func (x *circulationListHoldsClient) Recv() (*Hold, error) {
+	m := new(Hold)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *circulationClient) ListDeadLetters(ctx context.Context, in *ListDeadLettersRequest, opts ...grpc.CallOption) (Circulation_ListDeadLettersClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Circulation_serviceDesc.Streams[5], "/booksapp.Circulation/listDeadLetters", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &circulationListDeadLettersClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Circulation_ListDeadLettersClient interface {
+	Recv() (*DeadLetter, error)
+	grpc.ClientStream
+}
+
+type circulationListDeadLettersClient struct {
+	grpc.ClientStream
+}
+
+func (x *circulationListDeadLettersClient) Recv() (*DeadLetter, error) {
+	m := new(DeadLetter)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *circulationClient) Redeliver(ctx context.Context, in *DeadLetterID, opts ...grpc.CallOption) (*DeadLetter, error) {
+	out := new(DeadLetter)
+	err := c.cc.Invoke(ctx, "/booksapp.Circulation/redeliver", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CirculationServer is the server API for Circulation service.
+type CirculationServer interface {
+	AddCopy(context.Context, *Copy) (*Copy, error)
+	GetCopy(context.Context, *Barcode) (*Copy, error)
+	// updateCopy moves a copy to another location.
+	UpdateCopy(context.Context, *Copy) (*Copy, error)
+	// deleteCopy withdraws a copy, which must be on the shelf.
+	DeleteCopy(context.Context, *Barcode) (*Copy, error)
+	ListCopies(*ListCopiesRequest, Circulation_ListCopiesServer) error
+	AddPatron(context.Context, *Patron) (*Patron, error)
+	GetPatron(context.Context, *PatronID) (*Patron, error)
+	UpdatePatron(context.Context, *Patron) (*Patron, error)
+	// deletePatron deletes a patron with no copies on loan, cancelling
+	// their holds.
+	DeletePatron(context.Context, *PatronID) (*Patron, error)
+	ListPatrons(*ListPatronsRequest, Circulation_ListPatronsServer) error
+	// checkout lends a copy that is on the shelf, or on the hold shelf
+	// for the patron, unless the patron has the most loans allowed.
+	Checkout(context.Context, *CheckoutRequest) (*Loan, error)
+	// returnCopy ends the loan of a copy. The copy goes to the hold shelf
+	// for the first patron waiting for the book, if any.
+	ReturnCopy(context.Context, *Barcode) (*ReturnResponse, error)
+	// renew moves the due date of a loan a loan period past the renewal,
+	// unless the loan has been renewed the most times allowed or other
+	// patrons are waiting for the book.
+	Renew(context.Context, *LoanID) (*Loan, error)
+	ListLoans(*ListLoansRequest, Circulation_ListLoansServer) error
+	// listOverdue streams the loans past their due date, most overdue
+	// first.
+	ListOverdue(*ListOverdueRequest, Circulation_ListOverdueServer) error
+	// placeHold queues a patron for a book none of whose copies is on the
+	// shelf.
+	PlaceHold(context.Context, *PlaceHoldRequest) (*Hold, error)
+	// cancelHold removes a hold from its queue. A copy waiting on the hold
+	// shelf for it goes to the next patron in the queue.
+	CancelHold(context.Context, *HoldID) (*Hold, error)
+	// listHolds streams holds in queue order.
+	ListHolds(*ListHoldsRequest, Circulation_ListHoldsServer) error
+	ListDeadLetters(*ListDeadLettersRequest, Circulation_ListDeadLettersServer) error
+	// redeliver sends a dead letter's event again and removes it. It is
+	// dead-lettered anew if delivery fails again.
+	Redeliver(context.Context, *DeadLetterID) (*DeadLetter, error)
+}
+
+// UnimplementedCirculationServer can be embedded to have forward compatible implementations.
+type UnimplementedCirculationServer struct {
+}
+
+func (*UnimplementedCirculationServer) AddCopy(context.Context, *Copy) (*Copy, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddCopy not implemented")
+}
+func (*UnimplementedCirculationServer) GetCopy(context.Context, *Barcode) (*Copy, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCopy not implemented")
+}
+func (*UnimplementedCirculationServer) UpdateCopy(context.Context, *Copy) (*Copy, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateCopy not implemented")
+}
+func (*UnimplementedCirculationServer) DeleteCopy(context.Context, *Barcode) (*Copy, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteCopy not implemented")
+}
+func (*UnimplementedCirculationServer) ListCopies(*ListCopiesRequest, Circulation_ListCopiesServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListCopies not implemented")
+}
+func (*UnimplementedCirculationServer) AddPatron(context.Context, *Patron) (*Patron, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddPatron not implemented")
+}
+func (*UnimplementedCirculationServer) GetPatron(context.Context, *PatronID) (*Patron, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPatron not implemented")
+}
+func (*UnimplementedCirculationServer) UpdatePatron(context.Context, *Patron) (*Patron, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdatePatron not implemented")
+}
+func (*UnimplementedCirculationServer) DeletePatron(context.Context, *PatronID) (*Patron, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeletePatron not implemented")
+}
+func (*UnimplementedCirculationServer) ListPatrons(*ListPatronsRequest, Circulation_ListPatronsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListPatrons not implemented")
+}
+func (*UnimplementedCirculationServer) Checkout(context.Context, *CheckoutRequest) (*Loan, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Checkout not implemented")
+}
+func (*UnimplementedCirculationServer) ReturnCopy(context.Context, *Barcode) (*ReturnResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReturnCopy not implemented")
+}
+func (*UnimplementedCirculationServer) Renew(context.Context, *LoanID) (*Loan, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Renew not implemented")
+}
+func (*UnimplementedCirculationServer) ListLoans(*ListLoansRequest, Circulation_ListLoansServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListLoans not implemented")
+}
+func (*UnimplementedCirculationServer) ListOverdue(*ListOverdueRequest, Circulation_ListOverdueServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListOverdue not implemented")
+}
+func (*UnimplementedCirculationServer) PlaceHold(context.Context, *PlaceHoldRequest) (*Hold, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PlaceHold not implemented")
+}
+func (*UnimplementedCirculationServer) CancelHold(context.Context, *HoldID) (*Hold, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelHold not implemented")
+}
+func (*UnimplementedCirculationServer) ListHolds(*ListHoldsRequest, Circulation_ListHoldsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListHolds not implemented")
+}
+func (*UnimplementedCirculationServer) ListDeadLetters(*ListDeadLettersRequest, Circulation_ListDeadLettersServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListDeadLetters not implemented")
+}
+func (*UnimplementedCirculationServer) Redeliver(context.Context, *DeadLetterID) (*DeadLetter, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Redeliver not implemented")
+}
+
+func RegisterCirculationServer(s *grpc.Server, srv CirculationServer) {
+	s.RegisterService(&_Circulation_serviceDesc, srv)
+}
+
+func _Circulation_AddCopy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Copy)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CirculationServer).AddCopy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/booksapp.Circulation/AddCopy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CirculationServer).AddCopy(ctx, req.(*Copy))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Circulation_GetCopy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Barcode)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CirculationServer).GetCopy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/booksapp.Circulation/GetCopy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CirculationServer).GetCopy(ctx, req.(*Barcode))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Circulation_UpdateCopy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Copy)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CirculationServer).UpdateCopy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/booksapp.Circulation/UpdateCopy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CirculationServer).UpdateCopy(ctx, req.(*Copy))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Circulation_DeleteCopy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Barcode)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CirculationServer).DeleteCopy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/booksapp.Circulation/DeleteCopy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CirculationServer).DeleteCopy(ctx, req.(*Barcode))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Circulation_ListCopies_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListCopiesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CirculationServer).ListCopies(m, &circulationListCopiesServer{stream})
+}
+
+type Circulation_ListCopiesServer interface {
+	Send(*Copy) error
+	grpc.ServerStream
+}
+
+type circulationListCopiesServer struct {
+	grpc.ServerStream
+}
+
+func (x *circulationListCopiesServer) Send(m *Copy) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Circulation_AddPatron_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Patron)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CirculationServer).AddPatron(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/booksapp.Circulation/AddPatron",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CirculationServer).AddPatron(ctx, req.(*Patron))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Circulation_GetPatron_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PatronID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CirculationServer).GetPatron(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/booksapp.Circulation/GetPatron",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CirculationServer).GetPatron(ctx, req.(*PatronID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Circulation_UpdatePatron_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Patron)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CirculationServer).UpdatePatron(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/booksapp.Circulation/UpdatePatron",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CirculationServer).UpdatePatron(ctx, req.(*Patron))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Circulation_DeletePatron_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PatronID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CirculationServer).DeletePatron(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/booksapp.Circulation/DeletePatron",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CirculationServer).DeletePatron(ctx, req.(*PatronID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Circulation_ListPatrons_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListPatronsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CirculationServer).ListPatrons(m, &circulationListPatronsServer{stream})
+}
+
+type Circulation_ListPatronsServer interface {
+	Send(*Patron) error
+	grpc.ServerStream
+}
+
+type circulationListPatronsServer struct {
+	grpc.ServerStream
+}
+
+func (x *circulationListPatronsServer) Send(m *Patron) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Circulation_Checkout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckoutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CirculationServer).Checkout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/booksapp.Circulation/Checkout",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CirculationServer).Checkout(ctx, req.(*CheckoutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Circulation_ReturnCopy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Barcode)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CirculationServer).ReturnCopy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/booksapp.Circulation/ReturnCopy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CirculationServer).ReturnCopy(ctx, req.(*Barcode))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Circulation_Renew_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoanID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CirculationServer).Renew(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/booksapp.Circulation/Renew",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CirculationServer).Renew(ctx, req.(*LoanID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Circulation_ListLoans_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListLoansRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CirculationServer).ListLoans(m, &circulationListLoansServer{stream})
+}
+
+type Circulation_ListLoansServer interface {
+	Send(*Loan) error
+	grpc.ServerStream
+}
+
+type circulationListLoansServer struct {
+	grpc.ServerStream
+}
+
+func (x *circulationListLoansServer) Send(m *Loan) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Circulation_ListOverdue_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListOverdueRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CirculationServer).ListOverdue(m, &circulationListOverdueServer{stream})
+}
+
+type Circulation_ListOverdueServer interface {
+	Send(*Loan) error
+	grpc.ServerStream
+}
+
+type circulationListOverdueServer struct {
+	grpc.ServerStream
+}
+
+func (x *circulationListOverdueServer) Send(m *Loan) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Circulation_PlaceHold_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlaceHoldRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CirculationServer).PlaceHold(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/booksapp.Circulation/PlaceHold",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CirculationServer).PlaceHold(ctx, req.(*PlaceHoldRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Circulation_CancelHold_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HoldID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CirculationServer).CancelHold(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/booksapp.Circulation/CancelHold",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CirculationServer).CancelHold(ctx, req.(*HoldID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Circulation_ListHolds_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListHoldsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CirculationServer).ListHolds(m, &circulationListHoldsServer{stream})
+}
+
+type Circulation_ListHoldsServer interface {
+	Send(*Hold) error
+	grpc.ServerStream
+}
+
+type circulationListHoldsServer struct {
+	grpc.ServerStream
+}
+
+func (x *circulationListHoldsServer) Send(m *Hold) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Circulation_ListDeadLetters_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListDeadLettersRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CirculationServer).ListDeadLetters(m, &circulationListDeadLettersServer{stream})
+}
+
+type Circulation_ListDeadLettersServer interface {
+	Send(*DeadLetter) error
+	grpc.ServerStream
+}
+
+type circulationListDeadLettersServer struct {
+	grpc.ServerStream
+}
+
+func (x *circulationListDeadLettersServer) Send(m *DeadLetter) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Circulation_Redeliver_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeadLetterID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CirculationServer).Redeliver(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/booksapp.Circulation/Redeliver",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CirculationServer).Redeliver(ctx, req.(*DeadLetterID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Circulation_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "booksapp.Circulation",
+	HandlerType: (*CirculationServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "addCopy",
+			Handler:    _Circulation_AddCopy_Handler,
+		},
+		{
+			MethodName: "getCopy",
+			Handler:    _Circulation_GetCopy_Handler,
+		},
+		{
+			MethodName: "updateCopy",
+			Handler:    _Circulation_UpdateCopy_Handler,
+		},
+		{
+			MethodName: "deleteCopy",
+			Handler:    _Circulation_DeleteCopy_Handler,
+		},
+		{
+			MethodName: "addPatron",
+			Handler:    _Circulation_AddPatron_Handler,
+		},
+		{
+			MethodName: "getPatron",
+			Handler:    _Circulation_GetPatron_Handler,
+		},
+		{
+			MethodName: "updatePatron",
+			Handler:    _Circulation_UpdatePatron_Handler,
+		},
+		{
+			MethodName: "deletePatron",
+			Handler:    _Circulation_DeletePatron_Handler,
+		},
+		{
+			MethodName: "checkout",
+			Handler:    _Circulation_Checkout_Handler,
+		},
+		{
+			MethodName: "returnCopy",
+			Handler:    _Circulation_ReturnCopy_Handler,
+		},
+		{
+			MethodName: "renew",
+			Handler:    _Circulation_Renew_Handler,
+		},
+		{
+			MethodName: "placeHold",
+			Handler:    _Circulation_PlaceHold_Handler,
+		},
+		{
+			MethodName: "cancelHold",
+			Handler:    _Circulation_CancelHold_Handler,
+		},
+		{
+			MethodName: "redeliver",
+			Handler:    _Circulation_Redeliver_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "listCopies",
+			Handler:       _Circulation_ListCopies_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "listPatrons",
+			Handler:       _Circulation_ListPatrons_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "listLoans",
+			Handler:       _Circulation_ListLoans_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "listOverdue",
+			Handler:       _Circulation_ListOverdue_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "listHolds",
+			Handler:       _Circulation_ListHolds_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "listDeadLetters",
+			Handler:       _Circulation_ListDeadLetters_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "circulation.proto",
+}
@@ -0,0 +1,8 @@
+package booksapp
+
+import _ "embed" // for go:embed
+
+// ProtoSource is the books_info.proto file this package is generated from.
+//
+//go:embed books_info.proto
+var ProtoSource []byte
@@ -0,0 +1,624 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"github.com/marcoc22/tutorial3/circulation"
+	"github.com/marcoc22/tutorial3/store"
+	"github.com/marcoc22/tutorial3/webhook"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// The store collections circulation records are kept in. Copies are
+// keyed by barcode.
+const (
+	copiesCollection  = "copies"
+	patronsCollection = "patrons"
+	loansCollection   = "loans"
+	holdsCollection   = "holds"
+)
+
+// circulationService lends the copies of the books of books to patrons
+// following policy. Its writes are serialized with those of books, under
+// books.mu.
+type circulationService struct {
+	books  *server
+	policy circulation.Policy
+	now    func() time.Time
+	// webhooks, if set, delivers the hold events.
+	webhooks *webhook.Dispatcher
+	// leader, if set, reports whether this server leads a replicated
+	// store; only the leader expires holds.
+	leader        func() bool
+	stop, stopped chan struct{}
+}
+
+func newCirculationService(books *server, policy circulation.Policy) *circulationService {
+	return &circulationService{books: books, policy: policy, now: time.Now}
+}
+
+func (c *circulationService) get(collection, kind, id string, m proto.Message) error {
+	if err := store.GetMessage(c.books.store, collection, id, m); err != nil {
+		return recordStatus(err, kind, id)
+	}
+	return nil
+}
+
+func (c *circulationService) put(collection, kind, id string, m proto.Message) error {
+	if err := store.PutMessage(c.books.store, collection, id, m); err != nil {
+		return recordStatus(err, kind, id)
+	}
+	return nil
+}
+
+func (c *circulationService) delete(collection, kind, id string) error {
+	if err := c.books.store.Delete(collection, id); err != nil {
+		return recordStatus(err, kind, id)
+	}
+	return nil
+}
+
+// scan calls fn with every record of collection, decoded into a new
+// message from m, in ID order.
+func (c *circulationService) scan(collection, kind string, m func() proto.Message, fn func(proto.Message) error) error {
+	err := c.books.store.Scan(collection, func(id string, value []byte) error {
+		record := m()
+		if err := proto.Unmarshal(value, record); err != nil {
+			return status.Errorf(codes.DataLoss, "%s %s is corrupt: %v", kind, id, err)
+		}
+		return fn(record)
+	})
+	if _, ok := status.FromError(err); !ok {
+		return status.Errorf(codes.Internal, "Error while listing %ss: %v", strings.ToLower(kind), err)
+	}
+	return err
+}
+
+func (c *circulationService) getCopy(barcode string) (*pb.Copy, error) {
+	copy := &pb.Copy{}
+	if err := c.get(copiesCollection, "Copy", barcode, copy); err != nil {
+		return nil, err
+	}
+	return copy, nil
+}
+
+func (c *circulationService) getPatron(id string) (*pb.Patron, error) {
+	patron := &pb.Patron{}
+	if err := c.get(patronsCollection, "Patron", id, patron); err != nil {
+		return nil, err
+	}
+	return patron, nil
+}
+
+func (c *circulationService) getLoan(id string) (*pb.Loan, error) {
+	loan := &pb.Loan{}
+	if err := c.get(loansCollection, "Loan", id, loan); err != nil {
+		return nil, err
+	}
+	return loan, nil
+}
+
+func (c *circulationService) getHold(id string) (*pb.Hold, error) {
+	hold := &pb.Hold{}
+	if err := c.get(holdsCollection, "Hold", id, hold); err != nil {
+		return nil, err
+	}
+	return hold, nil
+}
+
+func (c *circulationService) scanCopies(fn func(*pb.Copy) error) error {
+	return c.scan(copiesCollection, "Copy", func() proto.Message { return &pb.Copy{} }, func(m proto.Message) error {
+		return fn(m.(*pb.Copy))
+	})
+}
+
+func (c *circulationService) scanLoans(fn func(*pb.Loan) error) error {
+	return c.scan(loansCollection, "Loan", func() proto.Message { return &pb.Loan{} }, func(m proto.Message) error {
+		return fn(m.(*pb.Loan))
+	})
+}
+
+// holds returns the holds passing filter in queue order: first placed,
+// first served.
+func (c *circulationService) holds(filter func(*pb.Hold) bool) ([]*pb.Hold, error) {
+	var holds []*pb.Hold
+	err := c.scan(holdsCollection, "Hold", func() proto.Message { return &pb.Hold{} }, func(m proto.Message) error {
+		if hold := m.(*pb.Hold); filter(hold) {
+			holds = append(holds, hold)
+		}
+		return nil
+	})
+	sort.SliceStable(holds, func(i, j int) bool {
+		a, b := holds[i].Placed.AsTime(), holds[j].Placed.AsTime()
+		if !a.Equal(b) {
+			return a.Before(b)
+		}
+		return holds[i].Id < holds[j].Id
+	})
+	return holds, err
+}
+
+// waiting returns the queue of WAITING holds on the book with id.
+func (c *circulationService) waiting(bookID string) ([]*pb.Hold, error) {
+	return c.holds(func(h *pb.Hold) bool {
+		return h.BookId == bookID && h.Status == pb.HoldStatus_WAITING
+	})
+}
+
+// activeLoans counts the copies the patron with id has on loan.
+func (c *circulationService) activeLoans(patronID string) (int, error) {
+	n := 0
+	err := c.scanLoans(func(loan *pb.Loan) error {
+		if loan.PatronId == patronID && loan.Returned == nil {
+			n++
+		}
+		return nil
+	})
+	return n, err
+}
+
+// shelve puts a copy that is not on loan on the hold shelf for the first
+// patron waiting for its book, returning their hold and notifying them,
+// or else back on the shelf.
+func (c *circulationService) shelve(copy *pb.Copy) (*pb.Hold, error) {
+	queue, err := c.waiting(copy.BookId)
+	if err != nil {
+		return nil, err
+	}
+	copy.LoanId, copy.HoldId = "", ""
+	copy.Status = pb.CopyStatus_ON_SHELF
+	var hold *pb.Hold
+	if len(queue) > 0 {
+		hold = queue[0]
+		hold.Status = pb.HoldStatus_READY
+		hold.Barcode = copy.Barcode
+		now := c.now()
+		hold.Ready = timestamppb.New(now)
+		hold.Expires = timestamppb.New(c.policy.HoldExpires(now))
+		if err := c.put(holdsCollection, "Hold", hold.Id, hold); err != nil {
+			return nil, err
+		}
+		copy.Status = pb.CopyStatus_ON_HOLD_SHELF
+		copy.HoldId = hold.Id
+	}
+	if err := c.put(copiesCollection, "Copy", copy.Barcode, copy); err != nil {
+		return nil, err
+	}
+	if hold != nil {
+		c.notify(holdReadyEvent, hold)
+	}
+	return hold, nil
+}
+
+// cancelHold deletes hold and passes the copy kept for it, if any, on.
+func (c *circulationService) cancelHold(hold *pb.Hold) error {
+	if err := c.delete(holdsCollection, "Hold", hold.Id); err != nil {
+		return err
+	}
+	if hold.Status != pb.HoldStatus_READY {
+		return nil
+	}
+	copy, err := c.getCopy(hold.Barcode)
+	if err != nil {
+		return err
+	}
+	_, err = c.shelve(copy)
+	return err
+}
+
+func newID(kind string) (string, error) {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "Error while generating %s ID: %v", kind, err)
+	}
+	return id.String(), nil
+}
+
+func (c *circulationService) AddCopy(ctx context.Context, in *pb.Copy) (*pb.Copy, error) {
+	in.Barcode = strings.TrimSpace(in.Barcode)
+	if in.Barcode == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Copy barcode is required.")
+	}
+	c.books.mu.Lock()
+	defer c.books.mu.Unlock()
+	if _, err := c.books.getBook(ctx, in.BookId); err != nil {
+		return nil, referenceError(err)
+	}
+	if _, err := c.getCopy(in.Barcode); err == nil {
+		return nil, status.Errorf(codes.AlreadyExists, "Copy %s already exists.", in.Barcode)
+	} else if status.Code(err) != codes.NotFound {
+		return nil, err
+	}
+	// A new copy goes to the first patron waiting for the book.
+	if _, err := c.shelve(in); err != nil {
+		return nil, err
+	}
+	return in, status.New(codes.OK, "").Err()
+}
+
+func (c *circulationService) GetCopy(ctx context.Context, in *pb.Barcode) (*pb.Copy, error) {
+	return c.getCopy(in.Value)
+}
+
+func (c *circulationService) UpdateCopy(ctx context.Context, in *pb.Copy) (*pb.Copy, error) {
+	c.books.mu.Lock()
+	defer c.books.mu.Unlock()
+	copy, err := c.getCopy(in.Barcode)
+	if err != nil {
+		return nil, err
+	}
+	copy.Location = in.Location
+	if err := c.put(copiesCollection, "Copy", copy.Barcode, copy); err != nil {
+		return nil, err
+	}
+	return copy, status.New(codes.OK, "").Err()
+}
+
+func (c *circulationService) DeleteCopy(ctx context.Context, in *pb.Barcode) (*pb.Copy, error) {
+	c.books.mu.Lock()
+	defer c.books.mu.Unlock()
+	copy, err := c.getCopy(in.Value)
+	if err != nil {
+		return nil, err
+	}
+	if copy.Status != pb.CopyStatus_ON_SHELF {
+		return nil, status.Errorf(codes.FailedPrecondition, "Copy %s is not on the shelf.", in.Value)
+	}
+	if err := c.delete(copiesCollection, "Copy", in.Value); err != nil {
+		return nil, err
+	}
+	return copy, status.New(codes.OK, "").Err()
+}
+
+func (c *circulationService) ListCopies(in *pb.ListCopiesRequest, stream pb.Circulation_ListCopiesServer) error {
+	return c.scanCopies(func(copy *pb.Copy) error {
+		if in.BookId != "" && copy.BookId != in.BookId {
+			return nil
+		}
+		return stream.Send(copy)
+	})
+}
+
+func (c *circulationService) AddPatron(ctx context.Context, in *pb.Patron) (*pb.Patron, error) {
+	if strings.TrimSpace(in.Name) == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Patron name is required.")
+	}
+	id, err := newID("Patron")
+	if err != nil {
+		return nil, err
+	}
+	in.Id = id
+	if err := c.put(patronsCollection, "Patron", in.Id, in); err != nil {
+		return nil, err
+	}
+	return in, status.New(codes.OK, "").Err()
+}
+
+func (c *circulationService) GetPatron(ctx context.Context, in *pb.PatronID) (*pb.Patron, error) {
+	return c.getPatron(in.Value)
+}
+
+func (c *circulationService) UpdatePatron(ctx context.Context, in *pb.Patron) (*pb.Patron, error) {
+	if strings.TrimSpace(in.Name) == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Patron name is required.")
+	}
+	c.books.mu.Lock()
+	defer c.books.mu.Unlock()
+	if _, err := c.getPatron(in.Id); err != nil {
+		return nil, err
+	}
+	if err := c.put(patronsCollection, "Patron", in.Id, in); err != nil {
+		return nil, err
+	}
+	return in, status.New(codes.OK, "").Err()
+}
+
+func (c *circulationService) DeletePatron(ctx context.Context, in *pb.PatronID) (*pb.Patron, error) {
+	c.books.mu.Lock()
+	defer c.books.mu.Unlock()
+	patron, err := c.getPatron(in.Value)
+	if err != nil {
+		return nil, err
+	}
+	loans, err := c.activeLoans(in.Value)
+	if err != nil {
+		return nil, err
+	}
+	if loans > 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "Patron %s has %d copies on loan.", in.Value, loans)
+	}
+	holds, err := c.holds(func(h *pb.Hold) bool { return h.PatronId == in.Value })
+	if err != nil {
+		return nil, err
+	}
+	for _, hold := range holds {
+		if err := c.cancelHold(hold); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.delete(patronsCollection, "Patron", in.Value); err != nil {
+		return nil, err
+	}
+	return patron, status.New(codes.OK, "").Err()
+}
+
+func (c *circulationService) ListPatrons(in *pb.ListPatronsRequest, stream pb.Circulation_ListPatronsServer) error {
+	query := strings.ToLower(in.Query)
+	return c.scan(patronsCollection, "Patron", func() proto.Message { return &pb.Patron{} }, func(m proto.Message) error {
+		patron := m.(*pb.Patron)
+		if !strings.Contains(strings.ToLower(patron.Name), query) {
+			return nil
+		}
+		return stream.Send(patron)
+	})
+}
+
+func (c *circulationService) Checkout(ctx context.Context, in *pb.CheckoutRequest) (*pb.Loan, error) {
+	c.books.mu.Lock()
+	defer c.books.mu.Unlock()
+	copy, err := c.getCopy(in.Barcode)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := c.getPatron(in.PatronId); err != nil {
+		return nil, err
+	}
+
+	// The hold the loan fulfils: the one the copy is kept for, or else
+	// the patron's hold on the book, whose own copy, if one is kept for
+	// it, goes to the next patron.
+	var fulfilled *pb.Hold
+	switch copy.Status {
+	case pb.CopyStatus_ON_LOAN:
+		return nil, status.Errorf(codes.FailedPrecondition, "Copy %s is already on loan.", in.Barcode)
+	case pb.CopyStatus_ON_HOLD_SHELF:
+		if fulfilled, err = c.getHold(copy.HoldId); err != nil {
+			return nil, err
+		}
+		if fulfilled.PatronId != in.PatronId {
+			return nil, status.Errorf(codes.FailedPrecondition, "Copy %s is kept for another patron.", in.Barcode)
+		}
+	default:
+		held, err := c.holds(func(h *pb.Hold) bool { return h.BookId == copy.BookId && h.PatronId == in.PatronId })
+		if err != nil {
+			return nil, err
+		}
+		if len(held) > 0 {
+			fulfilled = held[0]
+		}
+	}
+
+	loans, err := c.activeLoans(in.PatronId)
+	if err != nil {
+		return nil, err
+	}
+	now := c.now()
+	due, err := c.policy.Checkout(loans, now)
+	if err == circulation.ErrMaxLoans {
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"Patron %s already has %d copies on loan, the most allowed.", in.PatronId, loans)
+	}
+	id, err := newID("Loan")
+	if err != nil {
+		return nil, err
+	}
+	loan := &pb.Loan{
+		Id:         id,
+		Barcode:    copy.Barcode,
+		BookId:     copy.BookId,
+		PatronId:   in.PatronId,
+		CheckedOut: timestamppb.New(now),
+		Due:        timestamppb.New(due),
+	}
+	if err := c.put(loansCollection, "Loan", loan.Id, loan); err != nil {
+		return nil, err
+	}
+	keptFor := copy.HoldId
+	copy.Status = pb.CopyStatus_ON_LOAN
+	copy.LoanId, copy.HoldId = loan.Id, ""
+	if err := c.put(copiesCollection, "Copy", copy.Barcode, copy); err != nil {
+		return nil, err
+	}
+	if fulfilled != nil && fulfilled.Id == keptFor {
+		err = c.delete(holdsCollection, "Hold", fulfilled.Id)
+	} else if fulfilled != nil {
+		err = c.cancelHold(fulfilled)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return loan, status.New(codes.OK, "").Err()
+}
+
+func (c *circulationService) ReturnCopy(ctx context.Context, in *pb.Barcode) (*pb.ReturnResponse, error) {
+	c.books.mu.Lock()
+	defer c.books.mu.Unlock()
+	copy, err := c.getCopy(in.Value)
+	if err != nil {
+		return nil, err
+	}
+	if copy.Status != pb.CopyStatus_ON_LOAN {
+		return nil, status.Errorf(codes.FailedPrecondition, "Copy %s is not on loan.", in.Value)
+	}
+	loan, err := c.getLoan(copy.LoanId)
+	if err != nil {
+		return nil, err
+	}
+	loan.Returned = timestamppb.New(c.now())
+	if err := c.put(loansCollection, "Loan", loan.Id, loan); err != nil {
+		return nil, err
+	}
+	hold, err := c.shelve(copy)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ReturnResponse{Loan: loan, Hold: hold}, status.New(codes.OK, "").Err()
+}
+
+func (c *circulationService) Renew(ctx context.Context, in *pb.LoanID) (*pb.Loan, error) {
+	c.books.mu.Lock()
+	defer c.books.mu.Unlock()
+	loan, err := c.getLoan(in.Value)
+	if err != nil {
+		return nil, err
+	}
+	if loan.Returned != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "Loan %s has ended.", in.Value)
+	}
+	queue, err := c.waiting(loan.BookId)
+	if err != nil {
+		return nil, err
+	}
+	due, err := c.policy.Renew(int(loan.Renewals), len(queue), loan.Due.AsTime(), c.now())
+	switch err {
+	case circulation.ErrMaxRenewals:
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"Loan %s has been renewed %d times, the most allowed.", in.Value, loan.Renewals)
+	case circulation.ErrHoldsWaiting:
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"Loan %s cannot be renewed: %d patrons are waiting for Book %s.", in.Value, len(queue), loan.BookId)
+	}
+	loan.Due = timestamppb.New(due)
+	loan.Renewals++
+	if err := c.put(loansCollection, "Loan", loan.Id, loan); err != nil {
+		return nil, err
+	}
+	return loan, status.New(codes.OK, "").Err()
+}
+
+func (c *circulationService) ListLoans(in *pb.ListLoansRequest, stream pb.Circulation_ListLoansServer) error {
+	return c.scanLoans(func(loan *pb.Loan) error {
+		if (in.PatronId != "" && loan.PatronId != in.PatronId) || (loan.Returned != nil && !in.IncludeReturned) {
+			return nil
+		}
+		return stream.Send(loan)
+	})
+}
+
+func (c *circulationService) ListOverdue(in *pb.ListOverdueRequest, stream pb.Circulation_ListOverdueServer) error {
+	now := c.now()
+	var overdue []*pb.Loan
+	err := c.scanLoans(func(loan *pb.Loan) error {
+		if loan.Returned == nil && circulation.Overdue(loan.Due.AsTime(), now) {
+			overdue = append(overdue, loan)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(overdue, func(i, j int) bool {
+		return overdue[i].Due.AsTime().Before(overdue[j].Due.AsTime())
+	})
+	for _, loan := range overdue {
+		if err := stream.Send(loan); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *circulationService) PlaceHold(ctx context.Context, in *pb.PlaceHoldRequest) (*pb.Hold, error) {
+	c.books.mu.Lock()
+	defer c.books.mu.Unlock()
+	if _, err := c.books.getBook(ctx, in.BookId); err != nil {
+		return nil, err
+	}
+	if _, err := c.getPatron(in.PatronId); err != nil {
+		return nil, err
+	}
+	copies := 0
+	err := c.scanCopies(func(copy *pb.Copy) error {
+		if copy.BookId != in.BookId {
+			return nil
+		}
+		copies++
+		if copy.Status == pb.CopyStatus_ON_SHELF {
+			return status.Errorf(codes.FailedPrecondition, "Copy %s of Book %s is on the shelf.", copy.Barcode, in.BookId)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if copies == 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "Book %s has no copies.", in.BookId)
+	}
+	held, err := c.holds(func(h *pb.Hold) bool { return h.BookId == in.BookId && h.PatronId == in.PatronId })
+	if err != nil {
+		return nil, err
+	}
+	if len(held) > 0 {
+		return nil, status.Errorf(codes.AlreadyExists, "Patron %s already holds Book %s.", in.PatronId, in.BookId)
+	}
+	id, err := newID("Hold")
+	if err != nil {
+		return nil, err
+	}
+	hold := &pb.Hold{
+		Id:       id,
+		BookId:   in.BookId,
+		PatronId: in.PatronId,
+		Placed:   timestamppb.New(c.now()),
+		Status:   pb.HoldStatus_WAITING,
+	}
+	if err := c.put(holdsCollection, "Hold", hold.Id, hold); err != nil {
+		return nil, err
+	}
+	return hold, status.New(codes.OK, "").Err()
+}
+
+func (c *circulationService) CancelHold(ctx context.Context, in *pb.HoldID) (*pb.Hold, error) {
+	c.books.mu.Lock()
+	defer c.books.mu.Unlock()
+	hold, err := c.getHold(in.Value)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.cancelHold(hold); err != nil {
+		return nil, err
+	}
+	return hold, status.New(codes.OK, "").Err()
+}
+
+func (c *circulationService) ListHolds(in *pb.ListHoldsRequest, stream pb.Circulation_ListHoldsServer) error {
+	holds, err := c.holds(func(h *pb.Hold) bool {
+		return (in.BookId == "" || h.BookId == in.BookId) && (in.PatronId == "" || h.PatronId == in.PatronId)
+	})
+	if err != nil {
+		return err
+	}
+	for _, hold := range holds {
+		if err := stream.Send(hold); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// countCopies counts the copies of the book with id.
+func (s *server) countCopies(id string) (int, error) {
+	n := 0
+	err := s.store.Scan(copiesCollection, func(_ string, value []byte) error {
+		copy := &pb.Copy{}
+		if err := proto.Unmarshal(value, copy); err != nil {
+			return status.Errorf(codes.DataLoss, "Copy is corrupt: %v", err)
+		}
+		if copy.BookId == id {
+			n++
+		}
+		return nil
+	})
+	if _, ok := status.FromError(err); !ok {
+		return 0, status.Errorf(codes.Internal, "Error while listing copies: %v", err)
+	}
+	return n, err
+}
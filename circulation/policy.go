@@ -0,0 +1,87 @@
+// Package circulation holds the lending rules of a library: how long
+// loans last, how many a patron may have, how often they can be renewed
+// and how long a copy waits on the hold shelf. The rules are plain
+// functions of the state they judge, kept apart from the service storing
+// that state.
+package circulation
+
+import (
+	"errors"
+	"time"
+)
+
+// The default lending rules.
+const (
+	DefaultLoanPeriod  = 21 * 24 * time.Hour
+	DefaultMaxLoans    = 5
+	DefaultMaxRenewals = 2
+	DefaultHoldPeriod  = 7 * 24 * time.Hour
+)
+
+var (
+	// ErrMaxLoans is returned when a patron already has the most loans
+	// allowed.
+	ErrMaxLoans = errors.New("circulation: patron has the most loans allowed")
+	// ErrMaxRenewals is returned when a loan has been renewed the most
+	// times allowed.
+	ErrMaxRenewals = errors.New("circulation: loan has been renewed the most times allowed")
+	// ErrHoldsWaiting is returned when a loan cannot be renewed because
+	// other patrons are waiting for the book.
+	ErrHoldsWaiting = errors.New("circulation: other patrons are waiting for the book")
+)
+
+// Policy is the set of lending rules.
+type Policy struct {
+	// LoanPeriod is how long a loan, or a renewal, lasts.
+	LoanPeriod time.Duration
+	// MaxLoans is the most copies a patron may have on loan at once.
+	MaxLoans int
+	// MaxRenewals is the most times a loan may be renewed.
+	MaxRenewals int
+	// HoldPeriod is how long a copy is kept on the hold shelf for the
+	// patron of a hold.
+	HoldPeriod time.Duration
+}
+
+// Checkout returns the due date of a loan made at now to a patron who
+// already has loans copies on loan, or ErrMaxLoans.
+func (p Policy) Checkout(loans int, now time.Time) (time.Time, error) {
+	if loans >= p.MaxLoans {
+		return time.Time{}, ErrMaxLoans
+	}
+	return now.Add(p.LoanPeriod), nil
+}
+
+// Renew returns the due date of a loan due at due and renewed renewals
+// times so far when it is renewed at now, with waiting patrons queued for
+// the book. The loan then lasts a loan period from now, or stays due at
+// due if that is later.
+func (p Policy) Renew(renewals, waiting int, due, now time.Time) (time.Time, error) {
+	if renewals >= p.MaxRenewals {
+		return time.Time{}, ErrMaxRenewals
+	}
+	if waiting > 0 {
+		return time.Time{}, ErrHoldsWaiting
+	}
+	next := now.Add(p.LoanPeriod)
+	if next.Before(due) {
+		return due, nil
+	}
+	return next, nil
+}
+
+// Overdue reports whether a loan due at due is overdue at now.
+func Overdue(due, now time.Time) bool {
+	return now.After(due)
+}
+
+// HoldExpires returns when a hold whose copy was put on the hold shelf at
+// ready expires unless the patron borrows the copy.
+func (p Policy) HoldExpires(ready time.Time) time.Time {
+	return ready.Add(p.HoldPeriod)
+}
+
+// Expired reports whether a hold expiring at expires has expired at now.
+func Expired(expires, now time.Time) bool {
+	return !now.Before(expires)
+}
@@ -0,0 +1,113 @@
+package circulation
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var (
+	policy = Policy{
+		LoanPeriod:  DefaultLoanPeriod,
+		MaxLoans:    DefaultMaxLoans,
+		MaxRenewals: DefaultMaxRenewals,
+		HoldPeriod:  DefaultHoldPeriod,
+	}
+	now = time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)
+	day = 24 * time.Hour
+)
+
+func TestCheckout(t *testing.T) {
+	tests := []struct {
+		loans   int
+		wantDue time.Time
+		wantErr error
+	}{
+		{loans: 0, wantDue: now.Add(21 * day)},
+		{loans: DefaultMaxLoans - 1, wantDue: now.Add(21 * day)},
+		{loans: DefaultMaxLoans, wantErr: ErrMaxLoans},
+		{loans: DefaultMaxLoans + 1, wantErr: ErrMaxLoans},
+	}
+	for _, tt := range tests {
+		due, err := policy.Checkout(tt.loans, now)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("Checkout(%d) error = %v, want %v", tt.loans, err, tt.wantErr)
+			continue
+		}
+		if !due.Equal(tt.wantDue) {
+			t.Errorf("Checkout(%d) = %v, want %v", tt.loans, due, tt.wantDue)
+		}
+	}
+}
+
+func TestRenew(t *testing.T) {
+	due := now.Add(3 * day)
+	tests := []struct {
+		name     string
+		renewals int
+		waiting  int
+		due      time.Time
+		wantDue  time.Time
+		wantErr  error
+	}{
+		{name: "first renewal", due: due, wantDue: now.Add(21 * day)},
+		{name: "last renewal", renewals: DefaultMaxRenewals - 1, due: due, wantDue: now.Add(21 * day)},
+		{name: "overdue", due: now.Add(-2 * day), wantDue: now.Add(21 * day)},
+		{name: "due later than a loan period", due: now.Add(30 * day), wantDue: now.Add(30 * day)},
+		{name: "renewed too often", renewals: DefaultMaxRenewals, due: due, wantErr: ErrMaxRenewals},
+		{name: "holds waiting", waiting: 1, due: due, wantErr: ErrHoldsWaiting},
+		{name: "renewed too often with holds waiting", renewals: DefaultMaxRenewals, waiting: 2, due: due, wantErr: ErrMaxRenewals},
+	}
+	for _, tt := range tests {
+		got, err := policy.Renew(tt.renewals, tt.waiting, tt.due, now)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: Renew error = %v, want %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if !got.Equal(tt.wantDue) {
+			t.Errorf("%s: Renew = %v, want %v", tt.name, got, tt.wantDue)
+		}
+	}
+}
+
+func TestOverdue(t *testing.T) {
+	due := now.Add(21 * day)
+	tests := []struct {
+		at   time.Time
+		want bool
+	}{
+		{at: now, want: false},
+		{at: due.Add(-time.Nanosecond), want: false},
+		{at: due, want: false},
+		{at: due.Add(time.Nanosecond), want: true},
+	}
+	for _, tt := range tests {
+		if got := Overdue(due, tt.at); got != tt.want {
+			t.Errorf("Overdue(%v, %v) = %v, want %v", due, tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestHoldExpires(t *testing.T) {
+	if got, want := policy.HoldExpires(now), now.Add(7*day); !got.Equal(want) {
+		t.Errorf("HoldExpires(%v) = %v, want %v", now, got, want)
+	}
+}
+
+func TestExpired(t *testing.T) {
+	expires := policy.HoldExpires(now)
+	tests := []struct {
+		at   time.Time
+		want bool
+	}{
+		{at: now, want: false},
+		{at: expires.Add(-time.Nanosecond), want: false},
+		{at: expires, want: true},
+		{at: expires.Add(day), want: true},
+	}
+	for _, tt := range tests {
+		if got := Expired(expires, tt.at); got != tt.want {
+			t.Errorf("Expired(%v, %v) = %v, want %v", expires, tt.at, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+// Package citation renders books as citations, in the BibTeX, RIS and
+// CSL-JSON interchange formats and in the APA, MLA and Chicago text
+// styles.
+package citation
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+)
+
+// ContentTypes holds the media type of each style's output.
+var ContentTypes = map[pb.CitationStyle]string{
+	pb.CitationStyle_BIBTEX:   "application/x-bibtex",
+	pb.CitationStyle_RIS:      "application/x-research-info-systems",
+	pb.CitationStyle_CSL_JSON: "application/vnd.citationstyles.csl+json",
+	pb.CitationStyle_APA:      "text/plain",
+	pb.CitationStyle_MLA:      "text/plain",
+	pb.CitationStyle_CHICAGO:  "text/plain",
+}
+
+// Format renders b in style. Text styles are plain text, without the
+// italics the styles call for on titles.
+func Format(b *pb.Book, style pb.CitationStyle) (string, error) {
+	switch style {
+	case pb.CitationStyle_BIBTEX:
+		return BibTeX(b), nil
+	case pb.CitationStyle_RIS:
+		return RIS(b), nil
+	case pb.CitationStyle_CSL_JSON:
+		return CSLJSON(b)
+	case pb.CitationStyle_APA:
+		return APA(b), nil
+	case pb.CitationStyle_MLA:
+		return MLA(b), nil
+	case pb.CitationStyle_CHICAGO:
+		return Chicago(b), nil
+	}
+	return "", fmt.Errorf("citation: unsupported style %v", style)
+}
+
+// Name is a personal name split the way citation styles need it.
+type Name struct {
+	Given  string
+	Family string
+}
+
+// particles are the lower case name prefixes that belong to the family
+// name, as in "Ludwig van Beethoven".
+var particles = map[string]bool{
+	"van": true, "von": true, "de": true, "der": true, "den": true, "da": true,
+	"del": true, "della": true, "di": true, "du": true, "la": true, "le": true,
+}
+
+var authorSeparator = regexp.MustCompile(`\s*(?:;|,|&|\band\b)\s*`)
+
+// Authors splits the Author field of a book, which holds one name or
+// several separated by commas, semicolons or "and", into names written
+// given name first.
+func Authors(author string) []Name {
+	var names []Name
+	for _, s := range authorSeparator.Split(author, -1) {
+		words := strings.Fields(s)
+		if len(words) == 0 {
+			continue
+		}
+		family := len(words) - 1
+		for family > 0 && particles[words[family-1]] {
+			family--
+		}
+		names = append(names, Name{
+			Given:  strings.Join(words[:family], " "),
+			Family: strings.Join(words[family:], " "),
+		})
+	}
+	return names
+}
+
+// Inverted writes n family name first, as in "Silberschatz, Abraham".
+func (n Name) Inverted() string {
+	if n.Given == "" {
+		return n.Family
+	}
+	return n.Family + ", " + n.Given
+}
+
+// String writes n given name first.
+func (n Name) String() string {
+	return strings.TrimSpace(n.Given + " " + n.Family)
+}
+
+// Initials abbreviates the given names of n, as in "P. B.".
+func (n Name) Initials() string {
+	var initials []string
+	for _, part := range strings.Fields(n.Given) {
+		var hyphenated []string
+		for _, p := range strings.Split(part, "-") {
+			if r := []rune(p); len(r) > 0 {
+				hyphenated = append(hyphenated, string(r[0])+".")
+			}
+		}
+		initials = append(initials, strings.Join(hyphenated, "-"))
+	}
+	return strings.Join(initials, " ")
+}
+
+var editionNumber = regexp.MustCompile(`^(\d+)(?:st|nd|rd|th)?\b`)
+
+// edition returns the edition of b the way styles write it before "ed.",
+// as in "9th", or "" for a first edition, which is not cited.
+func edition(b *pb.Book) string {
+	e := strings.TrimSpace(b.Edition)
+	e = strings.TrimSuffix(strings.TrimSuffix(e, "."), " ed")
+	e = strings.TrimSuffix(e, " edition")
+	if m := editionNumber.FindStringSubmatch(e); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		if n <= 1 {
+			return ""
+		}
+		return ordinal(n)
+	}
+	if strings.EqualFold(e, "first") {
+		return ""
+	}
+	return e
+}
+
+func ordinal(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+	return strconv.Itoa(n) + suffix
+}
+
+// year returns the copyright year of b, or "n.d." ("no date") for books
+// without one.
+func year(b *pb.Book) string {
+	if y := strings.TrimSpace(b.Copyright); y != "" {
+		return y
+	}
+	return "n.d."
+}
+
+// sentence ends s with a full stop unless it already ends in punctuation.
+func sentence(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return ""
+	}
+	if strings.ContainsRune(".?!", rune(s[len(s)-1])) {
+		return s
+	}
+	return s + "."
+}
+
+// titleCase capitalizes the principal words of a title, as MLA and
+// Chicago ask, leaving words with capitals inside them as they are.
+func titleCase(title string) string {
+	minor := map[string]bool{
+		"a": true, "an": true, "the": true, "and": true, "but": true, "or": true,
+		"nor": true, "for": true, "so": true, "yet": true, "as": true, "at": true,
+		"by": true, "in": true, "of": true, "on": true, "to": true, "up": true,
+		"via": true, "with": true, "from": true, "into": true,
+	}
+	words := strings.Fields(title)
+	for i, w := range words {
+		afterColon := i > 0 && strings.HasSuffix(words[i-1], ":")
+		if i > 0 && i < len(words)-1 && !afterColon && minor[strings.ToLower(w)] {
+			words[i] = strings.ToLower(w)
+			continue
+		}
+		r := []rune(w)
+		if len(r) > 0 && unicode.IsLower(r[0]) && !strings.ContainsFunc(string(r[1:]), unicode.IsUpper) {
+			r[0] = unicode.ToUpper(r[0])
+			words[i] = string(r)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// capitalize writes the first letter of s in upper case.
+func capitalize(s string) string {
+	r := []rune(s)
+	if len(r) > 0 {
+		r[0] = unicode.ToUpper(r[0])
+	}
+	return string(r)
+}
+
+// languageCodes maps the language names BookInfo uses to ISO 639-1 codes.
+var languageCodes = map[string]string{
+	"ENGLISH": "en", "FRENCH": "fr", "GERMAN": "de", "SPANISH": "es",
+	"ITALIAN": "it", "PORTUGUESE": "pt", "DUTCH": "nl", "RUSSIAN": "ru",
+	"CHINESE": "zh", "JAPANESE": "ja", "ARABIC": "ar", "LATIN": "la",
+	"GREEK": "el", "POLISH": "pl", "SWEDISH": "sv",
+}
+
+// languageName writes the language of b with only its first letter in
+// upper case, as in "English".
+func languageName(b *pb.Book) string {
+	return capitalize(strings.ToLower(strings.TrimSpace(b.Language)))
+}
@@ -0,0 +1,202 @@
+package citation
+
+import (
+	"encoding/csv"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// edgeBooks exercise what the sample books do not: several authors, name
+// particles, first editions, missing fields and characters BibTeX must
+// escape.
+var edgeBooks = []*pb.Book{
+	{
+		Id:        "e1",
+		Title:     "the art of computer programming: fundamental algorithms",
+		Edition:   "1st ed.",
+		Copyright: "1968",
+		Language:  "ENGLISH",
+		Pages:     "634",
+		Author:    "Donald E. Knuth",
+		Publisher: "Addison-Wesley",
+		Isbn:      "0201038013",
+	},
+	{
+		Id:        "e2",
+		Title:     "Structure and Interpretation of Computer Programs",
+		Edition:   "second edition",
+		Copyright: "1996",
+		Author:    "Harold Abelson, Gerald Jay Sussman and Julie Sussman",
+		Publisher: "MIT Press",
+	},
+	{
+		Id:        "e3",
+		Title:     "Sinfonien & Ouvertüren {Urtext}",
+		Language:  "GERMAN",
+		Author:    "Ludwig van Beethoven; Jean-Pierre de la Tour",
+		Publisher: "Bärenreiter",
+	},
+	{
+		Id:    "e4",
+		Title: "Anonymous Pamphlet",
+	},
+}
+
+// csvBooks reads the sample books of the client.
+func csvBooks(t *testing.T) []*pb.Book {
+	t.Helper()
+	f, err := os.Open("../client/books.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var books []*pb.Book
+	for _, row := range rows[1:] {
+		books = append(books, &pb.Book{
+			Id:        row[0],
+			Title:     row[1],
+			Edition:   row[2],
+			Copyright: row[3],
+			Language:  row[4],
+			Pages:     row[5],
+			Author:    row[6],
+			Publisher: row[7],
+		})
+	}
+	return books
+}
+
+// TestFormatGolden renders the sample books and edgeBooks in every style
+// and compares them with testdata/<style>.golden. Run with -update after
+// a deliberate change to a style.
+func TestFormatGolden(t *testing.T) {
+	books := append(csvBooks(t), edgeBooks...)
+	for style := range ContentTypes {
+		name := strings.ToLower(style.String())
+		var got strings.Builder
+		for _, b := range books {
+			citation, err := Format(b, style)
+			if err != nil {
+				t.Fatalf("Format(%s, %v): %v", b.Id, style, err)
+			}
+			got.WriteString(citation)
+			if !strings.HasSuffix(citation, "\n") {
+				got.WriteString("\n")
+			}
+			got.WriteString("\n")
+		}
+		golden := filepath.Join("testdata", name+".golden")
+		if *update {
+			if err := os.WriteFile(golden, []byte(got.String()), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		want, err := os.ReadFile(golden)
+		if err != nil {
+			t.Fatalf("%v; run with -update to create it", err)
+		}
+		if got.String() != string(want) {
+			t.Errorf("%v citations differ from %s:\ngot:\n%s\nwant:\n%s", style, golden, got.String(), want)
+		}
+	}
+}
+
+func TestFormatUnsupported(t *testing.T) {
+	if _, err := Format(edgeBooks[0], pb.CitationStyle_CITATION_STYLE_UNSPECIFIED); err == nil {
+		t.Error("Format with no style succeeded, want an error")
+	}
+}
+
+func TestAuthors(t *testing.T) {
+	tests := []struct {
+		author string
+		want   []Name
+	}{
+		{"Abraham Silberschatz", []Name{{"Abraham", "Silberschatz"}}},
+		{"Plato", []Name{{"", "Plato"}}},
+		{"Harold Abelson, Gerald Jay Sussman and Julie Sussman", []Name{{"Harold", "Abelson"}, {"Gerald Jay", "Sussman"}, {"Julie", "Sussman"}}},
+		{"Ludwig van Beethoven; Jean-Pierre de la Tour", []Name{{"Ludwig", "van Beethoven"}, {"Jean-Pierre", "de la Tour"}}},
+		{"Kernighan & Ritchie", []Name{{"", "Kernighan"}, {"", "Ritchie"}}},
+		{" ; ", nil},
+	}
+	for _, tt := range tests {
+		got := Authors(tt.author)
+		if len(got) != len(tt.want) {
+			t.Errorf("Authors(%q) = %q, want %q", tt.author, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("Authors(%q) = %q, want %q", tt.author, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestNameForms(t *testing.T) {
+	tests := []struct {
+		name                        Name
+		inverted, str, initialsWant string
+	}{
+		{Name{"Andrew S.", "Tanenbaum"}, "Tanenbaum, Andrew S.", "Andrew S. Tanenbaum", "A. S."},
+		{Name{"Jean-Pierre", "de la Tour"}, "de la Tour, Jean-Pierre", "Jean-Pierre de la Tour", "J.-P."},
+		{Name{"", "Plato"}, "Plato", "Plato", ""},
+	}
+	for _, tt := range tests {
+		if got := tt.name.Inverted(); got != tt.inverted {
+			t.Errorf("%#v.Inverted() = %q, want %q", tt.name, got, tt.inverted)
+		}
+		if got := tt.name.String(); got != tt.str {
+			t.Errorf("%#v.String() = %q, want %q", tt.name, got, tt.str)
+		}
+		if got := tt.name.Initials(); got != tt.initialsWant {
+			t.Errorf("%#v.Initials() = %q, want %q", tt.name, got, tt.initialsWant)
+		}
+	}
+}
+
+func TestEdition(t *testing.T) {
+	tests := []struct{ edition, want string }{
+		{"", ""},
+		{"1st", ""},
+		{"First", ""},
+		{"5th", "5th"},
+		{"2", "2nd"},
+		{"3rd ed.", "3rd"},
+		{"11 edition", "11th"},
+		{"22nd", "22nd"},
+		{"Rev. ed.", "Rev."},
+	}
+	for _, tt := range tests {
+		if got := edition(&pb.Book{Edition: tt.edition}); got != tt.want {
+			t.Errorf("edition(%q) = %q, want %q", tt.edition, got, tt.want)
+		}
+	}
+}
+
+func TestTitleCase(t *testing.T) {
+	tests := []struct{ title, want string }{
+		{"the art of computer programming", "The Art of Computer Programming"},
+		{"a tale of two cities: the end of an era", "A Tale of Two Cities: The End of an Era"},
+		{"learning iOS with the pros", "Learning iOS with the Pros"},
+		{"what it is for", "What It Is For"},
+	}
+	for _, tt := range tests {
+		if got := titleCase(tt.title); got != tt.want {
+			t.Errorf("titleCase(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,271 @@
+package citation
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+)
+
+// APA renders b as an APA 7th edition reference list entry:
+//
+//	Silberschatz, A., Galvin, P. B., & Gagne, G. (2012). Operating System Concepts (9th ed.). Wiley.
+//
+// The title is written as stored, only capitalizing its first letter:
+// APA's sentence case cannot be derived reliably, since proper nouns keep
+// their capitals.
+func APA(b *pb.Book) string {
+	authors := Authors(b.Author)
+	names := make([]string, len(authors))
+	for i, n := range authors {
+		names[i] = n.Family
+		if initials := n.Initials(); initials != "" {
+			names[i] += ", " + initials
+		}
+	}
+	var s strings.Builder
+	switch {
+	case len(names) == 0:
+	case len(names) == 1:
+		s.WriteString(names[0])
+	case len(names) <= 20:
+		s.WriteString(strings.Join(names[:len(names)-1], ", ") + ", & " + names[len(names)-1])
+	default:
+		// APA lists the first 19 authors, an ellipsis and the last.
+		s.WriteString(strings.Join(names[:19], ", ") + ", . . . " + names[len(names)-1])
+	}
+	if s.Len() > 0 && !strings.HasSuffix(s.String(), ".") {
+		s.WriteString(".")
+	}
+	if s.Len() > 0 {
+		s.WriteString(" ")
+	}
+	fmt.Fprintf(&s, "(%s). ", year(b))
+	s.WriteString(capitalize(strings.TrimSpace(b.Title)))
+	if e := edition(b); e != "" {
+		fmt.Fprintf(&s, " (%s ed.)", e)
+	}
+	s.WriteString(".")
+	if p := strings.TrimSpace(b.Publisher); p != "" {
+		s.WriteString(" " + sentence(p))
+	}
+	return s.String()
+}
+
+// MLA renders b as an MLA 9th edition works cited entry:
+//
+//	Silberschatz, Abraham, et al. Operating System Concepts. 9th ed., Wiley, 2012.
+func MLA(b *pb.Book) string {
+	authors := Authors(b.Author)
+	var s strings.Builder
+	switch len(authors) {
+	case 0:
+	case 1:
+		s.WriteString(sentence(authors[0].Inverted()))
+	case 2:
+		s.WriteString(sentence(authors[0].Inverted() + ", and " + authors[1].String()))
+	default:
+		s.WriteString(authors[0].Inverted() + ", et al.")
+	}
+	if s.Len() > 0 {
+		s.WriteString(" ")
+	}
+	s.WriteString(sentence(titleCase(b.Title)))
+
+	var details []string
+	if e := edition(b); e != "" {
+		details = append(details, e+" ed.")
+	}
+	if p := strings.TrimSpace(b.Publisher); p != "" {
+		details = append(details, p)
+	}
+	if y := strings.TrimSpace(b.Copyright); y != "" {
+		details = append(details, y)
+	}
+	if len(details) > 0 {
+		s.WriteString(" " + sentence(strings.Join(details, ", ")))
+	}
+	return s.String()
+}
+
+// Chicago renders b as a Chicago 17th edition bibliography entry:
+//
+//	Silberschatz, Abraham, Peter Baer Galvin, and Greg Gagne. Operating System Concepts. 9th ed. Wiley, 2012.
+func Chicago(b *pb.Book) string {
+	authors := Authors(b.Author)
+	var s strings.Builder
+	if len(authors) > 0 {
+		names := []string{authors[0].Inverted()}
+		// Chicago lists up to ten authors, and otherwise the first seven.
+		rest := authors[1:]
+		if len(authors) > 10 {
+			rest = authors[1:7]
+		}
+		for _, n := range rest {
+			names = append(names, n.String())
+		}
+		switch {
+		case len(authors) > 10:
+			s.WriteString(strings.Join(names, ", ") + ", et al.")
+		case len(names) == 2:
+			s.WriteString(sentence(names[0] + ", and " + names[1]))
+		case len(names) > 2:
+			s.WriteString(sentence(strings.Join(names[:len(names)-1], ", ") + ", and " + names[len(names)-1]))
+		default:
+			s.WriteString(sentence(names[0]))
+		}
+		s.WriteString(" ")
+	}
+	s.WriteString(sentence(titleCase(b.Title)))
+	if e := edition(b); e != "" {
+		s.WriteString(" " + e + " ed.")
+	}
+	var imprint []string
+	if p := strings.TrimSpace(b.Publisher); p != "" {
+		imprint = append(imprint, p)
+	}
+	imprint = append(imprint, year(b))
+	s.WriteString(" " + sentence(strings.Join(imprint, ", ")))
+	return s.String()
+}
+
+// BibTeX renders b as a BibTeX @book entry keyed by the first author's
+// family name, the year and the first significant word of the title.
+func BibTeX(b *pb.Book) string {
+	authors := Authors(b.Author)
+	names := make([]string, len(authors))
+	for i, n := range authors {
+		names[i] = n.Inverted()
+	}
+	fields := [][2]string{
+		{"author", strings.Join(names, " and ")},
+		{"title", b.Title},
+		{"edition", edition(b)},
+		{"publisher", b.Publisher},
+		{"year", b.Copyright},
+		{"language", strings.ToLower(b.Language)},
+		{"pagetotal", b.Pages},
+		{"isbn", b.Isbn},
+	}
+	var s strings.Builder
+	fmt.Fprintf(&s, "@book{%s,\n", bibKey(b, authors))
+	for _, f := range fields {
+		if v := strings.TrimSpace(f[1]); v != "" {
+			fmt.Fprintf(&s, "  %-9s = {%s},\n", f[0], escapeBibTeX(v))
+		}
+	}
+	s.WriteString("}\n")
+	return s.String()
+}
+
+func bibKey(b *pb.Book, authors []Name) string {
+	var key strings.Builder
+	keep := func(s string) {
+		for _, r := range strings.ToLower(s) {
+			if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+				key.WriteRune(r)
+			}
+		}
+	}
+	if len(authors) > 0 {
+		keep(authors[0].Family)
+	}
+	keep(b.Copyright)
+	for _, w := range strings.Fields(b.Title) {
+		switch strings.ToLower(w) {
+		case "a", "an", "the":
+			continue
+		}
+		keep(w)
+		break
+	}
+	if key.Len() == 0 {
+		return "book" + b.Id
+	}
+	return key.String()
+}
+
+// escapeBibTeX escapes the characters LaTeX gives special meanings to.
+func escapeBibTeX(s string) string {
+	return strings.NewReplacer(
+		`\`, `\textbackslash{}`,
+		`{`, `\{`, `}`, `\}`,
+		`&`, `\&`, `%`, `\%`, `$`, `\$`, `#`, `\#`, `_`, `\_`,
+		`~`, `\textasciitilde{}`, `^`, `\textasciicircum{}`,
+	).Replace(s)
+}
+
+// RIS renders b as a RIS record of type BOOK.
+func RIS(b *pb.Book) string {
+	var s strings.Builder
+	tag := func(t, v string) {
+		if v = strings.TrimSpace(v); v != "" {
+			fmt.Fprintf(&s, "%s  - %s\r\n", t, v)
+		}
+	}
+	tag("TY", "BOOK")
+	for _, n := range Authors(b.Author) {
+		tag("AU", n.Inverted())
+	}
+	tag("TI", b.Title)
+	tag("ET", edition(b))
+	tag("PB", b.Publisher)
+	tag("PY", b.Copyright)
+	tag("SN", b.Isbn)
+	tag("LA", languageName(b))
+	tag("SP", b.Pages)
+	tag("ID", b.Id)
+	s.WriteString("ER  - \r\n")
+	return s.String()
+}
+
+type cslName struct {
+	Family string `json:"family"`
+	Given  string `json:"given,omitempty"`
+}
+
+type cslDate struct {
+	DateParts [][]int `json:"date-parts"`
+}
+
+type cslItem struct {
+	ID            string    `json:"id"`
+	Type          string    `json:"type"`
+	Title         string    `json:"title,omitempty"`
+	Author        []cslName `json:"author,omitempty"`
+	Edition       string    `json:"edition,omitempty"`
+	Publisher     string    `json:"publisher,omitempty"`
+	Issued        *cslDate  `json:"issued,omitempty"`
+	ISBN          string    `json:"ISBN,omitempty"`
+	Language      string    `json:"language,omitempty"`
+	NumberOfPages string    `json:"number-of-pages,omitempty"`
+}
+
+// CSLJSON renders b as a CSL-JSON item, the input citation processors
+// such as citeproc-js and Zotero read.
+func CSLJSON(b *pb.Book) (string, error) {
+	item := cslItem{
+		ID:            b.Id,
+		Type:          "book",
+		Title:         b.Title,
+		Edition:       edition(b),
+		Publisher:     b.Publisher,
+		ISBN:          b.Isbn,
+		Language:      languageCodes[strings.ToUpper(b.Language)],
+		NumberOfPages: b.Pages,
+	}
+	for _, n := range Authors(b.Author) {
+		item.Author = append(item.Author, cslName{Family: n.Family, Given: n.Given})
+	}
+	if y, err := strconv.Atoi(strings.TrimSpace(b.Copyright)); err == nil {
+		item.Issued = &cslDate{DateParts: [][]int{{y}}}
+	}
+	out, err := json.MarshalIndent(item, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
@@ -1,14 +1,29 @@
+// Command bookctl manages the books kept by a BookInfo server.
+//
+// Usage:
+//
+//	bookctl [flags] <command> [command flags] [arguments]
+//
+// Build it with "go build -o bookctl ./client".
 package main
 
 import (
 	"context"
-	"encoding/csv"
-	"log"
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
 	"os"
+	"sort"
 	"time"
 
+	"github.com/marcoc22/tutorial3/bookclient"
 	pb "github.com/marcoc22/tutorial3/booksapp"
+	"github.com/marcoc22/tutorial3/tracing"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
 type Book struct {
@@ -20,110 +35,239 @@ type Book struct {
 	Pages     string `json:"pages"`
 	Author    string `json:"author"`
 	Publisher string `json:"publisher"`
+	Isbn      string `json:"isbn"`
 }
 
-var books []Book
+func fromProto(b *pb.Book) Book {
+	return Book{
+		Id:        b.Id,
+		Title:     b.Title,
+		Edition:   b.Edition,
+		Copyright: b.Copyright,
+		Language:  b.Language,
+		Pages:     b.Pages,
+		Author:    b.Author,
+		Publisher: b.Publisher,
+		Isbn:      b.Isbn,
+	}
+}
 
-func checkError(message string, err error) {
-	if err != nil {
-		log.Fatal(message, err)
+func (b Book) toProto() *pb.Book {
+	return &pb.Book{
+		Id:        b.Id,
+		Title:     b.Title,
+		Edition:   b.Edition,
+		Copyright: b.Copyright,
+		Language:  b.Language,
+		Pages:     b.Pages,
+		Author:    b.Author,
+		Publisher: b.Publisher,
+		Isbn:      b.Isbn,
 	}
 }
 
-func readData(filePath string) {
-	file, err1 := os.Open(filePath)
-	checkError("Unable to read input file "+filePath, err1)
-	defer file.Close()
-
-	csvReader := csv.NewReader(file)
-	records, err2 := csvReader.ReadAll()
-	checkError("Unable to parse file as CSV for "+filePath, err2)
-	defer file.Close()
-
-	books = []Book{}
-
-	for _, record := range records {
-		book := Book{
-			Id:        record[0],
-			Title:     record[1],
-			Edition:   record[2],
-			Copyright: record[3],
-			Language:  record[4],
-			Pages:     record[5],
-			Author:    record[6],
-			Publisher: record[7]}
-		books = append(books, book)
-	}
-	file.Close()
+// options are the flags every command accepts.
+type options struct {
+	addr    string
+	timeout time.Duration
+	output  string
+	token   string
+	// insecureToken allows sending token over a connection without TLS.
+	insecureToken bool
+	tls           bool
+	caFile        string
+	// balancer is the policy spreading calls over the replicas addr
+	// resolves to.
+	balancer string
 }
 
-func main() {
-	address := os.Getenv("ADDRESS")
-	conn, err := grpc.Dial(address, grpc.WithInsecure())
-	if err != nil {
-		log.Fatalf("did not connect: %v", err)
-	}
-	defer conn.Close()
-	c := pb.NewBookInfoClient(conn)
-
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
-
-	r, err := c.AddBook(ctx, &pb.Book{
-		Id:        "1",
-		Title:     "Operating System Concepts",
-		Edition:   "9th",
-		Copyright: "2012",
-		Language:  "ENGLISH",
-		Pages:     "976",
-		Author:    "Abraham Silberschatz",
-		Publisher: "John Wiley & Sons"})
-	if err != nil {
-		log.Fatalf("Could not add book: %v", err)
+func (o *options) register(fs *flag.FlagSet) {
+	addr := os.Getenv("ADDRESS")
+	if addr == "" {
+		addr = "localhost:50051"
+	}
+	fs.StringVar(&o.addr, "addr", addr, "BookInfo server `address`, comma separated replica addresses or a gRPC target such as dns:///host:port (defaults to $ADDRESS)")
+	fs.StringVar(&o.balancer, "balancer", bookclient.RoundRobin, "how calls are spread over replicas: pick_first, round_robin or least_request")
+	fs.DurationVar(&o.timeout, "timeout", 5*time.Second, "deadline for each call to the server")
+	fs.StringVar(&o.output, "o", "table", "output `format`: table, json or yaml")
+	fs.StringVar(&o.token, "token", os.Getenv("TOKEN"), "bearer `token` to authenticate with (defaults to $TOKEN)")
+	fs.BoolVar(&o.insecureToken, "insecure-token", false, "send --token even without TLS, e.g. to a server on localhost")
+	fs.BoolVar(&o.tls, "tls", false, "connect with TLS, verifying the server against the system CAs")
+	fs.StringVar(&o.caFile, "ca-file", "", "connect with TLS, verifying the server against the CAs in `file`")
+}
+
+// dialOptions returns the credentials options selects.
+func (o *options) dialOptions() ([]grpc.DialOption, error) {
+	creds := insecure.NewCredentials()
+	if o.caFile != "" {
+		var err error
+		if creds, err = credentials.NewClientTLSFromFile(o.caFile, ""); err != nil {
+			return nil, err
+		}
+	} else if o.tls {
+		creds = credentials.NewTLS(&tls.Config{})
+	}
+	dialOpts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	if o.token != "" {
+		secure := o.caFile != "" || o.tls
+		if !secure && !o.insecureToken {
+			return nil, errors.New("--token needs --tls or --ca-file; pass --insecure-token to send it in plain text")
+		}
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(bearerToken{token: o.token, insecure: !secure}))
 	}
+	return dialOpts, nil
+}
 
-	log.Printf("Book ID: %s added successfully", r.Value)
+// bearerToken sends a token in the authorization metadata of every call.
+// It is only sent over TLS unless insecure, which --insecure-token sets
+// for servers on localhost.
+type bearerToken struct {
+	token    string
+	insecure bool
+}
 
-	book, err := c.GetBook(ctx, &pb.BookID{Value: r.Value})
-	if err != nil {
-		log.Fatalf("Could not get book: %v", err)
+func (t bearerToken) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + t.token}, nil
+}
+
+func (t bearerToken) RequireTransportSecurity() bool { return !t.insecure }
+
+// command is a bookctl subcommand.
+type command struct {
+	usage string
+	help  string
+	// flags registers the command's own flags, if any.
+	flags func(fs *flag.FlagSet)
+	run   func(ctx context.Context, env *env, args []string) error
+}
+
+// env is what commands run with. The client applies the timeout to unary
+// calls and retries them when that is safe; circulation calls are not
+// retried and take their deadline from call.
+type env struct {
+	options
+	client      *bookclient.Client
+	circulation pb.CirculationClient
+	fs          *flag.FlagSet
+}
+
+// call returns a context for a streaming call, whose deadline covers the
+// whole stream.
+func (e *env) call(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, e.timeout)
+}
+
+var commands = map[string]*command{}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: bookctl [flags] <command> [command flags] [arguments]")
+	fmt.Fprintln(os.Stderr, "\nCommands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-40s %s\n", commands[name].usage, commands[name].help)
 	}
-	log.Printf("Book: ", book.String())
+	fmt.Fprintln(os.Stderr, "\nFlags:")
+	flag.PrintDefaults()
+}
 
-	//Update edition and then update the book in the server.
-	book.Edition = "5th"
+// parseInterspersed parses args with fs, allowing flags to follow
+// positional arguments as in "bookctl update <id> --edition 5th", and
+// returns the positional arguments. A "--" ends flag parsing.
+func parseInterspersed(fs *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		fs.Parse(args)
+		rest := fs.Args()
+		if len(rest) == 0 {
+			return positional
+		}
+		if len(args) > len(rest) && args[len(args)-len(rest)-1] == "--" {
+			return append(positional, rest...)
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
 
-	u, erru := c.UpdateBook(ctx, book)
-	if erru != nil {
-		log.Fatalf("Could not update book: %v", erru)
+func main() {
+	var opts options
+	opts.register(flag.CommandLine)
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[flag.Arg(0)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "bookctl: unknown command %q\n", flag.Arg(0))
+		usage()
+		os.Exit(2)
 	}
 
-	log.Printf("Book ID: %s updated successfully", u.Id)
+	// Global flags may also be given after the command name.
+	global := make(map[string]string)
+	flag.Visit(func(f *flag.Flag) { global[f.Name] = f.Value.String() })
+	fs := flag.NewFlagSet("bookctl "+flag.Arg(0), flag.ExitOnError)
+	opts.register(fs)
+	for name, value := range global {
+		fs.Set(name, value)
+	}
+	if cmd.flags != nil {
+		cmd.flags(fs)
+	}
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: bookctl %s\n\n%s\n\nFlags:\n", cmd.usage, cmd.help)
+		fs.PrintDefaults()
+	}
+	args := parseInterspersed(fs, flag.Args()[1:])
+	if err := validFormat(opts.output); err != nil {
+		fmt.Fprintf(os.Stderr, "bookctl: %v\n", err)
+		os.Exit(2)
+	}
 
-	book1, err2 := c.DeleteBook(ctx, &pb.BookID{Value: r.Value})
-	if err2 != nil {
-		log.Fatalf("Could not get book: %v", err2)
+	shutdownTracing, err := tracing.Setup("bookinfo-client", os.Getenv("TRACE_EXPORT"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "bookctl: failed to set up tracing: %v\n", err)
+		os.Exit(1)
 	}
-	log.Printf("Deleted Book: ", book1.String())
 
-	//Read the csv file and add each book to the server.
-	readData("books.csv")
+	dialOpts, err := opts.dialOptions()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "bookctl: %v\n", err)
+		os.Exit(1)
+	}
+	balancing, err := bookclient.DialOptions(opts.balancer)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "bookctl: %v\n", err)
+		os.Exit(2)
+	}
+	target, resolverOpts := bookclient.Target(opts.addr)
+	dialOpts = append(append(dialOpts, balancing...), resolverOpts...)
+	conn, err := grpc.Dial(target,
+		append(dialOpts, grpc.WithStatsHandler(otelgrpc.NewClientHandler()))...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "bookctl: did not connect: %v\n", err)
+		os.Exit(1)
+	}
 
-	for _, book := range books {
-		rb, err := c.AddBook(ctx, &pb.Book{
-			Id:        book.Id,
-			Title:     book.Title,
-			Edition:   book.Edition,
-			Copyright: book.Copyright,
-			Language:  book.Language,
-			Pages:     book.Pages,
-			Author:    book.Author,
-			Publisher: book.Publisher})
-		if err != nil {
-			log.Fatalf("Could not add book: %v", err)
-		} else {
-			b, _ := c.GetBook(ctx, &pb.BookID{Value: rb.Value})
-			log.Printf("Book: ", b.String())
-		}
+	retryOpts := bookclient.DefaultOptions()
+	retryOpts.CallTimeout = opts.timeout
+	e := &env{
+		options:     opts,
+		client:      bookclient.New(conn, retryOpts),
+		circulation: pb.NewCirculationClient(conn),
+		fs:          fs,
+	}
+	err = cmd.run(context.Background(), e, args)
+	conn.Close()
+	shutdownTracing(context.Background())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "bookctl %s: %v\n", flag.Arg(0), err)
+		os.Exit(1)
 	}
 }
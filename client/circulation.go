@@ -0,0 +1,455 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	copyLocation string
+	patronEmail  string
+	loansAll     bool
+	holdsBook    string
+	holdsPatron  string
+	copyKeys     = []string{"barcode", "book_id", "location", "status", "loan_id", "hold_id"}
+	patronKeys   = []string{"id", "name", "email"}
+	loanKeys     = []string{"id", "barcode", "book_id", "patron_id", "checked_out", "due", "renewals", "returned"}
+	holdKeys     = []string{"id", "book_id", "patron_id", "placed", "status", "barcode", "ready", "expires"}
+)
+
+func init() {
+	commands["add-copy"] = &command{
+		usage: "add-copy [--location L] <book-id> <barcode>",
+		help:  "Add a copy of a book and print it.",
+		flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&copyLocation, "location", "", "where the copy is shelved")
+		},
+		run: runAddCopy,
+	}
+	commands["copies"] = &command{
+		usage: "copies [book-id]",
+		help:  "Print the copies, or those of a book.",
+		run:   runCopies,
+	}
+	commands["move-copy"] = &command{
+		usage: "move-copy <barcode> <location>",
+		help:  "Change where a copy is shelved.",
+		run:   runMoveCopy,
+	}
+	commands["delete-copy"] = &command{
+		usage: "delete-copy <barcode>...",
+		help:  "Withdraw copies that are on the shelf and print them.",
+		run:   runDeleteCopy,
+	}
+	commands["add-patron"] = &command{
+		usage: "add-patron [--email E] <name>",
+		help:  "Add a patron and print them with their new ID.",
+		flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&patronEmail, "email", "", "email address")
+		},
+		run: runAddPatron,
+	}
+	commands["patrons"] = &command{
+		usage: "patrons [text]",
+		help:  "Print the patrons, or those with a name containing text.",
+		run:   runPatrons,
+	}
+	commands["delete-patron"] = &command{
+		usage: "delete-patron <id>...",
+		help:  "Delete patrons with no copies on loan, cancelling their holds.",
+		run:   runDeletePatron,
+	}
+	commands["checkout"] = &command{
+		usage: "checkout <barcode> <patron-id>",
+		help:  "Lend a copy to a patron and print the loan.",
+		run:   runCheckout,
+	}
+	commands["return"] = &command{
+		usage: "return <barcode>...",
+		help:  "Return copies and print the holds they are now kept for.",
+		run:   runReturn,
+	}
+	commands["renew"] = &command{
+		usage: "renew <loan-id>...",
+		help:  "Renew loans and print them with their new due dates.",
+		run:   runRenew,
+	}
+	commands["loans"] = &command{
+		usage: "loans [--all] [patron-id]",
+		help:  "Print the current loans, or those of a patron.",
+		flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(&loansAll, "all", false, "include returned loans")
+		},
+		run: runLoans,
+	}
+	commands["overdue"] = &command{
+		usage: "overdue",
+		help:  "Print the loans past their due date, most overdue first.",
+		run:   runOverdue,
+	}
+	commands["hold"] = &command{
+		usage: "hold <book-id> <patron-id>",
+		help:  "Queue a patron for a book with no copy on the shelf.",
+		run:   runHold,
+	}
+	commands["cancel-hold"] = &command{
+		usage: "cancel-hold <hold-id>...",
+		help:  "Cancel holds and print them.",
+		run:   runCancelHold,
+	}
+	commands["holds"] = &command{
+		usage: "holds [--book ID] [--patron ID]",
+		help:  "Print holds in queue order.",
+		flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&holdsBook, "book", "", "only holds on the book with `ID`")
+			fs.StringVar(&holdsPatron, "patron", "", "only holds of the patron with `ID`")
+		},
+		run: runHolds,
+	}
+}
+
+func copyRow(c *pb.Copy) []string {
+	return []string{c.Barcode, c.BookId, c.Location, c.Status.String(), c.LoanId, c.HoldId}
+}
+
+func patronRow(p *pb.Patron) []string {
+	return []string{p.Id, p.Name, p.Email}
+}
+
+func loanRow(l *pb.Loan) []string {
+	return []string{l.Id, l.Barcode, l.BookId, l.PatronId, formatTime(l.CheckedOut),
+		formatTime(l.Due), strconv.Itoa(int(l.Renewals)), formatTime(l.Returned)}
+}
+
+func holdRow(h *pb.Hold) []string {
+	return []string{h.Id, h.BookId, h.PatronId, formatTime(h.Placed), h.Status.String(), h.Barcode,
+		formatTime(h.Ready), formatTime(h.Expires)}
+}
+
+// formatTime prints t in local time to the minute, or "" if unset.
+func formatTime(t *timestamppb.Timestamp) string {
+	if t == nil {
+		return ""
+	}
+	return t.AsTime().Local().Format("2006-01-02 15:04")
+}
+
+func runAddCopy(ctx context.Context, e *env, args []string) error {
+	if len(args) != 2 {
+		return errors.New("want a book ID and a barcode")
+	}
+	ctx, cancel := e.call(ctx)
+	defer cancel()
+	c, err := e.circulation.AddCopy(ctx, &pb.Copy{BookId: args[0], Barcode: args[1], Location: copyLocation})
+	if err != nil {
+		return err
+	}
+	return printRows(e.output, copyKeys, [][]string{copyRow(c)}, false)
+}
+
+func runCopies(ctx context.Context, e *env, args []string) error {
+	if len(args) > 1 {
+		return errors.New("want at most one book ID")
+	}
+	ctx, cancel := e.call(ctx)
+	defer cancel()
+	stream, err := e.circulation.ListCopies(ctx, &pb.ListCopiesRequest{BookId: strings.Join(args, "")})
+	if err != nil {
+		return err
+	}
+	var rows [][]string
+	for {
+		c, err := stream.Recv()
+		if err == io.EOF {
+			return printRows(e.output, copyKeys, rows, true)
+		}
+		if err != nil {
+			return err
+		}
+		rows = append(rows, copyRow(c))
+	}
+}
+
+func runMoveCopy(ctx context.Context, e *env, args []string) error {
+	if len(args) != 2 {
+		return errors.New("want a barcode and a location")
+	}
+	ctx, cancel := e.call(ctx)
+	defer cancel()
+	c, err := e.circulation.UpdateCopy(ctx, &pb.Copy{Barcode: args[0], Location: args[1]})
+	if err != nil {
+		return err
+	}
+	return printRows(e.output, copyKeys, [][]string{copyRow(c)}, false)
+}
+
+func runDeleteCopy(ctx context.Context, e *env, args []string) error {
+	if len(args) == 0 {
+		return errors.New("want at least one barcode")
+	}
+	ctx, cancel := e.call(ctx)
+	defer cancel()
+	var rows [][]string
+	for _, barcode := range args {
+		c, err := e.circulation.DeleteCopy(ctx, &pb.Barcode{Value: barcode})
+		if err != nil {
+			return err
+		}
+		rows = append(rows, copyRow(c))
+	}
+	return printRows(e.output, copyKeys, rows, false)
+}
+
+func runAddPatron(ctx context.Context, e *env, args []string) error {
+	if len(args) != 1 {
+		return errors.New("want exactly one name")
+	}
+	ctx, cancel := e.call(ctx)
+	defer cancel()
+	p, err := e.circulation.AddPatron(ctx, &pb.Patron{Name: args[0], Email: patronEmail})
+	if err != nil {
+		return err
+	}
+	return printRows(e.output, patronKeys, [][]string{patronRow(p)}, false)
+}
+
+func runPatrons(ctx context.Context, e *env, args []string) error {
+	if len(args) > 1 {
+		return errors.New("want at most one search text")
+	}
+	ctx, cancel := e.call(ctx)
+	defer cancel()
+	stream, err := e.circulation.ListPatrons(ctx, &pb.ListPatronsRequest{Query: strings.Join(args, "")})
+	if err != nil {
+		return err
+	}
+	var rows [][]string
+	for {
+		p, err := stream.Recv()
+		if err == io.EOF {
+			return printRows(e.output, patronKeys, rows, true)
+		}
+		if err != nil {
+			return err
+		}
+		rows = append(rows, patronRow(p))
+	}
+}
+
+func runDeletePatron(ctx context.Context, e *env, args []string) error {
+	if len(args) == 0 {
+		return errors.New("want at least one patron ID")
+	}
+	ctx, cancel := e.call(ctx)
+	defer cancel()
+	var rows [][]string
+	for _, id := range args {
+		p, err := e.circulation.DeletePatron(ctx, &pb.PatronID{Value: id})
+		if err != nil {
+			return err
+		}
+		rows = append(rows, patronRow(p))
+	}
+	return printRows(e.output, patronKeys, rows, false)
+}
+
+func runCheckout(ctx context.Context, e *env, args []string) error {
+	if len(args) != 2 {
+		return errors.New("want a barcode and a patron ID")
+	}
+	ctx, cancel := e.call(ctx)
+	defer cancel()
+	l, err := e.circulation.Checkout(ctx, &pb.CheckoutRequest{Barcode: args[0], PatronId: args[1]})
+	if err != nil {
+		return err
+	}
+	return printRows(e.output, loanKeys, [][]string{loanRow(l)}, false)
+}
+
+func runReturn(ctx context.Context, e *env, args []string) error {
+	if len(args) == 0 {
+		return errors.New("want at least one barcode")
+	}
+	ctx, cancel := e.call(ctx)
+	defer cancel()
+	var rows [][]string
+	for _, barcode := range args {
+		r, err := e.circulation.ReturnCopy(ctx, &pb.Barcode{Value: barcode})
+		if err != nil {
+			return err
+		}
+		if r.Hold != nil {
+			rows = append(rows, holdRow(r.Hold))
+		}
+	}
+	return printRows(e.output, holdKeys, rows, true)
+}
+
+func runRenew(ctx context.Context, e *env, args []string) error {
+	if len(args) == 0 {
+		return errors.New("want at least one loan ID")
+	}
+	ctx, cancel := e.call(ctx)
+	defer cancel()
+	var rows [][]string
+	for _, id := range args {
+		l, err := e.circulation.Renew(ctx, &pb.LoanID{Value: id})
+		if err != nil {
+			return err
+		}
+		rows = append(rows, loanRow(l))
+	}
+	return printRows(e.output, loanKeys, rows, false)
+}
+
+func runLoans(ctx context.Context, e *env, args []string) error {
+	if len(args) > 1 {
+		return errors.New("want at most one patron ID")
+	}
+	ctx, cancel := e.call(ctx)
+	defer cancel()
+	stream, err := e.circulation.ListLoans(ctx, &pb.ListLoansRequest{PatronId: strings.Join(args, ""), IncludeReturned: loansAll})
+	if err != nil {
+		return err
+	}
+	return printLoanStream(e, stream)
+}
+
+func runOverdue(ctx context.Context, e *env, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("unexpected arguments %q", args)
+	}
+	ctx, cancel := e.call(ctx)
+	defer cancel()
+	stream, err := e.circulation.ListOverdue(ctx, &pb.ListOverdueRequest{})
+	if err != nil {
+		return err
+	}
+	return printLoanStream(e, stream)
+}
+
+func printLoanStream(e *env, stream interface{ Recv() (*pb.Loan, error) }) error {
+	var rows [][]string
+	for {
+		l, err := stream.Recv()
+		if err == io.EOF {
+			return printRows(e.output, loanKeys, rows, true)
+		}
+		if err != nil {
+			return err
+		}
+		rows = append(rows, loanRow(l))
+	}
+}
+
+func runHold(ctx context.Context, e *env, args []string) error {
+	if len(args) != 2 {
+		return errors.New("want a book ID and a patron ID")
+	}
+	ctx, cancel := e.call(ctx)
+	defer cancel()
+	h, err := e.circulation.PlaceHold(ctx, &pb.PlaceHoldRequest{BookId: args[0], PatronId: args[1]})
+	if err != nil {
+		return err
+	}
+	return printRows(e.output, holdKeys, [][]string{holdRow(h)}, false)
+}
+
+func runCancelHold(ctx context.Context, e *env, args []string) error {
+	if len(args) == 0 {
+		return errors.New("want at least one hold ID")
+	}
+	ctx, cancel := e.call(ctx)
+	defer cancel()
+	var rows [][]string
+	for _, id := range args {
+		h, err := e.circulation.CancelHold(ctx, &pb.HoldID{Value: id})
+		if err != nil {
+			return err
+		}
+		rows = append(rows, holdRow(h))
+	}
+	return printRows(e.output, holdKeys, rows, false)
+}
+
+func runHolds(ctx context.Context, e *env, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("unexpected arguments %q", args)
+	}
+	ctx, cancel := e.call(ctx)
+	defer cancel()
+	stream, err := e.circulation.ListHolds(ctx, &pb.ListHoldsRequest{BookId: holdsBook, PatronId: holdsPatron})
+	if err != nil {
+		return err
+	}
+	var rows [][]string
+	for {
+		h, err := stream.Recv()
+		if err == io.EOF {
+			return printRows(e.output, holdKeys, rows, true)
+		}
+		if err != nil {
+			return err
+		}
+		rows = append(rows, holdRow(h))
+	}
+}
+
+// printRows writes records, given as rows of values for keys, to stdout
+// in format, like printBooks.
+func printRows(format string, keys []string, rows [][]string, list bool) error {
+	switch format {
+	case "json":
+		objects := make([]map[string]string, 0, len(rows))
+		for _, row := range rows {
+			object := make(map[string]string)
+			for i, key := range keys {
+				object[key] = row[i]
+			}
+			objects = append(objects, object)
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if !list && len(objects) == 1 {
+			return enc.Encode(objects[0])
+		}
+		return enc.Encode(objects)
+	case "yaml":
+		if list && len(rows) == 0 {
+			fmt.Println("[]")
+			return nil
+		}
+		indent, prefix := "", ""
+		if list || len(rows) > 1 {
+			indent, prefix = "  ", "- "
+		}
+		for _, row := range rows {
+			for i, key := range keys {
+				lead := indent
+				if i == 0 {
+					lead = prefix
+				}
+				fmt.Printf("%s%s: %s\n", lead, key, strconv.Quote(row[i]))
+			}
+		}
+		return nil
+	default:
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, strings.ToUpper(strings.Join(keys, "\t")))
+		for _, row := range rows {
+			fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+		return w.Flush()
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+)
+
+// citationStyles maps the names the cite command accepts to styles.
+var citationStyles = map[string]pb.CitationStyle{
+	"bibtex":   pb.CitationStyle_BIBTEX,
+	"ris":      pb.CitationStyle_RIS,
+	"csl-json": pb.CitationStyle_CSL_JSON,
+	"apa":      pb.CitationStyle_APA,
+	"mla":      pb.CitationStyle_MLA,
+	"chicago":  pb.CitationStyle_CHICAGO,
+}
+
+var citeStyle string
+
+func init() {
+	commands["cite"] = &command{
+		usage: "cite [--style apa] <id>...",
+		help:  "Print citations of books in a bibliography format or text style.",
+		flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&citeStyle, "style", "apa", "citation `style`: bibtex, ris, csl-json, apa, mla or chicago")
+		},
+		run: runCite,
+	}
+}
+
+func runCite(ctx context.Context, e *env, args []string) error {
+	if len(args) == 0 {
+		return errors.New("missing book ID")
+	}
+	style, ok := citationStyles[strings.ToLower(citeStyle)]
+	if !ok {
+		return fmt.Errorf("unknown citation style %q: want bibtex, ris, csl-json, apa, mla or chicago", citeStyle)
+	}
+	var citations []string
+	for _, id := range args {
+		c, err := e.client.FormatCitation(ctx, &pb.FormatCitationRequest{Id: id, Style: style})
+		if err != nil {
+			return err
+		}
+		citations = append(citations, strings.TrimRight(c.Text, "\r\n"))
+	}
+	switch style {
+	case pb.CitationStyle_CSL_JSON:
+		// CSL-JSON files hold an array of items.
+		fmt.Printf("[\n%s\n]\n", strings.Join(citations, ",\n"))
+	case pb.CitationStyle_BIBTEX, pb.CitationStyle_RIS:
+		fmt.Println(strings.Join(citations, "\n\n"))
+	default:
+		fmt.Println(strings.Join(citations, "\n"))
+	}
+	return nil
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"go.opentelemetry.io/otel"
+)
+
+// bookFlags binds a flag to every Book field except the ID.
+type bookFlags struct {
+	book Book
+}
+
+func (f *bookFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.book.Title, "title", "", "book title")
+	fs.StringVar(&f.book.Edition, "edition", "", "edition, e.g. 9th")
+	fs.StringVar(&f.book.Copyright, "copyright", "", "copyright year")
+	fs.StringVar(&f.book.Language, "language", "", "language, e.g. ENGLISH")
+	fs.StringVar(&f.book.Pages, "pages", "", "number of pages")
+	fs.StringVar(&f.book.Author, "author", "", "author")
+	fs.StringVar(&f.book.Publisher, "publisher", "", "publisher")
+	fs.StringVar(&f.book.Isbn, "isbn", "", "ISBN")
+}
+
+// apply copies the fields whose flags were set on the command line to b.
+func (f *bookFlags) apply(fs *flag.FlagSet, b *pb.Book) {
+	fs.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "title":
+			b.Title = f.book.Title
+		case "edition":
+			b.Edition = f.book.Edition
+		case "copyright":
+			b.Copyright = f.book.Copyright
+		case "language":
+			b.Language = f.book.Language
+		case "pages":
+			b.Pages = f.book.Pages
+		case "author":
+			b.Author = f.book.Author
+		case "publisher":
+			b.Publisher = f.book.Publisher
+		case "isbn":
+			b.Isbn = f.book.Isbn
+		}
+	})
+}
+
+var (
+	addFlags    bookFlags
+	updateFlags bookFlags
+
+	importOpts struct {
+		file      string
+		format    string
+		delimiter string
+		encoding  string
+		dryRun    bool
+	}
+)
+
+func init() {
+	commands["add"] = &command{
+		usage: "add --title T [--author A ...]",
+		help:  "Add a book and print it with its new ID.",
+		flags: addFlags.register,
+		run:   runAdd,
+	}
+	commands["get"] = &command{
+		usage: "get <id>...",
+		help:  "Print books by ID.",
+		run:   runGet,
+	}
+	commands["update"] = &command{
+		usage: "update <id> [--title T ...]",
+		help:  "Change the given fields of a book.",
+		flags: updateFlags.register,
+		run:   runUpdate,
+	}
+	commands["delete"] = &command{
+		usage: "delete <id>...",
+		help:  "Delete books by ID and print them.",
+		run:   runDelete,
+	}
+	commands["list"] = &command{
+		usage: "list",
+		help:  "Print every book.",
+		run:   runList,
+	}
+	commands["search"] = &command{
+		usage: "search <text>",
+		help:  "Print the books with a field containing text, ignoring case.",
+		run:   runSearch,
+	}
+	commands["import"] = &command{
+		usage: "import [--file books.csv] [--format csv|marc|marcxml|onix] [--dry-run]",
+		help:  "Add every book of a CSV, MARC21 or MARCXML file, or apply an ONIX feed.",
+		flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&importOpts.file, "file", "books.csv", "`file` to import")
+			fs.StringVar(&importOpts.format, "format", "csv", "file `format`: csv (columns matched by header name), marc (ISO 2709), marcxml or onix (ONIX 3.0, matched to books by ISBN)")
+			fs.StringVar(&importOpts.delimiter, "delimiter", ",", "CSV field `separator`; \\t or tab for tabs")
+			fs.StringVar(&importOpts.encoding, "encoding", "utf-8", "CSV file `encoding`: utf-8, latin1, windows-1252, utf-16, utf-16le or utf-16be")
+			fs.BoolVar(&importOpts.dryRun, "dry-run", false, "check the file and print the books without adding them")
+		},
+		run: runImport,
+	}
+	commands["export"] = &command{
+		usage: "export [--file F] [--format csv|jsonl|protobuf] [--author A ...]",
+		help:  "Write the books, in ID order, to a file or to stdout.",
+		flags: exportOpts.register,
+		run:   runExport,
+	}
+}
+
+func runAdd(ctx context.Context, e *env, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("unexpected arguments %q", args)
+	}
+	book := &pb.Book{}
+	addFlags.apply(e.fs, book)
+	if book.Title == "" {
+		return errors.New("--title is required")
+	}
+	id, err := e.client.AddBook(ctx, book)
+	if err != nil {
+		return err
+	}
+	book.Id = id.Value
+	return printBooks(e.output, []Book{fromProto(book)}, false)
+}
+
+func runGet(ctx context.Context, e *env, args []string) error {
+	if len(args) == 0 {
+		return errors.New("missing book ID")
+	}
+	var books []Book
+	for _, id := range args {
+		book, err := e.client.GetBook(ctx, &pb.BookID{Value: id})
+		if err != nil {
+			return err
+		}
+		books = append(books, fromProto(book))
+	}
+	return printBooks(e.output, books, len(args) > 1)
+}
+
+func runUpdate(ctx context.Context, e *env, args []string) error {
+	if len(args) != 1 {
+		return errors.New("want exactly one book ID")
+	}
+	book, err := e.client.GetBook(ctx, &pb.BookID{Value: args[0]})
+	if err != nil {
+		return err
+	}
+	updateFlags.apply(e.fs, book)
+	updated, err := e.client.UpdateBook(ctx, book)
+	if err != nil {
+		return err
+	}
+	return printBooks(e.output, []Book{fromProto(updated)}, false)
+}
+
+func runDelete(ctx context.Context, e *env, args []string) error {
+	if len(args) == 0 {
+		return errors.New("missing book ID")
+	}
+	var books []Book
+	for _, id := range args {
+		book, err := e.client.DeleteBook(ctx, &pb.BookID{Value: id})
+		if err != nil {
+			return err
+		}
+		books = append(books, fromProto(book))
+	}
+	return printBooks(e.output, books, len(args) > 1)
+}
+
+func runList(ctx context.Context, e *env, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("unexpected arguments %q", args)
+	}
+	books, err := listBooks(ctx, e, &pb.ListBooksRequest{})
+	if err != nil {
+		return err
+	}
+	return printBooks(e.output, books, true)
+}
+
+func runSearch(ctx context.Context, e *env, args []string) error {
+	if len(args) != 1 {
+		return errors.New("want exactly one search text")
+	}
+	books, err := listBooks(ctx, e, &pb.ListBooksRequest{Query: args[0]})
+	if err != nil {
+		return err
+	}
+	return printBooks(e.output, books, true)
+}
+
+// listBooks collects the books streamed back by ListBooks. The deadline
+// covers the whole stream.
+func listBooks(ctx context.Context, e *env, req *pb.ListBooksRequest) ([]Book, error) {
+	ctx, cancel := e.call(ctx)
+	defer cancel()
+	stream, err := e.client.ListBooks(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	var books []Book
+	for {
+		book, err := stream.Recv()
+		if err == io.EOF {
+			return books, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		books = append(books, fromProto(book))
+	}
+}
+
+func runImport(ctx context.Context, e *env, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("unexpected arguments %q", args)
+	}
+	switch importOpts.format {
+	case "csv":
+		return importCSV(ctx, e)
+	case "marc", "marcxml", "onix":
+		return importRecords(ctx, e)
+	}
+	return fmt.Errorf("unknown import format %q: want csv, marc, marcxml or onix", importOpts.format)
+}
+
+func importCSV(ctx context.Context, e *env) error {
+	delimiter, err := parseDelimiter(importOpts.delimiter)
+	if err != nil {
+		return err
+	}
+	ctx, span := otel.Tracer("github.com/marcoc22/tutorial3/client").Start(ctx, "ImportCSV")
+	defer span.End()
+
+	file, err := os.Open(importOpts.file)
+	if err != nil {
+		return fmt.Errorf("unable to read input file %s: %v", importOpts.file, err)
+	}
+	rows, problems, err := readCSV(importOpts.file, file, csvOptions{delimiter: delimiter, encoding: importOpts.encoding})
+	file.Close()
+	for _, p := range problems {
+		fmt.Fprintln(os.Stderr, p)
+	}
+	if err != nil {
+		return err
+	}
+
+	var added []Book
+	failed := 0
+	for _, row := range rows {
+		if importOpts.dryRun {
+			added = append(added, row.book)
+			continue
+		}
+		id, err := e.client.AddBook(ctx, row.book.toProto())
+		if err != nil {
+			fmt.Fprintln(os.Stderr, lineError{importOpts.file, row.line, fmt.Errorf("could not add book %q: %v", row.book.Title, err)})
+			failed++
+			continue
+		}
+		row.book.Id = id.Value
+		added = append(added, row.book)
+	}
+	if err := printBooks(e.output, added, true); err != nil {
+		return err
+	}
+	rejected := 0
+	for _, p := range problems {
+		if p.line > 1 {
+			rejected++
+		}
+	}
+	if rejected+failed > 0 {
+		return fmt.Errorf("%d of %d rows not imported", rejected+failed, len(rows)+rejected)
+	}
+	return nil
+}
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// csvColumns maps normalized header names to the Book field they hold.
+var csvColumns = map[string]string{
+	"id":          "id",
+	"bookid":      "id",
+	"title":       "title",
+	"name":        "title",
+	"edition":     "edition",
+	"copyright":   "copyright",
+	"year":        "copyright",
+	"language":    "language",
+	"lang":        "language",
+	"pages":       "pages",
+	"pagecount":   "pages",
+	"numpages":    "pages",
+	"author":      "author",
+	"authors":     "author",
+	"publisher":   "publisher",
+	"publishedby": "publisher",
+	"isbn":        "isbn",
+	"isbn13":      "isbn",
+}
+
+// normalizeHeader folds case and drops the separators people put in
+// column names, so "Page Count", "page_count" and "pagecount" match.
+func normalizeHeader(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.NewReplacer(" ", "", "_", "", "-", "", ".", "").Replace(name)
+}
+
+// csvRow is a book read from a CSV file together with where it came from.
+type csvRow struct {
+	line int
+	book Book
+}
+
+// lineError is a problem with one line of an imported file.
+type lineError struct {
+	file string
+	line int
+	err  error
+}
+
+func (e lineError) Error() string {
+	if e.line == 0 {
+		return fmt.Sprintf("%s: %v", e.file, e.err)
+	}
+	return fmt.Sprintf("%s:%d: %v", e.file, e.line, e.err)
+}
+
+// csvOptions controls how readCSV parses its input.
+type csvOptions struct {
+	delimiter rune
+	encoding  string
+}
+
+// parseDelimiter accepts a single character, or one of the escapes \t
+// and "tab".
+func parseDelimiter(s string) (rune, error) {
+	switch s {
+	case `\t`, "tab":
+		return '\t', nil
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	if size == 0 || size != len(s) || r == utf8.RuneError || r == '"' || r == '\r' || r == '\n' {
+		return 0, fmt.Errorf("invalid delimiter %q", s)
+	}
+	return r, nil
+}
+
+// readCSV reads the books in a CSV file whose first line names the
+// columns. Columns are matched by name, in any order; unknown columns are
+// ignored with a warning and missing ones leave their field empty. Rows
+// that are malformed or fail validation are returned as errors, with
+// their line numbers, instead of books.
+func readCSV(name string, r io.Reader, opts csvOptions) ([]csvRow, []lineError, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	text, err := decode(data, opts.encoding)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %v", name, err)
+	}
+
+	reader := csv.NewReader(strings.NewReader(text))
+	if opts.delimiter != 0 {
+		reader.Comma = opts.delimiter
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil, fmt.Errorf("%s: file is empty", name)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: unable to read header: %v", name, err)
+	}
+
+	var problems []lineError
+	columns := make([]string, len(header))
+	seen := make(map[string]bool)
+	for i, h := range header {
+		field, ok := csvColumns[normalizeHeader(h)]
+		switch {
+		case !ok:
+			problems = append(problems, lineError{name, 1, fmt.Errorf("ignoring unknown column %q", h)})
+		case seen[field]:
+			problems = append(problems, lineError{name, 1, fmt.Errorf("ignoring duplicate column %q", h)})
+		default:
+			columns[i] = field
+			seen[field] = true
+		}
+	}
+	if !seen["title"] {
+		return nil, problems, fmt.Errorf("%s: no title column in header %q", name, header)
+	}
+
+	var rows []csvRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			problems = append(problems, lineError{name, parseErr.Line, parseErr.Err})
+			continue
+		}
+		if err != nil {
+			return rows, problems, err
+		}
+		line, _ := reader.FieldPos(0)
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		if len(record) > len(header) {
+			problems = append(problems, lineError{name, line,
+				fmt.Errorf("%d fields but the header has %d", len(record), len(header))})
+			continue
+		}
+
+		var book Book
+		for i, value := range record {
+			setField(&book, columns[i], strings.TrimSpace(value))
+		}
+		if err := validateBook(book); err != nil {
+			problems = append(problems, lineError{name, line, err})
+			continue
+		}
+		rows = append(rows, csvRow{line: line, book: book})
+	}
+	return rows, problems, nil
+}
+
+func setField(b *Book, field, value string) {
+	switch field {
+	case "id":
+		b.Id = value
+	case "title":
+		b.Title = value
+	case "edition":
+		b.Edition = value
+	case "copyright":
+		b.Copyright = value
+	case "language":
+		b.Language = value
+	case "pages":
+		b.Pages = value
+	case "author":
+		b.Author = value
+	case "publisher":
+		b.Publisher = value
+	case "isbn":
+		b.Isbn = value
+	}
+}
+
+// validateBook checks the fields a catalog entry cannot do without or
+// that must be numbers.
+func validateBook(b Book) error {
+	if b.Title == "" {
+		return errors.New("missing title")
+	}
+	if b.Pages != "" {
+		if n, err := strconv.Atoi(b.Pages); err != nil || n <= 0 {
+			return fmt.Errorf("pages %q is not a positive number", b.Pages)
+		}
+	}
+	if b.Copyright != "" {
+		if _, err := strconv.Atoi(b.Copyright); err != nil || len(b.Copyright) != 4 {
+			return fmt.Errorf("copyright %q is not a year", b.Copyright)
+		}
+	}
+	return nil
+}
+
+// windows1252 holds the characters Windows-1252 puts in 0x80-0x9F, where
+// ISO-8859-1 has control codes. Unassigned positions map to themselves.
+var windows1252 = [32]rune{
+	'€', 0x81, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0x8D, 'Ž', 0x8F,
+	0x90, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0x9D, 'ž', 'Ÿ',
+}
+
+// decode converts data in encoding to a string. UTF-8 and UTF-16 byte
+// order marks are honoured and dropped.
+func decode(data []byte, encoding string) (string, error) {
+	switch strings.ToLower(strings.Replace(encoding, "_", "-", -1)) {
+	case "", "utf-8", "utf8":
+		if bytes.HasPrefix(data, []byte{0xFF, 0xFE}) || bytes.HasPrefix(data, []byte{0xFE, 0xFF}) {
+			return decodeUTF16(data, false)
+		}
+		data = bytes.TrimPrefix(data, []byte("\xEF\xBB\xBF"))
+		if !utf8.Valid(data) {
+			return "", errors.New("input is not valid UTF-8; set --encoding")
+		}
+		return string(data), nil
+	case "latin1", "latin-1", "iso-8859-1", "iso8859-1":
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+		}
+		return string(runes), nil
+	case "windows-1252", "cp1252":
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+			if b >= 0x80 && b < 0xA0 {
+				runes[i] = windows1252[b-0x80]
+			}
+		}
+		return string(runes), nil
+	case "utf-16", "utf16":
+		return decodeUTF16(data, false)
+	case "utf-16le":
+		return decodeUTF16(data, false)
+	case "utf-16be":
+		return decodeUTF16(data, true)
+	}
+	return "", fmt.Errorf("unsupported encoding %q", encoding)
+}
+
+// decodeUTF16 decodes data, in big endian order if bigEndian is set and
+// there is no byte order mark saying otherwise.
+func decodeUTF16(data []byte, bigEndian bool) (string, error) {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}):
+		data, bigEndian = data[2:], false
+	case bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		data, bigEndian = data[2:], true
+	}
+	if len(data)%2 != 0 {
+		return "", errors.New("input is not valid UTF-16: odd number of bytes")
+	}
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		if bigEndian {
+			units[i] = uint16(data[2*i])<<8 | uint16(data[2*i+1])
+		} else {
+			units[i] = uint16(data[2*i+1])<<8 | uint16(data[2*i])
+		}
+	}
+	return string(utf16.Decode(units)), nil
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// readFixture reads testdata/name with readCSV.
+func readFixture(t *testing.T, name string, opts csvOptions) ([]csvRow, []lineError, error) {
+	t.Helper()
+	f, err := os.Open("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	return readCSV(name, f, opts)
+}
+
+// checkProblems compares problems with want, given as "line: message".
+func checkProblems(t *testing.T, problems []lineError, want []string) {
+	t.Helper()
+	var got []string
+	for _, p := range problems {
+		got = append(got, strings.TrimPrefix(p.Error(), p.file+":"))
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("problems = %q, want %q", got, want)
+	}
+}
+
+func TestReadCSVByHeaderName(t *testing.T) {
+	rows, problems, err := readFixture(t, "books.csv", csvOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkProblems(t, problems, []string{`1: ignoring unknown column "Shelf"`})
+	want := []csvRow{
+		{2, Book{Title: "Dune", Author: "Frank Herbert", Pages: "412", Copyright: "1965", Language: "English", Publisher: "Chilton"}},
+		{3, Book{Title: "Good Omens", Author: "Pratchett, Terry; Gaiman, Neil", Pages: "288", Copyright: "1990", Language: "English", Publisher: "Gollancz"}},
+		// Blank lines are skipped, fields trimmed and short rows leave
+		// the missing columns empty.
+		{5, Book{Title: "Momo", Author: "Michael Ende", Copyright: "1973", Language: "German"}},
+		{6, Book{Title: "Le Petit Prince", Author: "Antoine de Saint-Exupéry", Pages: "96"}},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %+v, want %+v", rows, want)
+	}
+}
+
+func TestReadCSVBadRows(t *testing.T) {
+	rows, problems, err := readFixture(t, "bad_rows.csv", csvOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkProblems(t, problems, []string{
+		`1: ignoring duplicate column "title"`,
+		`3: missing title`,
+		`4: pages "-3" is not a positive number`,
+		`5: copyright "65" is not a year`,
+		`6: 5 fields but the header has 4`,
+		`7: extraneous or missing " in quoted-field`,
+	})
+	want := []csvRow{{2, Book{Title: "Fine", Pages: "100", Copyright: "2001"}}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %+v, want %+v", rows, want)
+	}
+}
+
+func TestReadCSVEncodings(t *testing.T) {
+	tests := []struct {
+		file string
+		opts csvOptions
+		want Book
+	}{
+		{"latin1.csv", csvOptions{encoding: "ISO_8859-1"}, Book{Title: "Émile", Author: "Jean-Jacques Rousseau"}},
+		{"windows1252.csv", csvOptions{encoding: "cp1252"}, Book{Title: "“Quoted” — Dash", Author: "Anon"}},
+		// The byte order mark gives UTF-16 away without --encoding.
+		{"utf16.csv", csvOptions{delimiter: ';'}, Book{Title: "源氏物語", Author: "紫式部"}},
+		{"bom.csv", csvOptions{}, Book{Title: "With BOM"}},
+	}
+	for _, tt := range tests {
+		rows, problems, err := readFixture(t, tt.file, tt.opts)
+		if err != nil || len(problems) != 0 {
+			t.Errorf("%s: read with problems %v and error %v", tt.file, problems, err)
+			continue
+		}
+		if len(rows) != 1 || rows[0].book != tt.want {
+			t.Errorf("%s: rows = %+v, want %+v", tt.file, rows, tt.want)
+		}
+	}
+
+	if _, _, err := readFixture(t, "latin1.csv", csvOptions{}); err == nil || !strings.Contains(err.Error(), "not valid UTF-8") {
+		t.Errorf("latin1.csv read as UTF-8: %v, want an invalid UTF-8 error", err)
+	}
+	if _, _, err := readFixture(t, "books.csv", csvOptions{encoding: "ebcdic"}); err == nil {
+		t.Error("read with an unknown encoding succeeded")
+	}
+}
+
+func TestReadCSVHeaderErrors(t *testing.T) {
+	for _, tt := range []struct{ input, want string }{
+		{"", "in.csv: file is empty"},
+		{"author,pages\nHerbert,412\n", `in.csv: no title column in header ["author" "pages"]`},
+	} {
+		_, _, err := readCSV("in.csv", strings.NewReader(tt.input), csvOptions{})
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("readCSV(%q) = %v, want %s", tt.input, err, tt.want)
+		}
+	}
+}
+
+func TestParseDelimiter(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want rune
+	}{{",", ','}, {";", ';'}, {`\t`, '\t'}, {"tab", '\t'}, {"|", '|'}} {
+		if got, err := parseDelimiter(tt.in); err != nil || got != tt.want {
+			t.Errorf("parseDelimiter(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+		}
+	}
+	for _, in := range []string{"", ";;", `"`, "\n"} {
+		if _, err := parseDelimiter(in); err == nil {
+			t.Errorf("parseDelimiter(%q) succeeded, want an error", in)
+		}
+	}
+}
@@ -0,0 +1,313 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+)
+
+// Entity is an author or a publisher as printed by bookctl.
+type Entity struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+var (
+	byAuthorRole string
+	linkDryRun   bool
+)
+
+func init() {
+	commands["authors"] = &command{
+		usage: "authors [text]",
+		help:  "Print the authors, or those with a name containing text.",
+		run:   runAuthors,
+	}
+	commands["publishers"] = &command{
+		usage: "publishers [text]",
+		help:  "Print the publishers, or those with a name containing text.",
+		run:   runPublishers,
+	}
+	commands["add-author"] = &command{
+		usage: "add-author <name>",
+		help:  "Add an author and print it with its new ID.",
+		run:   runAddAuthor,
+	}
+	commands["add-publisher"] = &command{
+		usage: "add-publisher <name>",
+		help:  "Add a publisher and print it with its new ID.",
+		run:   runAddPublisher,
+	}
+	commands["rename-author"] = &command{
+		usage: "rename-author <id> <name>",
+		help:  "Change the name of an author.",
+		run:   runRenameAuthor,
+	}
+	commands["rename-publisher"] = &command{
+		usage: "rename-publisher <id> <name>",
+		help:  "Change the name of a publisher.",
+		run:   runRenamePublisher,
+	}
+	commands["delete-author"] = &command{
+		usage: "delete-author <id>...",
+		help:  "Delete authors no book credits and print them.",
+		run:   runDeleteAuthor,
+	}
+	commands["delete-publisher"] = &command{
+		usage: "delete-publisher <id>...",
+		help:  "Delete publishers no book references and print them.",
+		run:   runDeletePublisher,
+	}
+	commands["by-author"] = &command{
+		usage: "by-author [--role editor] <author-id>",
+		help:  "Print the books crediting an author.",
+		flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&byAuthorRole, "role", "", "only books crediting the author as `role`: author, editor, translator or illustrator")
+		},
+		run: runByAuthor,
+	}
+	commands["by-publisher"] = &command{
+		usage: "by-publisher <publisher-id>",
+		help:  "Print the books of a publisher.",
+		run:   runByPublisher,
+	}
+	commands["link"] = &command{
+		usage: "link [--dry-run]",
+		help:  "Link books to authors and publishers by name, creating those missing.",
+		flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(&linkDryRun, "dry-run", false, "print what would be linked and created without changing anything")
+		},
+		run: runLink,
+	}
+}
+
+func runAuthors(ctx context.Context, e *env, args []string) error {
+	if len(args) > 1 {
+		return errors.New("want at most one search text")
+	}
+	ctx, cancel := e.call(ctx)
+	defer cancel()
+	stream, err := e.client.ListAuthors(ctx, &pb.ListAuthorsRequest{Query: strings.Join(args, "")})
+	if err != nil {
+		return err
+	}
+	var entities []Entity
+	for {
+		a, err := stream.Recv()
+		if err == io.EOF {
+			return printEntities(e.output, entities, true)
+		}
+		if err != nil {
+			return err
+		}
+		entities = append(entities, Entity{Id: a.Id, Name: a.Name})
+	}
+}
+
+func runPublishers(ctx context.Context, e *env, args []string) error {
+	if len(args) > 1 {
+		return errors.New("want at most one search text")
+	}
+	ctx, cancel := e.call(ctx)
+	defer cancel()
+	stream, err := e.client.ListPublishers(ctx, &pb.ListPublishersRequest{Query: strings.Join(args, "")})
+	if err != nil {
+		return err
+	}
+	var entities []Entity
+	for {
+		p, err := stream.Recv()
+		if err == io.EOF {
+			return printEntities(e.output, entities, true)
+		}
+		if err != nil {
+			return err
+		}
+		entities = append(entities, Entity{Id: p.Id, Name: p.Name})
+	}
+}
+
+func runAddAuthor(ctx context.Context, e *env, args []string) error {
+	if len(args) != 1 {
+		return errors.New("want exactly one name")
+	}
+	a, err := e.client.AddAuthor(ctx, &pb.Author{Name: args[0]})
+	if err != nil {
+		return err
+	}
+	return printEntities(e.output, []Entity{{Id: a.Id, Name: a.Name}}, false)
+}
+
+func runAddPublisher(ctx context.Context, e *env, args []string) error {
+	if len(args) != 1 {
+		return errors.New("want exactly one name")
+	}
+	p, err := e.client.AddPublisher(ctx, &pb.Publisher{Name: args[0]})
+	if err != nil {
+		return err
+	}
+	return printEntities(e.output, []Entity{{Id: p.Id, Name: p.Name}}, false)
+}
+
+func runRenameAuthor(ctx context.Context, e *env, args []string) error {
+	if len(args) != 2 {
+		return errors.New("want an author ID and a name")
+	}
+	a, err := e.client.UpdateAuthor(ctx, &pb.Author{Id: args[0], Name: args[1]})
+	if err != nil {
+		return err
+	}
+	return printEntities(e.output, []Entity{{Id: a.Id, Name: a.Name}}, false)
+}
+
+func runRenamePublisher(ctx context.Context, e *env, args []string) error {
+	if len(args) != 2 {
+		return errors.New("want a publisher ID and a name")
+	}
+	p, err := e.client.UpdatePublisher(ctx, &pb.Publisher{Id: args[0], Name: args[1]})
+	if err != nil {
+		return err
+	}
+	return printEntities(e.output, []Entity{{Id: p.Id, Name: p.Name}}, false)
+}
+
+func runDeleteAuthor(ctx context.Context, e *env, args []string) error {
+	if len(args) == 0 {
+		return errors.New("want at least one author ID")
+	}
+	var deleted []Entity
+	for _, id := range args {
+		a, err := e.client.DeleteAuthor(ctx, &pb.AuthorID{Value: id})
+		if err != nil {
+			return err
+		}
+		deleted = append(deleted, Entity{Id: a.Id, Name: a.Name})
+	}
+	return printEntities(e.output, deleted, false)
+}
+
+func runDeletePublisher(ctx context.Context, e *env, args []string) error {
+	if len(args) == 0 {
+		return errors.New("want at least one publisher ID")
+	}
+	var deleted []Entity
+	for _, id := range args {
+		p, err := e.client.DeletePublisher(ctx, &pb.PublisherID{Value: id})
+		if err != nil {
+			return err
+		}
+		deleted = append(deleted, Entity{Id: p.Id, Name: p.Name})
+	}
+	return printEntities(e.output, deleted, false)
+}
+
+func runByAuthor(ctx context.Context, e *env, args []string) error {
+	if len(args) != 1 {
+		return errors.New("want exactly one author ID")
+	}
+	req := &pb.ListBooksByAuthorRequest{AuthorId: args[0]}
+	if byAuthorRole != "" {
+		role, ok := pb.ContributorRole_value[strings.ToUpper(byAuthorRole)]
+		if !ok || role == 0 {
+			return fmt.Errorf("unknown role %q: want author, editor, translator or illustrator", byAuthorRole)
+		}
+		req.Role = pb.ContributorRole(role)
+	}
+	ctx, cancel := e.call(ctx)
+	defer cancel()
+	stream, err := e.client.ListBooksByAuthor(ctx, req)
+	if err != nil {
+		return err
+	}
+	return printBookStream(e, stream)
+}
+
+func runByPublisher(ctx context.Context, e *env, args []string) error {
+	if len(args) != 1 {
+		return errors.New("want exactly one publisher ID")
+	}
+	ctx, cancel := e.call(ctx)
+	defer cancel()
+	stream, err := e.client.ListBooksByPublisher(ctx, &pb.ListBooksByPublisherRequest{PublisherId: args[0]})
+	if err != nil {
+		return err
+	}
+	return printBookStream(e, stream)
+}
+
+// printBookStream prints the books a listing call streams back.
+func printBookStream(e *env, stream interface{ Recv() (*pb.Book, error) }) error {
+	var books []Book
+	for {
+		book, err := stream.Recv()
+		if err == io.EOF {
+			return printBooks(e.output, books, true)
+		}
+		if err != nil {
+			return err
+		}
+		books = append(books, fromProto(book))
+	}
+}
+
+func runLink(ctx context.Context, e *env, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("unexpected arguments %q", args)
+	}
+	summary, err := e.client.LinkEntities(ctx, &pb.LinkEntitiesRequest{DryRun: linkDryRun})
+	if err != nil {
+		return err
+	}
+	verb := "Linked"
+	if linkDryRun {
+		verb = "Would link"
+	}
+	fmt.Printf("%s %d books, creating %d authors and %d publishers.\n",
+		verb, summary.BooksLinked, summary.AuthorsCreated, summary.PublishersCreated)
+	return nil
+}
+
+// printEntities writes entities to stdout in format, like printBooks.
+func printEntities(format string, entities []Entity, list bool) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if !list && len(entities) == 1 {
+			return enc.Encode(entities[0])
+		}
+		if entities == nil {
+			entities = []Entity{}
+		}
+		return enc.Encode(entities)
+	case "yaml":
+		if list && len(entities) == 0 {
+			fmt.Println("[]")
+			return nil
+		}
+		indent, prefix := "", ""
+		if list || len(entities) > 1 {
+			indent, prefix = "  ", "- "
+		}
+		for _, en := range entities {
+			fmt.Printf("%sid: %s\n%sname: %s\n", prefix, strconv.Quote(en.Id), indent, strconv.Quote(en.Name))
+		}
+		return nil
+	default:
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME")
+		for _, en := range entities {
+			fmt.Fprintf(w, "%s\t%s\n", en.Id, en.Name)
+		}
+		return w.Flush()
+	}
+}
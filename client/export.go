@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// exportFormats start an export to w in each of the export formats. They
+// return a function writing one book and one finishing the export.
+var exportFormats = map[string]func(w io.Writer) (write func(*pb.Book) error, finish func() error){
+	"csv":      writeCSV,
+	"jsonl":    writeJSONLines,
+	"protobuf": writeDelimitedProto,
+}
+
+// csvHeader is the header line of books.csv, which CSV exports reproduce,
+// followed by the ISBN.
+var csvHeader = []string{"id", "title", "edition", "copyright", "language", "pages", "author", "publisher", "isbn"}
+
+// exportOptions are the flags of the export command.
+type exportOptions struct {
+	file      string
+	format    string
+	query     string
+	author    string
+	publisher string
+	language  string
+	fromYear  int
+	toYear    int
+}
+
+var exportOpts exportOptions
+
+func (o *exportOptions) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.file, "file", "", "`file` to write instead of stdout")
+	fs.StringVar(&o.format, "format", "csv", "export `format`: csv, jsonl or protobuf (length-delimited pb.Book records)")
+	fs.StringVar(&o.query, "query", "", "only books with a field containing `text`, ignoring case")
+	fs.StringVar(&o.author, "author", "", "only books by this author, ignoring case")
+	fs.StringVar(&o.publisher, "publisher", "", "only books from this publisher, ignoring case")
+	fs.StringVar(&o.language, "language", "", "only books in this language, ignoring case")
+	fs.IntVar(&o.fromYear, "from-year", 0, "only books with a copyright year from this `year` on")
+	fs.IntVar(&o.toYear, "to-year", 0, "only books with a copyright year up to this `year`")
+}
+
+func runExport(ctx context.Context, e *env, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("unexpected arguments %q", args)
+	}
+	format, ok := exportFormats[exportOpts.format]
+	if !ok {
+		return fmt.Errorf("unknown export format %q: want csv, jsonl or protobuf", exportOpts.format)
+	}
+	export := func(w io.Writer) error {
+		write, finish := format(w)
+		if err := streamBooks(ctx, e, write); err != nil {
+			return err
+		}
+		return finish()
+	}
+	if exportOpts.file == "" {
+		out := bufio.NewWriter(os.Stdout)
+		if err := export(out); err != nil {
+			return err
+		}
+		return out.Flush()
+	}
+	return writeFileAtomic(exportOpts.file, export)
+}
+
+// streamBooks calls write with each book the export selects, in the ID
+// order the server lists them in. Large catalogs take longer than
+// --timeout to list, so the timeout bounds the wait for each book rather
+// than the whole call.
+func streamBooks(ctx context.Context, e *env, write func(*pb.Book) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	idle := time.AfterFunc(e.timeout, cancel)
+	defer idle.Stop()
+	stream, err := e.client.ListBooks(ctx, &pb.ListBooksRequest{
+		Query:     exportOpts.query,
+		Author:    exportOpts.author,
+		Publisher: exportOpts.publisher,
+		Language:  exportOpts.language,
+		FromYear:  int32(exportOpts.fromYear),
+		ToYear:    int32(exportOpts.toYear),
+	})
+	if err != nil {
+		return err
+	}
+	for {
+		book, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			if !idle.Stop() {
+				return fmt.Errorf("no book received for %v: %w", e.timeout, err)
+			}
+			return err
+		}
+		idle.Reset(e.timeout)
+		if err := write(book); err != nil {
+			return err
+		}
+	}
+}
+
+// writeFileAtomic writes name through a temporary file, so a failed
+// export never leaves a truncated file behind.
+func writeFileAtomic(name string, write func(w io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".export-*")
+	if err != nil {
+		return err
+	}
+	out := bufio.NewWriter(tmp)
+	err = write(out)
+	if err == nil {
+		err = out.Flush()
+	}
+	if err == nil {
+		err = tmp.Chmod(0644)
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), name)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
+
+// writeCSV writes books in the layout of csvHeader.
+func writeCSV(w io.Writer) (func(*pb.Book) error, func() error) {
+	cw := csv.NewWriter(w)
+	cw.Write(csvHeader)
+	write := func(b *pb.Book) error {
+		return cw.Write([]string{b.Id, b.Title, b.Edition, b.Copyright, b.Language, b.Pages, b.Author, b.Publisher, b.Isbn})
+	}
+	finish := func() error {
+		cw.Flush()
+		return cw.Error()
+	}
+	return write, finish
+}
+
+// writeJSONLines writes one JSON object per book and line, with every
+// field of the pb.Book message. Lines are compacted, as protojson varies
+// its spacing between builds.
+func writeJSONLines(w io.Writer) (func(*pb.Book) error, func() error) {
+	opts := protojson.MarshalOptions{EmitUnpopulated: true}
+	var line bytes.Buffer
+	write := func(b *pb.Book) error {
+		out, err := opts.Marshal(b)
+		if err != nil {
+			return err
+		}
+		line.Reset()
+		if err := json.Compact(&line, out); err != nil {
+			return err
+		}
+		line.WriteByte('\n')
+		_, err = w.Write(line.Bytes())
+		return err
+	}
+	return write, func() error { return nil }
+}
+
+// writeDelimitedProto writes each book as a pb.Book message preceded by
+// its length as a varint, the framing protodelim and Java's
+// writeDelimitedTo use.
+func writeDelimitedProto(w io.Writer) (func(*pb.Book) error, func() error) {
+	write := func(b *pb.Book) error {
+		_, err := protodelim.MarshalTo(w, b)
+		return err
+	}
+	return write, func() error { return nil }
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// exportBooks are awkward to encode: separators, quotes and line breaks
+// inside fields, non-ASCII text and empty fields.
+var exportBooks = []*pb.Book{
+	{Id: "1", Title: "Dune", Edition: "1st", Copyright: "1965", Language: "English", Pages: "412", Author: "Frank Herbert", Publisher: "Chilton", Isbn: "9780801950773"},
+	{Id: "2", Title: `The "Good" Omens, annotated`, Author: "Pratchett, Terry\nGaiman, Neil", Pages: "288"},
+	{Id: "3", Title: "源氏物語", Author: "紫式部", Language: "Japanese"},
+	{Id: "4", Title: "Untitled draft"},
+}
+
+// export writes books in format and returns the output.
+func export(t *testing.T, format string, books []*pb.Book) []byte {
+	t.Helper()
+	var out bytes.Buffer
+	write, finish := exportFormats[format](&out)
+	for _, b := range books {
+		if err := write(b); err != nil {
+			t.Fatalf("%s: writing %v: %v", format, b, err)
+		}
+	}
+	if err := finish(); err != nil {
+		t.Fatalf("%s: finishing: %v", format, err)
+	}
+	return out.Bytes()
+}
+
+func checkRoundTrip(t *testing.T, format string, got []*pb.Book) {
+	t.Helper()
+	if len(got) != len(exportBooks) {
+		t.Fatalf("%s: read back %d books, want %d", format, len(got), len(exportBooks))
+	}
+	for i, want := range exportBooks {
+		if !proto.Equal(got[i], want) {
+			t.Errorf("%s: book %d read back as %v, want %v", format, i, got[i], want)
+		}
+	}
+}
+
+func TestExportCSVRoundTrip(t *testing.T) {
+	out := export(t, "csv", exportBooks)
+	if header := strings.SplitN(string(out), "\n", 2)[0]; header != strings.Join(csvHeader, ",") {
+		t.Errorf("header = %q, want the books.csv one", header)
+	}
+	// The import reads what the export writes.
+	rows, problems, err := readCSV("export.csv", bytes.NewReader(out), csvOptions{})
+	if err != nil || len(problems) != 0 {
+		t.Fatalf("reading the export back: %v, problems %v", err, problems)
+	}
+	var got []*pb.Book
+	for _, row := range rows {
+		got = append(got, row.book.toProto())
+	}
+	checkRoundTrip(t, "csv", got)
+}
+
+func TestExportJSONLinesRoundTrip(t *testing.T) {
+	out := export(t, "jsonl", exportBooks)
+	lines := bufio.NewScanner(bytes.NewReader(out))
+	var got []*pb.Book
+	for lines.Scan() {
+		// Every book is a single compact line with every field.
+		if !strings.HasPrefix(lines.Text(), `{"id":`) || !strings.Contains(lines.Text(), `"publisher":`) {
+			t.Errorf("line %q is not a compact object with every field", lines.Text())
+		}
+		b := &pb.Book{}
+		if err := protojson.Unmarshal(lines.Bytes(), b); err != nil {
+			t.Fatalf("line %q: %v", lines.Text(), err)
+		}
+		got = append(got, b)
+	}
+	checkRoundTrip(t, "jsonl", got)
+}
+
+func TestExportDelimitedProtoRoundTrip(t *testing.T) {
+	out := export(t, "protobuf", exportBooks)
+	r := bufio.NewReader(bytes.NewReader(out))
+	var got []*pb.Book
+	for {
+		b := &pb.Book{}
+		err := protodelim.UnmarshalFrom(r, b)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("reading book %d: %v", len(got), err)
+		}
+		got = append(got, b)
+	}
+	checkRoundTrip(t, "protobuf", got)
+}
+
+func TestExportEmpty(t *testing.T) {
+	for format, want := range map[string]string{
+		"csv":      strings.Join(csvHeader, ",") + "\n",
+		"jsonl":    "",
+		"protobuf": "",
+	} {
+		if got := string(export(t, format, nil)); got != want {
+			t.Errorf("%s export of no books = %q, want %q", format, got, want)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+)
+
+func validFormat(format string) error {
+	switch format {
+	case "table", "json", "yaml":
+		return nil
+	}
+	return fmt.Errorf("unknown output format %q: want table, json or yaml", format)
+}
+
+// printBooks writes books to stdout in format. Unless list is set, a
+// single book is printed as an object rather than as a one element list.
+func printBooks(format string, books []Book, list bool) error {
+	switch format {
+	case "json":
+		return writeJSON(os.Stdout, books, list)
+	case "yaml":
+		return writeYAML(os.Stdout, books, list)
+	default:
+		return writeTable(os.Stdout, books)
+	}
+}
+
+func writeTable(out io.Writer, books []Book) error {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTITLE\tEDITION\tCOPYRIGHT\tLANGUAGE\tPAGES\tAUTHOR\tPUBLISHER\tISBN")
+	for _, b := range books {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			b.Id, b.Title, b.Edition, b.Copyright, b.Language, b.Pages, b.Author, b.Publisher, b.Isbn)
+	}
+	return w.Flush()
+}
+
+func writeJSON(out io.Writer, books []Book, list bool) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if !list && len(books) == 1 {
+		return enc.Encode(books[0])
+	}
+	if books == nil {
+		books = []Book{}
+	}
+	return enc.Encode(books)
+}
+
+// writeYAML writes books with the same keys as the JSON output. Every
+// value is a double quoted scalar, so no escaping rules beyond Go's
+// quoting are needed.
+func writeYAML(out io.Writer, books []Book, list bool) error {
+	if list && len(books) == 0 {
+		_, err := fmt.Fprintln(out, "[]")
+		return err
+	}
+	for _, b := range books {
+		indent := ""
+		prefix := ""
+		if list || len(books) > 1 {
+			indent, prefix = "  ", "- "
+		}
+		fields := [][2]string{
+			{"id", b.Id}, {"title", b.Title}, {"edition", b.Edition},
+			{"copyright", b.Copyright}, {"language", b.Language}, {"pages", b.Pages},
+			{"author", b.Author}, {"publisher", b.Publisher}, {"isbn", b.Isbn},
+		}
+		for i, f := range fields {
+			lead := indent
+			if i == 0 {
+				lead = prefix
+			}
+			if _, err := fmt.Fprintf(out, "%s%s: %s\n", lead, f[0], strconv.Quote(f[1])); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
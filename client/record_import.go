@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"github.com/marcoc22/tutorial3/marc"
+	"github.com/marcoc22/tutorial3/onix"
+	"go.opentelemetry.io/otel"
+)
+
+// importChunkSize is how much of a file each ImportChunk carries.
+const importChunkSize = 64 << 10
+
+var importFormats = map[string]pb.ImportFormat{
+	"marc":    pb.ImportFormat_MARC21,
+	"marcxml": pb.ImportFormat_MARCXML,
+	"onix":    pb.ImportFormat_ONIX,
+}
+
+// importRecords sends a MARC or ONIX file to the server's ImportBooks,
+// which parses it. With --dry-run the file is parsed here instead and the
+// books it holds are printed.
+func importRecords(ctx context.Context, e *env) error {
+	format := importFormats[importOpts.format]
+	ctx, span := otel.Tracer("github.com/marcoc22/tutorial3/client").Start(ctx, "Import"+format.String())
+	defer span.End()
+
+	file, err := os.Open(importOpts.file)
+	if err != nil {
+		return fmt.Errorf("unable to read input file %s: %v", importOpts.file, err)
+	}
+	defer file.Close()
+	if importOpts.dryRun {
+		if format == pb.ImportFormat_ONIX {
+			return checkONIX(e, file)
+		}
+		return checkMARC(e, format, file)
+	}
+
+	ctx, cancel := e.call(ctx)
+	defer cancel()
+	stream, err := e.client.ImportBooks(ctx)
+	if err != nil {
+		return err
+	}
+	buf := make([]byte, importChunkSize)
+	for {
+		n, err := file.Read(buf)
+		if n > 0 {
+			if serr := stream.Send(&pb.ImportChunk{Format: format, Data: buf[:n]}); serr != nil {
+				// The server ended the call; CloseAndRecv says why.
+				break
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("unable to read input file %s: %v", importOpts.file, err)
+		}
+	}
+	summary, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+	for _, ie := range summary.Errors {
+		if ie.Record == 0 {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", importOpts.file, ie.Message)
+		} else {
+			fmt.Fprintf(os.Stderr, "%s: record %d: %s\n", importOpts.file, ie.Record, ie.Message)
+		}
+	}
+	fmt.Printf("Added %d, updated %d and deleted %d books.\n", summary.Added, summary.Updated, summary.Deleted)
+	if len(summary.Errors) > 0 {
+		return errors.New("some records were not imported")
+	}
+	return nil
+}
+
+// checkMARC parses file the way the server would and prints the books it
+// holds and the records it would reject.
+func checkMARC(e *env, format pb.ImportFormat, file io.Reader) error {
+	var records marc.RecordReader
+	if format == pb.ImportFormat_MARCXML {
+		records = marc.NewXMLReader(file)
+	} else {
+		records = marc.NewReader(file)
+	}
+	var books []Book
+	rejected := 0
+	for n := 1; ; n++ {
+		rec, err := records.Read()
+		if err == io.EOF {
+			break
+		}
+		var recordErr *marc.RecordError
+		if errors.As(err, &recordErr) {
+			fmt.Fprintf(os.Stderr, "%s: record %d: %v\n", importOpts.file, recordErr.Record, recordErr.Err)
+			rejected++
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		book := marc.Book(rec)
+		if book.Title == "" {
+			fmt.Fprintf(os.Stderr, "%s: record %d: record has no title\n", importOpts.file, n)
+			rejected++
+			continue
+		}
+		books = append(books, fromProto(book))
+	}
+	if err := printBooks(e.output, books, true); err != nil {
+		return err
+	}
+	if rejected > 0 {
+		return fmt.Errorf("%d of %d records not imported", rejected, len(books)+rejected)
+	}
+	return nil
+}
+
+// checkONIX parses an ONIX feed and prints the books of the products it
+// would add or replace, and the products it would reject. Deletions are
+// only listed, since matching them needs the server's books.
+func checkONIX(e *env, file io.Reader) error {
+	products := onix.NewReader(file)
+	var books []Book
+	rejected, total := 0, 0
+	for n := 1; ; n++ {
+		p, err := products.Read()
+		if err == io.EOF {
+			break
+		}
+		total++
+		var productErr *onix.ProductError
+		if errors.As(err, &productErr) {
+			fmt.Fprintf(os.Stderr, "%s: product %d: %v\n", importOpts.file, productErr.Product, productErr.Err)
+			rejected++
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		switch {
+		case p.IsTest():
+		case p.IsDelete():
+			fmt.Fprintf(os.Stderr, "%s: product %d: would delete ISBN %s\n", importOpts.file, n, p.Book.Isbn)
+		case p.Book.Title == "":
+			fmt.Fprintf(os.Stderr, "%s: product %d: record has no title\n", importOpts.file, n)
+			rejected++
+		default:
+			books = append(books, fromProto(p.Book))
+		}
+	}
+	if err := printBooks(e.output, books, true); err != nil {
+		return err
+	}
+	if rejected > 0 {
+		return fmt.Errorf("%d of %d products not imported", rejected, total)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"github.com/marcoc22/tutorial3/webhook"
+)
+
+var (
+	listenPort     int
+	listenSecret   string
+	listenFail     int
+	deadLetterKeys = []string{"id", "event_type", "attempts", "error", "failed"}
+)
+
+func init() {
+	commands["dead-letters"] = &command{
+		usage: "dead-letters",
+		help:  "Print the webhook events that could not be delivered.",
+		run:   runDeadLetters,
+	}
+	commands["redeliver"] = &command{
+		usage: "redeliver <delivery-id>...",
+		help:  "Send dead-lettered webhook events again.",
+		run:   runRedeliver,
+	}
+	commands["webhook-listen"] = &command{
+		usage: "webhook-listen [--port P] [--secret S] [--fail N]",
+		help:  "Print the webhook events the server sends to a local port.",
+		flags: func(fs *flag.FlagSet) {
+			fs.IntVar(&listenPort, "port", 8090, "listen on `port`")
+			fs.StringVar(&listenSecret, "secret", "", "reject events not signed with `secret`")
+			fs.IntVar(&listenFail, "fail", 0, "answer the first `N` events with 503, to exercise retries")
+		},
+		run: runWebhookListen,
+	}
+}
+
+func deadLetterRow(d *pb.DeadLetter) []string {
+	return []string{d.Id, d.EventType, strconv.Itoa(int(d.Attempts)), d.Error, formatTime(d.Failed)}
+}
+
+func runDeadLetters(ctx context.Context, e *env, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("unexpected arguments %q", args)
+	}
+	ctx, cancel := e.call(ctx)
+	defer cancel()
+	stream, err := e.circulation.ListDeadLetters(ctx, &pb.ListDeadLettersRequest{})
+	if err != nil {
+		return err
+	}
+	var rows [][]string
+	for {
+		d, err := stream.Recv()
+		if err == io.EOF {
+			return printRows(e.output, deadLetterKeys, rows, true)
+		}
+		if err != nil {
+			return err
+		}
+		rows = append(rows, deadLetterRow(d))
+	}
+}
+
+func runRedeliver(ctx context.Context, e *env, args []string) error {
+	if len(args) == 0 {
+		return errors.New("want at least one delivery ID")
+	}
+	ctx, cancel := e.call(ctx)
+	defer cancel()
+	var rows [][]string
+	for _, id := range args {
+		d, err := e.circulation.Redeliver(ctx, &pb.DeadLetterID{Value: id})
+		if err != nil {
+			return err
+		}
+		rows = append(rows, deadLetterRow(d))
+	}
+	return printRows(e.output, deadLetterKeys, rows, false)
+}
+
+// runWebhookListen serves webhook deliveries until interrupted, printing
+// each event's headers and indented body.
+func runWebhookListen(ctx context.Context, e *env, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("unexpected arguments %q", args)
+	}
+	var mu sync.Mutex
+	received := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "POST only", http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		received++
+		id, eventType := r.Header.Get(webhook.IDHeader), r.Header.Get(webhook.EventHeader)
+		if listenSecret != "" && !webhook.Verify(listenSecret, body, r.Header.Get(webhook.SignatureHeader)) {
+			fmt.Printf("%s %s: bad signature, rejected\n", eventType, id)
+			http.Error(w, "bad signature", http.StatusUnauthorized)
+			return
+		}
+		if received <= listenFail {
+			fmt.Printf("%s %s: failing delivery %d of %d\n", eventType, id, received, listenFail)
+			http.Error(w, "failing on purpose", http.StatusServiceUnavailable)
+			return
+		}
+		var out bytes.Buffer
+		if json.Indent(&out, body, "", "  ") != nil {
+			out.Reset()
+			out.Write(body)
+		}
+		fmt.Printf("%s %s:\n%s\n", eventType, id, out.String())
+		w.WriteHeader(http.StatusNoContent)
+	}
+	addr := fmt.Sprintf("localhost:%d", listenPort)
+	fmt.Fprintf(os.Stderr, "Listening for webhook events on http://%s\n", addr)
+	return http.ListenAndServe(addr, http.HandlerFunc(handler))
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+)
+
+// Work is a work as printed by bookctl.
+type Work struct {
+	Id     string `json:"id"`
+	Title  string `json:"title"`
+	Author string `json:"author"`
+}
+
+var (
+	editionsBy  string
+	workFlags   Work
+	groupDryRun bool
+)
+
+func init() {
+	commands["works"] = &command{
+		usage: "works [text]",
+		help:  "Print the works, or those with a title or author containing text.",
+		run:   runWorks,
+	}
+	commands["editions"] = &command{
+		usage: "editions [--by edition|year] <work-id>",
+		help:  "Print the editions and translations of a work in order.",
+		flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&editionsBy, "by", "edition", "order by edition number or copyright year: edition or year")
+		},
+		run: runEditions,
+	}
+	commands["update-work"] = &command{
+		usage: "update-work <id> [--title T] [--author A]",
+		help:  "Change the title or author of a work.",
+		flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&workFlags.Title, "title", "", "work title")
+			fs.StringVar(&workFlags.Author, "author", "", "work author")
+		},
+		run: runUpdateWork,
+	}
+	commands["group-works"] = &command{
+		usage: "group-works [--dry-run]",
+		help:  "Put the books that are in no work into the works of their titles and authors.",
+		flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(&groupDryRun, "dry-run", false, "print what would be grouped and created without changing anything")
+		},
+		run: runGroupWorks,
+	}
+}
+
+func runWorks(ctx context.Context, e *env, args []string) error {
+	if len(args) > 1 {
+		return errors.New("want at most one search text")
+	}
+	ctx, cancel := e.call(ctx)
+	defer cancel()
+	stream, err := e.client.ListWorks(ctx, &pb.ListWorksRequest{Query: strings.Join(args, "")})
+	if err != nil {
+		return err
+	}
+	var works []Work
+	for {
+		w, err := stream.Recv()
+		if err == io.EOF {
+			return printWorks(e.output, works, true)
+		}
+		if err != nil {
+			return err
+		}
+		works = append(works, Work{Id: w.Id, Title: w.Title, Author: w.Author})
+	}
+}
+
+func runEditions(ctx context.Context, e *env, args []string) error {
+	if len(args) != 1 {
+		return errors.New("want exactly one work ID")
+	}
+	req := &pb.ListEditionsRequest{WorkId: args[0]}
+	switch editionsBy {
+	case "edition":
+		req.Order = pb.EditionOrder_EDITION_NUMBER
+	case "year":
+		req.Order = pb.EditionOrder_COPYRIGHT_YEAR
+	default:
+		return fmt.Errorf("unknown order %q: want edition or year", editionsBy)
+	}
+	ctx, cancel := e.call(ctx)
+	defer cancel()
+	stream, err := e.client.ListEditions(ctx, req)
+	if err != nil {
+		return err
+	}
+	return printBookStream(e, stream)
+}
+
+func runUpdateWork(ctx context.Context, e *env, args []string) error {
+	if len(args) != 1 {
+		return errors.New("want exactly one work ID")
+	}
+	w, err := e.client.GetWork(ctx, &pb.WorkID{Value: args[0]})
+	if err != nil {
+		return err
+	}
+	if workFlags.Title != "" {
+		w.Title = workFlags.Title
+	}
+	if workFlags.Author != "" {
+		w.Author = workFlags.Author
+	}
+	w, err = e.client.UpdateWork(ctx, w)
+	if err != nil {
+		return err
+	}
+	return printWorks(e.output, []Work{{Id: w.Id, Title: w.Title, Author: w.Author}}, false)
+}
+
+func runGroupWorks(ctx context.Context, e *env, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("unexpected arguments %q", args)
+	}
+	summary, err := e.client.GroupWorks(ctx, &pb.GroupWorksRequest{DryRun: groupDryRun})
+	if err != nil {
+		return err
+	}
+	verb := "Grouped"
+	if groupDryRun {
+		verb = "Would group"
+	}
+	fmt.Printf("%s %d books, creating %d works.\n", verb, summary.BooksGrouped, summary.WorksCreated)
+	return nil
+}
+
+// printWorks writes works to stdout in format, like printBooks.
+func printWorks(format string, works []Work, list bool) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if !list && len(works) == 1 {
+			return enc.Encode(works[0])
+		}
+		if works == nil {
+			works = []Work{}
+		}
+		return enc.Encode(works)
+	case "yaml":
+		if list && len(works) == 0 {
+			fmt.Println("[]")
+			return nil
+		}
+		indent, prefix := "", ""
+		if list || len(works) > 1 {
+			indent, prefix = "  ", "- "
+		}
+		for _, w := range works {
+			fmt.Printf("%sid: %s\n%stitle: %s\n%sauthor: %s\n", prefix, strconv.Quote(w.Id),
+				indent, strconv.Quote(w.Title), indent, strconv.Quote(w.Author))
+		}
+		return nil
+	default:
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tTITLE\tAUTHOR")
+		for _, work := range works {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", work.Id, work.Title, work.Author)
+		}
+		return w.Flush()
+	}
+}
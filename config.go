@@ -0,0 +1,625 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marcoc22/tutorial3/circulation"
+	"gopkg.in/yaml.v2"
+)
+
+// config is the server configuration. It is read from an optional YAML
+// file, then overridden by environment variables and finally by command
+// line flags; settings holds the variable and flag of each field.
+type config struct {
+	Listen struct {
+		// Address serves native gRPC, gRPC-Web, REST and metrics.
+		Address string `yaml:"address"`
+		// MetricsAddress and GatewayAddress optionally expose metrics,
+		// and the whole HTTP side, on listeners of their own as well.
+		MetricsAddress string `yaml:"metrics_address"`
+		GatewayAddress string `yaml:"gateway_address"`
+		// TrustedProxies are the addresses or CIDR networks of the
+		// reverse proxies whose X-Forwarded-For headers name the HTTP
+		// clients the rate limits and the log go by.
+		TrustedProxies []string `yaml:"trusted_proxies"`
+	} `yaml:"listen"`
+	TLS struct {
+		CertFile string `yaml:"cert_file"`
+		KeyFile  string `yaml:"key_file"`
+		// ClientCAFile, if set, makes client certificates signed by one
+		// of its CAs mandatory.
+		ClientCAFile string `yaml:"client_ca_file"`
+	} `yaml:"tls"`
+	Store struct {
+		Backend       string        `yaml:"backend"`
+		Path          string        `yaml:"path"`
+		FlushInterval time.Duration `yaml:"flush_interval"`
+		// CacheEntries and CacheBytes bound the read cache kept in front
+		// of the backend. Both zero disables it.
+		CacheEntries int   `yaml:"cache_entries"`
+		CacheBytes   int64 `yaml:"cache_bytes"`
+	} `yaml:"store"`
+	Replication struct {
+		// NodeID names this server among its peers.
+		NodeID string `yaml:"node_id"`
+		// Peers maps the node IDs of the other servers to their
+		// addresses; none disables replication.
+		Peers map[string]string `yaml:"peers"`
+		// Dir keeps the replication log; empty keeps it in memory only,
+		// which only the memory backend allows.
+		Dir             string        `yaml:"dir"`
+		ElectionTimeout time.Duration `yaml:"election_timeout"`
+	} `yaml:"replication"`
+	Sharding struct {
+		// Shards maps shard IDs to the addresses of the servers the
+		// catalog is partitioned over; when set, this server routes
+		// calls to them instead of keeping books itself. Shards can be
+		// added by a reload, not removed.
+		Shards map[string]string `yaml:"shards"`
+		// Serve makes this server a shard, serving its records to the
+		// routers of the cluster.
+		Serve bool `yaml:"serve"`
+	} `yaml:"sharding"`
+	Cluster struct {
+		// Token authenticates the servers of a replicated or sharded
+		// cluster to each other; client tokens are not accepted for the
+		// calls between servers.
+		Token string `yaml:"token"`
+		// CommonNames identify the servers of the cluster by the common
+		// names of their TLS client certificates instead.
+		CommonNames []string `yaml:"common_names"`
+		// InsecureToken allows sending Token without TLS, e.g. between
+		// servers on one host.
+		InsecureToken bool `yaml:"insecure_token"`
+	} `yaml:"cluster"`
+	Circulation struct {
+		// LoanPeriod is how long loans and renewals last.
+		LoanPeriod  time.Duration `yaml:"loan_period"`
+		MaxLoans    int           `yaml:"max_loans"`
+		MaxRenewals int           `yaml:"max_renewals"`
+		// HoldPeriod is how long a copy waits on the hold shelf.
+		HoldPeriod time.Duration `yaml:"hold_period"`
+	} `yaml:"circulation"`
+	Webhooks struct {
+		// URL receives the hold notifications; empty disables them.
+		URL string `yaml:"url"`
+		// Secret keys the HMAC signing every delivery.
+		Secret      string        `yaml:"secret"`
+		MaxAttempts int           `yaml:"max_attempts"`
+		Timeout     time.Duration `yaml:"timeout"`
+	} `yaml:"webhooks"`
+	Limits struct {
+		// RateLimits is a parseRateLimits spec, or "off".
+		RateLimits           string        `yaml:"rate_limits"`
+		MaxMessageSize       int           `yaml:"max_message_size"`
+		MaxConcurrentStreams uint32        `yaml:"max_concurrent_streams"`
+		ShutdownTimeout      time.Duration `yaml:"shutdown_timeout"`
+	} `yaml:"limits"`
+	Auth struct {
+		// Tokens are the accepted bearer tokens; none disables auth.
+		Tokens []string `yaml:"tokens"`
+	} `yaml:"auth"`
+	Log struct {
+		Level    string   `yaml:"level"`
+		Payloads bool     `yaml:"payloads"`
+		Redact   []string `yaml:"redact"`
+	} `yaml:"log"`
+	Metrics struct {
+		Enabled bool `yaml:"enabled"`
+	} `yaml:"metrics"`
+	Tracing struct {
+		// Export is a tracing.Setup destination.
+		Export string `yaml:"export"`
+	} `yaml:"tracing"`
+	Reflection bool `yaml:"reflection"`
+}
+
+// reloadable lists the settings, by section and field name, a SIGHUP
+// applies to a running server.
+var reloadable = map[string]bool{
+	"log.level":          true,
+	"log.payloads":       true,
+	"log.redact":         true,
+	"limits.rate_limits": true,
+	"auth.tokens":        true,
+}
+
+func defaultConfig() *config {
+	c := &config{}
+	c.Listen.Address = ":50051"
+	c.Store.Backend = "memory"
+	c.Store.Path = "books.db"
+	c.Store.FlushInterval = 10 * time.Second
+	c.Replication.ElectionTimeout = 300 * time.Millisecond
+	c.Circulation.LoanPeriod = circulation.DefaultLoanPeriod
+	c.Circulation.MaxLoans = circulation.DefaultMaxLoans
+	c.Circulation.MaxRenewals = circulation.DefaultMaxRenewals
+	c.Circulation.HoldPeriod = circulation.DefaultHoldPeriod
+	c.Webhooks.MaxAttempts = 5
+	c.Webhooks.Timeout = 10 * time.Second
+	c.Limits.RateLimits = defaultRateLimits
+	c.Limits.MaxMessageSize = 4 << 20
+	c.Limits.ShutdownTimeout = 10 * time.Second
+	c.Log.Level = "info"
+	c.Metrics.Enabled = true
+	c.Reflection = true
+	return c
+}
+
+// setting is a configuration field that can be set from an environment
+// variable or a flag.
+type setting struct {
+	flag, env, usage string
+	set              func(c *config, v string) error
+}
+
+var settings = []setting{
+	{"address", "LISTEN_ADDRESS", "`address` to serve on", func(c *config, v string) error {
+		c.Listen.Address = v
+		return nil
+	}},
+	{"port", "PORT", "`port` to serve on, short for --address :port", func(c *config, v string) error {
+		c.Listen.Address = ":" + v
+		return nil
+	}},
+	{"metrics-port", "METRICS_PORT", "extra `port` serving only metrics", func(c *config, v string) error {
+		c.Listen.MetricsAddress = ":" + v
+		return nil
+	}},
+	{"gateway-port", "GATEWAY_PORT", "extra `port` serving gRPC-Web, REST and metrics", func(c *config, v string) error {
+		c.Listen.GatewayAddress = ":" + v
+		return nil
+	}},
+	{"trusted-proxies", "TRUSTED_PROXIES", "comma separated `addresses` or networks of the proxies whose X-Forwarded-For is believed", func(c *config, v string) error {
+		c.Listen.TrustedProxies = splitList(v)
+		return nil
+	}},
+	{"tls-cert", "TLS_CERT_FILE", "TLS certificate `file`", func(c *config, v string) error {
+		c.TLS.CertFile = v
+		return nil
+	}},
+	{"tls-key", "TLS_KEY_FILE", "TLS private key `file`", func(c *config, v string) error {
+		c.TLS.KeyFile = v
+		return nil
+	}},
+	{"tls-client-ca", "TLS_CLIENT_CA_FILE", "`file` of CAs client certificates must be signed by", func(c *config, v string) error {
+		c.TLS.ClientCAFile = v
+		return nil
+	}},
+	{"store", "STORE", "storage `backend`: memory or file", func(c *config, v string) error {
+		c.Store.Backend = v
+		return nil
+	}},
+	{"store-path", "STORE_PATH", "`file` the file backend keeps books in", func(c *config, v string) error {
+		c.Store.Path = v
+		return nil
+	}},
+	{"flush-interval", "FLUSH_INTERVAL", "how often the store is flushed", durationSetter(func(c *config) *time.Duration {
+		return &c.Store.FlushInterval
+	})},
+	{"store-cache-entries", "STORE_CACHE_ENTRIES", "most `records` the store read cache holds", func(c *config, v string) error {
+		n, err := strconv.Atoi(v)
+		c.Store.CacheEntries = n
+		return err
+	}},
+	{"store-cache-bytes", "STORE_CACHE_BYTES", "most `bytes` of records the store read cache holds", func(c *config, v string) error {
+		n, err := strconv.ParseInt(v, 10, 64)
+		c.Store.CacheBytes = n
+		return err
+	}},
+	{"node-id", "REPLICATION_NODE_ID", "`ID` of this server among its replication peers", func(c *config, v string) error {
+		c.Replication.NodeID = v
+		return nil
+	}},
+	{"peers", "REPLICATION_PEERS", "comma separated `id=address` of the other replicas", func(c *config, v string) (err error) {
+		c.Replication.Peers, err = splitAddresses(v)
+		return err
+	}},
+	{"replication-dir", "REPLICATION_DIR", "`directory` keeping the replication log", func(c *config, v string) error {
+		c.Replication.Dir = v
+		return nil
+	}},
+	{"election-timeout", "REPLICATION_ELECTION_TIMEOUT", "how long followers wait for the leader before an election", durationSetter(func(c *config) *time.Duration {
+		return &c.Replication.ElectionTimeout
+	})},
+	{"shards", "SHARDS", "comma separated `id=address` of the shards to route calls to", func(c *config, v string) (err error) {
+		c.Sharding.Shards, err = splitAddresses(v)
+		return err
+	}},
+	{"shard-server", "SHARD_SERVER", "serve the records of this server to the routers of a sharded cluster", boolSetter(func(c *config) *bool {
+		return &c.Sharding.Serve
+	})},
+	{"cluster-token", "CLUSTER_TOKEN", "bearer `token` the servers of a cluster authenticate to each other with", func(c *config, v string) error {
+		c.Cluster.Token = v
+		return nil
+	}},
+	{"cluster-names", "CLUSTER_COMMON_NAMES", "comma separated common `names` of the client certificates of the cluster's servers", func(c *config, v string) error {
+		c.Cluster.CommonNames = splitList(v)
+		return nil
+	}},
+	{"cluster-insecure-token", "CLUSTER_INSECURE_TOKEN", "send the cluster token without TLS", boolSetter(func(c *config) *bool {
+		return &c.Cluster.InsecureToken
+	})},
+	{"loan-period", "LOAN_PERIOD", "how long loans and renewals last", durationSetter(func(c *config) *time.Duration {
+		return &c.Circulation.LoanPeriod
+	})},
+	{"max-loans", "MAX_LOANS", "most copies a patron may have on loan", func(c *config, v string) error {
+		n, err := strconv.Atoi(v)
+		c.Circulation.MaxLoans = n
+		return err
+	}},
+	{"max-renewals", "MAX_RENEWALS", "most times a loan may be renewed", func(c *config, v string) error {
+		n, err := strconv.Atoi(v)
+		c.Circulation.MaxRenewals = n
+		return err
+	}},
+	{"hold-period", "HOLD_PERIOD", "how long a copy waits on the hold shelf", durationSetter(func(c *config) *time.Duration {
+		return &c.Circulation.HoldPeriod
+	})},
+	{"webhook-url", "WEBHOOK_URL", "`URL` receiving hold notifications", func(c *config, v string) error {
+		c.Webhooks.URL = v
+		return nil
+	}},
+	{"webhook-secret", "WEBHOOK_SECRET", "`secret` signing webhook deliveries", func(c *config, v string) error {
+		c.Webhooks.Secret = v
+		return nil
+	}},
+	{"webhook-max-attempts", "WEBHOOK_MAX_ATTEMPTS", "most times a webhook event is sent before it is dead-lettered", func(c *config, v string) error {
+		n, err := strconv.Atoi(v)
+		c.Webhooks.MaxAttempts = n
+		return err
+	}},
+	{"webhook-timeout", "WEBHOOK_TIMEOUT", "how long each webhook delivery attempt may take", durationSetter(func(c *config) *time.Duration {
+		return &c.Webhooks.Timeout
+	})},
+	{"rate-limits", "RATE_LIMITS", "per method rate limits, `method=rate:burst,...`, or off", func(c *config, v string) error {
+		c.Limits.RateLimits = v
+		return nil
+	}},
+	{"max-message-size", "MAX_MESSAGE_SIZE", "largest message, in `bytes`, the server accepts", func(c *config, v string) error {
+		n, err := strconv.Atoi(v)
+		c.Limits.MaxMessageSize = n
+		return err
+	}},
+	{"max-concurrent-streams", "MAX_CONCURRENT_STREAMS", "calls each connection may have in flight, 0 for no limit", func(c *config, v string) error {
+		n, err := strconv.ParseUint(v, 10, 32)
+		c.Limits.MaxConcurrentStreams = uint32(n)
+		return err
+	}},
+	{"shutdown-timeout", "SHUTDOWN_TIMEOUT", "how long shutdown waits for calls in flight", durationSetter(func(c *config) *time.Duration {
+		return &c.Limits.ShutdownTimeout
+	})},
+	{"auth-tokens", "AUTH_TOKENS", "comma separated bearer `tokens` callers must present", func(c *config, v string) error {
+		c.Auth.Tokens = splitList(v)
+		return nil
+	}},
+	{"log-level", "LOG_LEVEL", "`level` of the request log: debug, info, warn or error", func(c *config, v string) error {
+		c.Log.Level = v
+		return nil
+	}},
+	{"log-payloads", "LOG_PAYLOADS", "log request messages at debug level", boolSetter(func(c *config) *bool {
+		return &c.Log.Payloads
+	})},
+	{"log-redact", "LOG_REDACT", "comma separated request `fields` to mask in the log", func(c *config, v string) error {
+		c.Log.Redact = splitList(v)
+		return nil
+	}},
+	{"metrics", "METRICS", "serve Prometheus metrics on /metrics", boolSetter(func(c *config) *bool {
+		return &c.Metrics.Enabled
+	})},
+	{"trace-export", "TRACE_EXPORT", "where to export spans: stdout or file:`path`", func(c *config, v string) error {
+		c.Tracing.Export = v
+		return nil
+	}},
+	{"reflection", "REFLECTION", "register the gRPC reflection service", boolSetter(func(c *config) *bool {
+		return &c.Reflection
+	})},
+}
+
+func durationSetter(field func(c *config) *time.Duration) func(c *config, v string) error {
+	return func(c *config, v string) error {
+		d, err := time.ParseDuration(v)
+		*field(c) = d
+		return err
+	}
+}
+
+// boolSetter accepts on and off besides the values strconv.ParseBool does.
+func boolSetter(field func(c *config) *bool) func(c *config, v string) error {
+	return func(c *config, v string) error {
+		switch strings.ToLower(v) {
+		case "on":
+			v = "true"
+		case "off":
+			v = "false"
+		}
+		b, err := strconv.ParseBool(v)
+		*field(c) = b
+		return err
+	}
+}
+
+func splitList(v string) []string {
+	var list []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// splitAddresses parses a comma separated list of id=address.
+func splitAddresses(v string) (map[string]string, error) {
+	var addresses map[string]string
+	for _, item := range splitList(v) {
+		id, addr, ok := strings.Cut(item, "=")
+		if !ok || id == "" || addr == "" {
+			return nil, fmt.Errorf("want id=address, got %q", item)
+		}
+		if addresses == nil {
+			addresses = make(map[string]string)
+		}
+		addresses[id] = addr
+	}
+	return addresses, nil
+}
+
+// configFlags are the command line flags of the server.
+type configFlags struct {
+	fs     *flag.FlagSet
+	file   string
+	print  bool
+	values map[string]*string
+}
+
+func registerConfigFlags(fs *flag.FlagSet) *configFlags {
+	f := &configFlags{fs: fs, values: make(map[string]*string)}
+	fs.StringVar(&f.file, "config", os.Getenv("CONFIG_FILE"), "YAML configuration `file` (defaults to $CONFIG_FILE)")
+	fs.BoolVar(&f.print, "print-config", false, "print the configuration in effect and exit")
+	for _, s := range settings {
+		f.values[s.flag] = fs.String(s.flag, "", s.usage+" ($"+s.env+")")
+	}
+	return f
+}
+
+// loadConfig reads the configuration file named by the flags, if any,
+// applies the environment and the flags set on the command line on top of
+// it and validates the result.
+func loadConfig(flags *configFlags) (*config, error) {
+	c := defaultConfig()
+	if flags.file != "" {
+		data, err := ioutil.ReadFile(flags.file)
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.UnmarshalStrict(data, c); err != nil {
+			return nil, fmt.Errorf("%s: %v", flags.file, err)
+		}
+	}
+	for _, s := range settings {
+		if v, ok := os.LookupEnv(s.env); ok && v != "" {
+			if err := s.set(c, v); err != nil {
+				return nil, fmt.Errorf("invalid %s: %v", s.env, err)
+			}
+		}
+	}
+	set := make(map[string]bool)
+	flags.fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	for _, s := range settings {
+		if set[s.flag] {
+			if err := s.set(c, *flags.values[s.flag]); err != nil {
+				return nil, fmt.Errorf("invalid --%s: %v", s.flag, err)
+			}
+		}
+	}
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *config) validate() error {
+	for _, addr := range []string{c.Listen.Address, c.Listen.MetricsAddress, c.Listen.GatewayAddress} {
+		if addr == "" {
+			continue
+		}
+		if _, port, err := net.SplitHostPort(addr); err != nil {
+			return fmt.Errorf("invalid listen address %q: %v", addr, err)
+		} else if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+			return fmt.Errorf("invalid port in listen address %q", addr)
+		}
+	}
+	if c.Listen.Address == "" {
+		return fmt.Errorf("listen.address is required")
+	}
+	if _, err := parseTrustedProxies(c.Listen.TrustedProxies); err != nil {
+		return fmt.Errorf("invalid listen.trusted_proxies: %v", err)
+	}
+	if (c.TLS.CertFile == "") != (c.TLS.KeyFile == "") {
+		return fmt.Errorf("tls.cert_file and tls.key_file must be set together")
+	}
+	if c.TLS.ClientCAFile != "" && c.TLS.CertFile == "" {
+		return fmt.Errorf("tls.client_ca_file requires tls.cert_file and tls.key_file")
+	}
+	switch c.Store.Backend {
+	case "memory":
+	case "file":
+		if c.Store.Path == "" {
+			return fmt.Errorf("store.path is required by the file backend")
+		}
+	default:
+		return fmt.Errorf("unknown store.backend %q: want memory or file", c.Store.Backend)
+	}
+	if c.Store.CacheEntries < 0 || c.Store.CacheBytes < 0 {
+		return fmt.Errorf("store.cache_entries and store.cache_bytes must not be negative")
+	}
+	if c.Store.FlushInterval <= 0 {
+		return fmt.Errorf("store.flush_interval must be positive")
+	}
+	if len(c.Replication.Peers) > 0 {
+		if c.Replication.NodeID == "" {
+			return fmt.Errorf("replication.peers requires replication.node_id")
+		}
+		if _, ok := c.Replication.Peers[c.Replication.NodeID]; ok {
+			return fmt.Errorf("replication.peers must not list node %s itself", c.Replication.NodeID)
+		}
+		if c.Replication.ElectionTimeout <= 0 {
+			return fmt.Errorf("replication.election_timeout must be positive")
+		}
+		// A file store outlives the process, and so must the log of the
+		// writes applied to it, or a restarted replica would replay them
+		// over a store that already holds them.
+		if c.Store.Backend == "file" && c.Replication.Dir == "" {
+			return fmt.Errorf("replication.dir is required when replicating the file backend")
+		}
+		if len(c.Sharding.Shards) > 0 {
+			return fmt.Errorf("sharding.shards and replication.peers cannot be set together: replicate the shards instead")
+		}
+	}
+	if c.Cluster.Token != "" {
+		if strings.ContainsAny(c.Cluster.Token, " \t\r\n") {
+			return fmt.Errorf("cluster.token must not contain white space")
+		}
+		for _, t := range c.Auth.Tokens {
+			if t == c.Cluster.Token {
+				return fmt.Errorf("cluster.token must not be one of auth.tokens")
+			}
+		}
+		if c.TLS.CertFile == "" && !c.Cluster.InsecureToken {
+			return fmt.Errorf("cluster.token requires TLS, or cluster.insecure_token")
+		}
+	}
+	if len(c.Cluster.CommonNames) > 0 && c.TLS.ClientCAFile == "" {
+		return fmt.Errorf("cluster.common_names requires tls.client_ca_file")
+	}
+	if c.Sharding.Serve && len(c.Sharding.Shards) > 0 {
+		return fmt.Errorf("sharding.serve and sharding.shards cannot be set together: a router is not a shard")
+	}
+	if (len(c.Replication.Peers) > 0 || len(c.Sharding.Shards) > 0 || c.Sharding.Serve) && c.Cluster.Token == "" && len(c.Cluster.CommonNames) == 0 {
+		return fmt.Errorf("replication.peers and sharding require cluster.token or cluster.common_names")
+	}
+	if c.Circulation.LoanPeriod <= 0 || c.Circulation.MaxLoans <= 0 {
+		return fmt.Errorf("circulation.loan_period and circulation.max_loans must be positive")
+	}
+	if c.Circulation.MaxRenewals < 0 {
+		return fmt.Errorf("circulation.max_renewals must not be negative")
+	}
+	if c.Circulation.HoldPeriod <= 0 {
+		return fmt.Errorf("circulation.hold_period must be positive")
+	}
+	if c.Webhooks.URL != "" {
+		if u, err := url.Parse(c.Webhooks.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid webhooks.url %q: want an http or https URL", c.Webhooks.URL)
+		}
+		if c.Webhooks.Secret == "" {
+			return fmt.Errorf("webhooks.url requires webhooks.secret")
+		}
+		if c.Webhooks.MaxAttempts <= 0 || c.Webhooks.Timeout <= 0 {
+			return fmt.Errorf("webhooks.max_attempts and webhooks.timeout must be positive")
+		}
+	}
+	if _, err := c.rateLimits(); err != nil {
+		return fmt.Errorf("invalid limits.rate_limits: %v", err)
+	}
+	if c.Limits.MaxMessageSize <= 0 {
+		return fmt.Errorf("limits.max_message_size must be positive")
+	}
+	if c.Limits.ShutdownTimeout <= 0 {
+		return fmt.Errorf("limits.shutdown_timeout must be positive")
+	}
+	for _, t := range c.Auth.Tokens {
+		if strings.TrimSpace(t) == "" || strings.ContainsAny(t, " \t\r\n") {
+			return fmt.Errorf("auth.tokens must not be empty or contain white space")
+		}
+	}
+	if _, err := parseLogLevel(c.Log.Level); err != nil {
+		return fmt.Errorf("invalid log.level: %v", err)
+	}
+	switch {
+	case c.Tracing.Export == "", c.Tracing.Export == "stdout", strings.HasPrefix(c.Tracing.Export, "file:"):
+	default:
+		return fmt.Errorf("invalid tracing.export %q: want stdout or file:<path>", c.Tracing.Export)
+	}
+	return nil
+}
+
+// rateLimits parses the rate limits, "off" meaning none.
+func (c *config) rateLimits() (map[string]rateLimit, error) {
+	if c.Limits.RateLimits == "off" {
+		return map[string]rateLimit{}, nil
+	}
+	return parseRateLimits(c.Limits.RateLimits)
+}
+
+// circulationPolicy returns the lending rules.
+func (c *config) circulationPolicy() circulation.Policy {
+	return circulation.Policy{
+		LoanPeriod:  c.Circulation.LoanPeriod,
+		MaxLoans:    c.Circulation.MaxLoans,
+		MaxRenewals: c.Circulation.MaxRenewals,
+		HoldPeriod:  c.Circulation.HoldPeriod,
+	}
+}
+
+// logLevel returns the parsed log level; validate has checked it.
+func (c *config) logLevel() logLevel {
+	level, _ := parseLogLevel(c.Log.Level)
+	return level
+}
+
+// redacted returns a copy of c that is safe to print.
+func (c *config) redacted() *config {
+	r := *c
+	r.Auth.Tokens = nil
+	for range c.Auth.Tokens {
+		r.Auth.Tokens = append(r.Auth.Tokens, "[REDACTED]")
+	}
+	if c.Cluster.Token != "" {
+		r.Cluster.Token = "[REDACTED]"
+	}
+	if c.Webhooks.Secret != "" {
+		r.Webhooks.Secret = "[REDACTED]"
+	}
+	return &r
+}
+
+// YAML renders c, with its secrets redacted, as a configuration file.
+func (c *config) YAML() string {
+	out, err := yaml.Marshal(c.redacted())
+	if err != nil {
+		return fmt.Sprintf("# unable to render configuration: %v\n", err)
+	}
+	return string(out)
+}
+
+// changedSettings lists, by section and field name, the settings whose
+// values differ between c and other.
+func (c *config) changedSettings(other *config) []string {
+	var changed []string
+	var walk func(prefix string, a, b reflect.Value)
+	walk = func(prefix string, a, b reflect.Value) {
+		for i := 0; i < a.NumField(); i++ {
+			name := prefix + strings.Split(a.Type().Field(i).Tag.Get("yaml"), ",")[0]
+			fa, fb := a.Field(i), b.Field(i)
+			if fa.Kind() == reflect.Struct && fa.Type() != reflect.TypeOf(time.Duration(0)) {
+				walk(name+".", fa, fb)
+				continue
+			}
+			if !reflect.DeepEqual(fa.Interface(), fb.Interface()) {
+				changed = append(changed, name)
+			}
+		}
+	}
+	walk("", reflect.ValueOf(*c), reflect.ValueOf(*other))
+	return changed
+}
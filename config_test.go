@@ -0,0 +1,256 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// writeConfig writes a configuration file into a temporary directory and
+// returns its path.
+func writeConfig(t *testing.T, yaml string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "bookinfo.yaml")
+	if err := ioutil.WriteFile(path, []byte(yaml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// parseConfig loads the configuration with the command line args.
+func parseConfig(t *testing.T, args ...string) (*config, *configFlags, error) {
+	t.Helper()
+	fs := flag.NewFlagSet("bookinfo", flag.ContinueOnError)
+	flags := registerConfigFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	c, err := loadConfig(flags)
+	return c, flags, err
+}
+
+func TestConfigPrecedence(t *testing.T) {
+	file := writeConfig(t, `
+listen:
+  address: ":1000"
+  gateway_address: ":1001"
+store:
+  backend: file
+  path: /var/lib/bookinfo/books.db
+limits:
+  rate_limits: "*=5:10"
+log:
+  level: warn
+  redact: [author]
+`)
+	tests := []struct {
+		name string
+		env  map[string]string
+		args []string
+		// want is checked against the configuration as address, log
+		// level, rate limits, store path and redacted fields.
+		want []interface{}
+	}{
+		{"file", nil, nil,
+			[]interface{}{":1000", "warn", "*=5:10", "/var/lib/bookinfo/books.db", []string{"author"}}},
+		{"environment over file",
+			map[string]string{"PORT": "2000", "LOG_LEVEL": "error", "LOG_REDACT": "title, publisher"}, nil,
+			[]interface{}{":2000", "error", "*=5:10", "/var/lib/bookinfo/books.db", []string{"title", "publisher"}}},
+		// Empty variables are ignored rather than clearing the setting.
+		{"empty environment", map[string]string{"LOG_LEVEL": "", "STORE_PATH": ""}, nil,
+			[]interface{}{":1000", "warn", "*=5:10", "/var/lib/bookinfo/books.db", []string{"author"}}},
+		{"flags over environment",
+			map[string]string{"PORT": "2000", "LOG_LEVEL": "error"},
+			[]string{"--address", "127.0.0.1:3000", "--rate-limits", "off", "--store-path", "/tmp/books.db"},
+			[]interface{}{"127.0.0.1:3000", "error", "off", "/tmp/books.db", []string{"author"}}},
+		// A flag set to its zero value still overrides.
+		{"empty flag", nil, []string{"--log-redact", ""},
+			[]interface{}{":1000", "warn", "*=5:10", "/var/lib/bookinfo/books.db", []string(nil)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("CONFIG_FILE", file)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			c, _, err := parseConfig(t, tt.args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []interface{}{c.Listen.Address, c.Log.Level, c.Limits.RateLimits, c.Store.Path, c.Log.Redact}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("config = %q, want %q", got, tt.want)
+			}
+			// Settings nobody sets keep their file or default values.
+			if c.Listen.GatewayAddress != ":1001" || c.Store.Backend != "file" || c.Limits.ShutdownTimeout != 10*time.Second {
+				t.Errorf("untouched settings changed: gateway %q, backend %q, shutdown timeout %v",
+					c.Listen.GatewayAddress, c.Store.Backend, c.Limits.ShutdownTimeout)
+			}
+		})
+	}
+}
+
+func TestConfigErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		env  map[string]string
+		args []string
+		want string
+	}{
+		{"unknown file setting", "listen:\n  adress: \":1\"\n", nil, nil, "field adress not found"},
+		{"bad file value", "store:\n  flush_interval: soon\n", nil, nil, "cannot unmarshal"},
+		{"bad variable", "", map[string]string{"SHUTDOWN_TIMEOUT": "soon"}, nil, "invalid SHUTDOWN_TIMEOUT"},
+		{"bad flag", "", nil, []string{"--metrics", "maybe"}, "invalid --metrics"},
+		{"bad port", "", map[string]string{"PORT": "http"}, nil, `invalid port in listen address ":http"`},
+		{"bad proxy", "listen:\n  trusted_proxies: [10.0.0.0/99]\n", nil, nil, "invalid listen.trusted_proxies"},
+		{"half of TLS", "", nil, []string{"--tls-cert", "cert.pem"}, "must be set together"},
+		{"file store without path", "store:\n  backend: file\n  path: \"\"\n", nil, nil, "store.path is required"},
+		{"bad rate limits", "", map[string]string{"RATE_LIMITS": "AddBook"}, nil, "invalid limits.rate_limits"},
+		{"blank token", "auth:\n  tokens: [\"a b\"]\n", nil, nil, "auth.tokens"},
+		{"peers without node ID", "", map[string]string{"REPLICATION_PEERS": "n2=10.0.0.2:50051"}, nil, "requires replication.node_id"},
+		{"peers without cluster credential", "", nil, []string{"--node-id", "n1", "--peers", "n2=10.0.0.2:50051"},
+			"require cluster.token or cluster.common_names"},
+		{"replicated file store without log", "store:\n  backend: file\n", nil,
+			[]string{"--node-id", "n1", "--peers", "n2=10.0.0.2:50051", "--cluster-token", "c", "--cluster-insecure-token=true"},
+			"replication.dir is required when replicating the file backend"},
+		{"shard serving without cluster credential", "", nil, []string{"--shard-server=true"}, "require cluster.token or cluster.common_names"},
+		{"router serving as a shard", "", nil, []string{"--shard-server=true", "--shards", "s1=10.0.0.2:50051"}, "a router is not a shard"},
+		{"replicated router", "", nil, []string{"--node-id", "n1", "--peers", "n2=10.0.0.2:50051", "--shards", "s1=10.0.0.3:50051"},
+			"cannot be set together"},
+		{"webhook URL without secret", "", map[string]string{"WEBHOOK_URL": "https://hooks.example.com/holds"}, nil, "requires webhooks.secret"},
+		{"webhook URL not HTTP", "", nil, []string{"--webhook-url", "ftp://hooks.example.com", "--webhook-secret", "s"}, "want an http or https URL"},
+		{"cluster token without TLS", "", nil, []string{"--cluster-token", "c"}, "cluster.token requires TLS"},
+		{"cluster token reused by clients", "auth:\n  tokens: [c]\n", nil, []string{"--cluster-token", "c", "--cluster-insecure-token=true"},
+			"must not be one of auth.tokens"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("CONFIG_FILE", writeConfig(t, tt.yaml))
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			_, _, err := parseConfig(t, tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("loadConfig = %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigYAMLRedactsTokens(t *testing.T) {
+	c := defaultConfig()
+	c.Auth.Tokens = []string{"s3cret", "t0ken"}
+	c.Cluster.Token = "clust3r"
+	c.Webhooks.Secret = "h00k"
+	out := c.YAML()
+	if strings.Contains(out, "s3cret") || strings.Contains(out, "clust3r") || strings.Contains(out, "h00k") || strings.Count(out, "[REDACTED]") != 4 {
+		t.Errorf("YAML shows the tokens:\n%s", out)
+	}
+	if len(c.Auth.Tokens) != 2 || c.Auth.Tokens[0] != "s3cret" {
+		t.Errorf("YAML changed the configuration's tokens to %q", c.Auth.Tokens)
+	}
+}
+
+func TestReload(t *testing.T) {
+	file := writeConfig(t, "listen:\n  address: \":1000\"\nlog:\n  level: info\n")
+	t.Setenv("CONFIG_FILE", file)
+	current, flags, err := parseConfig(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var logs bytes.Buffer
+	logger := newRequestLogger(&logs, current.logLevel(), false, nil)
+	limits, _ := current.rateLimits()
+	limiter := newRateLimiter(limits, nil)
+	auth := newTokenAuth(nil, nil)
+
+	// A broken file leaves everything as it was.
+	if err := ioutil.WriteFile(file, []byte("log:\n  level: loud\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got := reload(current, flags, logger, limiter, auth, nil); got != current {
+		t.Errorf("reload of a broken file applied %+v", got)
+	}
+
+	if err := ioutil.WriteFile(file, []byte(`
+listen:
+  address: ":2000"
+log:
+  level: debug
+  payloads: true
+  redact: [author]
+limits:
+  rate_limits: "*=1:1"
+auth:
+  tokens: [s3cret]
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	next := reload(current, flags, logger, limiter, auth, nil)
+	if next.Log.Level != "debug" || !next.Log.Payloads || next.Limits.RateLimits != "*=1:1" || len(next.Auth.Tokens) != 1 {
+		t.Errorf("reloaded configuration %+v lacks the new log, limits and auth settings", next)
+	}
+	// The listen address only changes on restart.
+	if next.Listen.Address != ":1000" {
+		t.Errorf("reload moved the listen address to %q", next.Listen.Address)
+	}
+	// Payloads are only logged at debug level.
+	if !logger.logsPayloads() {
+		t.Error("logger not reconfigured for debug payloads")
+	}
+	if got := logger.payload(&pb.Book{Author: "Frank Herbert"}).(map[string]interface{}); got["author"] != "[REDACTED]" {
+		t.Errorf("payload after reload = %v, want the author redacted", got)
+	}
+	if err := auth.check(context.Background(), "/booksapp.BookInfo/GetBook"); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("call without a token after reload = %v, want Unauthenticated", err)
+	}
+	alice := peerContext(&net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 40000})
+	if _, err := limiter.check(alice, "/booksapp.BookInfo/GetBook"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := limiter.check(alice, "/booksapp.BookInfo/GetBook"); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("second call after reload = %v, want ResourceExhausted", err)
+	}
+}
+
+// TestSIGHUPReload changes the configuration file of a running server and
+// checks that SIGHUP applies it.
+func TestSIGHUPReload(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the server")
+	}
+	file := writeConfig(t, "")
+	server := startServer(t, "CONFIG_FILE="+file)
+	client := pb.NewBookInfoClient(server.dial(t))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := client.AddBook(ctx, &pb.Book{Title: "Dune"}); err != nil {
+		t.Fatalf("AddBook without auth: %v", err)
+	}
+
+	if err := ioutil.WriteFile(file, []byte("auth:\n  tokens: [s3cret]\nstore:\n  backend: file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.cmd.Process.Signal(syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+	server.waitLog(t, "Ignoring change to store.backend until restart")
+	server.waitLog(t, "Reloaded configuration")
+
+	if _, err := client.AddBook(ctx, &pb.Book{Title: "Emma"}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("AddBook without a token after reload = %v, want Unauthenticated", err)
+	}
+}
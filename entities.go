@@ -0,0 +1,408 @@
+package main
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"github.com/marcoc22/tutorial3/citation"
+	"github.com/marcoc22/tutorial3/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// The store collections authors and publishers are kept in.
+const (
+	authorsCollection    = "authors"
+	publishersCollection = "publishers"
+)
+
+// entity is an author or a publisher: a named record books refer to.
+type entity interface {
+	proto.Message
+	GetId() string
+	GetName() string
+}
+
+// entityKind describes one kind of entity.
+type entityKind struct {
+	name       string
+	collection string
+	new        func() entity
+	// references reports whether book refers to the entity with id.
+	references func(book *pb.Book, id string) bool
+}
+
+var (
+	authorKind = &entityKind{
+		name:       "Author",
+		collection: authorsCollection,
+		new:        func() entity { return &pb.Author{} },
+		references: func(book *pb.Book, id string) bool {
+			for _, c := range book.Contributors {
+				if c.AuthorId == id {
+					return true
+				}
+			}
+			return false
+		},
+	}
+	publisherKind = &entityKind{
+		name:       "Publisher",
+		collection: publishersCollection,
+		new:        func() entity { return &pb.Publisher{} },
+		references: func(book *pb.Book, id string) bool {
+			return book.PublisherId == id
+		},
+	}
+)
+
+// newEntity returns an entity of kind with id and name.
+func (k *entityKind) newEntity(id, name string) entity {
+	e := k.new()
+	m := e.ProtoReflect()
+	fields := m.Descriptor().Fields()
+	m.Set(fields.ByName("id"), protoreflect.ValueOfString(id))
+	m.Set(fields.ByName("name"), protoreflect.ValueOfString(name))
+	return e
+}
+
+// normalizeName folds the case and spacing of a name for matching.
+func normalizeName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+func (s *server) getEntity(kind *entityKind, id string) (entity, error) {
+	e := kind.new()
+	if err := store.GetMessage(s.store, kind.collection, id, e); err != nil {
+		return nil, recordStatus(err, kind.name, id)
+	}
+	return e, nil
+}
+
+func (s *server) putEntity(kind *entityKind, e entity) error {
+	if strings.TrimSpace(e.GetName()) == "" {
+		return status.Errorf(codes.InvalidArgument, "%s name is required.", kind.name)
+	}
+	if err := store.PutMessage(s.store, kind.collection, e.GetId(), e); err != nil {
+		return recordStatus(err, kind.name, e.GetId())
+	}
+	return nil
+}
+
+// addEntity stores e under a new ID, which it sets.
+func (s *server) addEntity(kind *entityKind, e entity) error {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return status.Errorf(codes.Internal, "Error while generating %s ID: %v", kind.name, err)
+	}
+	m := e.ProtoReflect()
+	m.Set(m.Descriptor().Fields().ByName("id"), protoreflect.ValueOfString(id.String()))
+	return s.putEntity(kind, e)
+}
+
+// updateEntity replaces the entity with e's ID, which must exist. It
+// holds s.mu so that an entity being deleted is not brought back.
+func (s *server) updateEntity(kind *entityKind, e entity) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.getEntity(kind, e.GetId()); err != nil {
+		return err
+	}
+	return s.putEntity(kind, e)
+}
+
+// deleteEntity deletes the entity with id unless a book refers to it.
+func (s *server) deleteEntity(kind *entityKind, id string) (entity, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, err := s.getEntity(kind, id)
+	if err != nil {
+		return nil, err
+	}
+	referenced := 0
+	err = s.scanBooks(func(book *pb.Book) error {
+		if kind.references(book, id) {
+			referenced++
+		}
+		return nil
+	})
+	if _, ok := status.FromError(err); !ok {
+		return nil, status.Errorf(codes.Internal, "Error while listing books: %v", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if referenced > 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "%s %s is referenced by %d books.", kind.name, id, referenced)
+	}
+	if err := s.store.Delete(kind.collection, id); err != nil {
+		return nil, recordStatus(err, kind.name, id)
+	}
+	return e, nil
+}
+
+// scanEntities calls fn with the entities of kind whose name contains
+// query, ignoring case, in ID order.
+func (s *server) scanEntities(kind *entityKind, query string, fn func(e entity) error) error {
+	query = strings.ToLower(query)
+	err := s.store.Scan(kind.collection, func(id string, value []byte) error {
+		e := kind.new()
+		if err := proto.Unmarshal(value, e); err != nil {
+			return status.Errorf(codes.DataLoss, "%s %s is corrupt: %v", kind.name, id, err)
+		}
+		if !strings.Contains(strings.ToLower(e.GetName()), query) {
+			return nil
+		}
+		return fn(e)
+	})
+	if _, ok := status.FromError(err); !ok {
+		return status.Errorf(codes.Internal, "Error while listing %ss: %v", strings.ToLower(kind.name), err)
+	}
+	return err
+}
+
+// listBooksReferencing sends the books referring to the entity with id
+// and passing filter to send.
+func (s *server) listBooksReferencing(kind *entityKind, id string, filter func(book *pb.Book) bool, send func(book *pb.Book) error) error {
+	if _, err := s.getEntity(kind, id); err != nil {
+		return err
+	}
+	err := s.scanBooks(func(book *pb.Book) error {
+		if !kind.references(book, id) || !filter(book) {
+			return nil
+		}
+		return send(book)
+	})
+	if _, ok := status.FromError(err); !ok {
+		return status.Errorf(codes.Internal, "Error while listing books: %v", err)
+	}
+	return err
+}
+
+// resolveReferences checks that the authors and publisher book refers to
+// exist, makes contributors without a role authors and fills in empty
+// Author and Publisher text from the names referenced. s.mu must be held.
+func (s *server) resolveReferences(ctx context.Context, book *pb.Book) error {
+	var authors []string
+	for _, c := range book.Contributors {
+		if c.AuthorId == "" {
+			return status.Errorf(codes.InvalidArgument, "Contributors need an author_id.")
+		}
+		if c.Role == pb.ContributorRole_CONTRIBUTOR_ROLE_UNSPECIFIED {
+			c.Role = pb.ContributorRole_AUTHOR
+		}
+		author, err := s.getEntity(authorKind, c.AuthorId)
+		if err != nil {
+			return referenceError(err)
+		}
+		if c.Role == pb.ContributorRole_AUTHOR {
+			authors = append(authors, author.GetName())
+		}
+	}
+	if book.Author == "" {
+		book.Author = strings.Join(authors, ", ")
+	}
+	if book.PublisherId != "" {
+		publisher, err := s.getEntity(publisherKind, book.PublisherId)
+		if err != nil {
+			return referenceError(err)
+		}
+		if book.Publisher == "" {
+			book.Publisher = publisher.GetName()
+		}
+	}
+	return nil
+}
+
+// referenceError reports a missing entity as a failed precondition of
+// the book call referring to it.
+func referenceError(err error) error {
+	if st := status.Convert(err); st.Code() == codes.NotFound {
+		return status.Error(codes.FailedPrecondition, st.Message())
+	}
+	return err
+}
+
+func (s *server) AddAuthor(ctx context.Context, in *pb.Author) (*pb.Author, error) {
+	if err := s.addEntity(authorKind, in); err != nil {
+		return nil, err
+	}
+	return in, status.New(codes.OK, "").Err()
+}
+
+func (s *server) GetAuthor(ctx context.Context, in *pb.AuthorID) (*pb.Author, error) {
+	e, err := s.getEntity(authorKind, in.Value)
+	if err != nil {
+		return nil, err
+	}
+	return e.(*pb.Author), status.New(codes.OK, "").Err()
+}
+
+// UpdateAuthor renames an author. The Author text of the books crediting
+// them is left as it is.
+func (s *server) UpdateAuthor(ctx context.Context, in *pb.Author) (*pb.Author, error) {
+	if err := s.updateEntity(authorKind, in); err != nil {
+		return nil, err
+	}
+	return in, status.New(codes.OK, "").Err()
+}
+
+func (s *server) DeleteAuthor(ctx context.Context, in *pb.AuthorID) (*pb.Author, error) {
+	e, err := s.deleteEntity(authorKind, in.Value)
+	if err != nil {
+		return nil, err
+	}
+	return e.(*pb.Author), status.New(codes.OK, "").Err()
+}
+
+func (s *server) ListAuthors(in *pb.ListAuthorsRequest, stream pb.BookInfo_ListAuthorsServer) error {
+	return s.scanEntities(authorKind, in.Query, func(e entity) error {
+		return stream.Send(e.(*pb.Author))
+	})
+}
+
+func (s *server) AddPublisher(ctx context.Context, in *pb.Publisher) (*pb.Publisher, error) {
+	if err := s.addEntity(publisherKind, in); err != nil {
+		return nil, err
+	}
+	return in, status.New(codes.OK, "").Err()
+}
+
+func (s *server) GetPublisher(ctx context.Context, in *pb.PublisherID) (*pb.Publisher, error) {
+	e, err := s.getEntity(publisherKind, in.Value)
+	if err != nil {
+		return nil, err
+	}
+	return e.(*pb.Publisher), status.New(codes.OK, "").Err()
+}
+
+// UpdatePublisher renames a publisher. The Publisher text of its books is
+// left as it is.
+func (s *server) UpdatePublisher(ctx context.Context, in *pb.Publisher) (*pb.Publisher, error) {
+	if err := s.updateEntity(publisherKind, in); err != nil {
+		return nil, err
+	}
+	return in, status.New(codes.OK, "").Err()
+}
+
+func (s *server) DeletePublisher(ctx context.Context, in *pb.PublisherID) (*pb.Publisher, error) {
+	e, err := s.deleteEntity(publisherKind, in.Value)
+	if err != nil {
+		return nil, err
+	}
+	return e.(*pb.Publisher), status.New(codes.OK, "").Err()
+}
+
+func (s *server) ListPublishers(in *pb.ListPublishersRequest, stream pb.BookInfo_ListPublishersServer) error {
+	return s.scanEntities(publisherKind, in.Query, func(e entity) error {
+		return stream.Send(e.(*pb.Publisher))
+	})
+}
+
+func (s *server) ListBooksByAuthor(in *pb.ListBooksByAuthorRequest, stream pb.BookInfo_ListBooksByAuthorServer) error {
+	inRole := func(book *pb.Book) bool {
+		if in.Role == pb.ContributorRole_CONTRIBUTOR_ROLE_UNSPECIFIED {
+			return true
+		}
+		for _, c := range book.Contributors {
+			if c.AuthorId == in.AuthorId && c.Role == in.Role {
+				return true
+			}
+		}
+		return false
+	}
+	return s.listBooksReferencing(authorKind, in.AuthorId, inRole, stream.Send)
+}
+
+func (s *server) ListBooksByPublisher(in *pb.ListBooksByPublisherRequest, stream pb.BookInfo_ListBooksByPublisherServer) error {
+	all := func(*pb.Book) bool { return true }
+	return s.listBooksReferencing(publisherKind, in.PublisherId, all, stream.Send)
+}
+
+// LinkEntities gives the books with Author or Publisher text but no
+// references to authors or a publisher references to the ones with those
+// names, creating them as needed. Author text naming several authors, as
+// citations read it, credits each.
+func (s *server) LinkEntities(ctx context.Context, in *pb.LinkEntitiesRequest) (*pb.LinkEntitiesSummary, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Map the normalized names of the existing entities to their IDs.
+	authors := make(map[string]string)
+	publishers := make(map[string]string)
+	for kind, index := range map[*entityKind]map[string]string{authorKind: authors, publisherKind: publishers} {
+		err := s.scanEntities(kind, "", func(e entity) error {
+			if _, ok := index[normalizeName(e.GetName())]; !ok {
+				index[normalizeName(e.GetName())] = e.GetId()
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var unlinked []*pb.Book
+	err := s.scanBooks(func(book *pb.Book) error {
+		if (len(book.Contributors) == 0 && book.Author != "") || (book.PublisherId == "" && book.Publisher != "") {
+			unlinked = append(unlinked, book)
+		}
+		return nil
+	})
+	if _, ok := status.FromError(err); !ok {
+		return nil, status.Errorf(codes.Internal, "Error while listing books: %v", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &pb.LinkEntitiesSummary{}
+	// find returns the ID of the entity of kind named name, creating it
+	// if there is none.
+	find := func(kind *entityKind, index map[string]string, name string, created *int32) (string, error) {
+		key := normalizeName(name)
+		if id, ok := index[key]; ok {
+			return id, nil
+		}
+		e := kind.newEntity("", strings.Join(strings.Fields(name), " "))
+		if !in.DryRun {
+			if err := s.addEntity(kind, e); err != nil {
+				return "", err
+			}
+		}
+		*created++
+		index[key] = e.GetId()
+		return e.GetId(), nil
+	}
+	for _, book := range unlinked {
+		if len(book.Contributors) == 0 {
+			for _, n := range citation.Authors(book.Author) {
+				id, err := find(authorKind, authors, n.Given+" "+n.Family, &summary.AuthorsCreated)
+				if err != nil {
+					return nil, err
+				}
+				book.Contributors = append(book.Contributors, &pb.Contributor{AuthorId: id, Role: pb.ContributorRole_AUTHOR})
+			}
+		}
+		if book.PublisherId == "" && book.Publisher != "" {
+			id, err := find(publisherKind, publishers, book.Publisher, &summary.PublishersCreated)
+			if err != nil {
+				return nil, err
+			}
+			book.PublisherId = id
+		}
+		summary.BooksLinked++
+		if in.DryRun {
+			continue
+		}
+		if err := s.putBook(ctx, book); err != nil {
+			return nil, err
+		}
+		s.publishChange(&pb.BookChange{Type: pb.ChangeType_UPDATED, Id: book.Id, Book: book})
+	}
+	return summary, status.New(codes.OK, "").Err()
+}
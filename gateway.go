@@ -0,0 +1,317 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// booksPath is the REST collection the gateway serves.
+const booksPath = "/v1/books"
+
+// maxBodySize bounds the size of request bodies accepted by the gateway.
+const maxBodySize = 1 << 20
+
+var jsonOptions = protojson.MarshalOptions{EmitUnpopulated: true}
+
+// gateway translates REST/JSON requests into BookInfo RPCs:
+//
+//	POST   /v1/books       AddBook
+//	GET    /v1/books/{id}  GetBook
+//	PATCH  /v1/books/{id}  GetBook, then UpdateBook with the given fields
+//	DELETE /v1/books/{id}  DeleteBook
+//
+// Books are encoded with the lowerCamel JSON field names (id, title,
+// edition, ...) used by the client's Book type.
+type gateway struct {
+	client pb.BookInfoClient
+}
+
+func newGateway(client pb.BookInfoClient) *gateway {
+	return &gateway{client: client}
+}
+
+func (g *gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := outgoingContext(r)
+	if r.URL.Path == booksPath {
+		if r.Method != http.MethodPost {
+			writeHTTPError(w, http.StatusMethodNotAllowed, codes.Unimplemented, "Method not allowed")
+			return
+		}
+		g.addBook(ctx, w, r)
+		return
+	}
+	if !strings.HasPrefix(r.URL.Path, booksPath+"/") {
+		writeHTTPError(w, http.StatusNotFound, codes.NotFound, "Not found")
+		return
+	}
+	id := strings.TrimPrefix(r.URL.Path, booksPath+"/")
+	if id == "" || strings.Contains(id, "/") {
+		writeHTTPError(w, http.StatusNotFound, codes.NotFound, "Not found")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		var trailer metadata.MD
+		book, err := g.client.GetBook(ctx, &pb.BookID{Value: id}, grpc.Trailer(&trailer))
+		g.reply(w, http.StatusOK, book, trailer, err)
+	case http.MethodPatch:
+		g.patchBook(ctx, w, r, id)
+	case http.MethodDelete:
+		var trailer metadata.MD
+		book, err := g.client.DeleteBook(ctx, &pb.BookID{Value: id}, grpc.Trailer(&trailer))
+		g.reply(w, http.StatusOK, book, trailer, err)
+	default:
+		writeHTTPError(w, http.StatusMethodNotAllowed, codes.Unimplemented, "Method not allowed")
+	}
+}
+
+func (g *gateway) addBook(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	book := &pb.Book{}
+	if _, err := readBook(r, book); err != nil {
+		writeHTTPError(w, http.StatusBadRequest, codes.InvalidArgument, err.Error())
+		return
+	}
+	var trailer metadata.MD
+	id, err := g.client.AddBook(ctx, book, grpc.Trailer(&trailer))
+	if err == nil {
+		book.Id = id.Value
+		w.Header().Set("Location", booksPath+"/"+id.Value)
+	}
+	g.reply(w, http.StatusCreated, book, trailer, err)
+}
+
+// patchBook only changes the fields present in the request body.
+func (g *gateway) patchBook(ctx context.Context, w http.ResponseWriter, r *http.Request, id string) {
+	patch := &pb.Book{}
+	fields, err := readBook(r, patch)
+	if err != nil {
+		writeHTTPError(w, http.StatusBadRequest, codes.InvalidArgument, err.Error())
+		return
+	}
+	var trailer metadata.MD
+	book, err := g.client.GetBook(ctx, &pb.BookID{Value: id}, grpc.Trailer(&trailer))
+	if err != nil {
+		g.reply(w, http.StatusOK, nil, trailer, err)
+		return
+	}
+	src, dst := patch.ProtoReflect(), book.ProtoReflect()
+	descriptors := dst.Descriptor().Fields()
+	for _, name := range fields {
+		fd := descriptors.ByJSONName(name)
+		if fd == nil {
+			fd = descriptors.ByName(protoreflect.Name(name))
+		}
+		if fd == nil || fd.Name() == "Id" {
+			continue
+		}
+		dst.Set(fd, src.Get(fd))
+	}
+	updated, err := g.client.UpdateBook(ctx, book, grpc.Trailer(&trailer))
+	g.reply(w, http.StatusOK, updated, trailer, err)
+}
+
+// readBook decodes the request body into book and returns the names of
+// the fields it set.
+func readBook(r *http.Request, book *pb.Book) ([]string, error) {
+	body, err := ioutil.ReadAll(http.MaxBytesReader(nil, r.Body, maxBodySize))
+	if err != nil {
+		return nil, err
+	}
+	if err := protojson.Unmarshal(body, book); err != nil {
+		return nil, err
+	}
+	var present map[string]json.RawMessage
+	if err := json.Unmarshal(body, &present); err != nil {
+		return nil, err
+	}
+	fields := make([]string, 0, len(present))
+	for name := range present {
+		fields = append(fields, name)
+	}
+	return fields, nil
+}
+
+func (g *gateway) reply(w http.ResponseWriter, code int, m proto.Message, trailer metadata.MD, err error) {
+	if err != nil {
+		st := status.Convert(err)
+		if retry := trailer.Get("retry-after"); len(retry) > 0 {
+			w.Header().Set("Retry-After", retry[0])
+		}
+		writeHTTPError(w, httpStatusFromCode(st.Code()), st.Code(), st.Message())
+		return
+	}
+	body, err := jsonOptions.Marshal(m)
+	if err != nil {
+		writeHTTPError(w, http.StatusInternalServerError, codes.Internal, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(body)
+}
+
+// writeHTTPError writes an error body shaped like a google.rpc.Status.
+func writeHTTPError(w http.ResponseWriter, httpCode int, code codes.Code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpCode)
+	json.NewEncoder(w).Encode(struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}{int(code), message})
+}
+
+// clientAddrKey is the metadata key under which the gateway and the
+// gRPC-Web bridge tell the gRPC server the address of the HTTP client a
+// call is made for. The server only believes it from the in-process pipe.
+const clientAddrKey = "bookinfo-client-addr"
+
+type clientAddrContextKey struct{}
+
+// trustedProxies are the networks of the reverse proxies whose
+// X-Forwarded-For headers are believed.
+type trustedProxies []*net.IPNet
+
+func parseTrustedProxies(list []string) (trustedProxies, error) {
+	var proxies trustedProxies
+	for _, item := range list {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if !strings.Contains(item, "/") {
+			ip := net.ParseIP(item)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid address %q", item)
+			}
+			bits := 8 * len(ip.To4())
+			if bits == 0 {
+				bits = 128
+			}
+			item = fmt.Sprintf("%s/%d", item, bits)
+		}
+		_, network, err := net.ParseCIDR(item)
+		if err != nil {
+			return nil, err
+		}
+		proxies = append(proxies, network)
+	}
+	return proxies, nil
+}
+
+func (t trustedProxies) contains(host string) bool {
+	ip := net.ParseIP(host)
+	for _, network := range t {
+		if ip != nil && network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientAddr returns the address of the client of r: the peer of the
+// connection, or when that is a trusted proxy, the last address in
+// X-Forwarded-For that is not.
+func (t trustedProxies) clientAddr(r *http.Request) string {
+	addr := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	if !t.contains(addr) {
+		return addr
+	}
+	forwarded := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(forwarded[i])
+		if hop == "" {
+			continue
+		}
+		addr = hop
+		if !t.contains(hop) {
+			break
+		}
+	}
+	return addr
+}
+
+// withClientAddr records the address of the client of each request for
+// httpClientAddr.
+func (t trustedProxies) withClientAddr(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), clientAddrContextKey{}, t.clientAddr(r))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// httpClientAddr returns the address of the client of r, as recorded by
+// withClientAddr.
+func httpClientAddr(r *http.Request) string {
+	if addr, ok := r.Context().Value(clientAddrContextKey{}).(string); ok {
+		return addr
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// outgoingContext carries the request ID, credentials, client address
+// and trace context of an HTTP request over to the RPC it is translated
+// into.
+func outgoingContext(r *http.Request) context.Context {
+	ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+	ctx = metadata.AppendToOutgoingContext(ctx, clientAddrKey, httpClientAddr(r))
+	if id := r.Header.Get("X-Request-Id"); id != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, requestIDKey, id)
+	}
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", auth)
+	}
+	return ctx
+}
+
+// httpStatusFromCode maps a gRPC status code to the HTTP status code the
+// gateway responds with.
+func httpStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
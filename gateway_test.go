@@ -0,0 +1,69 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseTrustedProxies(t *testing.T) {
+	proxies, err := parseTrustedProxies([]string{" 10.0.0.1", "", "192.168.0.0/16 ", "::1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for host, want := range map[string]bool{
+		"10.0.0.1":    true,
+		"10.0.0.2":    false,
+		"192.168.3.4": true,
+		"::1":         true,
+		"::2":         false,
+		"not an ip":   false,
+	} {
+		if got := proxies.contains(host); got != want {
+			t.Errorf("contains(%q) = %v, want %v", host, got, want)
+		}
+	}
+
+	for _, list := range [][]string{{"10.0.0"}, {"10.0.0.0/33"}, {"proxy.example.com"}} {
+		if _, err := parseTrustedProxies(list); err == nil {
+			t.Errorf("parseTrustedProxies(%q) succeeded, want an error", list)
+		}
+	}
+}
+
+func TestClientAddr(t *testing.T) {
+	proxies, err := parseTrustedProxies([]string{"10.0.0.1", "10.0.1.0/24"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  []string
+		want       string
+	}{
+		{"direct client", "203.0.113.5:40000", nil, "203.0.113.5"},
+		// Only a trusted proxy's X-Forwarded-For is believed.
+		{"direct client claiming another address", "203.0.113.5:40000", []string{"198.51.100.7"}, "203.0.113.5"},
+		{"trusted proxy", "10.0.0.1:40000", []string{"198.51.100.7"}, "198.51.100.7"},
+		{"trusted proxy without header", "10.0.0.1:40000", nil, "10.0.0.1"},
+		// A client may send X-Forwarded-For of its own: the proxy appends
+		// the address it saw, and that last untrusted hop is the client.
+		{"client spoofing through a proxy", "10.0.0.1:40000", []string{"192.0.2.1, 198.51.100.7"}, "198.51.100.7"},
+		{"spoofed trusted hop", "10.0.0.1:40000", []string{"10.0.1.9, 198.51.100.7"}, "198.51.100.7"},
+		{"chain of trusted proxies", "10.0.0.1:40000", []string{"192.0.2.1, 198.51.100.7, 10.0.1.2"}, "198.51.100.7"},
+		{"headers repeated", "10.0.0.1:40000", []string{"192.0.2.1", "198.51.100.7, 10.0.1.2"}, "198.51.100.7"},
+		{"empty hops", "10.0.0.1:40000", []string{"198.51.100.7, ,"}, "198.51.100.7"},
+		// With every hop trusted, the first one is the best guess.
+		{"only trusted hops", "10.0.0.1:40000", []string{"10.0.1.3, 10.0.1.2"}, "10.0.1.3"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", booksPath+"/1", nil)
+		r.RemoteAddr = tt.remoteAddr
+		for _, v := range tt.forwarded {
+			r.Header.Add("X-Forwarded-For", v)
+		}
+		if got := proxies.clientAddr(r); got != tt.want {
+			t.Errorf("%s: clientAddr = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
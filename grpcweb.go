@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	grpcWebContentType     = "application/grpc-web"
+	grpcWebTextContentType = "application/grpc-web-text"
+
+	// grpcWebTrailerFlag marks the frame carrying the trailers.
+	grpcWebTrailerFlag = 0x80
+)
+
+// isGRPCWeb reports whether r is a gRPC-Web call, or the CORS preflight
+// a browser sends ahead of one.
+func isGRPCWeb(r *http.Request) bool {
+	if r.Method == http.MethodOptions {
+		return strings.Contains(strings.ToLower(r.Header.Get("Access-Control-Request-Headers")), "x-grpc-web")
+	}
+	return strings.HasPrefix(r.Header.Get("Content-Type"), grpcWebContentType)
+}
+
+// rawCodec passes already encoded messages through untouched, so the
+// bridge can forward calls without knowing their message types.
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v interface{}) ([]byte, error) {
+	return *(v.(*[]byte)), nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	*(v.(*[]byte)) = append([]byte(nil), data...)
+	return nil
+}
+
+func (rawCodec) Name() string { return "proto" }
+
+// grpcWebBridge serves gRPC-Web calls from browsers by forwarding them to
+// the gRPC server. It handles unary and server streaming methods, in
+// both the binary and the base64 text encodings.
+type grpcWebBridge struct {
+	conn *grpc.ClientConn
+}
+
+func newGRPCWebBridge(conn *grpc.ClientConn) *grpcWebBridge {
+	return &grpcWebBridge{conn: conn}
+}
+
+func (b *grpcWebBridge) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Expose-Headers", "grpc-status, grpc-message, "+requestIDKey)
+	if r.Method == http.MethodOptions {
+		w.Header().Set("Access-Control-Allow-Methods", "POST")
+		w.Header().Set("Access-Control-Allow-Headers", r.Header.Get("Access-Control-Request-Headers"))
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "gRPC-Web calls must use POST", http.StatusMethodNotAllowed)
+		return
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	text := strings.HasPrefix(contentType, grpcWebTextContentType)
+	var body io.Reader = http.MaxBytesReader(w, r.Body, maxBodySize)
+	if text {
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	}
+	req, err := readGRPCWebMessage(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+	md := metadata.MD{}
+	for name, values := range r.Header {
+		name = strings.ToLower(name)
+		switch {
+		case name == "x-grpc-web" || name == "x-user-agent":
+		case name == "authorization" || strings.HasPrefix(name, "x-"):
+			md[name] = values
+		}
+	}
+	md.Set(clientAddrKey, httpClientAddr(r))
+	ctx = metadata.NewOutgoingContext(ctx, md)
+
+	var trailer metadata.MD
+	stream, err := b.conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, r.URL.Path,
+		grpc.ForceCodec(rawCodec{}), grpc.Trailer(&trailer))
+	if err == nil {
+		err = stream.SendMsg(&req)
+	}
+	if err == nil {
+		err = stream.CloseSend()
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	if err == nil {
+		// Header fails when the call does; RecvMsg below reports why.
+		if header, herr := stream.Header(); herr == nil {
+			copyMetadata(w.Header(), header)
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+	for err == nil {
+		var resp []byte
+		if err = stream.RecvMsg(&resp); err == nil {
+			writeGRPCWebFrame(w, 0, resp, text)
+		}
+	}
+	if err == io.EOF {
+		err = nil
+	}
+
+	st := status.Convert(err)
+	var trailers bytes.Buffer
+	fmt.Fprintf(&trailers, "grpc-status: %d\r\n", st.Code())
+	if st.Message() != "" {
+		fmt.Fprintf(&trailers, "grpc-message: %s\r\n", encodeGRPCMessage(st.Message()))
+	}
+	for name, values := range trailer {
+		for _, v := range values {
+			fmt.Fprintf(&trailers, "%s: %s\r\n", name, v)
+		}
+	}
+	writeGRPCWebFrame(w, grpcWebTrailerFlag, trailers.Bytes(), text)
+}
+
+// readGRPCWebMessage reads the single length-prefixed message a unary or
+// server streaming call sends.
+func readGRPCWebMessage(r io.Reader) ([]byte, error) {
+	var prefix [5]byte
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		return nil, fmt.Errorf("malformed gRPC-Web request: %v", err)
+	}
+	if prefix[0] != 0 {
+		return nil, fmt.Errorf("unsupported gRPC-Web frame flags %#x", prefix[0])
+	}
+	msg := make([]byte, binary.BigEndian.Uint32(prefix[1:]))
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return nil, fmt.Errorf("malformed gRPC-Web request: %v", err)
+	}
+	io.Copy(ioutil.Discard, r)
+	return msg, nil
+}
+
+func writeGRPCWebFrame(w http.ResponseWriter, flag byte, payload []byte, text bool) {
+	frame := make([]byte, 5+len(payload))
+	frame[0] = flag
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(payload)))
+	copy(frame[5:], payload)
+	if text {
+		frame = []byte(base64.StdEncoding.EncodeToString(frame))
+	}
+	w.Write(frame)
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func copyMetadata(h http.Header, md metadata.MD) {
+	for name, values := range md {
+		if strings.HasSuffix(name, "-bin") {
+			continue
+		}
+		for _, v := range values {
+			h.Add(name, v)
+		}
+	}
+}
+
+// encodeGRPCMessage percent-encodes a status message the way the gRPC
+// wire protocol requires.
+func encodeGRPCMessage(msg string) string {
+	var b strings.Builder
+	for i := 0; i < len(msg); i++ {
+		c := msg[i]
+		if c >= ' ' && c <= '~' && c != '%' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// healthHandler reports the serving status kept by hs over HTTP for load
+// balancers that cannot speak grpc.health.v1. The service to check is
+// taken from the "service" query parameter and defaults to the server as
+// a whole.
+func healthHandler(hs *health.Server) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		resp, err := hs.Check(r.Context(), &healthpb.HealthCheckRequest{
+			Service: r.URL.Query().Get("service"),
+		})
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		switch {
+		case err != nil:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintln(w, "SERVICE_UNKNOWN")
+		case resp.Status != healthpb.HealthCheckResponse_SERVING:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintln(w, resp.Status)
+		default:
+			fmt.Fprintln(w, resp.Status)
+		}
+	}
+}
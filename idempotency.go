@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// idempotencyKey is the metadata key clients send with AddBook so that
+// retrying a call whose answer was lost does not add the book twice.
+const idempotencyKey = "idempotency-key"
+
+// idempotencyTTL is how long the answer to a call is remembered.
+const idempotencyTTL = 24 * time.Hour
+
+// idempotentCall is a call made with an idempotency key. done is closed
+// once id and err are set.
+type idempotentCall struct {
+	done    chan struct{}
+	id      string
+	err     error
+	expires time.Time
+}
+
+// idempotencyCache remembers the book added by each idempotency key.
+type idempotencyCache struct {
+	mu    sync.Mutex
+	calls map[string]*idempotentCall
+	swept time.Time
+}
+
+func newIdempotencyCache() *idempotencyCache {
+	return &idempotencyCache{calls: make(map[string]*idempotentCall), swept: time.Now()}
+}
+
+// keyFromContext returns the idempotency key of the incoming call, if
+// any, scoped to the caller so that callers cannot see or take over each
+// other's calls by reusing a key.
+func keyFromContext(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if keys := md.Get(idempotencyKey); len(keys) > 0 && keys[0] != "" {
+		return callerID(ctx) + " " + keys[0]
+	}
+	return ""
+}
+
+// callerID identifies the caller: by a digest of its bearer token when it
+// sent one, otherwise by its client certificate or address as the rate
+// limits do.
+func callerID(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if auth := md.Get("authorization"); len(auth) > 0 {
+		sum := sha256.Sum256([]byte(auth[0]))
+		return "token:" + hex.EncodeToString(sum[:])
+	}
+	return clientKey(ctx)
+}
+
+// do runs add once per key and returns the ID it added. Calls with a key
+// already seen get the same ID, waiting for the first call if it has not
+// finished. Failed calls are forgotten so that they can be retried.
+func (c *idempotencyCache) do(key string, add func() (string, error)) (string, error) {
+	c.mu.Lock()
+	now := time.Now()
+	if now.Sub(c.swept) > time.Hour {
+		for k, call := range c.calls {
+			if !call.expires.IsZero() && now.After(call.expires) {
+				delete(c.calls, k)
+			}
+		}
+		c.swept = now
+	}
+	if call, ok := c.calls[key]; ok {
+		c.mu.Unlock()
+		<-call.done
+		return call.id, call.err
+	}
+	call := &idempotentCall{done: make(chan struct{})}
+	c.calls[key] = call
+	c.mu.Unlock()
+
+	call.id, call.err = add()
+	c.mu.Lock()
+	if call.err != nil {
+		delete(c.calls, key)
+	} else {
+		call.expires = time.Now().Add(idempotencyTTL)
+	}
+	c.mu.Unlock()
+	close(call.done)
+	return call.id, call.err
+}
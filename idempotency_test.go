@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"github.com/marcoc22/tutorial3/store"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestIdempotencyCacheDo(t *testing.T) {
+	c := newIdempotencyCache()
+	calls := 0
+	add := func() (string, error) {
+		calls++
+		return "book-1", nil
+	}
+	for i := 0; i < 2; i++ {
+		if id, err := c.do("k", add); id != "book-1" || err != nil {
+			t.Errorf("do %d = %q, %v, want book-1", i+1, id, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("add ran %d times for one key, want once", calls)
+	}
+
+	// Failed calls are forgotten, so a retry runs again.
+	failed := errors.New("store down")
+	if _, err := c.do("f", func() (string, error) { return "", failed }); err != failed {
+		t.Errorf("failing do = %v, want %v", err, failed)
+	}
+	if id, err := c.do("f", add); id != "book-1" || err != nil || calls != 2 {
+		t.Errorf("retry after a failure = %q, %v after %d calls, want a second call", id, err, calls)
+	}
+}
+
+func TestIdempotencyCacheConcurrent(t *testing.T) {
+	c := newIdempotencyCache()
+	release := make(chan struct{})
+	var mu sync.Mutex
+	calls := 0
+	add := func() (string, error) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		<-release
+		return "book-1", nil
+	}
+	var wg sync.WaitGroup
+	ids := make([]string, 8)
+	for i := range ids {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ids[i], _ = c.do("k", add)
+		}(i)
+	}
+	close(release)
+	wg.Wait()
+	if calls != 1 {
+		t.Errorf("add ran %d times for concurrent calls with one key, want once", calls)
+	}
+	for i, id := range ids {
+		if id != "book-1" {
+			t.Errorf("call %d got %q, want book-1", i, id)
+		}
+	}
+}
+
+func TestIdempotencyKeyScopedToCaller(t *testing.T) {
+	counted, counts, err := countBooks(store.NewMemory())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newServer(counted, counts)
+	call := func(addr string, auth string) context.Context {
+		md := metadata.Pairs(idempotencyKey, "retry-1")
+		if auth != "" {
+			md.Set("authorization", auth)
+		}
+		return metadata.NewIncomingContext(peerContext(&net.TCPAddr{IP: net.ParseIP(addr), Port: 40000}), md)
+	}
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		sameAs  int
+		wantNew bool
+	}{
+		{"first call", call("10.0.0.1", ""), -1, true},
+		{"retry from the same address", call("10.0.0.1", ""), 0, false},
+		{"another address reusing the key", call("10.0.0.2", ""), -1, true},
+		{"a token holder reusing the key", call("10.0.0.1", "Bearer alice"), -1, true},
+		// Token holders are the same caller from any address.
+		{"the same token from elsewhere", call("10.0.0.3", "Bearer alice"), 3, false},
+		{"another token", call("10.0.0.3", "Bearer bob"), -1, true},
+	}
+	var ids []string
+	seen := make(map[string]bool)
+	for _, tt := range tests {
+		id, err := s.AddBook(tt.ctx, &pb.Book{Title: tt.name})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		ids = append(ids, id.Value)
+		if tt.wantNew && seen[id.Value] {
+			t.Errorf("%s: got the book %s of another caller", tt.name, id.Value)
+		}
+		if !tt.wantNew && id.Value != ids[tt.sameAs] {
+			t.Errorf("%s: added %s, want the book %s of the first call", tt.name, id.Value, ids[tt.sameAs])
+		}
+		seen[id.Value] = true
+	}
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"github.com/marcoc22/tutorial3/marc"
+	"github.com/marcoc22/tutorial3/onix"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxImportErrors bounds the record errors an import summary lists, so
+// a file in the wrong format does not produce one error per record.
+const maxImportErrors = 100
+
+// chunkReader reads the data of the chunks sent to ImportBooks as one
+// stream, fetching chunks only as the parser needs them.
+type chunkReader struct {
+	stream pb.BookInfo_ImportBooksServer
+	buf    []byte
+	// err is the error that ended the stream, if not io.EOF.
+	err error
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		chunk, err := r.stream.Recv()
+		if err != nil {
+			if err != io.EOF {
+				r.err = err
+			}
+			return 0, err
+		}
+		r.buf = chunk.Data
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// importRecord is a record of an imported file.
+type importRecord struct {
+	// n is the position of the record in the file, from 1.
+	n    int
+	book *pb.Book
+	// isbns identify the book to replace or delete. Records without
+	// them are always added.
+	isbns  []string
+	delete bool
+	// merge is set when the book only holds the fields to change.
+	merge bool
+	// err, if set, is why the record is rejected.
+	err error
+}
+
+// importSource reads the records of a file. next returns io.EOF after the
+// last record; any other error ends the import.
+type importSource interface {
+	next() (importRecord, error)
+}
+
+func newImportSource(format pb.ImportFormat, r io.Reader) (importSource, error) {
+	switch format {
+	case pb.ImportFormat_MARC21:
+		return &marcSource{r: marc.NewReader(r)}, nil
+	case pb.ImportFormat_MARCXML:
+		return &marcSource{r: marc.NewXMLReader(r)}, nil
+	case pb.ImportFormat_ONIX:
+		return &onixSource{r: onix.NewReader(r)}, nil
+	}
+	return nil, status.Errorf(codes.InvalidArgument, "Unsupported import format %v.", format)
+}
+
+// marcSource adds every record of a MARC file.
+type marcSource struct {
+	r marc.RecordReader
+	n int
+}
+
+func (s *marcSource) next() (importRecord, error) {
+	rec, err := s.r.Read()
+	s.n++
+	var recordErr *marc.RecordError
+	if errors.As(err, &recordErr) {
+		return importRecord{n: recordErr.Record, err: recordErr.Err}, nil
+	}
+	if err != nil {
+		return importRecord{}, err
+	}
+	return importRecord{n: s.n, book: marc.Book(rec)}, nil
+}
+
+// onixSource applies the products of an ONIX feed, skipping test
+// records.
+type onixSource struct {
+	r *onix.Reader
+	n int
+}
+
+func (s *onixSource) next() (importRecord, error) {
+	for {
+		p, err := s.r.Read()
+		s.n++
+		var productErr *onix.ProductError
+		if errors.As(err, &productErr) {
+			return importRecord{n: productErr.Product, err: productErr.Err}, nil
+		}
+		if err != nil {
+			return importRecord{}, err
+		}
+		if p.IsTest() {
+			continue
+		}
+		return importRecord{n: s.n, book: p.Book, isbns: p.ISBNs,
+			delete: p.IsDelete(), merge: p.IsBlockUpdate()}, nil
+	}
+}
+
+// isbnKey normalizes an ISBN for matching: ISBN-10s are converted to the
+// equivalent ISBN-13.
+func isbnKey(isbn string) string {
+	isbn = strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(isbn))
+	if len(isbn) != 10 {
+		return isbn
+	}
+	digits := "978" + isbn[:9]
+	sum := 0
+	for i, c := range digits {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += int(c-'0') * weight
+	}
+	return digits + string(rune('0'+(10-sum%10)%10))
+}
+
+// isbnIndex maps the ISBN keys of the stored books to their IDs.
+func (s *server) isbnIndex() (map[string]string, error) {
+	index := make(map[string]string)
+	err := s.store.Scan(booksCollection, func(id string, value []byte) error {
+		book := &pb.Book{}
+		if err := proto.Unmarshal(value, book); err != nil {
+			return status.Errorf(codes.DataLoss, "Book %s is corrupt: %v", id, err)
+		}
+		if book.Isbn != "" {
+			index[isbnKey(book.Isbn)] = id
+		}
+		return nil
+	})
+	return index, err
+}
+
+func (s *server) ImportBooks(stream pb.BookInfo_ImportBooksServer) error {
+	first, err := stream.Recv()
+	if err == io.EOF {
+		return status.Errorf(codes.InvalidArgument, "No data to import.")
+	}
+	if err != nil {
+		return err
+	}
+	chunks := &chunkReader{stream: stream, buf: first.Data}
+	source, err := newImportSource(first.Format, chunks)
+	if err != nil {
+		return err
+	}
+	// Books are matched by ISBN against an index built once, so books
+	// added by other clients during the import are not matched.
+	var index map[string]string
+	if first.Format == pb.ImportFormat_ONIX {
+		if index, err = s.isbnIndex(); err != nil {
+			if _, ok := status.FromError(err); !ok {
+				err = status.Errorf(codes.Internal, "Error while indexing books: %v", err)
+			}
+			return err
+		}
+	}
+
+	ctx := stream.Context()
+	summary := &pb.ImportSummary{}
+	rejected := 0
+	reject := func(record int, msg string) {
+		rejected++
+		if rejected <= maxImportErrors {
+			summary.Errors = append(summary.Errors, &pb.ImportError{Record: int32(record), Message: msg})
+		}
+	}
+	for {
+		rec, err := source.next()
+		if err == io.EOF {
+			break
+		}
+		if chunks.err != nil {
+			return chunks.err
+		}
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "Import stopped: %v", err)
+		}
+		if rec.err != nil {
+			reject(rec.n, rec.err.Error())
+			continue
+		}
+		if err := s.applyImport(ctx, rec, index, summary); err != nil {
+			st := status.Convert(err)
+			if st.Code() == codes.NotFound || st.Code() == codes.InvalidArgument {
+				reject(rec.n, st.Message())
+				continue
+			}
+			return status.Errorf(st.Code(), "Import stopped at record %d: %v", rec.n, st.Message())
+		}
+	}
+	if rejected > maxImportErrors {
+		summary.Errors = append(summary.Errors, &pb.ImportError{
+			Message: fmt.Sprintf("%d further errors not listed", rejected-maxImportErrors)})
+	}
+	return stream.SendAndClose(summary)
+}
+
+// applyImport adds, replaces or deletes the book of rec, and counts it in
+// summary. index, if not nil, finds the books to replace or delete and is
+// kept up to date.
+func (s *server) applyImport(ctx context.Context, rec importRecord, index map[string]string, summary *pb.ImportSummary) error {
+	id := ""
+	for _, isbn := range rec.isbns {
+		if id = index[isbnKey(isbn)]; id != "" {
+			break
+		}
+	}
+
+	if rec.delete {
+		if id == "" {
+			return status.Errorf(codes.NotFound, "No book has ISBN %s.", strings.Join(rec.isbns, " or "))
+		}
+		if _, err := s.DeleteBook(ctx, &pb.BookID{Value: id}); err != nil {
+			return err
+		}
+		for _, isbn := range rec.isbns {
+			delete(index, isbnKey(isbn))
+		}
+		summary.Deleted++
+		return nil
+	}
+
+	if id != "" && rec.merge {
+		book, err := s.GetBook(ctx, &pb.BookID{Value: id})
+		if err != nil {
+			return err
+		}
+		// Merging proto3 messages only copies the fields that are set.
+		proto.Merge(book, rec.book)
+		rec.book = book
+	}
+	if rec.book.Title == "" {
+		return status.Errorf(codes.InvalidArgument, "Record has no title.")
+	}
+	if id != "" {
+		rec.book.Id = id
+		if _, err := s.UpdateBook(ctx, rec.book); err != nil {
+			return err
+		}
+		summary.Updated++
+		return nil
+	}
+	added, err := s.AddBook(ctx, rec.book)
+	if err != nil {
+		return err
+	}
+	if index != nil {
+		for _, isbn := range rec.isbns {
+			index[isbnKey(isbn)] = added.Value
+		}
+	}
+	summary.Added++
+	return nil
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid"
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// requestIDKey is the metadata key a request ID is read from and echoed
+// back in.
+const requestIDKey = "x-request-id"
+
+type logLevel int
+
+const (
+	levelDebug logLevel = iota
+	levelInfo
+	levelWarn
+	levelError
+)
+
+var levelNames = []string{"debug", "info", "warn", "error"}
+
+func (l logLevel) String() string { return levelNames[l] }
+
+func parseLogLevel(s string) (logLevel, error) {
+	for i, name := range levelNames {
+		if strings.EqualFold(s, name) {
+			return logLevel(i), nil
+		}
+	}
+	return levelInfo, fmt.Errorf("unknown log level %q", s)
+}
+
+// requestLogger writes one JSON object per line for every RPC.
+type requestLogger struct {
+	// settings guards the fields below, which configure can change while
+	// serving.
+	settings sync.RWMutex
+	level    logLevel
+	// payloads enables logging of request messages at debug level.
+	payloads bool
+	// redact lists the payload fields, by JSON name, whose values are
+	// replaced before logging.
+	redact map[string]bool
+
+	mu  sync.Mutex
+	out io.Writer
+}
+
+func newRequestLogger(out io.Writer, level logLevel, payloads bool, redact []string) *requestLogger {
+	l := &requestLogger{out: out}
+	l.configure(level, payloads, redact)
+	return l
+}
+
+// configure replaces the logger's settings.
+func (l *requestLogger) configure(level logLevel, payloads bool, redact []string) {
+	fields := make(map[string]bool)
+	for _, field := range redact {
+		if field = strings.TrimSpace(field); field != "" {
+			fields[strings.ToLower(field)] = true
+		}
+	}
+	l.settings.Lock()
+	defer l.settings.Unlock()
+	l.level, l.payloads, l.redact = level, payloads, fields
+}
+
+// logsPayloads reports whether request messages are logged.
+func (l *requestLogger) logsPayloads() bool {
+	l.settings.RLock()
+	defer l.settings.RUnlock()
+	return l.payloads && l.level == levelDebug
+}
+
+func (l *requestLogger) log(level logLevel, msg string, fields map[string]interface{}) {
+	l.settings.RLock()
+	min := l.level
+	l.settings.RUnlock()
+	if level < min {
+		return
+	}
+	entry := map[string]interface{}{
+		"time":  time.Now().UTC().Format(time.RFC3339Nano),
+		"level": level.String(),
+		"msg":   msg,
+	}
+	for k, v := range fields {
+		entry[k] = v
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		line = []byte(fmt.Sprintf(`{"level":"error","msg":"unable to encode log entry: %v"}`, err))
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.out.Write(append(line, '\n'))
+}
+
+// payload renders m as JSON with the redacted fields masked, at any
+// depth.
+func (l *requestLogger) payload(m interface{}) interface{} {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return nil
+	}
+	data, err := protojson.Marshal(msg)
+	if err != nil {
+		return nil
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil
+	}
+	l.settings.RLock()
+	defer l.settings.RUnlock()
+	l.redactFields(fields)
+	return fields
+}
+
+// redactFields masks the redacted fields of the decoded JSON value v and
+// of the objects nested in it. The caller holds l.settings.
+func (l *requestLogger) redactFields(v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, field := range v {
+			if l.redact[strings.ToLower(k)] {
+				v[k] = "[REDACTED]"
+			} else {
+				l.redactFields(field)
+			}
+		}
+	case []interface{}:
+		for _, elem := range v {
+			l.redactFields(elem)
+		}
+	}
+}
+
+// levelFor maps a status code to a log level: caller mistakes are
+// warnings, server side failures are errors.
+func levelFor(code codes.Code) logLevel {
+	switch code {
+	case codes.OK:
+		return levelInfo
+	case codes.Unknown, codes.Internal, codes.Unavailable, codes.DataLoss, codes.Unimplemented:
+		return levelError
+	default:
+		return levelWarn
+	}
+}
+
+type requestIDCtxKey struct{}
+
+// withRequestID reuses the caller's request ID when one is present in the
+// incoming metadata and generates one otherwise.
+func withRequestID(ctx context.Context) (context.Context, string) {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(requestIDKey); len(ids) > 0 {
+			id = ids[0]
+		}
+	}
+	if id == "" {
+		if u, err := uuid.NewV4(); err == nil {
+			id = u.String()
+		}
+	}
+	return context.WithValue(ctx, requestIDCtxKey{}, id), id
+}
+
+// bookID extracts the book the call is about from its request or response.
+func bookID(msgs ...interface{}) string {
+	for _, m := range msgs {
+		switch v := m.(type) {
+		case *pb.BookID:
+			if v != nil && v.Value != "" {
+				return v.Value
+			}
+		case *pb.Book:
+			if v != nil && v.Id != "" {
+				return v.Id
+			}
+		case *pb.FormatCitationRequest:
+			if v != nil && v.Id != "" {
+				return v.Id
+			}
+		}
+	}
+	return ""
+}
+
+func (l *requestLogger) fields(ctx context.Context, method, id string, start time.Time, err error) (logLevel, map[string]interface{}) {
+	code := status.Code(err)
+	fields := map[string]interface{}{
+		"method":     method,
+		"request_id": id,
+		"code":       code.String(),
+		"latency_ms": float64(time.Since(start)) / float64(time.Millisecond),
+	}
+	if _, ok := peer.FromContext(ctx); ok {
+		fields["peer"] = callerAddr(ctx)
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		fields["trace_id"] = sc.TraceID().String()
+	}
+	if err != nil {
+		fields["error"] = status.Convert(err).Message()
+	}
+	return levelFor(code), fields
+}
+
+func (l *requestLogger) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	ctx, id := withRequestID(ctx)
+	grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, id))
+
+	resp, err := handler(ctx, req)
+
+	level, fields := l.fields(ctx, info.FullMethod, id, start, err)
+	// Replicas call each other several times a second, and routers
+	// call their shards for every record.
+	if level == levelInfo && internalMethod(info.FullMethod) {
+		level = levelDebug
+	}
+	if book := bookID(req, resp); book != "" {
+		fields["book_id"] = book
+	}
+	if l.logsPayloads() {
+		fields["request"] = l.payload(req)
+	}
+	l.log(level, "rpc finished", fields)
+	return resp, err
+}
+
+// loggedStream carries the request ID in its context.
+type loggedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *loggedStream) Context() context.Context { return s.ctx }
+
+func (l *requestLogger) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	ctx, id := withRequestID(ss.Context())
+	ss.SetHeader(metadata.Pairs(requestIDKey, id))
+
+	err := handler(srv, &loggedStream{ServerStream: ss, ctx: ctx})
+
+	level, fields := l.fields(ctx, info.FullMethod, id, start, err)
+	l.log(level, "rpc finished", fields)
+	return err
+}
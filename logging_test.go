@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+)
+
+func TestPayloadRedaction(t *testing.T) {
+	l := newRequestLogger(&bytes.Buffer{}, levelDebug, true, []string{" author", "", "PUBLISHER "})
+	got := l.payload(&pb.Book{Id: "1", Title: "Dune", Author: "Frank Herbert", Publisher: "Chilton"})
+	want := map[string]interface{}{
+		"id":        "1",
+		"title":     "Dune",
+		"author":    "[REDACTED]",
+		"publisher": "[REDACTED]",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("payload = %v, want %v", got, want)
+	}
+	if got := l.payload("not a message"); got != nil {
+		t.Errorf("payload of a string = %v, want nil", got)
+	}
+}
+
+func TestRedactFieldsNested(t *testing.T) {
+	l := newRequestLogger(&bytes.Buffer{}, levelDebug, true, []string{"name", "email"})
+	var v interface{}
+	in := `{
+		"title": "Good Omens",
+		"contributors": [{"name": "Terry Pratchett", "role": "author"}, {"name": "Neil Gaiman", "role": "author"}],
+		"patron": {"id": "p1", "contact": {"email": "ann@example.com", "phone": "555"}},
+		"tags": ["name", {"email": "x"}]
+	}`
+	if err := json.Unmarshal([]byte(in), &v); err != nil {
+		t.Fatal(err)
+	}
+	l.redactFields(v)
+	var want interface{}
+	json.Unmarshal([]byte(`{
+		"title": "Good Omens",
+		"contributors": [{"name": "[REDACTED]", "role": "author"}, {"name": "[REDACTED]", "role": "author"}],
+		"patron": {"id": "p1", "contact": {"email": "[REDACTED]", "phone": "555"}},
+		"tags": ["name", {"email": "[REDACTED]"}]
+	}`), &want)
+	if !reflect.DeepEqual(v, want) {
+		t.Errorf("redacted to %v, want %v", v, want)
+	}
+}
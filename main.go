@@ -1,27 +1,358 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
+	"flag"
+	"fmt"
 	"log"
 	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	pb "github.com/marcoc22/tutorial3/booksapp"
+	"github.com/marcoc22/tutorial3/replication"
+	"github.com/marcoc22/tutorial3/shard"
+	"github.com/marcoc22/tutorial3/store"
+	"github.com/marcoc22/tutorial3/tracing"
+	"github.com/marcoc22/tutorial3/webhook"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
+// openStore opens the book store. Tests replace it to slow the store down.
+var openStore = store.Open
+
 func main() {
-	port := os.Getenv("PORT")
-	lis, err := net.Listen("tcp", ":"+port)
+	flags := registerConfigFlags(flag.CommandLine)
+	flag.Parse()
+	cfg, err := loadConfig(flags)
+	if err != nil {
+		log.Fatalf("invalid configuration: %v", err)
+	}
+	if flags.print {
+		fmt.Print(cfg.YAML())
+		return
+	}
+
+	tlsConfig, err := loadTLSConfig(cfg)
+	if err != nil {
+		log.Fatalf("failed to load TLS certificates: %v", err)
+	}
+	lis, err := net.Listen("tcp", cfg.Listen.Address)
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
 
-	s := grpc.NewServer()
-	pb.RegisterBookInfoServer(s, &server{})
+	logger := newRequestLogger(os.Stderr, cfg.logLevel(), cfg.Log.Payloads, cfg.Log.Redact)
+
+	shutdownTracing, err := tracing.Setup("bookinfo-server", cfg.Tracing.Export)
+	if err != nil {
+		log.Fatalf("failed to set up tracing: %v", err)
+	}
+
+	// A router keeps no books itself: its store is the shards.
+	var bookStore store.Store
+	var shards *shard.Store
+	if len(cfg.Sharding.Shards) > 0 {
+		shards, err = openShards(cfg)
+		bookStore = shards
+	} else {
+		bookStore, err = openStore(cfg.Store.Backend, cfg.Store.Path)
+	}
+	if err != nil {
+		log.Fatalf("failed to open book store: %v", err)
+	}
+	bookStore, counts, err := countBooks(bookStore)
+	if err != nil {
+		log.Fatalf("failed to count books: %v", err)
+	}
+	var cache *store.Cache
+	if cfg.Store.CacheEntries > 0 || cfg.Store.CacheBytes > 0 {
+		cache = store.NewCache(bookStore, cfg.Store.CacheEntries, cfg.Store.CacheBytes)
+		bookStore = cache
+	}
+	// A replica applies the writes the leader logs to its own copy of the
+	// store, cache included, and serves reads from it.
+	var node *replication.Node
+	if len(cfg.Replication.Peers) > 0 {
+		node, err = newReplicaNode(cfg, bookStore)
+		if err != nil {
+			log.Fatalf("failed to start replication: %v", err)
+		}
+		bookStore = node
+	}
+	books := newServer(bookStore, counts)
+	if node != nil {
+		books.replicated = true
+		node.Observe(books.publishMutation)
+	}
+
+	// The limiter and the authenticator are always installed, so that a
+	// reload can turn them on.
+	limits, _ := cfg.rateLimits()
+	peers := newPeerAuth(cfg.Cluster.Token, cfg.Cluster.CommonNames)
+	limiter := newRateLimiter(limits, peers)
+	auth := newTokenAuth(cfg.Auth.Tokens, peers)
+	m := newMetrics(counts)
+	m.cache = cache
+	m.node = node
+	unary := []grpc.UnaryServerInterceptor{logger.unaryInterceptor}
+	stream := []grpc.StreamServerInterceptor{logger.streamInterceptor}
+	if cfg.Metrics.Enabled {
+		unary = append(unary, m.unaryInterceptor)
+		stream = append(stream, m.streamInterceptor)
+	}
+	unary = append(unary, auth.unaryInterceptor, limiter.unaryInterceptor)
+	stream = append(stream, auth.streamInterceptor, limiter.streamInterceptor)
+	// Replicas run their writes on the leader, once the call has been
+	// authenticated and limited here.
+	var forwarder *leaderForwarder
+	if node != nil {
+		if forwarder, err = newLeaderForwarder(cfg, node, peers); err != nil {
+			log.Fatalf("failed to connect to the replicas: %v", err)
+		}
+		unary = append(unary, forwarder.unaryInterceptor)
+		stream = append(stream, forwarder.streamInterceptor)
+	}
+
+	s := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+		grpc.MaxRecvMsgSize(cfg.Limits.MaxMessageSize),
+		grpc.MaxConcurrentStreams(cfg.Limits.MaxConcurrentStreams),
+	)
+	pb.RegisterBookInfoServer(s, books)
+	// Unclaimed holds expire in the background, and the patrons are told
+	// of their holds through the webhook, if one is configured.
+	circ := newCirculationService(books, cfg.circulationPolicy())
+	if node != nil {
+		circ.leader = node.IsLeader
+	}
+	if cfg.Webhooks.URL != "" {
+		circ.startWebhooks(webhook.Config{
+			URL:         cfg.Webhooks.URL,
+			Secret:      cfg.Webhooks.Secret,
+			MaxAttempts: cfg.Webhooks.MaxAttempts,
+			Timeout:     cfg.Webhooks.Timeout,
+		})
+	}
+	circ.start()
+	pb.RegisterCirculationServer(s, circ)
+	if node != nil {
+		pb.RegisterReplicationServer(s, node)
+	}
+	if cfg.Sharding.Serve {
+		pb.RegisterShardServer(s, &shardService{store: bookStore})
+	}
+	if cfg.Reflection {
+		reflection.Register(s)
+	}
+
+	// Report NOT_SERVING until the listener is up, and again as soon as
+	// shutdown starts so that load balancers stop sending new calls.
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	healthServer.SetServingStatus("booksapp.BookInfo", healthpb.HealthCheckResponse_NOT_SERVING)
+	healthpb.RegisterHealthServer(s, healthServer)
+
+	// The REST gateway and the gRPC-Web bridge call the gRPC server
+	// through an in-process pipe, whatever the listener's address and TLS
+	// settings. Their requests go through the same interceptors as any
+	// other client's, which go by the HTTP client they name.
+	internal := newPipeListener()
+	conn, err := grpc.Dial("passthrough:///bookinfo",
+		grpc.WithContextDialer(internal.dial),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()))
+	if err != nil {
+		log.Fatalf("failed to connect gateway: %v", err)
+	}
+	gw := newGateway(pb.NewBookInfoClient(conn))
+	grpcWeb := newGRPCWebBridge(conn)
+
+	routes := http.NewServeMux()
+	routes.Handle(booksPath, gw)
+	routes.Handle(booksPath+"/", gw)
+	if cfg.Metrics.Enabled {
+		routes.Handle("/metrics", m)
+	}
+	routes.Handle("/healthz", healthHandler(healthServer))
+	describeRoutes(routes)
+	// validate has checked the proxies.
+	proxies, _ := parseTrustedProxies(cfg.Listen.TrustedProxies)
+	httpServer := &http.Server{Handler: proxies.withClientAddr(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isNativeGRPC(r) {
+			s.ServeHTTP(w, r)
+			return
+		}
+		if isGRPCWeb(r) {
+			grpcWeb.ServeHTTP(w, r)
+			return
+		}
+		routes.ServeHTTP(w, r)
+	}))}
+
+	// Native gRPC, gRPC-Web, REST and the health and metrics endpoints
+	// all share the listen address. The metrics and gateway addresses
+	// optionally expose the HTTP side on listeners of their own as well.
+	var extraServers []*http.Server
+	if cfg.Listen.MetricsAddress != "" && cfg.Metrics.Enabled {
+		metricsRoutes := http.NewServeMux()
+		metricsRoutes.Handle("/metrics", m)
+		extraServers = append(extraServers, &http.Server{Addr: cfg.Listen.MetricsAddress, Handler: metricsRoutes})
+	}
+	if cfg.Listen.GatewayAddress != "" {
+		extraServers = append(extraServers, &http.Server{Addr: cfg.Listen.GatewayAddress, Handler: httpServer.Handler})
+	}
+	for _, extra := range extraServers {
+		go func(extra *http.Server) {
+			log.Printf("Starting HTTP listener on " + extra.Addr)
+			if err := extra.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Fatalf("failed to serve HTTP: %v", err)
+			}
+		}(extra)
+	}
+
+	serveErr := make(chan error, 3)
+	go func() {
+		serveErr <- s.Serve(internal)
+	}()
+	// Over TLS net/http negotiates HTTP/2 with every client and hands
+	// native gRPC calls to the gRPC server; in plain text the protocol
+	// mux tells them apart by the HTTP/2 preface.
+	var mux *protocolMux
+	if tlsConfig != nil {
+		log.Printf("Starting gRPC listener with TLS on " + lis.Addr().String())
+		go func() {
+			if err := httpServer.Serve(tls.NewListener(lis, tlsConfig)); err != http.ErrServerClosed {
+				serveErr <- err
+			}
+		}()
+	} else {
+		log.Printf("Starting gRPC listener on " + lis.Addr().String())
+		mux = newProtocolMux(lis)
+		go func() {
+			serveErr <- s.Serve(mux.grpc)
+		}()
+		go func() {
+			if err := httpServer.Serve(mux.http); err != http.ErrServerClosed {
+				serveErr <- err
+			}
+		}()
+		go mux.serve()
+	}
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus("booksapp.BookInfo", healthpb.HealthCheckResponse_SERVING)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	flush := time.NewTicker(cfg.Store.FlushInterval)
+
+	exitCode := 0
+serving:
+	for {
+		select {
+		case sig := <-signals:
+			if sig == syscall.SIGHUP {
+				cfg = reload(cfg, flags, logger, limiter, auth, shards)
+				continue
+			}
+			log.Printf("Received %v, shutting down", sig)
+			break serving
+		case err := <-serveErr:
+			log.Printf("failed to serve: %v", err)
+			exitCode = 1
+			break serving
+		case <-flush.C:
+			if err := bookStore.Flush(); err != nil {
+				log.Printf("failed to flush book store: %v", err)
+			}
+		}
+	}
+
+	flush.Stop()
+
+	// Shutdown marks every service NOT_SERVING and ignores later updates.
+	healthServer.Shutdown()
+	books.changes.close()
+	if mux != nil {
+		mux.Close()
+	}
+	shutdownTimeout := cfg.Limits.ShutdownTimeout
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	httpServer.Shutdown(ctx)
+	for _, extra := range extraServers {
+		extra.Shutdown(ctx)
+	}
+	conn.Close()
+	internal.Close()
+	stopped := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(shutdownTimeout):
+		log.Printf("Graceful shutdown timed out after %v, cancelling in-flight calls", shutdownTimeout)
+		s.Stop()
+		<-stopped
+	}
+	// Events the webhook has not delivered yet are dead-lettered, so the
+	// store must still be open.
+	circ.close()
+	if forwarder != nil {
+		forwarder.close()
+	}
 
-	log.Printf("Starting gRPC listener on port " + port)
+	if err := bookStore.Close(); err != nil {
+		log.Printf("failed to close book store: %v", err)
+		exitCode = 1
+	}
+	if err := shutdownTracing(ctx); err != nil {
+		log.Printf("failed to flush traces: %v", err)
+	}
+	cancel()
+	os.Exit(exitCode)
+}
 
-	if err := s.Serve(lis); err != nil {
-		log.Fatalf("failed to serve: %v", err)
+// reload reads the configuration again and applies the settings that can
+// change while serving. It returns the configuration in effect.
+func reload(current *config, flags *configFlags, logger *requestLogger, limiter *rateLimiter, auth *tokenAuth, shards *shard.Store) *config {
+	next, err := loadConfig(flags)
+	if err != nil {
+		log.Printf("Not reloading configuration: %v", err)
+		return current
+	}
+	limits, _ := next.rateLimits()
+	logger.configure(next.logLevel(), next.Log.Payloads, next.Log.Redact)
+	limiter.setLimits(limits)
+	auth.setTokens(next.Auth.Tokens)
+
+	applied := *current
+	applied.Log = next.Log
+	applied.Limits.RateLimits = next.Limits.RateLimits
+	applied.Auth = next.Auth
+	if shards != nil {
+		applied.Sharding.Shards = addShards(shards, current.Sharding.Shards, next.Sharding.Shards)
+	}
+	for _, name := range next.changedSettings(current) {
+		if name == "sharding.shards" && shards != nil {
+			continue
+		}
+		if !reloadable[name] {
+			log.Printf("Ignoring change to %s until restart", name)
+		}
 	}
+	log.Printf("Reloaded configuration")
+	return &applied
 }
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"log"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"github.com/marcoc22/tutorial3/store"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// serverEnv makes the test binary run main instead of the tests, so that
+// tests can run the server as a process of its own and signal it.
+const serverEnv = "BOOKINFO_TEST_SERVER"
+
+// slowPutsEnv makes every book store write of the server take as long as
+// the duration it holds.
+const slowPutsEnv = "BOOKINFO_TEST_SLOW_PUTS"
+
+func TestMain(m *testing.M) {
+	if _, ok := os.LookupEnv(serverEnv); ok {
+		if v := os.Getenv(slowPutsEnv); v != "" {
+			delay, err := time.ParseDuration(v)
+			if err != nil {
+				log.Fatalf("invalid %s: %v", slowPutsEnv, err)
+			}
+			openStore = func(backend, path string) (store.Store, error) {
+				s, err := store.Open(backend, path)
+				return slowStore{s, delay}, err
+			}
+		}
+		main()
+		return
+	}
+	os.Exit(m.Run())
+}
+
+// slowStore delays every write to hold calls in flight.
+type slowStore struct {
+	store.Store
+	delay time.Duration
+}
+
+func (s slowStore) Put(collection, id string, value []byte) error {
+	log.Printf("Slow put of %s/%s", collection, id)
+	time.Sleep(s.delay)
+	return s.Store.Put(collection, id, value)
+}
+
+// testServer is a server running in a child process.
+type testServer struct {
+	cmd  *exec.Cmd
+	addr string
+	// logs receives the lines the server logs.
+	logs chan string
+	done chan error
+}
+
+// startServer runs the server with the environment env on a free local
+// port and waits until it reports SERVING.
+func startServer(t *testing.T, env ...string) *testServer {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := lis.Addr().String()
+	lis.Close()
+	_, port, _ := net.SplitHostPort(addr)
+
+	cmd := exec.Command(os.Args[0])
+	cmd.Env = append(os.Environ(), append([]string{serverEnv + "=1", "PORT=" + port, "RATE_LIMITS=off"}, env...)...)
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	s := &testServer{cmd: cmd, addr: addr, logs: make(chan string, 1000), done: make(chan error, 1)}
+	go func() {
+		lines := bufio.NewScanner(stderr)
+		for lines.Scan() {
+			select {
+			case s.logs <- lines.Text():
+			default:
+			}
+		}
+		close(s.logs)
+		s.done <- cmd.Wait()
+	}()
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+	})
+
+	conn := s.dial(t)
+	health := healthpb.NewHealthClient(conn)
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		resp, err := health.Check(ctx, &healthpb.HealthCheckRequest{})
+		cancel()
+		if err == nil && resp.Status == healthpb.HealthCheckResponse_SERVING {
+			return s
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("server on %s is not serving: %v", addr, err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func (s *testServer) dial(t *testing.T) *grpc.ClientConn {
+	t.Helper()
+	conn, err := grpc.Dial(s.addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// waitLog waits for the server to log a line containing text.
+func (s *testServer) waitLog(t *testing.T, text string) {
+	t.Helper()
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case line, ok := <-s.logs:
+			if !ok {
+				t.Fatalf("server exited without logging %q", text)
+			}
+			if strings.Contains(line, text) {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("server did not log %q", text)
+		}
+	}
+}
+
+// wait waits for the server to exit.
+func (s *testServer) wait(t *testing.T) error {
+	t.Helper()
+	for range s.logs {
+	}
+	select {
+	case err := <-s.done:
+		return err
+	case <-time.After(15 * time.Second):
+		t.Fatal("server did not exit")
+		return nil
+	}
+}
+
+// TestShutdownFinishesCallsInFlight sends SIGTERM while an AddBook is
+// held up by a slow store and checks that the call still completes, that
+// the server exits cleanly and that the book it added was kept.
+func TestShutdownFinishesCallsInFlight(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the server")
+	}
+	storePath := filepath.Join(t.TempDir(), "books.db")
+	env := []string{"STORE=file", "STORE_PATH=" + storePath, "SHUTDOWN_TIMEOUT=10s"}
+	server := startServer(t, append(env, slowPutsEnv+"=2s")...)
+	client := pb.NewBookInfoClient(server.dial(t))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	added := make(chan error, 1)
+	var id *pb.BookID
+	go func() {
+		var err error
+		id, err = client.AddBook(ctx, &pb.Book{Title: "Dune", Author: "Frank Herbert"})
+		added <- err
+	}()
+	server.waitLog(t, "Slow put of books/")
+
+	if err := server.cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+	server.waitLog(t, "shutting down")
+
+	// New calls are turned away while the AddBook is finished.
+	health, err := healthpb.NewHealthClient(server.dial(t)).Check(ctx, &healthpb.HealthCheckRequest{})
+	if err == nil && health.Status == healthpb.HealthCheckResponse_SERVING {
+		t.Error("server still reports SERVING after SIGTERM")
+	}
+
+	if err := <-added; err != nil {
+		t.Fatalf("AddBook in flight failed on shutdown: %v", err)
+	}
+	if err := server.wait(t); err != nil {
+		t.Fatalf("server exited with %v, want a clean exit", err)
+	}
+
+	// The book added during shutdown was written to the store.
+	restarted := startServer(t, env...)
+	book, err := pb.NewBookInfoClient(restarted.dial(t)).GetBook(ctx, id)
+	if err != nil {
+		t.Fatalf("after restart GetBook = %v", err)
+	}
+	if book.Title != "Dune" {
+		t.Errorf("after restart the store holds %q, want Dune", book.Title)
+	}
+}
@@ -0,0 +1,166 @@
+package marc
+
+import (
+	"regexp"
+	"strings"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+)
+
+// languages maps the MARC language codes of the languages books are
+// commonly catalogued in to the names BookInfo uses. Other codes are kept
+// as upper case codes.
+var languages = map[string]string{
+	"eng": "ENGLISH",
+	"fre": "FRENCH",
+	"ger": "GERMAN",
+	"spa": "SPANISH",
+	"ita": "ITALIAN",
+	"por": "PORTUGUESE",
+	"dut": "DUTCH",
+	"rus": "RUSSIAN",
+	"chi": "CHINESE",
+	"jpn": "JAPANESE",
+	"ara": "ARABIC",
+	"lat": "LATIN",
+	"gre": "GREEK",
+	"pol": "POLISH",
+	"swe": "SWEDISH",
+}
+
+// LanguageName returns the BookInfo name of a MARC language code.
+func LanguageName(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if name, ok := languages[code]; ok {
+		return name
+	}
+	return strings.ToUpper(code)
+}
+
+var (
+	yearPattern  = regexp.MustCompile(`\d{4}`)
+	pagesPattern = regexp.MustCompile(`(\d+)\s*(?:p\b|pages?\b)`)
+	firstNumber  = regexp.MustCompile(`\d+`)
+	isbnPattern  = regexp.MustCompile(`^[0-9Xx-]{10,17}`)
+)
+
+// Book maps the standard fields of r onto a book:
+//
+//	245 $a $b   title and subtitle
+//	100 $a      author (110 $a for corporate authors)
+//	250 $a      edition
+//	264 $b $c   publisher and year, from the publication statement,
+//	            falling back to 260 $b $c
+//	300 $a      pages
+//	041 $a      language, falling back to 008/35-37
+//	020 $a      ISBN
+//
+// The ID is left empty.
+func Book(r *Record) *pb.Book {
+	b := &pb.Book{}
+	if f := r.Field("245"); f != nil {
+		title := trimPunct(f.Subfield('a'))
+		if sub := trimPunct(f.Subfield('b')); sub != "" {
+			title += ": " + sub
+		}
+		b.Title = title
+	}
+	if f := r.Field("100"); f != nil {
+		b.Author = trimPunct(f.Subfield('a'))
+		if f.Ind1 == '1' {
+			b.Author = forenameFirst(b.Author)
+		}
+	} else if f := r.Field("110"); f != nil {
+		b.Author = trimPunct(f.Subfield('a'))
+	}
+	if f := r.Field("250"); f != nil {
+		b.Edition = trimPunct(f.Subfield('a'))
+	}
+
+	publication := r.Field("260")
+	for _, f := range r.AllFields("264") {
+		// Second indicator 1 marks the publication, rather than, say,
+		// the manufacture or copyright, statement.
+		if f.Ind2 == '1' {
+			f := f
+			publication = &f
+			break
+		}
+	}
+	if publication != nil {
+		b.Publisher = trimPunct(publication.Subfield('b'))
+		b.Copyright = yearPattern.FindString(publication.Subfield('c'))
+	}
+	if b.Copyright == "" {
+		if f := r.Field("008"); f != nil && len(f.Value) >= 11 {
+			b.Copyright = yearPattern.FindString(f.Value[7:11])
+		}
+	}
+
+	if f := r.Field("300"); f != nil {
+		extent := f.Subfield('a')
+		if m := pagesPattern.FindStringSubmatch(extent); m != nil {
+			b.Pages = m[1]
+		} else {
+			b.Pages = firstNumber.FindString(extent)
+		}
+	}
+
+	// Older records run several codes together in one 041 $a.
+	if f := r.Field("041"); f != nil && len(f.Subfield('a')) >= 3 {
+		b.Language = LanguageName(f.Subfield('a')[:3])
+	} else if f := r.Field("008"); f != nil && len(f.Value) >= 38 {
+		if code := strings.TrimSpace(f.Value[35:38]); code != "" && code != "|||" {
+			b.Language = LanguageName(code)
+		}
+	}
+
+	for _, f := range r.AllFields("020") {
+		if isbn := isbnPattern.FindString(strings.TrimSpace(f.Subfield('a'))); isbn != "" {
+			b.Isbn = strings.ToUpper(strings.Replace(isbn, "-", "", -1))
+			break
+		}
+	}
+	return b
+}
+
+// forenameFirst turns an inverted personal name, "Tanenbaum, Andrew S.",
+// into the "Andrew S. Tanenbaum" form books are stored with.
+func forenameFirst(name string) string {
+	i := strings.Index(name, ", ")
+	if i < 0 {
+		return name
+	}
+	return strings.TrimSpace(name[i+2:]) + " " + name[:i]
+}
+
+// trimPunct removes the ISBD punctuation that separates MARC subfields,
+// such as the " /" ending a title proper.
+func trimPunct(s string) string {
+	s = strings.TrimSpace(s)
+	for len(s) > 0 {
+		last := s[len(s)-1]
+		if last != '/' && last != ':' && last != ';' && last != ',' && last != '=' &&
+			!(last == '.' && !abbreviated(s)) {
+			break
+		}
+		s = strings.TrimSpace(s[:len(s)-1])
+	}
+	return s
+}
+
+// abbreviated reports whether s ends in an initial or a common
+// abbreviation, whose full stop is part of the data, as in "Tanenbaum,
+// Andrew S." or "2nd ed.".
+func abbreviated(s string) bool {
+	word := s[strings.LastIndexAny(s, " ,")+1:]
+	word = strings.TrimSuffix(word, ".")
+	if len(word) == 1 && word[0] >= 'A' && word[0] <= 'Z' {
+		return true
+	}
+	switch strings.ToLower(word) {
+	case "ed", "rev", "co", "inc", "ltd", "jr", "sr":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+package marc
+
+import (
+	"os"
+	"testing"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"google.golang.org/protobuf/proto"
+)
+
+// wantBooks are the books of testdata/books.mrc and testdata/books.xml.
+// The MARC-8 record drops the combining acute of "café", which MARCXML,
+// being UTF-8, keeps.
+var wantBooks = []*pb.Book{
+	{
+		Title:     "Modern operating systems",
+		Author:    "Andrew S. Tanenbaum",
+		Edition:   "4th ed.",
+		Publisher: "Pearson",
+		Copyright: "2014",
+		Pages:     "1136",
+		Language:  "ENGLISH",
+		Isbn:      "9780133591620",
+	},
+	{
+		Title:     "Naturens metaphysik: en café-samtale",
+		Author:    "Hans Christian Ørsted",
+		Publisher: "Gyldendal",
+		Copyright: "1998",
+		Pages:     "312",
+		Language:  "DAN",
+	},
+	{
+		Title:     "Proceedings",
+		Author:    "Association for Computing Machinery",
+		Copyright: "1987",
+		Pages:     "1",
+		Isbn:      "0201100886",
+	},
+}
+
+// readAll reads every record of the fixture file with newReader, keeping
+// the *RecordErrors and stopping at the first other error.
+func readAll(t *testing.T, file string, newReader func(f *os.File) RecordReader) (records []*Record, skipped []*RecordError, err error) {
+	t.Helper()
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	r := newReader(f)
+	for {
+		rec, err := r.Read()
+		if recordErr, ok := err.(*RecordError); ok {
+			skipped = append(skipped, recordErr)
+			continue
+		}
+		if err != nil {
+			return records, skipped, err
+		}
+		records = append(records, rec)
+	}
+}
+
+func checkBooks(t *testing.T, records []*Record, want []*pb.Book) {
+	t.Helper()
+	if len(records) != len(want) {
+		t.Fatalf("read %d records, want %d", len(records), len(want))
+	}
+	for i, rec := range records {
+		if got := Book(rec); !proto.Equal(got, want[i]) {
+			t.Errorf("Book(record %d) = %v, want %v", i+1, got, want[i])
+		}
+	}
+}
+
+func TestLanguageName(t *testing.T) {
+	tests := []struct{ code, want string }{
+		{"eng", "ENGLISH"},
+		{" FRE ", "FRENCH"},
+		{"dan", "DAN"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := LanguageName(tt.code); got != tt.want {
+			t.Errorf("LanguageName(%q) = %q, want %q", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestTrimPunct(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"Modern operating systems /", "Modern operating systems"},
+		{"Pearson,", "Pearson"},
+		{"Boston :", "Boston"},
+		{"Proceedings.", "Proceedings"},
+		{"Tanenbaum, Andrew S.", "Tanenbaum, Andrew S."},
+		{"2nd ed.", "2nd ed."},
+		{"Acme Inc.", "Acme Inc."},
+		{" = ;", ""},
+	}
+	for _, tt := range tests {
+		if got := trimPunct(tt.in); got != tt.want {
+			t.Errorf("trimPunct(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestForenameFirst(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"Tanenbaum, Andrew S.", "Andrew S. Tanenbaum"},
+		{"Homer", "Homer"},
+	}
+	for _, tt := range tests {
+		if got := forenameFirst(tt.in); got != tt.want {
+			t.Errorf("forenameFirst(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestBookPublication(t *testing.T) {
+	field := func(tag string, ind2 byte, b, c string) Field {
+		return Field{Tag: tag, Ind1: ' ', Ind2: ind2, Subfields: []Subfield{{'b', b}, {'c', c}}}
+	}
+	tests := []struct {
+		name   string
+		fields []Field
+		want   *pb.Book
+	}{
+		{
+			name:   "264 publication statement over 260",
+			fields: []Field{field("260", ' ', "Old,", "1990."), field("264", '4', "", "©2001"), field("264", '1', "New,", "2000.")},
+			want:   &pb.Book{Publisher: "New", Copyright: "2000"},
+		},
+		{
+			name:   "260 without a 264 publication statement",
+			fields: []Field{field("264", '3', "Printer,", "1999."), field("260", ' ', "Old,", "1990.")},
+			want:   &pb.Book{Publisher: "Old", Copyright: "1990"},
+		},
+		{
+			name:   "008 date without a year in the statement",
+			fields: []Field{{Tag: "008", Value: "140101s1987"}, field("260", ' ', "Old,", "[n.d.]")},
+			want:   &pb.Book{Publisher: "Old", Copyright: "1987"},
+		},
+		{
+			name:   "several languages in one 041 $a",
+			fields: []Field{{Tag: "041", Subfields: []Subfield{{'a', "engfre"}}}},
+			want:   &pb.Book{Language: "ENGLISH"},
+		},
+	}
+	for _, tt := range tests {
+		if got := Book(&Record{Fields: tt.fields}); !proto.Equal(got, tt.want) {
+			t.Errorf("%s: Book = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
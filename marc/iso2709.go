@@ -0,0 +1,181 @@
+package marc
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	leaderLen          = 24
+	directoryEntryLen  = 12
+	subfieldDelimiter  = 0x1F
+	fieldTerminator    = 0x1E
+	recordTerminator   = 0x1D
+	maxISO2709Record   = 99999
+	minISO2709Record   = leaderLen + 2
+	characterCodingPos = 9
+)
+
+// Reader reads MARC21 records in ISO 2709 transmission format.
+//
+// Records whose leader declares UCS/Unicode (position 9 "a") are read as
+// UTF-8. Others are taken to be MARC-8, which is decoded for the Latin
+// script only: combining diacritics are dropped and escapes to other
+// character sets are not followed.
+type Reader struct {
+	r *bufio.Reader
+	n int
+}
+
+// NewReader returns a Reader reading from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// Read returns the next record. A record with a readable length but a
+// broken directory or fields is skipped with a *RecordError; an unreadable
+// length leaves no way to find the next record and is final.
+func (r *Reader) Read() (*Record, error) {
+	// Some exports put line breaks between records.
+	for {
+		c, err := r.r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if c != '\n' && c != '\r' {
+			r.r.UnreadByte()
+			break
+		}
+	}
+	r.n++
+
+	var length [5]byte
+	if _, err := io.ReadFull(r.r, length[:]); err != nil {
+		return nil, r.fatal(fmt.Errorf("truncated record length: %v", err))
+	}
+	n, err := strconv.Atoi(string(length[:]))
+	if err != nil || n < minISO2709Record || n > maxISO2709Record {
+		return nil, r.fatal(fmt.Errorf("invalid record length %q", length[:]))
+	}
+	data := make([]byte, n)
+	copy(data, length[:])
+	if _, err := io.ReadFull(r.r, data[5:]); err != nil {
+		return nil, r.fatal(fmt.Errorf("truncated record: %v", err))
+	}
+	rec, err := parseISO2709(data)
+	if err != nil {
+		return nil, &RecordError{Record: r.n, Err: err}
+	}
+	return rec, nil
+}
+
+// fatal returns err for the current record as an error that, unlike a
+// *RecordError, ends reading.
+func (r *Reader) fatal(err error) error {
+	return fmt.Errorf("marc: record %d: %v", r.n, err)
+}
+
+func parseISO2709(data []byte) (*Record, error) {
+	if data[len(data)-1] != recordTerminator {
+		return nil, errors.New("missing record terminator")
+	}
+	leader := string(data[:leaderLen])
+	base, err := strconv.Atoi(leader[12:17])
+	if err != nil || base <= leaderLen || base > len(data) {
+		return nil, fmt.Errorf("invalid base address of data %q", leader[12:17])
+	}
+	if data[base-1] != fieldTerminator {
+		return nil, errors.New("missing directory terminator")
+	}
+	directory := data[leaderLen : base-1]
+	if len(directory)%directoryEntryLen != 0 {
+		return nil, fmt.Errorf("directory length %d is not a multiple of %d", len(directory), directoryEntryLen)
+	}
+	decode := decodeUTF8
+	if leader[characterCodingPos] != 'a' {
+		decode = decodeMARC8
+	}
+
+	rec := &Record{Leader: leader}
+	for len(directory) > 0 {
+		entry := string(directory[:directoryEntryLen])
+		directory = directory[directoryEntryLen:]
+		tag := entry[:3]
+		length, err1 := strconv.Atoi(entry[3:7])
+		start, err2 := strconv.Atoi(entry[7:12])
+		if err1 != nil || err2 != nil || length < 1 || base+start+length > len(data)-1 {
+			return nil, fmt.Errorf("invalid directory entry %q", entry)
+		}
+		value := data[base+start : base+start+length]
+		if value[len(value)-1] != fieldTerminator {
+			return nil, fmt.Errorf("field %s is not terminated", tag)
+		}
+		value = value[:len(value)-1]
+
+		field := Field{Tag: tag}
+		if IsControl(tag) {
+			field.Value = decode(value)
+		} else {
+			parts := bytes.Split(value, []byte{subfieldDelimiter})
+			if len(parts[0]) >= 1 {
+				field.Ind1 = parts[0][0]
+			}
+			if len(parts[0]) >= 2 {
+				field.Ind2 = parts[0][1]
+			}
+			for _, p := range parts[1:] {
+				if len(p) == 0 {
+					continue
+				}
+				field.Subfields = append(field.Subfields, Subfield{Code: p[0], Value: decode(p[1:])})
+			}
+		}
+		rec.Fields = append(rec.Fields, field)
+	}
+	return rec, nil
+}
+
+func decodeUTF8(b []byte) string {
+	if utf8.Valid(b) {
+		return string(b)
+	}
+	return strings.ToValidUTF8(string(b), "�")
+}
+
+// marc8Latin maps the MARC-8 extended Latin characters that are letters
+// in their own right, rather than combining diacritics.
+var marc8Latin = map[byte]rune{
+	0xA1: 'Ł', 0xA2: 'Ø', 0xA3: 'Đ', 0xA4: 'Þ', 0xA5: 'Æ', 0xA6: 'Œ',
+	0xA7: 'ʹ', 0xA8: '·', 0xA9: '♭', 0xAA: '®', 0xAB: '±', 0xAC: 'Ơ',
+	0xAD: 'Ư', 0xAE: 'ʼ', 0xB0: 'ʻ', 0xB1: 'ł', 0xB2: 'ø', 0xB3: 'đ',
+	0xB4: 'þ', 0xB5: 'æ', 0xB6: 'œ', 0xB7: 'ʺ', 0xB8: 'ı', 0xB9: '£',
+	0xBA: 'ð', 0xBC: 'ơ', 0xBD: 'ư', 0xC0: '°', 0xC1: 'ℓ', 0xC2: '℗',
+	0xC3: '©', 0xC4: '♯', 0xC5: '¿', 0xC6: '¡', 0xC7: 'ß', 0xC8: '€',
+}
+
+func decodeMARC8(b []byte) string {
+	var s strings.Builder
+	for _, c := range b {
+		switch {
+		case c < 0x20:
+			// Escapes to other character sets are not followed.
+		case c < 0x80:
+			s.WriteByte(c)
+		case c >= 0xE0:
+			// A combining diacritic for the next letter.
+		default:
+			if r, ok := marc8Latin[c]; ok {
+				s.WriteRune(r)
+			} else {
+				s.WriteRune(utf8.RuneError)
+			}
+		}
+	}
+	return s.String()
+}
@@ -0,0 +1,148 @@
+package marc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"google.golang.org/protobuf/proto"
+)
+
+func newISO2709Reader(f *os.File) RecordReader { return NewReader(f) }
+
+func TestReaderBooks(t *testing.T) {
+	records, skipped, err := readAll(t, "testdata/books.mrc", newISO2709Reader)
+	if err != io.EOF || len(skipped) != 0 {
+		t.Fatalf("reading books.mrc ended with %v, skipping %v; want io.EOF and no skipped records", err, skipped)
+	}
+	want := append([]*pb.Book(nil), wantBooks...)
+	want[1] = proto.Clone(want[1]).(*pb.Book)
+	want[1].Title = "Naturens metaphysik: en cafe-samtale"
+	checkBooks(t, records, want)
+
+	if got := records[0].Field("001").Value; got != "rec-1" {
+		t.Errorf("control field 001 = %q, want rec-1", got)
+	}
+	title := records[0].Field("245")
+	if title.Ind1 != '1' || title.Ind2 != '0' || len(title.Subfields) != 2 || title.Subfield('c') != "Andrew S. Tanenbaum." {
+		t.Errorf("field 245 = %+v, want indicators 1 0 and subfields $a $c", title)
+	}
+}
+
+// TestReaderMalformed reads a file whose second record has a directory
+// entry pointing past its data, and whose last record is cut short.
+func TestReaderMalformed(t *testing.T) {
+	records, skipped, err := readAll(t, "testdata/malformed.mrc", newISO2709Reader)
+	if len(skipped) != 1 || skipped[0].Record != 2 || !strings.Contains(skipped[0].Err.Error(), "invalid directory entry") {
+		t.Errorf("skipped %v, want record 2 for its invalid directory entry", skipped)
+	}
+	checkBooks(t, records, []*pb.Book{wantBooks[0], wantBooks[2]})
+	if err == nil || !strings.Contains(err.Error(), "record 4: truncated record") {
+		t.Errorf("reading ended with %v, want record 4 truncated", err)
+	}
+	var recordErr *RecordError
+	if errors.As(err, &recordErr) {
+		t.Errorf("a truncated record ended reading with %T, want a final error", err)
+	}
+}
+
+// firstRecord returns the bytes of the first record of books.mrc.
+func firstRecord(t *testing.T) []byte {
+	t.Helper()
+	data, err := os.ReadFile("testdata/books.mrc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data[:bytes.IndexByte(data, recordTerminator)+1]
+}
+
+func TestParseISO2709Malformed(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(rec []byte) []byte
+		want   string
+	}{
+		{
+			name:   "no record terminator",
+			change: func(rec []byte) []byte { rec[len(rec)-1] = ' '; return rec },
+			want:   "missing record terminator",
+		},
+		{
+			name:   "base address in the leader",
+			change: func(rec []byte) []byte { copy(rec[12:17], "00010"); return rec },
+			want:   "invalid base address of data",
+		},
+		{
+			name:   "base address not a number",
+			change: func(rec []byte) []byte { copy(rec[12:17], "0x1f0"); return rec },
+			want:   "invalid base address of data",
+		},
+		{
+			name:   "no directory terminator",
+			change: func(rec []byte) []byte { copy(rec[12:17], "00037"); return rec },
+			want:   "missing directory terminator",
+		},
+		{
+			name: "directory of partial entries",
+			change: func(rec []byte) []byte {
+				// Move the base address and the directory terminator
+				// back by one byte.
+				base := bytes.IndexByte(rec, fieldTerminator)
+				rec[base-1] = fieldTerminator
+				copy(rec[12:17], fmt.Sprintf("%05d", base))
+				return rec
+			},
+			want: "is not a multiple of 12",
+		},
+		{
+			name:   "zero length field",
+			change: func(rec []byte) []byte { copy(rec[24+3:24+7], "0000"); return rec },
+			want:   "invalid directory entry",
+		},
+		{
+			name:   "field without its terminator",
+			change: func(rec []byte) []byte { copy(rec[24+3:24+7], "0004"); return rec },
+			want:   "field 001 is not terminated",
+		},
+	}
+	for _, tt := range tests {
+		_, err := parseISO2709(tt.change(firstRecord(t)))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: parseISO2709 error = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestReaderInvalidLength(t *testing.T) {
+	rec := firstRecord(t)
+	copy(rec, "12x45")
+	r := NewReader(bytes.NewReader(append(rec, firstRecord(t)...)))
+	_, err := r.Read()
+	var recordErr *RecordError
+	if err == nil || errors.As(err, &recordErr) || !strings.Contains(err.Error(), "invalid record length") {
+		t.Errorf("Read = %v, want a final invalid record length error", err)
+	}
+}
+
+func TestDecodeMARC8(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"plain ASCII", "plain ASCII"},
+		{"\xa2rsted", "Ørsted"},
+		{"caf\xe2e", "cafe"},
+		{"\x1b(Bescape", "(Bescape"},
+		{"\x80", "�"},
+	}
+	for _, tt := range tests {
+		if got := decodeMARC8([]byte(tt.in)); got != tt.want {
+			t.Errorf("decodeMARC8(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// Package marc reads MARC21 bibliographic records, in ISO 2709
+// transmission format or as MARCXML, and maps them onto books.
+package marc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Record is a MARC record: a leader and its fields in file order.
+type Record struct {
+	Leader string
+	Fields []Field
+}
+
+// Field is a control field (tags 001-009), which only has a Value, or a
+// data field with two indicators and subfields.
+type Field struct {
+	Tag       string
+	Value     string
+	Ind1      byte
+	Ind2      byte
+	Subfields []Subfield
+}
+
+// RecordReader reads records one at a time. Read returns io.EOF after the
+// last record, and a *RecordError for a malformed record it skipped, after
+// which reading may go on; any other error is final.
+type RecordReader interface {
+	Read() (*Record, error)
+}
+
+// RecordError reports a malformed record.
+type RecordError struct {
+	// Record is the position of the record in its file, from 1.
+	Record int
+	Err    error
+}
+
+func (e *RecordError) Error() string {
+	return fmt.Sprintf("marc: record %d: %v", e.Record, e.Err)
+}
+
+func (e *RecordError) Unwrap() error { return e.Err }
+
+// Subfield is a data element of a data field.
+type Subfield struct {
+	Code  byte
+	Value string
+}
+
+// IsControl reports whether tag names a control field.
+func IsControl(tag string) bool {
+	return strings.HasPrefix(tag, "00")
+}
+
+// AllFields returns the fields of r with tag.
+func (r *Record) AllFields(tag string) []Field {
+	var fields []Field
+	for _, f := range r.Fields {
+		if f.Tag == tag {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// Field returns the first field of r with tag, or nil.
+func (r *Record) Field(tag string) *Field {
+	for i := range r.Fields {
+		if r.Fields[i].Tag == tag {
+			return &r.Fields[i]
+		}
+	}
+	return nil
+}
+
+// Subfield returns the value of the first subfield of f with code, or "".
+func (f *Field) Subfield(code byte) string {
+	for _, sf := range f.Subfields {
+		if sf.Code == code {
+			return sf.Value
+		}
+	}
+	return ""
+}
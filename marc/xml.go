@@ -0,0 +1,97 @@
+package marc
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// XMLReader reads records from a MARCXML document, one <record> element
+// at a time, so collections of any size are read in constant memory.
+type XMLReader struct {
+	d *xml.Decoder
+	n int
+}
+
+// NewXMLReader returns an XMLReader reading from r.
+func NewXMLReader(r io.Reader) *XMLReader {
+	return &XMLReader{d: xml.NewDecoder(r)}
+}
+
+type xmlRecord struct {
+	Leader  string `xml:"leader"`
+	Control []struct {
+		Tag   string `xml:"tag,attr"`
+		Value string `xml:",chardata"`
+	} `xml:"controlfield"`
+	Data []struct {
+		Tag       string `xml:"tag,attr"`
+		Ind1      string `xml:"ind1,attr"`
+		Ind2      string `xml:"ind2,attr"`
+		Subfields []struct {
+			Code  string `xml:"code,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"subfield"`
+	} `xml:"datafield"`
+}
+
+// Read returns the next record. Records with missing tags or codes are
+// skipped with a *RecordError; XML syntax errors are final.
+func (r *XMLReader) Read() (*Record, error) {
+	for {
+		tok, err := r.d.Token()
+		if err != nil {
+			if err != io.EOF {
+				err = fmt.Errorf("marc: %v", err)
+			}
+			return nil, err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "record" {
+			continue
+		}
+		r.n++
+		var x xmlRecord
+		if err := r.d.DecodeElement(&x, &start); err != nil {
+			return nil, fmt.Errorf("marc: record %d: %v", r.n, err)
+		}
+		rec, err := x.record()
+		if err != nil {
+			return nil, &RecordError{Record: r.n, Err: err}
+		}
+		return rec, nil
+	}
+}
+
+func (x *xmlRecord) record() (*Record, error) {
+	rec := &Record{Leader: x.Leader}
+	for _, c := range x.Control {
+		if len(c.Tag) != 3 {
+			return nil, fmt.Errorf("invalid control field tag %q", c.Tag)
+		}
+		rec.Fields = append(rec.Fields, Field{Tag: c.Tag, Value: c.Value})
+	}
+	for _, d := range x.Data {
+		if len(d.Tag) != 3 {
+			return nil, fmt.Errorf("invalid data field tag %q", d.Tag)
+		}
+		field := Field{Tag: d.Tag, Ind1: indicator(d.Ind1), Ind2: indicator(d.Ind2)}
+		for _, sf := range d.Subfields {
+			if len(sf.Code) != 1 {
+				return nil, fmt.Errorf("field %s: invalid subfield code %q", d.Tag, sf.Code)
+			}
+			field.Subfields = append(field.Subfields, Subfield{Code: sf.Code[0], Value: sf.Value})
+		}
+		rec.Fields = append(rec.Fields, field)
+	}
+	return rec, nil
+}
+
+func indicator(s string) byte {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return ' '
+	}
+	return s[0]
+}
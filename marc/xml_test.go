@@ -0,0 +1,66 @@
+package marc
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+)
+
+func newXMLReader(f *os.File) RecordReader { return NewXMLReader(f) }
+
+func TestXMLReaderBooks(t *testing.T) {
+	records, skipped, err := readAll(t, "testdata/books.xml", newXMLReader)
+	if err != io.EOF || len(skipped) != 0 {
+		t.Fatalf("reading books.xml ended with %v, skipping %v; want io.EOF and no skipped records", err, skipped)
+	}
+	checkBooks(t, records, wantBooks)
+
+	// Empty indicators are blanks, as in ISO 2709.
+	if f := records[1].Field("260"); f.Ind1 != ' ' || f.Ind2 != ' ' {
+		t.Errorf("field 260 indicators = %q %q, want blanks", f.Ind1, f.Ind2)
+	}
+}
+
+// TestXMLReaderMalformed reads a collection with three records of bad
+// tags or codes, which are skipped, and a last one that is not well
+// formed, which ends reading.
+func TestXMLReaderMalformed(t *testing.T) {
+	records, skipped, err := readAll(t, "testdata/malformed.xml", newXMLReader)
+	want := []struct {
+		record int
+		err    string
+	}{
+		{2, `invalid data field tag "24"`},
+		{3, `field 245: invalid subfield code "ab"`},
+		{4, `invalid control field tag "8"`},
+	}
+	if len(skipped) != len(want) {
+		t.Errorf("skipped %v, want records 2, 3 and 4", skipped)
+	}
+	for i := 0; i < len(skipped) && i < len(want); i++ {
+		if skipped[i].Record != want[i].record || skipped[i].Err.Error() != want[i].err {
+			t.Errorf("skipped record %d for %q, want record %d for %q", skipped[i].Record, skipped[i].Err, want[i].record, want[i].err)
+		}
+	}
+	checkBooks(t, records, []*pb.Book{{Title: "First"}, {Title: "Last"}})
+	var recordErr *RecordError
+	if err == nil || err == io.EOF || errors.As(err, &recordErr) || !strings.HasPrefix(err.Error(), "marc: record 6: ") {
+		t.Errorf("reading ended with %v, want a final error for record 6", err)
+	}
+}
+
+func TestXMLReaderNotXML(t *testing.T) {
+	r := NewXMLReader(strings.NewReader("00052nam a2200037 i 4500"))
+	if _, err := r.Read(); err != io.EOF {
+		t.Errorf("Read of a document without records = %v, want io.EOF", err)
+	}
+	r = NewXMLReader(strings.NewReader("<collection><record>"))
+	var recordErr *RecordError
+	if _, err := r.Read(); err == nil || err == io.EOF || errors.As(err, &recordErr) {
+		t.Errorf("Read of a truncated document = %v, want a final error", err)
+	}
+}
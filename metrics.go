@@ -0,0 +1,362 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"github.com/marcoc22/tutorial3/replication"
+	"github.com/marcoc22/tutorial3/store"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// latencyBuckets are the upper bounds, in seconds, of the RPC latency
+// histogram buckets.
+var latencyBuckets = []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5}
+
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+func (h *histogram) observe(v float64) {
+	for i, bound := range latencyBuckets {
+		if v <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += v
+	h.count++
+}
+
+// bookCounts keeps the number of books, in total, per language and per
+// work, up to date as books are stored and removed, so that neither a
+// scrape nor a change to a work has to count them.
+type bookCounts struct {
+	mu         sync.Mutex
+	total      uint64
+	byLanguage map[string]uint64
+	byWork     map[string]uint64
+}
+
+func newBookCounts() *bookCounts {
+	return &bookCounts{byLanguage: make(map[string]uint64), byWork: make(map[string]uint64)}
+}
+
+// add counts a book stored.
+func (c *bookCounts) add(book *pb.Book) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.total++
+	c.byLanguage[book.Language]++
+	if book.WorkId != "" {
+		c.byWork[book.WorkId]++
+	}
+}
+
+// remove counts a book removed.
+func (c *bookCounts) remove(book *pb.Book) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.total--
+	if c.byLanguage[book.Language]--; c.byLanguage[book.Language] == 0 {
+		delete(c.byLanguage, book.Language)
+	}
+	if book.WorkId == "" {
+		return
+	}
+	if c.byWork[book.WorkId]--; c.byWork[book.WorkId] == 0 {
+		delete(c.byWork, book.WorkId)
+	}
+}
+
+// inWork returns the number of books in the work with id.
+func (c *bookCounts) inWork(id string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.byWork[id]
+}
+
+// snapshot returns the counts.
+func (c *bookCounts) snapshot() (uint64, map[string]uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	byLanguage := make(map[string]uint64, len(c.byLanguage))
+	for language, n := range c.byLanguage {
+		byLanguage[language] = n
+	}
+	return c.total, byLanguage
+}
+
+// countedStore is a store.Store keeping counts of the books written
+// through it.
+type countedStore struct {
+	store.Store
+	counts *bookCounts
+
+	// mu serializes the writes to books, so that each is counted against
+	// the record it replaces.
+	mu sync.Mutex
+}
+
+// countBooks counts the books of s once, and returns s wrapped so that
+// the counts follow the books written through it from then on.
+func countBooks(s store.Store) (store.Store, *bookCounts, error) {
+	c := &countedStore{Store: s, counts: newBookCounts()}
+	err := s.Scan(booksCollection, func(id string, value []byte) error {
+		c.counts.add(decodeBook(value))
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return c, c.counts, nil
+}
+
+// decodeBook decodes a stored book. A corrupt one is counted as a book
+// without a language or work.
+func decodeBook(value []byte) *pb.Book {
+	book := &pb.Book{}
+	if err := proto.Unmarshal(value, book); err != nil {
+		return &pb.Book{}
+	}
+	return book
+}
+
+func (c *countedStore) Put(collection, id string, value []byte) error {
+	if collection != booksCollection {
+		return c.Store.Put(collection, id, value)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	old, err := c.Store.Get(collection, id)
+	replaced := err == nil
+	if err != nil && err != store.ErrNotFound {
+		return err
+	}
+	if err := c.Store.Put(collection, id, value); err != nil {
+		return err
+	}
+	if replaced {
+		c.counts.remove(decodeBook(old))
+	}
+	c.counts.add(decodeBook(value))
+	return nil
+}
+
+func (c *countedStore) Delete(collection, id string) error {
+	if collection != booksCollection {
+		return c.Store.Delete(collection, id)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	old, err := c.Store.Get(collection, id)
+	if err != nil {
+		return err
+	}
+	if err := c.Store.Delete(collection, id); err != nil {
+		return err
+	}
+	c.counts.remove(decodeBook(old))
+	return nil
+}
+
+type handledKey struct {
+	method string
+	code   string
+}
+
+// metrics collects RPC statistics and serves them, together with the
+// book store gauges, in the Prometheus text exposition format.
+type metrics struct {
+	counts *bookCounts
+	// cache and node, when set, are the store's read cache and the
+	// replica it belongs to.
+	cache *store.Cache
+	node  *replication.Node
+
+	mu        sync.Mutex
+	started   map[string]uint64
+	handled   map[handledKey]uint64
+	latencies map[string]*histogram
+}
+
+func newMetrics(counts *bookCounts) *metrics {
+	return &metrics{
+		counts:    counts,
+		started:   make(map[string]uint64),
+		handled:   make(map[handledKey]uint64),
+		latencies: make(map[string]*histogram),
+	}
+}
+
+// metricMethod returns the service and Go method name of fullMethod as
+// "service/Method", which the metrics are kept by.
+func metricMethod(fullMethod string) string {
+	return strings.TrimPrefix(path.Dir(fullMethod), "/") + "/" + methodName(fullMethod)
+}
+
+// methodLabels renders the labels of a method kept by metricMethod.
+func methodLabels(method string) string {
+	i := strings.LastIndex(method, "/")
+	return "grpc_service=" + quote(method[:i]) + ",grpc_method=" + quote(method[i+1:])
+}
+
+func (m *metrics) start(method string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.started[method]++
+}
+
+func (m *metrics) finish(method string, start time.Time, err error) {
+	elapsed := time.Since(start).Seconds()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.handled[handledKey{method, status.Code(err).String()}]++
+	h, ok := m.latencies[method]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(latencyBuckets))}
+		m.latencies[method] = h
+	}
+	h.observe(elapsed)
+}
+
+func (m *metrics) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	method := metricMethod(info.FullMethod)
+	m.start(method)
+	resp, err := handler(ctx, req)
+	m.finish(method, start, err)
+	return resp, err
+}
+
+func (m *metrics) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	method := metricMethod(info.FullMethod)
+	m.start(method)
+	err := handler(srv, ss)
+	m.finish(method, start, err)
+	return err
+}
+
+func (m *metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.write(w)
+}
+
+func (m *metrics) write(w io.Writer) {
+	m.mu.Lock()
+	fmt.Fprintln(w, "# HELP grpc_server_started_total Total number of RPCs started on the server.")
+	fmt.Fprintln(w, "# TYPE grpc_server_started_total counter")
+	for _, method := range sortedKeys(m.started) {
+		fmt.Fprintf(w, "grpc_server_started_total{%s} %d\n", methodLabels(method), m.started[method])
+	}
+
+	fmt.Fprintln(w, "# HELP grpc_server_handled_total Total number of RPCs completed on the server, by status code.")
+	fmt.Fprintln(w, "# TYPE grpc_server_handled_total counter")
+	handled := make([]handledKey, 0, len(m.handled))
+	for k := range m.handled {
+		handled = append(handled, k)
+	}
+	sort.Slice(handled, func(i, j int) bool {
+		if handled[i].method != handled[j].method {
+			return handled[i].method < handled[j].method
+		}
+		return handled[i].code < handled[j].code
+	})
+	for _, k := range handled {
+		fmt.Fprintf(w, "grpc_server_handled_total{%s,grpc_code=%s} %d\n",
+			methodLabels(k.method), quote(k.code), m.handled[k])
+	}
+
+	fmt.Fprintln(w, "# HELP grpc_server_handling_seconds Latency of RPCs handled by the server.")
+	fmt.Fprintln(w, "# TYPE grpc_server_handling_seconds histogram")
+	methods := make([]string, 0, len(m.latencies))
+	for method := range m.latencies {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	for _, method := range methods {
+		h := m.latencies[method]
+		for i, bound := range latencyBuckets {
+			fmt.Fprintf(w, "grpc_server_handling_seconds_bucket{%s,le=%s} %d\n",
+				methodLabels(method), quote(strconv.FormatFloat(bound, 'g', -1, 64)), h.counts[i])
+		}
+		fmt.Fprintf(w, "grpc_server_handling_seconds_bucket{%s,le=\"+Inf\"} %d\n", methodLabels(method), h.count)
+		fmt.Fprintf(w, "grpc_server_handling_seconds_sum{%s} %g\n", methodLabels(method), h.sum)
+		fmt.Fprintf(w, "grpc_server_handling_seconds_count{%s} %d\n", methodLabels(method), h.count)
+	}
+	m.mu.Unlock()
+
+	total, byLanguage := m.counts.snapshot()
+	fmt.Fprintln(w, "# HELP bookinfo_books Number of books in the store.")
+	fmt.Fprintln(w, "# TYPE bookinfo_books gauge")
+	fmt.Fprintf(w, "bookinfo_books %d\n", total)
+	fmt.Fprintln(w, "# HELP bookinfo_books_by_language Number of books in the store, by language.")
+	fmt.Fprintln(w, "# TYPE bookinfo_books_by_language gauge")
+	for _, language := range sortedKeys(byLanguage) {
+		fmt.Fprintf(w, "bookinfo_books_by_language{language=%s} %d\n", quote(language), byLanguage[language])
+	}
+
+	if m.cache != nil {
+		stats := m.cache.Stats()
+		for _, c := range []struct {
+			name, help, kind string
+			value            interface{}
+		}{
+			{"bookinfo_store_cache_hits_total", "Store reads served by the read cache.", "counter", stats.Hits},
+			{"bookinfo_store_cache_misses_total", "Store reads the read cache passed to the backend.", "counter", stats.Misses},
+			{"bookinfo_store_cache_evictions_total", "Records evicted from the read cache to stay within its limits.", "counter", stats.Evictions},
+			{"bookinfo_store_cache_entries", "Records held by the read cache.", "gauge", stats.Entries},
+			{"bookinfo_store_cache_bytes", "Bytes of records held by the read cache.", "gauge", stats.Bytes},
+		} {
+			fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %d\n", c.name, c.help, c.name, c.kind, c.name, c.value)
+		}
+	}
+
+	if m.node != nil {
+		status := m.node.Status()
+		leader := 0
+		if status.Role == "leader" {
+			leader = 1
+		}
+		for _, c := range []struct {
+			name, help string
+			value      interface{}
+		}{
+			{"bookinfo_replication_leader", "Whether this replica is the leader.", leader},
+			{"bookinfo_replication_term", "Current election term.", status.Term},
+			{"bookinfo_replication_commit_index", "Index of the last log entry known to be committed.", status.CommitIndex},
+			{"bookinfo_replication_applied_index", "Index of the last log entry applied to the store.", status.LastApplied},
+		} {
+			fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s %d\n", c.name, c.help, c.name, c.name, c.value)
+		}
+	}
+}
+
+func sortedKeys(m map[string]uint64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// quote renders a label value as the exposition format expects.
+func quote(v string) string {
+	return `"` + labelEscaper.Replace(v) + `"`
+}
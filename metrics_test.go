@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"github.com/marcoc22/tutorial3/store"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestBookCounts(t *testing.T) {
+	backend := store.NewMemory()
+	// The books already stored are counted once, at the start.
+	if err := store.PutMessage(backend, booksCollection, "old", &pb.Book{Title: "Momo", Language: "German"}); err != nil {
+		t.Fatal(err)
+	}
+	counted, counts, err := countBooks(backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total, byLanguage := counts.snapshot(); total != 1 || byLanguage["German"] != 1 {
+		t.Errorf("counts of the stored books = %d, %v, want one German", total, byLanguage)
+	}
+	s := newServer(counted, counts)
+	ctx := context.Background()
+	var ids []string
+	for _, b := range []*pb.Book{
+		{Title: "Dune", Language: "English"},
+		{Title: "Emma", Language: "English"},
+		{Title: "Momo", Language: "German"},
+	} {
+		id, err := s.AddBook(ctx, b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id.Value)
+	}
+	if _, err := s.UpdateBook(ctx, &pb.Book{Id: ids[1], Title: "Emma", Language: "French"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.DeleteBook(ctx, &pb.BookID{Value: ids[2]}); err != nil {
+		t.Fatal(err)
+	}
+	// Failed writes count nothing.
+	s.UpdateBook(ctx, &pb.Book{Id: "missing", Language: "Latin"})
+	s.DeleteBook(ctx, &pb.BookID{Value: ids[2]})
+
+	// Other collections are not counted.
+	if err := counted.Put("other", "1", []byte("x")); err != nil {
+		t.Fatal(err)
+	}
+
+	total, byLanguage := counts.snapshot()
+	if total != 3 || len(byLanguage) != 3 || byLanguage["English"] != 1 || byLanguage["French"] != 1 || byLanguage["German"] != 1 {
+		t.Errorf("counts = %d, %v, want 3 with one English, one French and one German", total, byLanguage)
+	}
+}
+
+func TestMetricsWrite(t *testing.T) {
+	counted, counts, err := countBooks(store.NewMemory())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newServer(counted, counts)
+	m := newMetrics(counts)
+	ctx := context.Background()
+	add := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.AddBook(ctx, req.(*pb.Book))
+	}
+	m.unaryInterceptor(ctx, &pb.Book{Language: "English"}, &grpc.UnaryServerInfo{FullMethod: "/booksapp.BookInfo/AddBook"}, add)
+	m.unaryInterceptor(ctx, &pb.BookID{}, &grpc.UnaryServerInfo{FullMethod: "/booksapp.BookInfo/GetBook"},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, status.Error(codes.NotFound, "Book does not exist.")
+		})
+	// Streams name methods the way the wire does; they are counted under
+	// the same labels.
+	m.streamInterceptor(nil, nil, &grpc.StreamServerInfo{FullMethod: "/booksapp.BookInfo/addBook"},
+		func(srv interface{}, stream grpc.ServerStream) error { return nil })
+
+	var out bytes.Buffer
+	m.write(&out)
+	for _, want := range []string{
+		`grpc_server_started_total{grpc_service="booksapp.BookInfo",grpc_method="AddBook"} 2`,
+		`grpc_server_handled_total{grpc_service="booksapp.BookInfo",grpc_method="AddBook",grpc_code="OK"} 2`,
+		`grpc_server_handled_total{grpc_service="booksapp.BookInfo",grpc_method="GetBook",grpc_code="NotFound"} 1`,
+		`grpc_server_handling_seconds_count{grpc_service="booksapp.BookInfo",grpc_method="GetBook"} 1`,
+		`bookinfo_books 1`,
+		`bookinfo_books_by_language{language="English"} 1`,
+	} {
+		if !strings.Contains(out.String(), want+"\n") {
+			t.Errorf("metrics lack %s:\n%s", want, out.String())
+		}
+	}
+}
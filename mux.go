@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"sync"
+	"time"
+)
+
+// http2Preface is what every HTTP/2 connection with prior knowledge, such
+// as a native gRPC client's, starts with.
+const http2Preface = "PRI * HTTP/2.0\r\n\r\nSM\r\n\r\n"
+
+// sniffTimeout bounds how long a new connection may take to send enough
+// bytes to tell which protocol it speaks.
+const sniffTimeout = 10 * time.Second
+
+var errListenerClosed = errors.New("mux: listener closed")
+
+// protocolMux splits the connections accepted on one listener by
+// protocol: HTTP/2 connections go to the gRPC listener and everything
+// else (HTTP/1.x, which carries gRPC-Web, REST and metrics) to the HTTP
+// listener.
+type protocolMux struct {
+	root net.Listener
+	grpc *subListener
+	http *subListener
+}
+
+func newProtocolMux(root net.Listener) *protocolMux {
+	return &protocolMux{
+		root: root,
+		grpc: newSubListener(root.Addr()),
+		http: newSubListener(root.Addr()),
+	}
+}
+
+// serve accepts connections until the root listener is closed.
+func (m *protocolMux) serve() {
+	for {
+		conn, err := m.root.Accept()
+		if err != nil {
+			m.grpc.Close()
+			m.http.Close()
+			return
+		}
+		go m.route(conn)
+	}
+}
+
+// Close stops accepting connections on all listeners.
+func (m *protocolMux) Close() error {
+	return m.root.Close()
+}
+
+func (m *protocolMux) route(conn net.Conn) {
+	conn.SetReadDeadline(time.Now().Add(sniffTimeout))
+	prefix, isHTTP2, err := sniff(conn)
+	if err != nil {
+		log.Printf("mux: dropping connection from %v: %v", conn.RemoteAddr(), err)
+		conn.Close()
+		return
+	}
+	conn.SetReadDeadline(time.Time{})
+
+	routed := &prefixConn{Conn: conn, r: io.MultiReader(bytes.NewReader(prefix), conn)}
+	target := m.http
+	if isHTTP2 {
+		target = m.grpc
+	}
+	if !target.deliver(routed) {
+		conn.Close()
+	}
+}
+
+// sniff reads from conn until the bytes read either diverge from the
+// HTTP/2 preface or match all of it, and returns them.
+func sniff(conn net.Conn) ([]byte, bool, error) {
+	buf := make([]byte, len(http2Preface))
+	n := 0
+	for n < len(buf) {
+		read, err := conn.Read(buf[n:])
+		n += read
+		if !bytes.HasPrefix([]byte(http2Preface), buf[:n]) {
+			return buf[:n], false, nil
+		}
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	return buf, true, nil
+}
+
+// prefixConn replays the bytes consumed while sniffing before reading
+// from the underlying connection.
+type prefixConn struct {
+	net.Conn
+	r io.Reader
+}
+
+func (c *prefixConn) Read(p []byte) (int, error) {
+	return c.r.Read(p)
+}
+
+// subListener is a net.Listener fed with connections by protocolMux.
+type subListener struct {
+	addr  net.Addr
+	conns chan net.Conn
+
+	once   sync.Once
+	closed chan struct{}
+}
+
+func newSubListener(addr net.Addr) *subListener {
+	return &subListener{addr: addr, conns: make(chan net.Conn), closed: make(chan struct{})}
+}
+
+func (l *subListener) deliver(conn net.Conn) bool {
+	select {
+	case l.conns <- conn:
+		return true
+	case <-l.closed:
+		return false
+	}
+}
+
+func (l *subListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.closed:
+		return nil, errListenerClosed
+	}
+}
+
+func (l *subListener) Close() error {
+	l.once.Do(func() { close(l.closed) })
+	return nil
+}
+
+func (l *subListener) Addr() net.Addr {
+	return l.addr
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSniff(t *testing.T) {
+	tests := []struct {
+		name      string
+		writes    []string
+		wantHTTP2 bool
+	}{
+		{name: "preface", writes: []string{http2Preface + "rest"}, wantHTTP2: true},
+		{name: "split preface", writes: []string{"PRI * HT", "TP/2.0\r\n", "\r\nSM\r\n\r\n"}, wantHTTP2: true},
+		{name: "HTTP/1.1", writes: []string{"GET /healthz HTTP/1.1\r\n"}},
+		{name: "diverges late", writes: []string{"PRI * HT", "TP/1.1\r\n"}},
+	}
+	for _, tt := range tests {
+		client, server := net.Pipe()
+		go func() {
+			for _, w := range tt.writes {
+				client.Write([]byte(w))
+			}
+		}()
+		// sniff returns the bytes it consumed, all of the preface for
+		// HTTP/2.
+		sent := strings.Join(tt.writes, "")
+		prefix, isHTTP2, err := sniff(server)
+		switch {
+		case err != nil:
+			t.Errorf("%s: sniff: %v", tt.name, err)
+		case isHTTP2 != tt.wantHTTP2:
+			t.Errorf("%s: sniff reports HTTP/2 %v, want %v", tt.name, isHTTP2, tt.wantHTTP2)
+		case len(prefix) == 0 || !strings.HasPrefix(sent, string(prefix)):
+			t.Errorf("%s: sniff consumed %q, which does not start %q", tt.name, prefix, sent)
+		case isHTTP2 && string(prefix) != http2Preface:
+			t.Errorf("%s: sniff consumed %q, want the preface", tt.name, prefix)
+		}
+		client.Close()
+		server.Close()
+	}
+}
+
+func TestProtocolMuxRoutes(t *testing.T) {
+	root, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := newProtocolMux(root)
+	go mux.serve()
+	defer mux.Close()
+
+	tests := []struct {
+		data   string
+		target *subListener
+	}{
+		{data: http2Preface + "frames", target: mux.grpc},
+		{data: "POST /booksapp.BookInfo/getBook HTTP/1.1\r\n\r\n", target: mux.http},
+	}
+	for _, tt := range tests {
+		conn, err := net.Dial("tcp", root.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		conn.Write([]byte(tt.data))
+		routed, err := tt.target.Accept()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The sniffed bytes are replayed to the listener they are routed to.
+		got := make([]byte, len(tt.data))
+		if _, err := io.ReadFull(routed, got); err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != tt.data {
+			t.Errorf("routed connection reads %q, want %q", got, tt.data)
+		}
+		routed.Close()
+		conn.Close()
+	}
+}
+
+// TestSharedPort calls the server over native gRPC, gRPC-Web in both
+// encodings and REST, and reads /healthz and /metrics, all on its one
+// listen address.
+func TestSharedPort(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the server")
+	}
+	server := startServer(t)
+	base := "http://" + server.addr
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Native gRPC.
+	added, err := pb.NewBookInfoClient(server.dial(t)).AddBook(ctx, &pb.Book{Title: "Native"})
+	if err != nil {
+		t.Fatalf("native gRPC AddBook: %v", err)
+	}
+
+	// REST.
+	resp, err := http.Post(base+booksPath, "application/json", strings.NewReader(`{"title": "REST"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	restBook := &pb.Book{}
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("POST %s = %s, want 201", booksPath, resp.Status)
+	} else if err := unmarshalBody(resp, restBook); err != nil {
+		t.Errorf("POST %s: %v", booksPath, err)
+	}
+	resp, err = http.Get(base + booksPath + "/" + added.Value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := &pb.Book{}
+	if err := unmarshalBody(resp, got); err != nil || got.Title != "Native" {
+		t.Errorf("GET %s/%s = %v, %v, want the book added over native gRPC", booksPath, added.Value, got, err)
+	}
+
+	// gRPC-Web, binary and text.
+	for _, text := range []bool{false, true} {
+		book := &pb.Book{}
+		code, err := grpcWebCall(base+"/booksapp.BookInfo/getBook", &pb.BookID{Value: restBook.Id}, book, text)
+		if err != nil || code != "0" || book.Title != "REST" {
+			t.Errorf("gRPC-Web getBook (text %v) = %v, grpc-status %q, %v, want the book added over REST", text, book, code, err)
+		}
+	}
+	code, err := grpcWebCall(base+"/booksapp.BookInfo/getBook", &pb.BookID{Value: "missing"}, &pb.Book{}, false)
+	if err != nil || code != "5" {
+		t.Errorf("gRPC-Web getBook of a missing book = grpc-status %q, %v, want 5 (NotFound)", code, err)
+	}
+
+	// Health and metrics.
+	resp, err = http.Get(base + "/healthz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || strings.TrimSpace(string(body)) != "SERVING" {
+		t.Errorf("GET /healthz = %s %q, want 200 SERVING", resp.Status, body)
+	}
+	resp, err = http.Get(base + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	// The native, REST and gRPC-Web calls all went through the same
+	// interceptors and are counted alike.
+	for _, method := range []string{"AddBook", "GetBook"} {
+		label := fmt.Sprintf(`grpc_service="booksapp.BookInfo",grpc_method="%s"`, method)
+		if !bytes.Contains(body, []byte(label)) {
+			t.Errorf("GET /metrics has no series for %s", label)
+		}
+	}
+}
+
+func unmarshalBody(resp *http.Response, m proto.Message) error {
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return protojson.Unmarshal(body, m)
+}
+
+// grpcWebCall makes a unary gRPC-Web call to url, decoding the response
+// into reply, and returns the grpc-status of the trailers.
+func grpcWebCall(url string, req, reply proto.Message, text bool) (string, error) {
+	msg, err := proto.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	frame := make([]byte, 5+len(msg))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(msg)))
+	copy(frame[5:], msg)
+	contentType := grpcWebContentType + "+proto"
+	if text {
+		frame = []byte(base64.StdEncoding.EncodeToString(frame))
+		contentType = grpcWebTextContentType
+	}
+	httpReq, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(frame))
+	if err != nil {
+		return "", err
+	}
+	httpReq.Header.Set("Content-Type", contentType)
+	httpReq.Header.Set("X-Grpc-Web", "1")
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("HTTP %s", resp.Status)
+	}
+	for len(body) > 0 {
+		var flag byte
+		var payload []byte
+		if flag, payload, body, err = nextGRPCWebFrame(body, text); err != nil {
+			return "", err
+		}
+		if flag&grpcWebTrailerFlag == 0 {
+			if err := proto.Unmarshal(payload, reply); err != nil {
+				return "", err
+			}
+			continue
+		}
+		for _, line := range strings.Split(string(payload), "\r\n") {
+			if value, ok := strings.CutPrefix(line, "grpc-status: "); ok {
+				return value, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no grpc-status trailer")
+}
+
+// nextGRPCWebFrame splits the first frame off body. In the text encoding
+// every frame is base64 encoded on its own.
+func nextGRPCWebFrame(body []byte, text bool) (flag byte, payload, rest []byte, err error) {
+	if text {
+		// Eight characters decode to the five byte prefix and more.
+		if len(body) < 8 {
+			return 0, nil, nil, fmt.Errorf("short gRPC-Web text frame %q", body)
+		}
+		prefix, err := base64.StdEncoding.DecodeString(string(body[:8]))
+		if err != nil {
+			return 0, nil, nil, err
+		}
+		n := 5 + int(binary.BigEndian.Uint32(prefix[1:5]))
+		encoded := base64.StdEncoding.EncodedLen(n)
+		if len(body) < encoded {
+			return 0, nil, nil, fmt.Errorf("short gRPC-Web text frame")
+		}
+		frame, err := base64.StdEncoding.DecodeString(string(body[:encoded]))
+		if err != nil {
+			return 0, nil, nil, err
+		}
+		return frame[0], frame[5:], body[encoded:], nil
+	}
+	if len(body) < 5 {
+		return 0, nil, nil, fmt.Errorf("short gRPC-Web frame %q", body)
+	}
+	n := 5 + int(binary.BigEndian.Uint32(body[1:5]))
+	if len(body) < n {
+		return 0, nil, nil, fmt.Errorf("short gRPC-Web frame")
+	}
+	return body[0], body[5:n], body[n:], nil
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/gofrs/uuid"
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"github.com/marcoc22/tutorial3/circulation"
+	"github.com/marcoc22/tutorial3/webhook"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// deadLettersCollection keeps the webhook events that could not be
+// delivered, keyed by delivery ID.
+const deadLettersCollection = "dead_letters"
+
+// The types of the webhook events.
+const (
+	holdReadyEvent   = "hold.ready"
+	holdExpiredEvent = "hold.expired"
+)
+
+// holdEvent is the body of a webhook event about a hold. The hold, its
+// patron and its book are in their protobuf JSON form, so that the
+// receiver can reach the patron without calling back.
+type holdEvent struct {
+	ID      string          `json:"id"`
+	Type    string          `json:"type"`
+	Created time.Time       `json:"created"`
+	Hold    json.RawMessage `json:"hold"`
+	Patron  json.RawMessage `json:"patron,omitempty"`
+	Book    json.RawMessage `json:"book,omitempty"`
+}
+
+// startWebhooks delivers the hold events to the endpoint of config, and
+// keeps the events it gives up on as dead letters.
+func (c *circulationService) startWebhooks(config webhook.Config) {
+	config.DeadLetter = c.deadLetter
+	c.webhooks = webhook.New(config)
+}
+
+// notify sends a webhook event of type about hold, if webhooks are on.
+func (c *circulationService) notify(eventType string, hold *pb.Hold) {
+	if c.webhooks == nil {
+		return
+	}
+	id, err := uuid.NewV4()
+	if err != nil {
+		log.Printf("Not sending %s event for hold %s: %v", eventType, hold.Id, err)
+		return
+	}
+	event := holdEvent{ID: id.String(), Type: eventType, Created: c.now().UTC()}
+	event.Hold, _ = protojson.Marshal(hold)
+	if patron, err := c.getPatron(hold.PatronId); err == nil {
+		event.Patron, _ = protojson.Marshal(patron)
+	}
+	if book, err := c.books.getBook(context.Background(), hold.BookId); err == nil {
+		event.Book, _ = protojson.Marshal(book)
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Not sending %s event for hold %s: %v", eventType, hold.Id, err)
+		return
+	}
+	c.webhooks.Send(webhook.Event{ID: event.ID, Type: eventType, Body: body})
+}
+
+// deadLetter keeps an event the webhooks gave up on.
+func (c *circulationService) deadLetter(f webhook.Failure) {
+	log.Printf("Dead-lettering %s event %s after %d attempts: %v", f.Type, f.ID, f.Attempts, f.Err)
+	letter := &pb.DeadLetter{
+		Id:        f.ID,
+		EventType: f.Type,
+		Body:      f.Body,
+		Attempts:  int32(f.Attempts),
+		Error:     f.Err.Error(),
+		Failed:    timestamppb.New(c.now()),
+	}
+	if err := c.put(deadLettersCollection, "Dead letter", letter.Id, letter); err != nil {
+		log.Printf("Lost %s event %s: %v", f.Type, f.ID, err)
+	}
+}
+
+// expireHolds removes the ready holds whose copies have waited on the
+// hold shelf past their expiry, passing the copies on to the next
+// patrons. It returns how many it removed.
+func (c *circulationService) expireHolds() (int, error) {
+	c.books.mu.Lock()
+	defer c.books.mu.Unlock()
+	now := c.now()
+	expired, err := c.holds(func(h *pb.Hold) bool {
+		return h.Status == pb.HoldStatus_READY && h.Expires != nil && circulation.Expired(h.Expires.AsTime(), now)
+	})
+	if err != nil {
+		return 0, err
+	}
+	for i, hold := range expired {
+		if err := c.cancelHold(hold); err != nil {
+			return i, err
+		}
+		c.notify(holdExpiredEvent, hold)
+	}
+	return len(expired), nil
+}
+
+// expireHoldsEvery calls expireHolds every interval until stop is
+// closed. Only the leader of a replicated store does, so that replicas
+// do not pass the same copy on twice.
+func (c *circulationService) expireHoldsEvery(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		if c.leader != nil && !c.leader() {
+			continue
+		}
+		n, err := c.expireHolds()
+		if n > 0 {
+			log.Printf("Expired %d unclaimed holds", n)
+		}
+		if err != nil {
+			log.Printf("Failed to expire holds: %v", err)
+		}
+	}
+}
+
+// start runs the expiry of holds in the background.
+func (c *circulationService) start() {
+	c.stop = make(chan struct{})
+	c.stopped = make(chan struct{})
+	go func() {
+		defer close(c.stopped)
+		c.expireHoldsEvery(min(c.policy.HoldPeriod/4, time.Minute), c.stop)
+	}()
+}
+
+// close stops the expiry of holds and the webhooks, dead-lettering the
+// events not delivered yet.
+func (c *circulationService) close() {
+	if c.stop != nil {
+		close(c.stop)
+		<-c.stopped
+	}
+	if c.webhooks != nil {
+		c.webhooks.Close()
+	}
+}
+
+func (c *circulationService) ListDeadLetters(in *pb.ListDeadLettersRequest, stream pb.Circulation_ListDeadLettersServer) error {
+	return c.scan(deadLettersCollection, "Dead letter", func() proto.Message { return &pb.DeadLetter{} }, func(m proto.Message) error {
+		return stream.Send(m.(*pb.DeadLetter))
+	})
+}
+
+func (c *circulationService) Redeliver(ctx context.Context, in *pb.DeadLetterID) (*pb.DeadLetter, error) {
+	if c.webhooks == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "Webhooks are not configured.")
+	}
+	letter := &pb.DeadLetter{}
+	if err := c.get(deadLettersCollection, "Dead letter", in.Value, letter); err != nil {
+		return nil, err
+	}
+	if err := c.delete(deadLettersCollection, "Dead letter", in.Value); err != nil {
+		return nil, err
+	}
+	c.webhooks.Send(webhook.Event{ID: letter.Id, Type: letter.EventType, Body: letter.Body})
+	return letter, status.New(codes.OK, "").Err()
+}
@@ -0,0 +1,302 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"github.com/marcoc22/tutorial3/circulation"
+	"github.com/marcoc22/tutorial3/store"
+	"github.com/marcoc22/tutorial3/webhook"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const testWebhookSecret = "s3cret"
+
+// webhookReceiver is an endpoint keeping the hold events delivered to
+// it, after checking their signatures. It answers with status, or 200 OK
+// if status is 0.
+type webhookReceiver struct {
+	t *testing.T
+
+	mu     sync.Mutex
+	status int
+	events []holdEvent
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		r.t.Errorf("reading event: %v", err)
+	}
+	if !webhook.Verify(testWebhookSecret, body, req.Header.Get(webhook.SignatureHeader)) {
+		r.t.Errorf("event %s does not verify", body)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.status != 0 {
+		w.WriteHeader(r.status)
+		return
+	}
+	var event holdEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		r.t.Errorf("decoding event %s: %v", body, err)
+	}
+	if event.Type != req.Header.Get(webhook.EventHeader) || event.ID != req.Header.Get(webhook.IDHeader) {
+		r.t.Errorf("event %s %s sent with headers %s %s", event.Type, event.ID, req.Header.Get(webhook.EventHeader), req.Header.Get(webhook.IDHeader))
+	}
+	r.events = append(r.events, event)
+}
+
+// wait waits for n events, and returns the type and patron of each,
+// sorted: the events about different patrons are sent in no set order.
+func (r *webhookReceiver) wait(n int) []string {
+	r.t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		r.mu.Lock()
+		got := len(r.events)
+		r.mu.Unlock()
+		if got >= n {
+			break
+		}
+		if time.Now().After(deadline) {
+			r.t.Fatalf("got %d webhook events, want %d", got, n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var events []string
+	for _, e := range r.events {
+		hold, patron := &pb.Hold{}, &pb.Patron{}
+		if err := protojson.Unmarshal(e.Hold, hold); err != nil {
+			r.t.Fatalf("decoding hold of %s: %v", e.ID, err)
+		}
+		if err := protojson.Unmarshal(e.Patron, patron); err != nil || patron.Id != hold.PatronId {
+			r.t.Errorf("event %s is about the hold of %s but names patron %q", e.ID, hold.PatronId, patron.Id)
+		}
+		events = append(events, e.Type+" "+patron.Name)
+	}
+	sort.Strings(events)
+	return events
+}
+
+// circulationFixture is a circulation service with one book, one copy of
+// it and three patrons, Ann, Ben and Cat, delivering its events to a
+// local endpoint.
+type circulationFixture struct {
+	*circulationService
+	clock    time.Time
+	receiver *webhookReceiver
+	book     string
+	patrons  map[string]string
+}
+
+func newCirculationFixture(t *testing.T) *circulationFixture {
+	t.Helper()
+	f := &circulationFixture{
+		clock:    time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC),
+		receiver: &webhookReceiver{t: t},
+		patrons:  make(map[string]string),
+	}
+	srv := httptest.NewServer(f.receiver)
+	t.Cleanup(srv.Close)
+
+	counted, counts, err := countBooks(store.NewMemory())
+	if err != nil {
+		t.Fatal(err)
+	}
+	books := newServer(counted, counts)
+	f.circulationService = newCirculationService(books, circulation.Policy{
+		LoanPeriod:  circulation.DefaultLoanPeriod,
+		MaxLoans:    circulation.DefaultMaxLoans,
+		MaxRenewals: circulation.DefaultMaxRenewals,
+		HoldPeriod:  circulation.DefaultHoldPeriod,
+	})
+	f.now = func() time.Time { return f.clock }
+	f.startWebhooks(webhook.Config{
+		URL:            srv.URL,
+		Secret:         testWebhookSecret,
+		MaxAttempts:    1,
+		InitialBackoff: time.Millisecond,
+		// A single worker delivers the events in the order sent.
+		Workers: 1,
+	})
+	t.Cleanup(f.close)
+
+	ctx := context.Background()
+	id, err := books.AddBook(ctx, &pb.Book{Title: "The Left Hand of Darkness"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.book = id.Value
+	if _, err := f.AddCopy(ctx, &pb.Copy{Barcode: "C1", BookId: f.book}); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"Ann", "Ben", "Cat"} {
+		patron, err := f.AddPatron(ctx, &pb.Patron{Name: name})
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.patrons[name] = patron.Id
+	}
+	return f
+}
+
+// copyStatus returns the status of copy C1 and the patron of the hold it
+// is kept for, if any.
+func (f *circulationFixture) copyStatus(t *testing.T) (pb.CopyStatus, string) {
+	t.Helper()
+	copy, err := f.getCopy("C1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if copy.HoldId == "" {
+		return copy.Status, ""
+	}
+	hold, err := f.getHold(copy.HoldId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, id := range f.patrons {
+		if id == hold.PatronId {
+			return copy.Status, name
+		}
+	}
+	return copy.Status, hold.PatronId
+}
+
+// TestExpireHolds lends the copy to Ann while Ben and Cat wait for it,
+// and checks that the copy is kept for each in turn, and the patrons
+// told, as the holds expire.
+func TestExpireHolds(t *testing.T) {
+	f := newCirculationFixture(t)
+	ctx := context.Background()
+	if _, err := f.Checkout(ctx, &pb.CheckoutRequest{Barcode: "C1", PatronId: f.patrons["Ann"]}); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"Ben", "Cat"} {
+		f.clock = f.clock.Add(time.Minute)
+		if _, err := f.PlaceHold(ctx, &pb.PlaceHoldRequest{BookId: f.book, PatronId: f.patrons[name]}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	resp, err := f.ReturnCopy(ctx, &pb.Barcode{Value: "C1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Hold == nil || resp.Hold.PatronId != f.patrons["Ben"] {
+		t.Fatalf("returned copy went to hold %v, want Ben's", resp.Hold)
+	}
+
+	steps := []struct {
+		name        string
+		after       time.Duration
+		wantExpired int
+		wantStatus  pb.CopyStatus
+		wantFor     string
+		wantEvents  []string
+	}{
+		{"before the expiry", circulation.DefaultHoldPeriod - time.Second, 0, pb.CopyStatus_ON_HOLD_SHELF, "Ben",
+			[]string{"hold.ready Ben"}},
+		{"at Ben's expiry", time.Second, 1, pb.CopyStatus_ON_HOLD_SHELF, "Cat",
+			[]string{"hold.expired Ben", "hold.ready Ben", "hold.ready Cat"}},
+		{"at Cat's expiry", circulation.DefaultHoldPeriod, 1, pb.CopyStatus_ON_SHELF, "",
+			[]string{"hold.expired Ben", "hold.expired Cat", "hold.ready Ben", "hold.ready Cat"}},
+		{"with no holds left", circulation.DefaultHoldPeriod, 0, pb.CopyStatus_ON_SHELF, "",
+			[]string{"hold.expired Ben", "hold.expired Cat", "hold.ready Ben", "hold.ready Cat"}},
+	}
+	for _, step := range steps {
+		f.clock = f.clock.Add(step.after)
+		n, err := f.expireHolds()
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if n != step.wantExpired {
+			t.Errorf("%s: expired %d holds, want %d", step.name, n, step.wantExpired)
+		}
+		if st, keptFor := f.copyStatus(t); st != step.wantStatus || keptFor != step.wantFor {
+			t.Errorf("%s: copy is %v for %q, want %v for %q", step.name, st, keptFor, step.wantStatus, step.wantFor)
+		}
+		events := f.receiver.wait(len(step.wantEvents))
+		if len(events) != len(step.wantEvents) {
+			t.Errorf("%s: events %q, want %q", step.name, events, step.wantEvents)
+			continue
+		}
+		for i := range events {
+			if events[i] != step.wantEvents[i] {
+				t.Errorf("%s: events %q, want %q", step.name, events, step.wantEvents)
+				break
+			}
+		}
+	}
+	holds, err := f.holds(func(*pb.Hold) bool { return true })
+	if err != nil || len(holds) != 0 {
+		t.Errorf("holds left after expiry: %v, %v", holds, err)
+	}
+}
+
+// TestDeadLetters checks that an event the endpoint refuses is kept as a
+// dead letter, and delivered again by Redeliver.
+func TestDeadLetters(t *testing.T) {
+	f := newCirculationFixture(t)
+	ctx := context.Background()
+	f.receiver.mu.Lock()
+	f.receiver.status = http.StatusBadRequest
+	f.receiver.mu.Unlock()
+
+	if _, err := f.Checkout(ctx, &pb.CheckoutRequest{Barcode: "C1", PatronId: f.patrons["Ann"]}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.PlaceHold(ctx, &pb.PlaceHoldRequest{BookId: f.book, PatronId: f.patrons["Ben"]}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.ReturnCopy(ctx, &pb.Barcode{Value: "C1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var letters []*pb.DeadLetter
+	deadline := time.Now().Add(10 * time.Second)
+	for len(letters) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("refused event was not dead-lettered")
+		}
+		time.Sleep(time.Millisecond)
+		letters = nil
+		err := f.scan(deadLettersCollection, "Dead letter", func() proto.Message { return &pb.DeadLetter{} }, func(m proto.Message) error {
+			letters = append(letters, m.(*pb.DeadLetter))
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	letter := letters[0]
+	if len(letters) != 1 || letter.EventType != holdReadyEvent || letter.Attempts != 1 || !letter.Failed.AsTime().Equal(f.clock) {
+		t.Fatalf("dead letters %v, want the hold.ready event after 1 attempt", letters)
+	}
+
+	f.receiver.mu.Lock()
+	f.receiver.status = 0
+	f.receiver.mu.Unlock()
+	if _, err := f.Redeliver(ctx, &pb.DeadLetterID{Value: letter.Id}); err != nil {
+		t.Fatal(err)
+	}
+	if events := f.receiver.wait(1); len(events) != 1 || events[0] != "hold.ready Ben" {
+		t.Errorf("redelivered %q, want Ben's hold.ready", events)
+	}
+	if _, err := f.Redeliver(ctx, &pb.DeadLetterID{Value: letter.Id}); status.Code(err) != codes.NotFound {
+		t.Errorf("Redeliver of a redelivered letter = %v, want NotFound", err)
+	}
+}
@@ -0,0 +1,403 @@
+// Package onix reads ONIX for Books 3.0 feeds and maps their products
+// onto books. Both the reference and the short tag forms are read.
+package onix
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"github.com/marcoc22/tutorial3/marc"
+)
+
+// Notification types, from ONIX code list 1, that change how a product
+// is applied.
+const (
+	NotificationBlockUpdate = "04"
+	NotificationDelete      = "05"
+	NotificationTestUpdate  = "88"
+	NotificationTestRecord  = "89"
+)
+
+// Product is a product of a feed.
+type Product struct {
+	// Reference is the sender's record reference for the product.
+	Reference    string
+	Notification string
+	// ISBNs holds every ISBN the product is identified by, without
+	// hyphens; Book.Isbn is the preferred one, ISBN-13 if there is one.
+	ISBNs []string
+	Book  *pb.Book
+}
+
+// IsDelete reports whether the feed asks for the product to be removed.
+func (p *Product) IsDelete() bool {
+	return p.Notification == NotificationDelete
+}
+
+// IsBlockUpdate reports whether the product only carries the blocks of
+// data that changed, to be merged into the book it updates.
+func (p *Product) IsBlockUpdate() bool {
+	return p.Notification == NotificationBlockUpdate
+}
+
+// IsTest reports whether the product is a test record, not to be applied.
+func (p *Product) IsTest() bool {
+	return p.Notification == NotificationTestUpdate || p.Notification == NotificationTestRecord
+}
+
+// ProductError reports a product that could not be mapped onto a book.
+// Reading may go on after one.
+type ProductError struct {
+	// Product is the position of the product in the feed, from 1.
+	Product   int
+	Reference string
+	Err       error
+}
+
+func (e *ProductError) Error() string {
+	if e.Reference != "" {
+		return fmt.Sprintf("onix: product %d (%s): %v", e.Product, e.Reference, e.Err)
+	}
+	return fmt.Sprintf("onix: product %d: %v", e.Product, e.Err)
+}
+
+func (e *ProductError) Unwrap() error { return e.Err }
+
+// Reader reads the products of a feed one at a time, so feeds of any
+// size are read in constant memory.
+type Reader struct {
+	d *xml.Decoder
+	n int
+}
+
+// NewReader returns a Reader reading from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{d: xml.NewTokenDecoder(referenceNames{xml.NewDecoder(r)})}
+}
+
+// Read returns the next product, io.EOF after the last one, or a
+// *ProductError for a product without an ISBN. XML syntax errors are
+// final.
+func (r *Reader) Read() (*Product, error) {
+	for {
+		tok, err := r.d.Token()
+		if err != nil {
+			if err != io.EOF {
+				err = fmt.Errorf("onix: %v", err)
+			}
+			return nil, err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "Product" {
+			continue
+		}
+		r.n++
+		var x xmlProduct
+		if err := r.d.DecodeElement(&x, &start); err != nil {
+			return nil, fmt.Errorf("onix: product %d: %v", r.n, err)
+		}
+		p := x.product()
+		if len(p.ISBNs) == 0 {
+			return nil, &ProductError{Product: r.n, Reference: p.Reference, Err: fmt.Errorf("no ISBN")}
+		}
+		return p, nil
+	}
+}
+
+// shortTags maps the short tags of the elements Reader uses to their
+// reference names.
+var shortTags = map[string]string{
+	"product":            "Product",
+	"a001":               "RecordReference",
+	"a002":               "NotificationType",
+	"productidentifier":  "ProductIdentifier",
+	"b221":               "ProductIDType",
+	"b244":               "IDValue",
+	"descriptivedetail":  "DescriptiveDetail",
+	"titledetail":        "TitleDetail",
+	"b202":               "TitleType",
+	"titleelement":       "TitleElement",
+	"x409":               "TitleElementLevel",
+	"b203":               "TitleText",
+	"b030":               "TitlePrefix",
+	"b031":               "TitleWithoutPrefix",
+	"b029":               "Subtitle",
+	"contributor":        "Contributor",
+	"b034":               "SequenceNumber",
+	"b035":               "ContributorRole",
+	"b036":               "PersonName",
+	"b039":               "NamesBeforeKey",
+	"b040":               "KeyNames",
+	"b047":               "CorporateName",
+	"b057":               "EditionNumber",
+	"b058":               "EditionStatement",
+	"language":           "Language",
+	"b253":               "LanguageRole",
+	"b252":               "LanguageCode",
+	"extent":             "Extent",
+	"b218":               "ExtentType",
+	"b219":               "ExtentValue",
+	"b220":               "ExtentUnit",
+	"publishingdetail":   "PublishingDetail",
+	"publisher":          "Publisher",
+	"b291":               "PublishingRole",
+	"b081":               "PublisherName",
+	"publishingdate":     "PublishingDate",
+	"x448":               "PublishingDateRole",
+	"b306":               "Date",
+	"copyrightstatement": "CopyrightStatement",
+	"b087":               "CopyrightYear",
+}
+
+// referenceNames renames short tag elements to their reference names and
+// drops namespaces, so a single set of struct tags decodes both forms.
+type referenceNames struct {
+	r xml.TokenReader
+}
+
+func (n referenceNames) Token() (xml.Token, error) {
+	tok, err := n.r.Token()
+	switch t := tok.(type) {
+	case xml.StartElement:
+		t.Name = rename(t.Name)
+		tok = t
+	case xml.EndElement:
+		t.Name = rename(t.Name)
+		tok = t
+	}
+	return tok, err
+}
+
+func rename(name xml.Name) xml.Name {
+	if long, ok := shortTags[name.Local]; ok {
+		return xml.Name{Local: long}
+	}
+	return xml.Name{Local: name.Local}
+}
+
+type xmlProduct struct {
+	RecordReference   string
+	NotificationType  string
+	ProductIdentifier []struct {
+		ProductIDType string
+		IDValue       string
+	}
+	DescriptiveDetail struct {
+		TitleDetail []struct {
+			TitleType    string
+			TitleElement []struct {
+				TitleElementLevel  string
+				TitleText          string
+				TitlePrefix        string
+				TitleWithoutPrefix string
+				Subtitle           string
+			}
+		}
+		Contributor []struct {
+			SequenceNumber  string
+			ContributorRole []string
+			PersonName      string
+			NamesBeforeKey  string
+			KeyNames        string
+			CorporateName   string
+		}
+		EditionNumber    string
+		EditionStatement string
+		Language         []struct {
+			LanguageRole string
+			LanguageCode string
+		}
+		Extent []struct {
+			ExtentType  string
+			ExtentValue string
+			ExtentUnit  string
+		}
+	}
+	PublishingDetail struct {
+		Publisher []struct {
+			PublishingRole string
+			PublisherName  string
+		}
+		PublishingDate []struct {
+			PublishingDateRole string
+			Date               string
+		}
+		CopyrightStatement []struct {
+			CopyrightYear []string
+		}
+	}
+}
+
+// Product identifier types, from ONIX code list 5.
+const (
+	idISBN10 = "02"
+	idGTIN13 = "03"
+	idISBN13 = "15"
+)
+
+func (x *xmlProduct) product() *Product {
+	p := &Product{
+		Reference:    strings.TrimSpace(x.RecordReference),
+		Notification: strings.TrimSpace(x.NotificationType),
+		Book:         &pb.Book{},
+	}
+	b := p.Book
+
+	preferred := 0
+	for _, id := range x.ProductIdentifier {
+		value := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(id.IDValue))
+		rank := 0
+		switch strings.TrimSpace(id.ProductIDType) {
+		case idISBN13:
+			rank = 3
+		case idISBN10:
+			rank = 2
+		case idGTIN13:
+			// Only GTINs in the Bookland ranges are ISBNs.
+			if strings.HasPrefix(value, "978") || strings.HasPrefix(value, "979") {
+				rank = 1
+			}
+		}
+		if rank == 0 || value == "" {
+			continue
+		}
+		p.ISBNs = append(p.ISBNs, value)
+		if rank > preferred {
+			b.Isbn, preferred = value, rank
+		}
+	}
+
+	d := &x.DescriptiveDetail
+	for _, td := range d.TitleDetail {
+		// Title type 01 is the distinctive title of the product, and
+		// element level 01 the product itself rather than a collection.
+		if strings.TrimSpace(td.TitleType) != "01" {
+			continue
+		}
+		for _, te := range td.TitleElement {
+			if strings.TrimSpace(te.TitleElementLevel) != "01" {
+				continue
+			}
+			title := strings.TrimSpace(te.TitleText)
+			if title == "" {
+				title = strings.TrimSpace(strings.TrimSpace(te.TitlePrefix) + " " + strings.TrimSpace(te.TitleWithoutPrefix))
+			}
+			if sub := strings.TrimSpace(te.Subtitle); sub != "" {
+				title += ": " + sub
+			}
+			b.Title = title
+			break
+		}
+	}
+
+	// Contributor role A01 is "by (author)".
+	type author struct {
+		seq  int
+		name string
+	}
+	var authors []author
+	for i, c := range d.Contributor {
+		isAuthor := false
+		for _, role := range c.ContributorRole {
+			isAuthor = isAuthor || strings.TrimSpace(role) == "A01"
+		}
+		if !isAuthor {
+			continue
+		}
+		name := strings.TrimSpace(c.PersonName)
+		if name == "" && c.KeyNames != "" {
+			name = strings.TrimSpace(strings.TrimSpace(c.NamesBeforeKey) + " " + strings.TrimSpace(c.KeyNames))
+		}
+		if name == "" {
+			name = strings.TrimSpace(c.CorporateName)
+		}
+		seq, err := strconv.Atoi(strings.TrimSpace(c.SequenceNumber))
+		if err != nil {
+			seq = 1000 + i
+		}
+		if name != "" {
+			authors = append(authors, author{seq, name})
+		}
+	}
+	sort.SliceStable(authors, func(i, j int) bool { return authors[i].seq < authors[j].seq })
+	names := make([]string, len(authors))
+	for i, a := range authors {
+		names[i] = a.name
+	}
+	b.Author = strings.Join(names, ", ")
+
+	if s := strings.TrimSpace(d.EditionStatement); s != "" {
+		b.Edition = s
+	} else if n, err := strconv.Atoi(strings.TrimSpace(d.EditionNumber)); err == nil && n > 0 {
+		b.Edition = ordinal(n)
+	}
+
+	// Language role 01 is the language of the text.
+	for _, l := range d.Language {
+		if strings.TrimSpace(l.LanguageRole) == "01" {
+			b.Language = marc.LanguageName(l.LanguageCode)
+			break
+		}
+	}
+
+	// Extent types 00, 11 and 08 are the main content, content and total
+	// numbered page counts, in order of preference; unit 03 is pages.
+	pagesRank := 0
+	for _, e := range d.Extent {
+		if strings.TrimSpace(e.ExtentUnit) != "03" {
+			continue
+		}
+		rank := map[string]int{"00": 3, "11": 2, "08": 1}[strings.TrimSpace(e.ExtentType)]
+		if rank > pagesRank {
+			b.Pages, pagesRank = strings.TrimSpace(e.ExtentValue), rank
+		}
+	}
+
+	pd := &x.PublishingDetail
+	// Publishing role 01 is the publisher, as opposed to a co-publisher
+	// or sponsor.
+	for _, pub := range pd.Publisher {
+		if strings.TrimSpace(pub.PublishingRole) == "01" {
+			b.Publisher = strings.TrimSpace(pub.PublisherName)
+			break
+		}
+	}
+	for _, cs := range pd.CopyrightStatement {
+		for _, year := range cs.CopyrightYear {
+			if year = strings.TrimSpace(year); len(year) == 4 {
+				b.Copyright = year
+			}
+		}
+	}
+	if b.Copyright == "" {
+		// Publishing date role 01 is the publication date.
+		for _, pdate := range pd.PublishingDate {
+			date := strings.TrimSpace(pdate.Date)
+			if strings.TrimSpace(pdate.PublishingDateRole) == "01" && len(date) >= 4 {
+				b.Copyright = date[:4]
+				break
+			}
+		}
+	}
+	return p
+}
+
+// ordinal spells n the way editions are written, as in "2nd".
+func ordinal(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+	return strconv.Itoa(n) + suffix
+}
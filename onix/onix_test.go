@@ -0,0 +1,134 @@
+package onix
+
+import (
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"google.golang.org/protobuf/proto"
+)
+
+// readFeed reads every product of testdata/name. It returns the products
+// read, the product errors skipped and the error that ended reading.
+func readFeed(t *testing.T, name string) ([]*Product, []*ProductError, error) {
+	t.Helper()
+	f, err := os.Open("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	r := NewReader(f)
+	var products []*Product
+	var skipped []*ProductError
+	for {
+		p, err := r.Read()
+		var productErr *ProductError
+		if errors.As(err, &productErr) {
+			skipped = append(skipped, productErr)
+			continue
+		}
+		if err != nil {
+			return products, skipped, err
+		}
+		products = append(products, p)
+	}
+}
+
+// goodOmens is the first product of reference.xml and short.xml.
+var goodOmens = &pb.Book{
+	Isbn:      "9780552137034",
+	Title:     "The Nice and Accurate Prophecies: of Agnes Nutter, Witch",
+	Author:    "Terry Pratchett, Neil Gaiman",
+	Edition:   "2nd",
+	Language:  "ENGLISH",
+	Pages:     "400",
+	Publisher: "Gollancz",
+	Copyright: "1990",
+}
+
+func checkProduct(t *testing.T, got *Product, want Product) {
+	t.Helper()
+	if got.Reference != want.Reference || got.Notification != want.Notification || !reflect.DeepEqual(got.ISBNs, want.ISBNs) {
+		t.Errorf("product %s: reference %q, notification %q, ISBNs %q; want %q, %q, %q",
+			want.Reference, got.Reference, got.Notification, got.ISBNs, want.Reference, want.Notification, want.ISBNs)
+	}
+	if !proto.Equal(got.Book, want.Book) {
+		t.Errorf("product %s: book %v, want %v", want.Reference, got.Book, want.Book)
+	}
+}
+
+func TestReadReferenceTags(t *testing.T) {
+	products, skipped, err := readFeed(t, "reference.xml")
+	if err != io.EOF {
+		t.Fatalf("reading reference.xml ended with %v, want io.EOF", err)
+	}
+	want := []Product{
+		{"com.example.0001", "03", []string{"0552137036", "9780552137034"}, goodOmens},
+		{"com.example.0002", "04", []string{"0441013597"}, &pb.Book{
+			Isbn: "0441013597", Title: "Dune", Edition: "40th anniversary edition", Copyright: "2005",
+		}},
+		{"com.example.0003", "05", []string{"9780140449136"}, &pb.Book{Isbn: "9780140449136"}},
+		{"com.example.0004", "89", []string{"9780000000002"}, &pb.Book{Isbn: "9780000000002"}},
+		{"com.example.0006", "03", []string{"9780261103573"}, &pb.Book{
+			Isbn: "9780261103573", Title: "The Fellowship of the Ring", Author: "Tolkien Estate", Edition: "11th",
+		}},
+	}
+	if len(products) != len(want) {
+		t.Fatalf("read %d products, want %d", len(products), len(want))
+	}
+	for i := range want {
+		checkProduct(t, products[i], want[i])
+	}
+
+	for i, tt := range []struct{ delete, blockUpdate, test bool }{
+		{false, false, false},
+		{false, true, false},
+		{true, false, false},
+		{false, false, true},
+		{false, false, false},
+	} {
+		p := products[i]
+		if p.IsDelete() != tt.delete || p.IsBlockUpdate() != tt.blockUpdate || p.IsTest() != tt.test {
+			t.Errorf("product %s: delete %v, block update %v, test %v; want %v, %v, %v",
+				p.Reference, p.IsDelete(), p.IsBlockUpdate(), p.IsTest(), tt.delete, tt.blockUpdate, tt.test)
+		}
+	}
+
+	if len(skipped) != 1 || skipped[0].Error() != "onix: product 5 (com.example.0005): no ISBN" {
+		t.Errorf("skipped %v, want product 5 for having no ISBN", skipped)
+	}
+}
+
+func TestReadShortTags(t *testing.T) {
+	products, skipped, err := readFeed(t, "short.xml")
+	if err != io.EOF || len(skipped) != 0 {
+		t.Fatalf("reading short.xml ended with %v, skipping %v; want io.EOF and nothing skipped", err, skipped)
+	}
+	if len(products) != 1 {
+		t.Fatalf("read %d products, want 1", len(products))
+	}
+	checkProduct(t, products[0], Product{"com.example.0001", "03", []string{"0552137036", "9780552137034"}, goodOmens})
+}
+
+func TestReadMalformed(t *testing.T) {
+	products, skipped, err := readFeed(t, "malformed.xml")
+	if len(products) != 1 || products[0].Book.Isbn != "9780552137034" || len(skipped) != 0 {
+		t.Errorf("read %v, skipping %v; want the first product only", products, skipped)
+	}
+	var productErr *ProductError
+	if err == nil || err == io.EOF || errors.As(err, &productErr) || !strings.HasPrefix(err.Error(), "onix: product 2: ") {
+		t.Errorf("reading ended with %v, want a final error for product 2", err)
+	}
+}
+
+func TestOrdinal(t *testing.T) {
+	for n, want := range map[int]string{1: "1st", 2: "2nd", 3: "3rd", 4: "4th", 11: "11th", 12: "12th", 13: "13th", 21: "21st", 102: "102nd", 111: "111th"} {
+		if got := ordinal(n); got != want {
+			t.Errorf("ordinal(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Paths the API description is published under.
+const (
+	protoSourcePath = "/proto/books_info.proto"
+	descriptorPath  = "/proto/descriptor"
+	openAPIPath     = "/openapi.json"
+)
+
+// describeRoutes adds the handlers publishing the API description to
+// routes: the .proto source, its FileDescriptorSet (binary by default,
+// JSON with ?format=json) and an OpenAPI document for the REST gateway.
+func describeRoutes(routes *http.ServeMux) {
+	routes.HandleFunc(protoSourcePath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write(pb.ProtoSource)
+	})
+	routes.HandleFunc(descriptorPath, func(w http.ResponseWriter, r *http.Request) {
+		set := descriptorSet(pb.File_books_info_proto)
+		var body []byte
+		var err error
+		if r.URL.Query().Get("format") == "json" {
+			w.Header().Set("Content-Type", "application/json")
+			body, err = protojson.Marshal(set)
+		} else {
+			w.Header().Set("Content-Type", "application/x-protobuf")
+			body, err = proto.Marshal(set)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(body)
+	})
+	routes.HandleFunc(openAPIPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.Encode(openAPIDocument())
+	})
+}
+
+// descriptorSet returns fd and everything it imports, dependencies first,
+// as protoc --include_imports would.
+func descriptorSet(fd protoreflect.FileDescriptor) *descriptorpb.FileDescriptorSet {
+	set := &descriptorpb.FileDescriptorSet{}
+	seen := make(map[string]bool)
+	var add func(protoreflect.FileDescriptor)
+	add = func(fd protoreflect.FileDescriptor) {
+		if seen[fd.Path()] {
+			return
+		}
+		seen[fd.Path()] = true
+		imports := fd.Imports()
+		for i := 0; i < imports.Len(); i++ {
+			add(imports.Get(i).FileDescriptor)
+		}
+		set.File = append(set.File, protodesc.ToFileDescriptorProto(fd))
+	}
+	add(fd)
+	return set
+}
+
+type object = map[string]interface{}
+
+// openAPIDocument describes the REST gateway as an OpenAPI 3.0 document.
+// Schemas are derived from the message descriptors, so they follow the
+// .proto file; the routes mirror the ones gateway serves.
+func openAPIDocument() object {
+	book := (&pb.Book{}).ProtoReflect().Descriptor()
+	schemas := object{
+		"Error": object{
+			"type": "object",
+			"properties": object{
+				"code":    object{"type": "integer", "format": "int32"},
+				"message": object{"type": "string"},
+			},
+		},
+	}
+	addSchema(schemas, book)
+
+	bookRef := object{"$ref": "#/components/schemas/" + string(book.Name())}
+	bookBody := object{"required": true, "content": object{"application/json": object{"schema": bookRef}}}
+	bookResponse := func(description string) object {
+		return object{"description": description, "content": object{"application/json": object{"schema": bookRef}}}
+	}
+	errorResponse := object{
+		"description": "The gRPC status of the failed call, mapped to an HTTP status.",
+		"content": object{"application/json": object{
+			"schema": object{"$ref": "#/components/schemas/Error"},
+		}},
+	}
+	idParam := []object{{"name": "id", "in": "path", "required": true, "schema": object{"type": "string"}}}
+
+	return object{
+		"openapi": "3.0.3",
+		"info": object{
+			"title":       "BookInfo",
+			"version":     "v1",
+			"description": "REST/JSON gateway for the booksapp.BookInfo gRPC service.",
+		},
+		"paths": object{
+			booksPath: object{
+				"post": object{
+					"operationId": "AddBook",
+					"requestBody": bookBody,
+					"responses":   object{"201": bookResponse("The created book."), "default": errorResponse},
+				},
+			},
+			booksPath + "/{id}": object{
+				"parameters": idParam,
+				"get": object{
+					"operationId": "GetBook",
+					"responses":   object{"200": bookResponse("The book."), "default": errorResponse},
+				},
+				"patch": object{
+					"operationId": "UpdateBook",
+					"description": "Only the fields present in the body are changed.",
+					"requestBody": bookBody,
+					"responses":   object{"200": bookResponse("The updated book."), "default": errorResponse},
+				},
+				"delete": object{
+					"operationId": "DeleteBook",
+					"responses":   object{"200": bookResponse("The deleted book."), "default": errorResponse},
+				},
+			},
+		},
+		"components": object{"schemas": schemas},
+	}
+}
+
+// addSchema adds a JSON schema for md, and the messages it refers to, to
+// schemas.
+func addSchema(schemas object, md protoreflect.MessageDescriptor) {
+	name := string(md.Name())
+	if _, ok := schemas[name]; ok {
+		return
+	}
+	properties := object{}
+	schemas[name] = object{"type": "object", "properties": properties}
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		schema := fieldSchema(schemas, fd)
+		if fd.IsList() {
+			schema = object{"type": "array", "items": schema}
+		}
+		properties[fd.JSONName()] = schema
+	}
+}
+
+func fieldSchema(schemas object, fd protoreflect.FieldDescriptor) object {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return object{"type": "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return object{"type": "integer", "format": "int32"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		// protojson encodes 64-bit integers as strings.
+		return object{"type": "string", "format": "int64"}
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return object{"type": "number"}
+	case protoreflect.BytesKind:
+		return object{"type": "string", "format": "byte"}
+	case protoreflect.EnumKind:
+		values := fd.Enum().Values()
+		names := make([]string, values.Len())
+		for i := range names {
+			names[i] = string(values.Get(i).Name())
+		}
+		return object{"type": "string", "enum": names}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		addSchema(schemas, fd.Message())
+		return object{"$ref": "#/components/schemas/" + string(fd.Message().Name())}
+	default:
+		return object{"type": "string"}
+	}
+}
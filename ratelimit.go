@@ -0,0 +1,234 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// defaultRateLimits is used when RATE_LIMITS is not set. AddBook is kept
+// stricter than the read path so a looping importer cannot starve readers.
+const defaultRateLimits = "*=50:100,AddBook=10:20"
+
+// bucketIdleTimeout is how long an unused bucket is kept before it is
+// dropped; an idle bucket is full anyway, so forgetting it is harmless.
+const bucketIdleTimeout = 10 * time.Minute
+
+// rateLimit is a token bucket configuration: rate tokens per second with
+// room for at most burst tokens.
+type rateLimit struct {
+	rate  float64
+	burst float64
+}
+
+// parseRateLimits parses a comma separated list of method=rate:burst
+// entries, e.g. "*=50:100,AddBook=10:20". The "*" entry applies to every
+// method without an entry of its own. Methods may be given by their Go or
+// their wire name, AddBook or addBook.
+func parseRateLimits(spec string) (map[string]rateLimit, error) {
+	limits := make(map[string]rateLimit)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		kv := strings.SplitN(entry, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid rate limit %q: want method=rate:burst", entry)
+		}
+		rb := strings.SplitN(kv[1], ":", 2)
+		rate, err := strconv.ParseFloat(rb[0], 64)
+		if err != nil || rate <= 0 {
+			return nil, fmt.Errorf("invalid rate in %q", entry)
+		}
+		burst := math.Max(rate, 1)
+		if len(rb) == 2 {
+			burst, err = strconv.ParseFloat(rb[1], 64)
+			if err != nil || burst < 1 {
+				return nil, fmt.Errorf("invalid burst in %q", entry)
+			}
+		}
+		method := strings.TrimSpace(kv[0])
+		if method != "*" {
+			method = methodName(method)
+		}
+		limits[method] = rateLimit{rate: rate, burst: burst}
+	}
+	return limits, nil
+}
+
+// methodName returns the Go name of the method of fullMethod, e.g.
+// "AddBook" for "/booksapp.BookInfo/addBook", so that limits and metrics
+// name methods the same way whatever their wire names are.
+func methodName(fullMethod string) string {
+	name := path.Base(fullMethod)
+	r, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToUpper(r)) + name[size:]
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// take refills the bucket for the time elapsed since the last call and
+// takes one token. When the bucket is empty it returns how long the caller
+// has to wait for the next token.
+func (b *tokenBucket) take(l rateLimit, now time.Time) (bool, time.Duration) {
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := (1 - b.tokens) / l.rate
+	return false, time.Duration(wait * float64(time.Second))
+}
+
+// rateLimiter keeps one token bucket per client and method. A limiter
+// without limits lets every call through, and so does every limiter for
+// the servers of the cluster, peers recognises.
+type rateLimiter struct {
+	peers *peerAuth
+
+	mu      sync.Mutex
+	limits  map[string]rateLimit
+	buckets map[string]*tokenBucket
+	swept   time.Time
+}
+
+func newRateLimiter(limits map[string]rateLimit, peers *peerAuth) *rateLimiter {
+	return &rateLimiter{
+		peers:   peers,
+		limits:  limits,
+		buckets: make(map[string]*tokenBucket),
+		swept:   time.Now(),
+	}
+}
+
+// setLimits replaces the limits, refilling every bucket.
+func (l *rateLimiter) setLimits(limits map[string]rateLimit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limits = limits
+	l.buckets = make(map[string]*tokenBucket)
+}
+
+// allow reports whether client may call method now, and if not, how long
+// it should wait before retrying.
+func (l *rateLimiter) allow(client, method string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	limit, ok := l.limits[method]
+	if !ok {
+		if limit, ok = l.limits["*"]; !ok {
+			return true, 0
+		}
+	}
+
+	now := time.Now()
+	if now.Sub(l.swept) > bucketIdleTimeout {
+		for key, b := range l.buckets {
+			if now.Sub(b.last) > bucketIdleTimeout {
+				delete(l.buckets, key)
+			}
+		}
+		l.swept = now
+	}
+	key := client + " " + method
+	b, exists := l.buckets[key]
+	if !exists {
+		b = &tokenBucket{tokens: limit.burst, last: now}
+		l.buckets[key] = b
+	}
+	return b.take(limit, now)
+}
+
+// clientKey identifies the caller: the subject of a verified TLS client
+// certificate when there is one, otherwise the caller's IP address so
+// that reconnecting from a new port does not reset the bucket.
+func clientKey(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "unknown"
+	}
+	if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+		if certs := tlsInfo.State.VerifiedChains; len(certs) > 0 && len(certs[0]) > 0 {
+			return "cn:" + certs[0][0].Subject.CommonName
+		}
+	}
+	addr := callerAddr(ctx)
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+// callerAddr returns the address of the caller: the peer, or for calls
+// the gateway and the gRPC-Web bridge make through the in-process pipe,
+// the HTTP client they make them for.
+func callerAddr(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "unknown"
+	}
+	if _, ok := p.Addr.(pipeAddr); ok {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if addr := md.Get(clientAddrKey); len(addr) > 0 {
+			return addr[0]
+		}
+	}
+	return p.Addr.String()
+}
+
+// check returns a ResourceExhausted error, along with the retry-after
+// trailer to send, when the caller is over its limit for fullMethod.
+// Calls from the other servers of a cluster are never limited.
+func (l *rateLimiter) check(ctx context.Context, fullMethod string) (metadata.MD, error) {
+	if l.peers.isPeer(ctx) {
+		return nil, nil
+	}
+	method := methodName(fullMethod)
+	ok, wait := l.allow(clientKey(ctx), method)
+	if ok {
+		return nil, nil
+	}
+	retryAfter := int(math.Ceil(wait.Seconds()))
+	trailer := metadata.Pairs(
+		"retry-after", strconv.Itoa(retryAfter),
+		"retry-after-ms", strconv.FormatInt(int64(wait/time.Millisecond), 10),
+	)
+	return trailer, status.Errorf(codes.ResourceExhausted,
+		"Rate limit exceeded for %s, retry after %ds", method, retryAfter)
+}
+
+func (l *rateLimiter) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if trailer, err := l.check(ctx, info.FullMethod); err != nil {
+		grpc.SetTrailer(ctx, trailer)
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (l *rateLimiter) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if trailer, err := l.check(ss.Context(), info.FullMethod); err != nil {
+		ss.SetTrailer(trailer)
+		return err
+	}
+	return handler(srv, ss)
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestParseRateLimits(t *testing.T) {
+	limits, err := parseRateLimits(" *=50:100, addBook=10:20,GetBook=2.5 ,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]rateLimit{
+		"*":       {rate: 50, burst: 100},
+		"AddBook": {rate: 10, burst: 20},
+		"GetBook": {rate: 2.5, burst: 2.5},
+	}
+	if len(limits) != len(want) {
+		t.Errorf("parsed %v, want %v", limits, want)
+	}
+	for method, l := range want {
+		if limits[method] != l {
+			t.Errorf("limit of %s = %+v, want %+v", method, limits[method], l)
+		}
+	}
+
+	for _, spec := range []string{"AddBook", "AddBook=x", "AddBook=0", "AddBook=-1:5", "AddBook=1:0.5", "AddBook=1:y"} {
+		if _, err := parseRateLimits(spec); err == nil {
+			t.Errorf("parseRateLimits(%q) succeeded, want an error", spec)
+		}
+	}
+}
+
+func TestMethodName(t *testing.T) {
+	tests := []struct{ fullMethod, want string }{
+		{"/booksapp.BookInfo/AddBook", "AddBook"},
+		{"/booksapp.BookInfo/addBook", "AddBook"},
+		{"/booksapp.BookInfo/listBooks", "ListBooks"},
+		{"getBook", "GetBook"},
+	}
+	for _, tt := range tests {
+		if got := methodName(tt.fullMethod); got != tt.want {
+			t.Errorf("methodName(%q) = %q, want %q", tt.fullMethod, got, tt.want)
+		}
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	limit := rateLimit{rate: 2, burst: 3}
+	now := time.Unix(1700000000, 0)
+	b := &tokenBucket{tokens: limit.burst, last: now}
+	for i := 0; i < 3; i++ {
+		if ok, _ := b.take(limit, now); !ok {
+			t.Fatalf("take %d of a full bucket of 3 refused", i+1)
+		}
+	}
+	ok, wait := b.take(limit, now)
+	if ok || wait != 500*time.Millisecond {
+		t.Errorf("take of an empty bucket = %v, wait %v, want refused for 500ms", ok, wait)
+	}
+
+	// Half a second at two tokens a second refills one token.
+	now = now.Add(500 * time.Millisecond)
+	if ok, _ := b.take(limit, now); !ok {
+		t.Error("take after the wait refused")
+	}
+	if ok, wait := b.take(limit, now.Add(100*time.Millisecond)); ok || wait != 400*time.Millisecond {
+		t.Errorf("take 100ms later = %v, wait %v, want refused for 400ms", ok, wait)
+	}
+
+	// An idle bucket fills up to its burst, no further.
+	now = now.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		if ok, _ := b.take(limit, now); !ok {
+			t.Fatalf("take %d after an hour refused", i+1)
+		}
+	}
+	if ok, _ := b.take(limit, now); ok {
+		t.Error("bucket refilled past its burst")
+	}
+}
+
+// peerContext returns the context of a call from addr.
+func peerContext(addr net.Addr) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+}
+
+func TestRateLimiterPerMethod(t *testing.T) {
+	limits, err := parseRateLimits("*=1:5,addBook=1:2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := newRateLimiter(limits, nil)
+	alice := peerContext(&net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 40000})
+	// Reconnecting from another port is the same client.
+	aliceAgain := peerContext(&net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 40001})
+	bob := peerContext(&net.TCPAddr{IP: net.IPv4(10, 0, 0, 2), Port: 40000})
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		fullMethod string
+		wantOK     bool
+	}{
+		// Unary calls name the method AddBook, streams and REST addBook:
+		// both draw on the AddBook bucket.
+		{"first AddBook", alice, "/booksapp.BookInfo/AddBook", true},
+		{"second AddBook", aliceAgain, "/booksapp.BookInfo/addBook", true},
+		{"third AddBook", alice, "/booksapp.BookInfo/AddBook", false},
+		{"another client's AddBook", bob, "/booksapp.BookInfo/AddBook", true},
+		// GetBook has no limit of its own and takes the "*" one.
+		{"GetBook over the AddBook limit", alice, "/booksapp.BookInfo/GetBook", true},
+	}
+	for _, tt := range tests {
+		trailer, err := l.check(tt.ctx, tt.fullMethod)
+		if ok := err == nil; ok != tt.wantOK {
+			t.Errorf("%s: check = %v, want allowed %v", tt.name, err, tt.wantOK)
+			continue
+		}
+		if tt.wantOK {
+			continue
+		}
+		if status.Code(err) != codes.ResourceExhausted {
+			t.Errorf("%s: check = %v, want ResourceExhausted", tt.name, err)
+		}
+		if got := trailer.Get("retry-after"); len(got) != 1 || got[0] != "1" {
+			t.Errorf("%s: retry-after = %v, want 1", tt.name, got)
+		}
+	}
+
+	for i := 0; i < 4; i++ {
+		if _, err := l.check(alice, "/booksapp.BookInfo/GetBook"); err != nil {
+			t.Fatalf("GetBook %d of a burst of 5: %v", i+2, err)
+		}
+	}
+	if _, err := l.check(alice, "/booksapp.BookInfo/getBook"); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("GetBook past the burst = %v, want ResourceExhausted", err)
+	}
+}
+
+func TestCallerAddr(t *testing.T) {
+	tcp := &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 40000}
+	claim := metadata.Pairs(clientAddrKey, "198.51.100.7")
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{"no peer", context.Background(), "unknown"},
+		{"network peer", peerContext(tcp), "10.0.0.1:40000"},
+		// Only the gateway, through the in-process pipe, may name the
+		// client it calls for.
+		{"network peer naming a client", metadata.NewIncomingContext(peerContext(tcp), claim), "10.0.0.1:40000"},
+		{"pipe naming a client", metadata.NewIncomingContext(peerContext(pipeAddr{}), claim), "198.51.100.7"},
+		{"pipe without a client", peerContext(pipeAddr{}), "in-process"},
+	}
+	for _, tt := range tests {
+		if got := callerAddr(tt.ctx); got != tt.want {
+			t.Errorf("%s: callerAddr = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	// Clients behind the gateway each have a bucket of their own.
+	limits, err := parseRateLimits("*=1:1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := newRateLimiter(limits, nil)
+	for _, addr := range []string{"198.51.100.7", "198.51.100.8"} {
+		ctx := metadata.NewIncomingContext(peerContext(pipeAddr{}), metadata.Pairs(clientAddrKey, addr))
+		if _, err := l.check(ctx, "/booksapp.BookInfo/GetBook"); err != nil {
+			t.Errorf("first call of %s through the gateway: %v", addr, err)
+		}
+	}
+}
+
+func TestRateLimiterExemptsPeers(t *testing.T) {
+	limits, err := parseRateLimits("*=1:1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := newRateLimiter(limits, newPeerAuth("clust3r", nil))
+	addr := &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 40000}
+	bearer := func(token string) context.Context {
+		return metadata.NewIncomingContext(peerContext(addr), metadata.Pairs("authorization", "Bearer "+token))
+	}
+
+	// Replicas call each other far more often than any client limit.
+	for i := 0; i < 5; i++ {
+		if _, err := l.check(bearer("clust3r"), "/booksapp.Replication/AppendEntries"); err != nil {
+			t.Fatalf("call %d of a peer: %v", i+1, err)
+		}
+	}
+	// Calling a replication method does not make a client a peer.
+	if _, err := l.check(bearer("client"), "/booksapp.Replication/AppendEntries"); err != nil {
+		t.Fatalf("first call of a client: %v", err)
+	}
+	if _, err := l.check(bearer("client"), "/booksapp.Replication/AppendEntries"); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("second call of a client = %v, want ResourceExhausted", err)
+	}
+	// Nor does a nil peerAuth recognise anyone.
+	l = newRateLimiter(limits, nil)
+	l.check(bearer("clust3r"), "/booksapp.BookInfo/GetBook")
+	if _, err := l.check(bearer("clust3r"), "/booksapp.BookInfo/GetBook"); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("second call without peers = %v, want ResourceExhausted", err)
+	}
+}
@@ -0,0 +1,317 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/marcoc22/tutorial3/replication"
+	"github.com/marcoc22/tutorial3/store"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// replicationMethods prefixes the methods replicas call on each other.
+const replicationMethods = "/booksapp.Replication/"
+
+// newReplicaNode makes backend one replica of the cluster c describes.
+func newReplicaNode(c *config, backend store.Store) (*replication.Node, error) {
+	opts, err := peerDialOptions(c)
+	if err != nil {
+		return nil, err
+	}
+	return replication.NewNode(replication.Config{
+		ID:              c.Replication.NodeID,
+		Peers:           c.Replication.Peers,
+		Dir:             c.Replication.Dir,
+		DialOptions:     opts,
+		ElectionTimeout: c.Replication.ElectionTimeout,
+	}, backend)
+}
+
+// peerDialOptions connects to the other servers of the cluster the way
+// they expect peers to: over TLS when it is on, presenting this server's
+// own certificate and trusting its client CAs, and with the cluster
+// token.
+func peerDialOptions(c *config) ([]grpc.DialOption, error) {
+	var opts []grpc.DialOption
+	if c.TLS.CertFile == "" {
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	} else {
+		cert, err := tls.LoadX509KeyPair(c.TLS.CertFile, c.TLS.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+		if c.TLS.ClientCAFile != "" {
+			pem, err := ioutil.ReadFile(c.TLS.ClientCAFile)
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.RootCAs = x509.NewCertPool()
+			if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("%s: no certificates found", c.TLS.ClientCAFile)
+			}
+		}
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	}
+	if c.Cluster.Token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(peerToken{token: c.Cluster.Token, insecure: c.Cluster.InsecureToken}))
+	}
+	return opts, nil
+}
+
+// peerToken authenticates calls to the other servers of the cluster. It
+// is only sent over TLS unless insecure.
+type peerToken struct {
+	token    string
+	insecure bool
+}
+
+func (t peerToken) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + t.token}, nil
+}
+
+func (t peerToken) RequireTransportSecurity() bool { return !t.insecure }
+
+// forwardedKey marks the calls a follower forwards to the leader, so
+// that a node that lost the leadership in the meantime does not forward
+// them again.
+const forwardedKey = "bookinfo-forwarded"
+
+// replicatedServices are the services whose writes leaderForwarder sends
+// to the leader.
+var replicatedServices = []string{"booksapp.BookInfo", "booksapp.Circulation"}
+
+// readPrefixes start the names of the methods that do not write.
+var readPrefixes = []string{"Get", "List", "Watch", "Format"}
+
+// leaderForwarder makes the leader run every write call. A handler checks
+// the store before writing to it, and only the leader's checks are
+// serialized with every write: a follower would check its own, possibly
+// stale, copy.
+type leaderForwarder struct {
+	node    *replication.Node
+	peers   *peerAuth
+	timeout time.Duration
+	// conns connect to the other replicas by node ID.
+	conns map[string]*grpc.ClientConn
+}
+
+func newLeaderForwarder(c *config, node *replication.Node, peers *peerAuth) (*leaderForwarder, error) {
+	opts, err := peerDialOptions(c)
+	if err != nil {
+		return nil, err
+	}
+	f := &leaderForwarder{node: node, peers: peers, timeout: 10 * c.Replication.ElectionTimeout,
+		conns: make(map[string]*grpc.ClientConn)}
+	for id, addr := range c.Replication.Peers {
+		conn, err := grpc.Dial(addr, opts...)
+		if err != nil {
+			f.close()
+			return nil, fmt.Errorf("replica %s: %v", id, err)
+		}
+		f.conns[id] = conn
+	}
+	return f, nil
+}
+
+func (f *leaderForwarder) close() {
+	for _, conn := range f.conns {
+		conn.Close()
+	}
+}
+
+// writeMethod reports whether fullMethod is a write of a replicated
+// service.
+func writeMethod(fullMethod string) bool {
+	service := strings.TrimPrefix(path.Dir(fullMethod), "/")
+	replicated := false
+	for _, s := range replicatedServices {
+		replicated = replicated || s == service
+	}
+	if !replicated {
+		return false
+	}
+	name := methodName(fullMethod)
+	for _, prefix := range readPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// leader waits for a leader that has caught up. It returns nil if that
+// is this node, and the connection to it otherwise.
+func (f *leaderForwarder) leader(ctx context.Context) (*grpc.ClientConn, error) {
+	ctx, cancel := context.WithTimeout(ctx, f.timeout)
+	defer cancel()
+	forwarded := false
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get(forwardedKey)) > 0 {
+		forwarded = f.peers.isPeer(ctx)
+	}
+	for {
+		st := f.node.Status()
+		switch {
+		case st.Leader == st.ID:
+			if f.node.AwaitLeadership(ctx) {
+				return nil, nil
+			}
+		case st.Leader != "" && !forwarded:
+			if conn := f.conns[st.Leader]; conn != nil {
+				return conn, nil
+			}
+		}
+		select {
+		case <-time.After(f.timeout / 50):
+		case <-ctx.Done():
+			return nil, status.Errorf(codes.Unavailable, "No replica is leading the cluster; retry later.")
+		}
+	}
+}
+
+// outgoing carries the metadata of the incoming call over to the call
+// forwarded to the leader, except for the credentials: the leader trusts
+// this replica to have checked them.
+func outgoing(ctx context.Context) context.Context {
+	in, _ := metadata.FromIncomingContext(ctx)
+	md := metadata.MD{}
+	for key, values := range in {
+		switch {
+		case key == "authorization", key == clientAddrKey, key == "user-agent", key == "content-type",
+			key == "te", strings.HasPrefix(key, ":"), strings.HasPrefix(key, "grpc-"):
+			continue
+		}
+		md[key] = values
+	}
+	md.Set(forwardedKey, "1")
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+func (f *leaderForwarder) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if !writeMethod(info.FullMethod) {
+		return handler(ctx, req)
+	}
+	conn, err := f.leader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if conn == nil {
+		return handler(ctx, req)
+	}
+	method, _, out, err := messageTypes(info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	reply := out.New().Interface()
+	var header, trailer metadata.MD
+	err = conn.Invoke(outgoing(ctx), method, req, reply, grpc.Header(&header), grpc.Trailer(&trailer))
+	grpc.SetHeader(ctx, header)
+	grpc.SetTrailer(ctx, trailer)
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (f *leaderForwarder) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if !writeMethod(info.FullMethod) {
+		return handler(srv, ss)
+	}
+	conn, err := f.leader(ss.Context())
+	if err != nil {
+		return err
+	}
+	if conn == nil {
+		return handler(srv, ss)
+	}
+	method, in, out, err := messageTypes(info.FullMethod)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(outgoing(ss.Context()))
+	defer cancel()
+	desc := &grpc.StreamDesc{ClientStreams: info.IsClientStream, ServerStreams: info.IsServerStream}
+	cs, err := conn.NewStream(ctx, desc, method)
+	if err != nil {
+		return err
+	}
+	go func() {
+		for {
+			m := in.New().Interface()
+			if err := ss.RecvMsg(m); err != nil {
+				if err == io.EOF {
+					cs.CloseSend()
+				} else {
+					cancel()
+				}
+				return
+			}
+			if err := cs.SendMsg(m); err != nil {
+				return
+			}
+		}
+	}()
+	if header, err := cs.Header(); err == nil {
+		ss.SetHeader(header)
+	}
+	for {
+		m := out.New().Interface()
+		err := cs.RecvMsg(m)
+		if err == io.EOF {
+			ss.SetTrailer(cs.Trailer())
+			return nil
+		}
+		if err != nil {
+			ss.SetTrailer(cs.Trailer())
+			return err
+		}
+		if err := ss.SendMsg(m); err != nil {
+			return err
+		}
+	}
+}
+
+// messageTypes returns the path fullMethod is served at and its request
+// and response types. Unary handlers see the method capitalised while the
+// generated clients call it in lower camel case, so names are matched
+// ignoring case and the path is taken from the descriptor.
+func messageTypes(fullMethod string) (method string, in, out protoreflect.MessageType, err error) {
+	service := strings.TrimPrefix(path.Dir(fullMethod), "/")
+	d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return "", nil, nil, status.Errorf(codes.Internal, "Error while forwarding %s: %v", fullMethod, err)
+	}
+	sd, ok := d.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return "", nil, nil, status.Errorf(codes.Internal, "Error while forwarding %s: %s is not a service", fullMethod, service)
+	}
+	methods := sd.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		md := methods.Get(i)
+		if !strings.EqualFold(string(md.Name()), path.Base(fullMethod)) {
+			continue
+		}
+		if in, err = protoregistry.GlobalTypes.FindMessageByName(md.Input().FullName()); err == nil {
+			out, err = protoregistry.GlobalTypes.FindMessageByName(md.Output().FullName())
+		}
+		if err != nil {
+			return "", nil, nil, status.Errorf(codes.Internal, "Error while forwarding %s: %v", fullMethod, err)
+		}
+		return "/" + service + "/" + string(md.Name()), in, out, nil
+	}
+	return "", nil, nil, status.Errorf(codes.Internal, "Error while forwarding %s: no such method", fullMethod)
+}
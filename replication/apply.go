@@ -0,0 +1,207 @@
+package replication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"github.com/marcoc22/tutorial3/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	errNotLeader = errors.New("replication: not the leader")
+	// errPending marks the results of entries proposed on this node that
+	// are not applied yet.
+	errPending = errors.New("replication: pending")
+)
+
+// applyLoop applies the committed entries to the store, in order.
+func (n *Node) applyLoop() {
+	defer n.wg.Done()
+	for {
+		select {
+		case <-n.stop:
+			return
+		case <-n.wakeApply:
+		}
+		for {
+			n.mu.Lock()
+			if n.lastApplied >= n.commitIndex {
+				n.mu.Unlock()
+				break
+			}
+			first := n.lastApplied + 1
+			entries := append([]*pb.LogEntry(nil), n.log[first:n.commitIndex+1]...)
+			observer := n.observer
+			n.mu.Unlock()
+
+			for i, entry := range entries {
+				index := first + uint64(i)
+				existed, err := n.apply(entry.Mutation, observer != nil)
+				n.mu.Lock()
+				n.lastApplied = index
+				if _, waiting := n.results[index]; waiting {
+					n.results[index] = err
+				}
+				close(n.applied)
+				n.applied = make(chan struct{})
+				n.mu.Unlock()
+				if observer != nil && entry.Mutation != nil && err == nil {
+					observer(entry.Mutation, existed)
+				}
+			}
+		}
+	}
+}
+
+// apply makes the change m describes to the store. Deleting a record that
+// does not exist is reported but harmless; any other failure would leave
+// the node's copy diverging from the others, so it is fatal.
+func (n *Node) apply(m *pb.Mutation, checkExisted bool) (existed bool, err error) {
+	if m == nil {
+		return false, nil
+	}
+	if checkExisted && !m.Delete {
+		_, getErr := n.backend.Get(m.Collection, m.Id)
+		existed = getErr == nil
+	}
+	if m.Delete {
+		err = n.backend.Delete(m.Collection, m.Id)
+		existed = err == nil
+	} else {
+		err = n.backend.Put(m.Collection, m.Id, m.Value)
+	}
+	if err != nil && err != store.ErrNotFound {
+		log.Fatalf("replication: failed to apply a mutation of %s/%s: %v", m.Collection, m.Id, err)
+	}
+	return existed, err
+}
+
+// proposeLocal logs m if the node leads, and waits for it to be applied.
+func (n *Node) proposeLocal(ctx context.Context, m *pb.Mutation) (index, term uint64, err error) {
+	n.mu.Lock()
+	if n.role != leader {
+		n.mu.Unlock()
+		return 0, 0, errNotLeader
+	}
+	term = n.term
+	n.appendEntries(&pb.LogEntry{Term: term, Mutation: m})
+	index = n.lastIndex()
+	n.results[index] = errPending
+	// A node without peers commits on its own.
+	n.advanceCommit()
+	n.mu.Unlock()
+	return index, term, n.waitApplied(ctx, index, term, true)
+}
+
+// waitApplied waits for the entry logged at index in term to be applied,
+// and returns its result if it was proposed on this node. It fails if a
+// different entry is committed at index.
+func (n *Node) waitApplied(ctx context.Context, index, term uint64, proposed bool) error {
+	for {
+		n.mu.Lock()
+		if n.lastApplied >= index {
+			var err error
+			if proposed {
+				err = n.results[index]
+				delete(n.results, index)
+			}
+			if n.log[index].Term != term {
+				err = errLostLeadership
+			}
+			n.mu.Unlock()
+			return err
+		}
+		if proposed && (index > n.lastIndex() || n.log[index].Term != term) {
+			delete(n.results, index)
+			n.mu.Unlock()
+			return errLostLeadership
+		}
+		applied := n.applied
+		n.mu.Unlock()
+
+		select {
+		case <-applied:
+		case <-ctx.Done():
+			n.mu.Lock()
+			if proposed {
+				delete(n.results, index)
+			}
+			n.mu.Unlock()
+			return fmt.Errorf("replication: write not applied in time: %w", store.ErrUnavailable)
+		case <-n.stop:
+			return errStopped
+		}
+	}
+}
+
+// write replicates m, forwarding it to the leader if need be, and waits
+// until it is applied on this node too, so that the writer reads its
+// write from any node it wrote through.
+func (n *Node) write(m *pb.Mutation) error {
+	ctx, cancel := context.WithTimeout(context.Background(), n.cfg.ProposeTimeout)
+	defer cancel()
+	for {
+		_, _, err := n.proposeLocal(ctx, m)
+		if err != errNotLeader {
+			return err
+		}
+
+		n.mu.Lock()
+		p := n.peers[n.leaderID]
+		n.mu.Unlock()
+		if p != nil {
+			resp, err := p.client.Propose(ctx, &pb.ProposeRequest{Mutation: m})
+			if err == nil {
+				if err := n.waitApplied(ctx, resp.Index, resp.Term, false); err != nil {
+					return err
+				}
+				if resp.NotFound {
+					return store.ErrNotFound
+				}
+				return nil
+			}
+			if code := status.Code(err); code != codes.FailedPrecondition && code != codes.Unavailable && code != codes.DeadlineExceeded {
+				return fmt.Errorf("replication: leader %s: %v", p.id, err)
+			}
+		}
+
+		// Wait for an election, or for news of the leader.
+		select {
+		case <-time.After(n.cfg.HeartbeatInterval):
+		case <-ctx.Done():
+			return ErrNoLeader
+		case <-n.stop:
+			return errStopped
+		}
+	}
+}
+
+func (n *Node) Get(collection, id string) ([]byte, error) {
+	return n.backend.Get(collection, id)
+}
+
+func (n *Node) Put(collection, id string, value []byte) error {
+	return n.write(&pb.Mutation{Collection: collection, Id: id, Value: value})
+}
+
+func (n *Node) Delete(collection, id string) error {
+	return n.write(&pb.Mutation{Collection: collection, Id: id, Delete: true})
+}
+
+func (n *Node) Scan(collection string, fn func(id string, value []byte) error) error {
+	return n.backend.Scan(collection, fn)
+}
+
+func (n *Node) Flush() error { return n.backend.Flush() }
+
+// Close stops the node and closes its store.
+func (n *Node) Close() error {
+	n.Stop()
+	return n.backend.Close()
+}
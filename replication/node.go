@@ -0,0 +1,438 @@
+// Package replication keeps a book store replicated over several nodes.
+//
+// The nodes elect a leader and keep a log of the mutations made to the
+// store, following the Raft consensus algorithm: the leader appends every
+// write to its log, copies the log to the followers and applies a
+// mutation once a majority of the nodes has it. Every node applies the
+// same mutations in the same order, so followers serve reads of a recent,
+// consistent state. A follower given a write forwards it to the leader.
+// When the leader stops answering, the others elect a new one.
+//
+// The log is never compacted: a node joining or rejoining the cluster is
+// sent the log from the start.
+package replication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"github.com/marcoc22/tutorial3/store"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+)
+
+// Config describes a node and its cluster.
+type Config struct {
+	// ID names the node among its peers.
+	ID string
+	// Peers maps the IDs of the other nodes to the addresses of their
+	// Replication services.
+	Peers map[string]string
+	// Dir, if set, is where the node keeps its term, vote and log, which
+	// must survive restarts for elections to be safe.
+	Dir string
+	// DialOptions are used to connect to the peers.
+	DialOptions []grpc.DialOption
+	// ElectionTimeout is the shortest time a follower waits for the
+	// leader before standing for election; the actual wait is picked at
+	// random between it and twice it.
+	ElectionTimeout time.Duration
+	// HeartbeatInterval is how often the leader contacts the followers
+	// when it has nothing new to send.
+	HeartbeatInterval time.Duration
+	// ProposeTimeout bounds how long a write waits to be applied.
+	ProposeTimeout time.Duration
+}
+
+type role int
+
+const (
+	follower role = iota
+	candidate
+	leader
+)
+
+func (r role) String() string {
+	return [...]string{"follower", "candidate", "leader"}[r]
+}
+
+// maxBatch is the most entries sent in one AppendEntries call.
+const maxBatch = 512
+
+var (
+	// ErrNoLeader is returned by writes while the cluster has no leader.
+	ErrNoLeader = fmt.Errorf("replication: no leader: %w", store.ErrUnavailable)
+	// errLostLeadership is returned by writes whose entry was replaced by
+	// a new leader before being committed.
+	errLostLeadership = fmt.Errorf("replication: leadership changed before the write was committed: %w", store.ErrUnavailable)
+	errStopped        = fmt.Errorf("replication: node stopped: %w", store.ErrUnavailable)
+)
+
+// Observer is told about every mutation applied to the store. existed
+// reports whether the record existed before.
+type Observer func(m *pb.Mutation, existed bool)
+
+// Node is a member of a replicated store. It is a store.Store: reads
+// are served from the local copy, writes are replicated.
+type Node struct {
+	cfg     Config
+	backend store.Store
+	wal     *wal
+	peers   map[string]*peer
+
+	mu       sync.Mutex
+	role     role
+	term     uint64
+	votedFor string
+	leaderID string
+	// log holds the entries from index 1; log[0] is a placeholder.
+	log         []*pb.LogEntry
+	commitIndex uint64
+	lastApplied uint64
+	// electionDeadline is when a follower or candidate stands for
+	// election if it has not heard from a leader by then.
+	electionDeadline time.Time
+	// applied is closed and replaced whenever entries are applied.
+	applied chan struct{}
+	// results holds the outcome of applying the entries proposed on this
+	// node, until their proposers collect them.
+	results  map[uint64]error
+	observer Observer
+
+	// replicate wakes the leader's replication loop when there is news
+	// for the followers, and wakeApply the apply loop when entries are
+	// committed.
+	replicate chan struct{}
+	wakeApply chan struct{}
+	stop      chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewNode returns a node replicating backend, which must hold no records
+// the log does not account for, and starts it as a follower.
+func NewNode(cfg Config, backend store.Store) (*Node, error) {
+	if cfg.ID == "" {
+		return nil, errors.New("replication: node ID is required")
+	}
+	if _, ok := cfg.Peers[cfg.ID]; ok {
+		return nil, fmt.Errorf("replication: node %s is listed among its own peers", cfg.ID)
+	}
+	if cfg.ElectionTimeout <= 0 {
+		cfg.ElectionTimeout = 300 * time.Millisecond
+	}
+	if cfg.HeartbeatInterval <= 0 {
+		cfg.HeartbeatInterval = cfg.ElectionTimeout / 5
+	}
+	if cfg.ProposeTimeout <= 0 {
+		cfg.ProposeTimeout = 5 * time.Second
+	}
+	n := &Node{
+		cfg:       cfg,
+		backend:   backend,
+		peers:     make(map[string]*peer),
+		log:       []*pb.LogEntry{{}},
+		applied:   make(chan struct{}),
+		results:   make(map[uint64]error),
+		replicate: make(chan struct{}, 1),
+		wakeApply: make(chan struct{}, 1),
+		stop:      make(chan struct{}),
+	}
+	if cfg.Dir != "" {
+		w, state, entries, err := openWAL(cfg.Dir)
+		if err != nil {
+			return nil, err
+		}
+		n.wal = w
+		n.term, n.votedFor = state.Term, state.VotedFor
+		n.log = append(n.log, entries...)
+	}
+	// Reconnect to a restarted peer before it times out waiting for the
+	// leader, rather than after gRPC's usual backoff.
+	dialOptions := append([]grpc.DialOption{grpc.WithConnectParams(grpc.ConnectParams{
+		Backoff: backoff.Config{
+			BaseDelay:  cfg.HeartbeatInterval,
+			Multiplier: backoff.DefaultConfig.Multiplier,
+			Jitter:     backoff.DefaultConfig.Jitter,
+			MaxDelay:   cfg.ElectionTimeout / 2,
+		},
+		MinConnectTimeout: cfg.ElectionTimeout,
+	})}, cfg.DialOptions...)
+	for id, addr := range cfg.Peers {
+		conn, err := grpc.Dial(addr, dialOptions...)
+		if err != nil {
+			return nil, fmt.Errorf("replication: peer %s: %v", id, err)
+		}
+		n.peers[id] = &peer{id: id, conn: conn, client: pb.NewReplicationClient(conn)}
+	}
+	n.resetElectionDeadline()
+	n.wg.Add(2)
+	go n.run()
+	go n.applyLoop()
+	return n, nil
+}
+
+// Observe makes fn the node's observer. It must be called before the
+// node applies anything it should be told about.
+func (n *Node) Observe(fn Observer) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.observer = fn
+}
+
+// Status describes a node's view of its cluster.
+type Status struct {
+	ID, Role, Leader string
+	Term             uint64
+	CommitIndex      uint64
+	LastApplied      uint64
+}
+
+func (n *Node) Status() Status {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return Status{ID: n.cfg.ID, Role: n.role.String(), Leader: n.leaderID, Term: n.term,
+		CommitIndex: n.commitIndex, LastApplied: n.lastApplied}
+}
+
+// IsLeader reports whether the node currently believes it is the leader.
+func (n *Node) IsLeader() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.role == leader
+}
+
+// AwaitLeadership waits until the node, if it leads, has applied every
+// entry committed before its term, so that its store holds every write
+// made so far. It reports false if the node does not lead, or ctx is done
+// or the node stopped first.
+func (n *Node) AwaitLeadership(ctx context.Context) bool {
+	for {
+		n.mu.Lock()
+		if n.role != leader {
+			n.mu.Unlock()
+			return false
+		}
+		// The leader's first entry is of its own term, so once an entry
+		// of the term is applied, so is every one before it.
+		if n.log[n.lastApplied].Term == n.term {
+			n.mu.Unlock()
+			return true
+		}
+		applied := n.applied
+		n.mu.Unlock()
+
+		select {
+		case <-applied:
+		case <-ctx.Done():
+			return false
+		case <-n.stop:
+			return false
+		}
+	}
+}
+
+// Stop stops the node, leaving its store open.
+func (n *Node) Stop() {
+	select {
+	case <-n.stop:
+		return
+	default:
+	}
+	close(n.stop)
+	n.wg.Wait()
+	for _, p := range n.peers {
+		p.conn.Close()
+	}
+	if n.wal != nil {
+		n.wal.close()
+	}
+}
+
+func (n *Node) resetElectionDeadline() {
+	timeout := n.cfg.ElectionTimeout + time.Duration(rand.Int63n(int64(n.cfg.ElectionTimeout)))
+	n.electionDeadline = time.Now().Add(timeout)
+}
+
+func (n *Node) lastIndex() uint64 { return uint64(len(n.log) - 1) }
+
+func (n *Node) quorum() int { return (len(n.peers)+1)/2 + 1 }
+
+// run stands for election when the leader goes quiet and, while leading,
+// sends heartbeats and new entries to the followers.
+func (n *Node) run() {
+	defer n.wg.Done()
+	tick := time.NewTicker(n.cfg.HeartbeatInterval / 2)
+	defer tick.Stop()
+	var lastBeat time.Time
+	for {
+		news := false
+		select {
+		case <-n.stop:
+			return
+		case <-tick.C:
+		case <-n.replicate:
+			news = true
+		}
+		n.mu.Lock()
+		r := n.role
+		due := r != leader && time.Now().After(n.electionDeadline)
+		n.mu.Unlock()
+		switch {
+		case due:
+			n.startElection()
+		case r == leader && (news || time.Since(lastBeat) >= n.cfg.HeartbeatInterval):
+			lastBeat = time.Now()
+			n.broadcastAppend()
+		}
+	}
+}
+
+// becomeFollower steps down into term. n.mu must be held.
+func (n *Node) becomeFollower(term uint64, leaderID string) {
+	if term > n.term {
+		n.term, n.votedFor = term, ""
+		n.persistState()
+	}
+	if n.role != follower {
+		log.Printf("replication: %s is now a follower in term %d", n.cfg.ID, n.term)
+	}
+	n.role = follower
+	n.leaderID = leaderID
+}
+
+func (n *Node) startElection() {
+	n.mu.Lock()
+	n.role = candidate
+	n.term++
+	n.votedFor = n.cfg.ID
+	n.leaderID = ""
+	n.persistState()
+	n.resetElectionDeadline()
+	term := n.term
+	req := &pb.VoteRequest{Term: term, CandidateId: n.cfg.ID,
+		LastLogIndex: n.lastIndex(), LastLogTerm: n.log[n.lastIndex()].Term}
+	n.mu.Unlock()
+
+	votes := 1
+	if votes >= n.quorum() {
+		n.winElection(term)
+		return
+	}
+	var mu sync.Mutex
+	for _, p := range n.peers {
+		go func(p *peer) {
+			ctx, cancel := context.WithTimeout(context.Background(), n.cfg.ElectionTimeout)
+			defer cancel()
+			resp, err := p.client.RequestVote(ctx, req)
+			if err != nil {
+				return
+			}
+			n.mu.Lock()
+			if resp.Term > n.term {
+				n.becomeFollower(resp.Term, "")
+				n.mu.Unlock()
+				return
+			}
+			n.mu.Unlock()
+			if !resp.Granted {
+				return
+			}
+			mu.Lock()
+			votes++
+			won := votes == n.quorum()
+			mu.Unlock()
+			if won {
+				n.winElection(term)
+			}
+		}(p)
+	}
+}
+
+// winElection makes the node leader of term, unless the term has moved
+// on since the election started.
+func (n *Node) winElection(term uint64) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.role != candidate || n.term != term {
+		return
+	}
+	n.role = leader
+	n.leaderID = n.cfg.ID
+	for _, p := range n.peers {
+		p.nextIndex = n.lastIndex() + 1
+		p.matchIndex = 0
+	}
+	log.Printf("replication: %s is the leader of term %d", n.cfg.ID, term)
+	// Entries of earlier terms only commit along with one of the
+	// current term.
+	n.appendEntries(&pb.LogEntry{Term: term})
+	n.advanceCommit()
+}
+
+// appendEntries appends entries to the log and persists them. n.mu must
+// be held.
+func (n *Node) appendEntries(entries ...*pb.LogEntry) {
+	n.log = append(n.log, entries...)
+	if n.wal != nil {
+		if err := n.wal.append(entries); err != nil {
+			log.Fatalf("replication: failed to write log: %v", err)
+		}
+	}
+	select {
+	case n.replicate <- struct{}{}:
+	default:
+	}
+}
+
+// truncate drops the entries from index on. n.mu must be held.
+func (n *Node) truncate(index uint64) {
+	n.log = n.log[:index]
+	if n.wal != nil {
+		if err := n.wal.rewrite(n.log[1:]); err != nil {
+			log.Fatalf("replication: failed to write log: %v", err)
+		}
+	}
+}
+
+// persistState saves the term and vote. n.mu must be held.
+func (n *Node) persistState() {
+	if n.wal == nil {
+		return
+	}
+	if err := n.wal.saveState(walState{Term: n.term, VotedFor: n.votedFor}); err != nil {
+		log.Fatalf("replication: failed to save state: %v", err)
+	}
+}
+
+// advanceCommit commits the entries of the current term a majority of the
+// nodes holds. n.mu must be held.
+func (n *Node) advanceCommit() {
+	matches := []uint64{n.lastIndex()}
+	for _, p := range n.peers {
+		matches = append(matches, p.matchIndex)
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i] > matches[j] })
+	committed := matches[n.quorum()-1]
+	if committed > n.commitIndex && n.log[committed].Term == n.term {
+		n.commitIndex = committed
+		n.signalApply()
+	}
+}
+
+// signalApply wakes applyLoop and, on the leader, sends the new commit
+// index to the followers. n.mu must be held.
+func (n *Node) signalApply() {
+	for _, ch := range []chan struct{}{n.wakeApply, n.replicate} {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,325 @@
+package replication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"testing"
+	"time"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"github.com/marcoc22/tutorial3/store"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const testElectionTimeout = 100 * time.Millisecond
+
+// testCluster runs nodes in process, serving Replication on loopback.
+type testCluster struct {
+	t       *testing.T
+	addrs   map[string]string
+	dirs    map[string]string
+	nodes   map[string]*Node
+	servers map[string]*grpc.Server
+}
+
+// newCluster starts n nodes, keeping their logs in directories of their
+// own if persist is set.
+func newCluster(t *testing.T, n int, persist bool) *testCluster {
+	t.Helper()
+	c := &testCluster{
+		t:       t,
+		addrs:   make(map[string]string),
+		dirs:    make(map[string]string),
+		nodes:   make(map[string]*Node),
+		servers: make(map[string]*grpc.Server),
+	}
+	listeners := make(map[string]net.Listener)
+	for i := 1; i <= n; i++ {
+		id := fmt.Sprintf("n%d", i)
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		listeners[id] = lis
+		c.addrs[id] = lis.Addr().String()
+		if persist {
+			c.dirs[id] = t.TempDir()
+		}
+	}
+	for id, lis := range listeners {
+		c.start(id, lis)
+	}
+	t.Cleanup(func() {
+		for id := range c.nodes {
+			c.stop(id)
+		}
+	})
+	return c
+}
+
+// start runs node id on lis, with an empty store.
+func (c *testCluster) start(id string, lis net.Listener) {
+	c.t.Helper()
+	peers := make(map[string]string)
+	for other, addr := range c.addrs {
+		if other != id {
+			peers[other] = addr
+		}
+	}
+	node, err := NewNode(Config{
+		ID:              id,
+		Peers:           peers,
+		Dir:             c.dirs[id],
+		DialOptions:     []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())},
+		ElectionTimeout: testElectionTimeout,
+	}, store.NewMemory())
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	s := grpc.NewServer()
+	pb.RegisterReplicationServer(s, node)
+	go s.Serve(lis)
+	c.nodes[id], c.servers[id] = node, s
+}
+
+// stop stops node id and its server.
+func (c *testCluster) stop(id string) {
+	c.servers[id].Stop()
+	c.nodes[id].Stop()
+	delete(c.nodes, id)
+	delete(c.servers, id)
+}
+
+// restart starts node id again, on its old address.
+func (c *testCluster) restart(id string) {
+	c.t.Helper()
+	lis, err := net.Listen("tcp", c.addrs[id])
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	c.start(id, lis)
+}
+
+// waitLeader waits until the running nodes agree on a leader of a term
+// after term, and returns it.
+func (c *testCluster) waitLeader(term uint64) (string, uint64) {
+	c.t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		var leaders []string
+		agreed := true
+		for _, node := range c.nodes {
+			if st := node.Status(); st.Role == leader.String() {
+				leaders = append(leaders, st.ID)
+			}
+		}
+		if len(leaders) == 1 {
+			want := c.nodes[leaders[0]].Status()
+			for _, node := range c.nodes {
+				if st := node.Status(); st.Leader != want.ID || st.Term != want.Term {
+					agreed = false
+				}
+			}
+			if agreed && want.Term > term {
+				return want.ID, want.Term
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	c.t.Fatalf("no leader agreed on after term %d: %v", term, c.statuses())
+	return "", 0
+}
+
+func (c *testCluster) statuses() []Status {
+	var statuses []Status
+	for _, node := range c.nodes {
+		statuses = append(statuses, node.Status())
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].ID < statuses[j].ID })
+	return statuses
+}
+
+// follower returns a running node other than leaderID.
+func (c *testCluster) follower(leaderID string) *Node {
+	ids := make([]string, 0, len(c.nodes))
+	for id := range c.nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if id != leaderID {
+			return c.nodes[id]
+		}
+	}
+	c.t.Fatal("no follower running")
+	return nil
+}
+
+// waitRecord waits until every running node holds want under id, or no
+// record if want is nil.
+func (c *testCluster) waitRecord(id string, want []byte) {
+	c.t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		missing := ""
+		for nodeID, node := range c.nodes {
+			got, err := node.Get("books", id)
+			if want == nil && !errors.Is(err, store.ErrNotFound) || want != nil && string(got) != string(want) {
+				missing = nodeID
+				break
+			}
+		}
+		if missing == "" {
+			return
+		}
+		if time.Now().After(deadline) {
+			c.t.Fatalf("node %s does not hold %q under %s", missing, want, id)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestElection(t *testing.T) {
+	c := newCluster(t, 3, false)
+	id, term := c.waitLeader(0)
+	if !c.nodes[id].IsLeader() {
+		t.Errorf("%s was agreed on as leader but does not believe it leads", id)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if !c.nodes[id].AwaitLeadership(ctx) {
+		t.Errorf("AwaitLeadership on leader %s = false", id)
+	}
+	if f := c.follower(id); f.AwaitLeadership(ctx) {
+		t.Errorf("AwaitLeadership on follower %s = true", f.cfg.ID)
+	}
+	// The leader keeps its term while it is heard from.
+	time.Sleep(5 * testElectionTimeout)
+	if again, againTerm := c.waitLeader(0); again != id || againTerm != term {
+		t.Errorf("leader changed from %s in term %d to %s in term %d without failures", id, term, again, againTerm)
+	}
+}
+
+func TestFollowerWrite(t *testing.T) {
+	c := newCluster(t, 3, false)
+	leaderID, _ := c.waitLeader(0)
+	f := c.follower(leaderID)
+
+	if err := f.Put("books", "1", []byte("Dune")); err != nil {
+		t.Fatalf("Put through follower %s: %v", f.cfg.ID, err)
+	}
+	// The writer reads its write from the node it wrote through.
+	if got, err := f.Get("books", "1"); err != nil || string(got) != "Dune" {
+		t.Errorf("Get from the follower after its Put = %q, %v", got, err)
+	}
+	c.waitRecord("1", []byte("Dune"))
+
+	if err := f.Delete("books", "1"); err != nil {
+		t.Fatalf("Delete through follower: %v", err)
+	}
+	c.waitRecord("1", nil)
+	if err := f.Delete("books", "1"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Delete of a missing record through a follower = %v, want store.ErrNotFound", err)
+	}
+}
+
+func TestFailover(t *testing.T) {
+	c := newCluster(t, 3, false)
+	oldLeader, oldTerm := c.waitLeader(0)
+	if err := c.nodes[oldLeader].Put("books", "1", []byte("before")); err != nil {
+		t.Fatal(err)
+	}
+	c.waitRecord("1", []byte("before"))
+
+	c.stop(oldLeader)
+	newLeader, _ := c.waitLeader(oldTerm)
+	if newLeader == oldLeader {
+		t.Fatalf("stopped node %s still leads", oldLeader)
+	}
+	// The remaining two are a majority, so writes go on.
+	if err := c.follower(newLeader).Put("books", "2", []byte("after")); err != nil {
+		t.Fatalf("Put after failover: %v", err)
+	}
+	c.waitRecord("1", []byte("before"))
+	c.waitRecord("2", []byte("after"))
+
+	// The old leader rejoins with an empty store and is sent the log.
+	c.restart(oldLeader)
+	c.waitRecord("1", []byte("before"))
+	c.waitRecord("2", []byte("after"))
+	if c.nodes[oldLeader].IsLeader() && c.nodes[newLeader].IsLeader() {
+		t.Error("two leaders after the old leader rejoined")
+	}
+}
+
+func TestWriteWithoutQuorum(t *testing.T) {
+	c := newCluster(t, 3, false)
+	leaderID, _ := c.waitLeader(0)
+	for id := range c.nodes {
+		if id != leaderID {
+			c.stop(id)
+		}
+	}
+	node := c.nodes[leaderID]
+	node.cfg.ProposeTimeout = 5 * testElectionTimeout
+	if err := node.Put("books", "1", []byte("alone")); !errors.Is(err, store.ErrUnavailable) {
+		t.Errorf("Put without a majority = %v, want store.ErrUnavailable", err)
+	}
+}
+
+// TestRecoveryFromDir restarts a whole cluster with empty stores and
+// checks that the nodes rebuild them from the logs kept in Dir.
+func TestRecoveryFromDir(t *testing.T) {
+	c := newCluster(t, 3, true)
+	leaderID, _ := c.waitLeader(0)
+	for i := 1; i <= 5; i++ {
+		id := fmt.Sprint(i)
+		if err := c.follower(leaderID).Put("books", id, []byte("book "+id)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := c.nodes[leaderID].Delete("books", "5"); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 4; i++ {
+		c.waitRecord(fmt.Sprint(i), []byte(fmt.Sprint("book ", i)))
+	}
+	var lastTerm, lastIndex uint64
+	for _, st := range c.statuses() {
+		lastTerm = max(lastTerm, st.Term)
+		lastIndex = max(lastIndex, st.CommitIndex)
+	}
+
+	ids := make([]string, 0, len(c.nodes))
+	for id := range c.nodes {
+		ids = append(ids, id)
+	}
+	for _, id := range ids {
+		c.stop(id)
+	}
+	for _, id := range ids {
+		c.restart(id)
+		// The log and term are read back before the node hears from
+		// any other.
+		node := c.nodes[id]
+		node.mu.Lock()
+		logged, term := node.lastIndex(), node.term
+		node.mu.Unlock()
+		if logged < lastIndex || term < lastTerm {
+			t.Errorf("%s restarted with %d entries in term %d, want %d in term %d", id, logged, term, lastIndex, lastTerm)
+		}
+	}
+
+	_, term := c.waitLeader(lastTerm)
+	if term <= lastTerm {
+		t.Errorf("new leader's term %d does not follow %d", term, lastTerm)
+	}
+	for i := 1; i <= 4; i++ {
+		c.waitRecord(fmt.Sprint(i), []byte(fmt.Sprint("book ", i)))
+	}
+	c.waitRecord("5", nil)
+}
@@ -0,0 +1,100 @@
+package replication
+
+import (
+	"context"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"google.golang.org/grpc"
+)
+
+// peer is another node of the cluster, as seen by this one.
+type peer struct {
+	id     string
+	conn   *grpc.ClientConn
+	client pb.ReplicationClient
+
+	// nextIndex and matchIndex are, while leading, the next entry to send
+	// to the peer and the last entry it is known to hold. inflight is set
+	// while an AppendEntries call to it is in progress. They are guarded
+	// by Node.mu.
+	nextIndex  uint64
+	matchIndex uint64
+	inflight   bool
+}
+
+// broadcastAppend sends each follower the entries it lacks, or a
+// heartbeat.
+func (n *Node) broadcastAppend() {
+	for _, p := range n.peers {
+		go n.sendAppend(p)
+	}
+}
+
+func (n *Node) sendAppend(p *peer) {
+	n.mu.Lock()
+	if n.role != leader || p.inflight {
+		n.mu.Unlock()
+		return
+	}
+	p.inflight = true
+	if p.nextIndex < 1 {
+		p.nextIndex = 1
+	}
+	if p.nextIndex > n.lastIndex()+1 {
+		p.nextIndex = n.lastIndex() + 1
+	}
+	prev := p.nextIndex - 1
+	end := n.lastIndex()
+	if end > prev+maxBatch {
+		end = prev + maxBatch
+	}
+	term := n.term
+	req := &pb.AppendRequest{
+		Term:         term,
+		LeaderId:     n.cfg.ID,
+		PrevLogIndex: prev,
+		PrevLogTerm:  n.log[prev].Term,
+		Entries:      append([]*pb.LogEntry(nil), n.log[prev+1:end+1]...),
+		LeaderCommit: n.commitIndex,
+	}
+	n.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), n.cfg.ElectionTimeout)
+	resp, err := p.client.AppendEntries(ctx, req)
+	cancel()
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	p.inflight = false
+	if err != nil {
+		return
+	}
+	if resp.Term > n.term {
+		n.becomeFollower(resp.Term, "")
+		n.resetElectionDeadline()
+		return
+	}
+	if n.role != leader || n.term != term {
+		return
+	}
+	if resp.Success {
+		if resp.MatchIndex > p.matchIndex {
+			p.matchIndex = resp.MatchIndex
+		}
+		p.nextIndex = p.matchIndex + 1
+		n.advanceCommit()
+	} else if resp.ConflictIndex > 0 && resp.ConflictIndex < p.nextIndex {
+		p.nextIndex = resp.ConflictIndex
+	} else if p.nextIndex > 1 {
+		p.nextIndex--
+	}
+	if p.nextIndex <= n.lastIndex() || req.LeaderCommit < n.commitIndex {
+		// More to send, or entries committed while the call was in
+		// flight, which the follower should apply without waiting for
+		// the next heartbeat.
+		select {
+		case n.replicate <- struct{}{}:
+		default:
+		}
+	}
+}
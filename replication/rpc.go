@@ -0,0 +1,106 @@
+package replication
+
+import (
+	"context"
+	"errors"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"github.com/marcoc22/tutorial3/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RequestVote grants the node's vote for the term to the first candidate
+// asking whose log is at least as recent as its own.
+func (n *Node) RequestVote(ctx context.Context, in *pb.VoteRequest) (*pb.VoteResponse, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if in.Term > n.term {
+		n.becomeFollower(in.Term, "")
+	}
+	resp := &pb.VoteResponse{Term: n.term}
+	if in.Term < n.term || (n.votedFor != "" && n.votedFor != in.CandidateId) {
+		return resp, nil
+	}
+	lastTerm := n.log[n.lastIndex()].Term
+	if in.LastLogTerm < lastTerm || (in.LastLogTerm == lastTerm && in.LastLogIndex < n.lastIndex()) {
+		return resp, nil
+	}
+	n.votedFor = in.CandidateId
+	n.persistState()
+	n.resetElectionDeadline()
+	resp.Granted = true
+	return resp, nil
+}
+
+// AppendEntries adds the leader's entries to the log, once the entry
+// before them matches, and commits up to the leader's commit index.
+func (n *Node) AppendEntries(ctx context.Context, in *pb.AppendRequest) (*pb.AppendResponse, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if in.Term < n.term {
+		return &pb.AppendResponse{Term: n.term}, nil
+	}
+	if in.Term > n.term || n.role != follower || n.leaderID != in.LeaderId {
+		n.becomeFollower(in.Term, in.LeaderId)
+	}
+	n.resetElectionDeadline()
+	resp := &pb.AppendResponse{Term: n.term}
+
+	if in.PrevLogIndex > n.lastIndex() {
+		resp.ConflictIndex = n.lastIndex() + 1
+		return resp, nil
+	}
+	if t := n.log[in.PrevLogIndex].Term; t != in.PrevLogTerm {
+		// Skip back over the whole conflicting term at once.
+		i := in.PrevLogIndex
+		for i > 1 && n.log[i-1].Term == t {
+			i--
+		}
+		resp.ConflictIndex = i
+		return resp, nil
+	}
+
+	for i, entry := range in.Entries {
+		index := in.PrevLogIndex + 1 + uint64(i)
+		if index <= n.lastIndex() {
+			if n.log[index].Term == entry.Term {
+				continue
+			}
+			n.truncate(index)
+		}
+		n.appendEntries(in.Entries[i:]...)
+		break
+	}
+	resp.Success = true
+	resp.MatchIndex = in.PrevLogIndex + uint64(len(in.Entries))
+	if in.LeaderCommit > n.commitIndex {
+		commit := in.LeaderCommit
+		if commit > resp.MatchIndex {
+			commit = resp.MatchIndex
+		}
+		if commit > n.commitIndex {
+			n.commitIndex = commit
+			n.signalApply()
+		}
+	}
+	return resp, nil
+}
+
+// Propose logs a mutation forwarded by a follower, and answers once it is
+// applied.
+func (n *Node) Propose(ctx context.Context, in *pb.ProposeRequest) (*pb.ProposeResponse, error) {
+	if in.Mutation == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Missing mutation.")
+	}
+	index, term, err := n.proposeLocal(ctx, in.Mutation)
+	switch {
+	case errors.Is(err, errNotLeader):
+		return nil, status.Errorf(codes.FailedPrecondition, "Node %s is not the leader.", n.cfg.ID)
+	case err == store.ErrNotFound:
+		return &pb.ProposeResponse{Index: index, Term: term, NotFound: true}, nil
+	case err != nil:
+		return nil, status.Errorf(codes.Unavailable, "%v", err)
+	}
+	return &pb.ProposeResponse{Index: index, Term: term}, nil
+}
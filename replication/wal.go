@@ -0,0 +1,157 @@
+package replication
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"google.golang.org/protobuf/encoding/protodelim"
+)
+
+// walState is what a node must remember besides its log.
+type walState struct {
+	Term     uint64 `json:"term"`
+	VotedFor string `json:"voted_for"`
+}
+
+// wal keeps a node's state and log in a directory: the state in a JSON
+// file replaced on every change, the log in a file of length-delimited
+// entries that is appended to, and only rewritten when a new leader
+// replaces entries.
+type wal struct {
+	dir string
+	log *os.File
+}
+
+func openWAL(dir string) (*wal, walState, []*pb.LogEntry, error) {
+	var state walState
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, state, nil, err
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, "state.json"))
+	if err == nil {
+		err = json.Unmarshal(data, &state)
+	} else if os.IsNotExist(err) {
+		err = nil
+	}
+	if err != nil {
+		return nil, state, nil, err
+	}
+
+	w := &wal{dir: dir}
+	entries, complete, err := readEntries(filepath.Join(dir, "log"))
+	if err != nil {
+		return nil, state, nil, err
+	}
+	if !complete {
+		// A crash cut the last entry short; it was never acknowledged.
+		if err := w.rewrite(entries); err != nil {
+			return nil, state, nil, err
+		}
+		return w, state, entries, nil
+	}
+	if w.log, err = os.OpenFile(filepath.Join(dir, "log"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644); err != nil {
+		return nil, state, nil, err
+	}
+	return w, state, entries, nil
+}
+
+// readEntries reads the log at path, reporting whether it ended cleanly.
+func readEntries(path string) ([]*pb.LogEntry, bool, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, true, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+	var entries []*pb.LogEntry
+	for {
+		entry := &pb.LogEntry{}
+		err := protodelim.UnmarshalFrom(r, entry)
+		if err == io.EOF {
+			return entries, true, nil
+		}
+		if err != nil {
+			return entries, false, nil
+		}
+		entries = append(entries, entry)
+	}
+}
+
+func (w *wal) append(entries []*pb.LogEntry) error {
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		if _, err := protodelim.MarshalTo(&buf, entry); err != nil {
+			return err
+		}
+	}
+	if _, err := w.log.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	return w.log.Sync()
+}
+
+// rewrite replaces the log with entries.
+func (w *wal) rewrite(entries []*pb.LogEntry) error {
+	path := filepath.Join(w.dir, "log")
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		if _, err := protodelim.MarshalTo(&buf, entry); err != nil {
+			return err
+		}
+	}
+	if err := writeFileSync(path, buf.Bytes()); err != nil {
+		return err
+	}
+	if w.log != nil {
+		w.log.Close()
+	}
+	var err error
+	w.log, err = os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	return err
+}
+
+func (w *wal) saveState(state walState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return writeFileSync(filepath.Join(w.dir, "state.json"), data)
+}
+
+func (w *wal) close() error {
+	if w.log == nil {
+		return errors.New("replication: log not open")
+	}
+	return w.log.Close()
+}
+
+// writeFileSync replaces the file at path with data, durably.
+func writeFileSync(path string, data []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,59 @@
+package main
+
+import "testing"
+
+func TestWriteMethod(t *testing.T) {
+	tests := []struct {
+		method string
+		want   bool
+	}{
+		// Unary handlers see capitalised names, streams lower camel case.
+		{"/booksapp.BookInfo/AddBook", true},
+		{"/booksapp.BookInfo/importBooks", true},
+		{"/booksapp.BookInfo/LinkEntities", true},
+		{"/booksapp.BookInfo/DeleteBook", true},
+		{"/booksapp.BookInfo/GetBook", false},
+		{"/booksapp.BookInfo/listBooks", false},
+		{"/booksapp.BookInfo/watchBooks", false},
+		{"/booksapp.BookInfo/FormatCitation", false},
+		{"/booksapp.Circulation/Checkout", true},
+		{"/booksapp.Circulation/placeHold", true},
+		{"/booksapp.Circulation/Redeliver", true},
+		{"/booksapp.Circulation/listOverdue", false},
+		{"/booksapp.Circulation/GetPatron", false},
+		{"/booksapp.Replication/AppendEntries", false},
+		{"/grpc.health.v1.Health/Check", false},
+	}
+	for _, tt := range tests {
+		if got := writeMethod(tt.method); got != tt.want {
+			t.Errorf("writeMethod(%q) = %v, want %v", tt.method, got, tt.want)
+		}
+	}
+}
+
+func TestMessageTypes(t *testing.T) {
+	tests := []struct {
+		method, wantPath, wantIn, wantOut string
+	}{
+		{"/booksapp.BookInfo/AddBook", "/booksapp.BookInfo/addBook", "booksapp.Book", "booksapp.BookID"},
+		{"/booksapp.BookInfo/importBooks", "/booksapp.BookInfo/importBooks", "booksapp.ImportChunk", "booksapp.ImportSummary"},
+		{"/booksapp.BookInfo/UpdateBook", "/booksapp.BookInfo/updateBook", "booksapp.Book", "booksapp.Book"},
+		{"/booksapp.Circulation/ReturnCopy", "/booksapp.Circulation/returnCopy", "booksapp.Barcode", "booksapp.ReturnResponse"},
+	}
+	for _, tt := range tests {
+		path, in, out, err := messageTypes(tt.method)
+		if err != nil {
+			t.Errorf("messageTypes(%q): %v", tt.method, err)
+			continue
+		}
+		if path != tt.wantPath {
+			t.Errorf("messageTypes(%q) path = %q, want %q", tt.method, path, tt.wantPath)
+		}
+		if string(in.Descriptor().FullName()) != tt.wantIn || string(out.Descriptor().FullName()) != tt.wantOut {
+			t.Errorf("messageTypes(%q) = %s, %s, want %s, %s", tt.method, in.Descriptor().FullName(), out.Descriptor().FullName(), tt.wantIn, tt.wantOut)
+		}
+	}
+	if _, _, _, err := messageTypes("/booksapp.BookInfo/NoSuchMethod"); err == nil {
+		t.Error("messageTypes of an unknown method succeeded")
+	}
+}
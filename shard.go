@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"github.com/marcoc22/tutorial3/shard"
+	"github.com/marcoc22/tutorial3/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// shardMethods prefixes the methods a router calls on its shards.
+const shardMethods = "/booksapp.Shard/"
+
+// internalMethod reports whether fullMethod is called by the other
+// servers of a cluster rather than by clients.
+func internalMethod(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, replicationMethods) || strings.HasPrefix(fullMethod, shardMethods)
+}
+
+// collections lists the store collections the server keeps records in,
+// which a router moves between shards when rebalancing.
+var collections = []string{
+	booksCollection, authorsCollection, publishersCollection, worksCollection,
+	copiesCollection, patronsCollection, loansCollection, holdsCollection,
+	deadLettersCollection,
+}
+
+// shardService serves the records of the store to a router, which uses
+// the server as one shard of a sharded catalog.
+type shardService struct {
+	store store.Store
+}
+
+func (s *shardService) GetRecord(ctx context.Context, in *pb.RecordKey) (*pb.Record, error) {
+	value, err := s.store.Get(in.Collection, in.Id)
+	if err != nil {
+		return nil, recordStatus(err, "Record", in.Collection+"/"+in.Id)
+	}
+	return &pb.Record{Collection: in.Collection, Id: in.Id, Value: value}, status.New(codes.OK, "").Err()
+}
+
+func (s *shardService) PutRecord(ctx context.Context, in *pb.Record) (*pb.PutRecordResponse, error) {
+	if in.Collection == "" || in.Id == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Records need a collection and an ID.")
+	}
+	if err := s.store.Put(in.Collection, in.Id, in.Value); err != nil {
+		return nil, recordStatus(err, "Record", in.Collection+"/"+in.Id)
+	}
+	return &pb.PutRecordResponse{}, status.New(codes.OK, "").Err()
+}
+
+func (s *shardService) DeleteRecord(ctx context.Context, in *pb.RecordKey) (*pb.DeleteRecordResponse, error) {
+	if err := s.store.Delete(in.Collection, in.Id); err != nil {
+		return nil, recordStatus(err, "Record", in.Collection+"/"+in.Id)
+	}
+	return &pb.DeleteRecordResponse{}, status.New(codes.OK, "").Err()
+}
+
+func (s *shardService) ScanRecords(in *pb.ScanRecordsRequest, stream pb.Shard_ScanRecordsServer) error {
+	err := s.store.Scan(in.Collection, func(id string, value []byte) error {
+		record := &pb.Record{Collection: in.Collection, Id: id}
+		if !in.KeysOnly {
+			record.Value = value
+		}
+		return stream.Send(record)
+	})
+	if _, ok := status.FromError(err); !ok {
+		return status.Errorf(codes.Internal, "Error while scanning %s: %v", in.Collection, err)
+	}
+	return err
+}
+
+// openShards opens the sharded store c describes, and finishes any
+// rebalancing interrupted by a restart.
+func openShards(c *config) (*shard.Store, error) {
+	opts, err := peerDialOptions(c)
+	if err != nil {
+		return nil, err
+	}
+	shards, err := shard.Open(shard.Config{
+		Shards:      c.Sharding.Shards,
+		DialOptions: opts,
+		Collections: collections,
+	})
+	if err != nil {
+		return nil, err
+	}
+	shards.Rebalance()
+	return shards, nil
+}
+
+// addShards adds the shards of next missing from current to the store,
+// and returns the shards in use. Shards cannot be removed or moved while
+// serving: their records would have to be moved first.
+func addShards(shards *shard.Store, current, next map[string]string) map[string]string {
+	inUse := make(map[string]string, len(current))
+	for id, addr := range current {
+		inUse[id] = addr
+		if next[id] == "" {
+			log.Printf("Ignoring removal of shard %s: shards cannot be removed", id)
+		} else if next[id] != addr {
+			log.Printf("Ignoring change to the address of shard %s until restart", id)
+		}
+	}
+	for id, addr := range next {
+		if _, ok := current[id]; ok {
+			continue
+		}
+		if err := shards.AddShard(id, addr); err != nil {
+			log.Printf("Not adding shard %s: %v", id, err)
+			continue
+		}
+		inUse[id] = addr
+	}
+	return inUse
+}
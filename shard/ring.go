@@ -0,0 +1,76 @@
+package shard
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+)
+
+// pointsPerShard is how many points each shard has on the ring. More
+// points spread the records more evenly.
+const pointsPerShard = 128
+
+// Ring assigns IDs to shards by consistent hashing: every shard owns the
+// arcs of the ring ending at its points, and an ID belongs to the arc its
+// hash falls in. Adding a shard moves only the IDs falling on the arcs it
+// takes over, about 1/n of them. A Ring is immutable.
+type Ring struct {
+	points []uint64
+	owners map[uint64]string
+	shards []string
+}
+
+// NewRing returns the ring of shards.
+func NewRing(shards ...string) *Ring {
+	r := &Ring{owners: make(map[uint64]string)}
+	for _, shard := range shards {
+		r.shards = append(r.shards, shard)
+		for i := 0; i < pointsPerShard; i++ {
+			p := hash(shard + "#" + strconv.Itoa(i))
+			// On a collision, keep the point of the shard sorting first,
+			// so that the ring does not depend on the order of shards.
+			if owner, ok := r.owners[p]; ok && owner < shard {
+				continue
+			} else if !ok {
+				r.points = append(r.points, p)
+			}
+			r.owners[p] = shard
+		}
+	}
+	sort.Slice(r.points, func(i, j int) bool { return r.points[i] < r.points[j] })
+	sort.Strings(r.shards)
+	return r
+}
+
+// Owner returns the shard owning id, or "" if the ring is empty.
+func (r *Ring) Owner(id string) string {
+	if len(r.points) == 0 {
+		return ""
+	}
+	h := hash(id)
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i] >= h })
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.owners[r.points[i]]
+}
+
+// Shards returns the shards of the ring, sorted.
+func (r *Ring) Shards() []string {
+	return append([]string(nil), r.shards...)
+}
+
+// hash is FNV-1a followed by the MurmurHash3 finalizer, which spreads
+// the hashes of similar strings, such as a shard's point names, over the
+// whole ring.
+func hash(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
@@ -0,0 +1,60 @@
+package shard
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRingPlacement(t *testing.T) {
+	if got := NewRing().Owner("1"); got != "" {
+		t.Errorf("owner on an empty ring = %q, want none", got)
+	}
+
+	r := NewRing("s2", "s3", "s1")
+	// The ring does not depend on the order the shards are listed in.
+	other := NewRing("s1", "s2", "s3")
+	counts := make(map[string]int)
+	const ids = 30000
+	for i := 0; i < ids; i++ {
+		id := fmt.Sprint(i)
+		owner := r.Owner(id)
+		if owner2 := other.Owner(id); owner2 != owner {
+			t.Fatalf("owner of %s = %s or %s depending on the order of shards", id, owner, owner2)
+		}
+		counts[owner]++
+	}
+	if len(counts) != 3 {
+		t.Fatalf("IDs owned by %v, want s1, s2 and s3", counts)
+	}
+	// Each shard owns its share of IDs, give or take a quarter.
+	for shard, n := range counts {
+		if n < ids/3*3/4 || n > ids/3*5/4 {
+			t.Errorf("shard %s owns %d of %d IDs, want about a third", shard, n, ids)
+		}
+	}
+	if got := r.Shards(); fmt.Sprint(got) != "[s1 s2 s3]" {
+		t.Errorf("Shards = %v, want [s1 s2 s3]", got)
+	}
+}
+
+func TestRingAddingShardMovesItsShare(t *testing.T) {
+	before := NewRing("s1", "s2", "s3")
+	after := NewRing("s1", "s2", "s3", "s4")
+	const ids = 30000
+	moved := 0
+	for i := 0; i < ids; i++ {
+		id := fmt.Sprint(i)
+		from, to := before.Owner(id), after.Owner(id)
+		if from == to {
+			continue
+		}
+		// IDs only ever move to the new shard.
+		if to != "s4" {
+			t.Fatalf("%s moved from %s to %s, want only moves to s4", id, from, to)
+		}
+		moved++
+	}
+	if moved < ids/4*3/4 || moved > ids/4*5/4 {
+		t.Errorf("adding a fourth shard moved %d of %d IDs, want about a quarter", moved, ids)
+	}
+}
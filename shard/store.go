@@ -0,0 +1,486 @@
+// Package shard partitions a catalog over several BookInfo servers.
+//
+// Store is a store.Store whose records are kept by the servers, called
+// shards, through their Shard service. Each record belongs to the shard a
+// consistent hash Ring assigns its ID to. A server using a Store serves
+// the whole catalog: reads and writes of a record go to its owner, and
+// scans merge the scans of every shard.
+//
+// When a shard is added, the records the new ring assigns to it are moved
+// there in the background while the catalog stays online. Until every
+// record is in place, a record missing from its owner is looked for on
+// the other shards, and moved when found.
+package shard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"github.com/marcoc22/tutorial3/store"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Config describes the shards of a Store.
+type Config struct {
+	// Shards maps shard IDs to the addresses of their servers.
+	Shards map[string]string
+	// DialOptions are used to connect to the shards.
+	DialOptions []grpc.DialOption
+	// Collections are the collections whose records are moved when
+	// rebalancing.
+	Collections []string
+	// CallTimeout bounds each call to a shard but scans. It defaults to
+	// 10 seconds.
+	CallTimeout time.Duration
+}
+
+// retryDelay is how long a failed rebalancing waits before starting over.
+const retryDelay = 5 * time.Second
+
+// lockStripes is the number of locks serializing the writes and moves of
+// records, by ID.
+const lockStripes = 256
+
+// Store is a store.Store partitioned over shards.
+type Store struct {
+	cfg Config
+
+	mu     sync.RWMutex
+	ring   *Ring
+	shards map[string]*shardClient
+	// rebalancing counts the rebalancings in progress.
+	rebalancing int
+
+	// locks keep a record from being written while it is moved.
+	locks [lockStripes]sync.Mutex
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// Open connects to the shards of cfg.
+func Open(cfg Config) (*Store, error) {
+	if len(cfg.Shards) == 0 {
+		return nil, errors.New("shard: no shards")
+	}
+	if cfg.CallTimeout <= 0 {
+		cfg.CallTimeout = 10 * time.Second
+	}
+	s := &Store{cfg: cfg, shards: make(map[string]*shardClient), stop: make(chan struct{})}
+	var ids []string
+	for id, addr := range cfg.Shards {
+		c, err := s.dial(id, addr)
+		if err != nil {
+			s.closeShards()
+			return nil, err
+		}
+		s.shards[id] = c
+		ids = append(ids, id)
+	}
+	s.ring = NewRing(ids...)
+	return s, nil
+}
+
+func (s *Store) dial(id, addr string) (*shardClient, error) {
+	conn, err := grpc.Dial(addr, s.cfg.DialOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("shard %s: %v", id, err)
+	}
+	return &shardClient{id: id, conn: conn, client: pb.NewShardClient(conn), timeout: s.cfg.CallTimeout}, nil
+}
+
+// AddShard adds a shard and moves the records it now owns to it, in the
+// background.
+func (s *Store) AddShard(id, addr string) error {
+	c, err := s.dial(id, addr)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	if _, ok := s.shards[id]; ok {
+		s.mu.Unlock()
+		c.conn.Close()
+		return fmt.Errorf("shard: shard %s already exists", id)
+	}
+	s.shards[id] = c
+	ids := make([]string, 0, len(s.shards))
+	for id := range s.shards {
+		ids = append(ids, id)
+	}
+	s.ring = NewRing(ids...)
+	// Look for records on every shard as soon as the ring changes.
+	s.rebalancing++
+	s.mu.Unlock()
+	log.Printf("shard: added shard %s at %s, rebalancing", id, addr)
+	s.rebalance()
+	return nil
+}
+
+// Rebalance moves every record not kept by its owner to its owner, in
+// the background, retrying until it succeeds. A Store rebalances after
+// a shard is added; rebalancing when it opens finishes a rebalancing a
+// previous Store over the same shards did not.
+func (s *Store) Rebalance() {
+	s.mu.Lock()
+	s.rebalancing++
+	s.mu.Unlock()
+	s.rebalance()
+}
+
+// rebalance runs a rebalancing already counted in s.rebalancing.
+func (s *Store) rebalance() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer func() {
+			s.mu.Lock()
+			s.rebalancing--
+			s.mu.Unlock()
+		}()
+		for {
+			start := time.Now()
+			moved, err := s.sweep()
+			if err == nil {
+				log.Printf("shard: rebalanced in %v, moving %d records", time.Since(start).Round(time.Millisecond), moved)
+				return
+			}
+			if err == errClosed {
+				return
+			}
+			log.Printf("shard: rebalancing failed after moving %d records, retrying in %v: %v", moved, retryDelay, err)
+			select {
+			case <-time.After(retryDelay):
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+var errClosed = errors.New("shard: store closed")
+
+// sweep moves the misplaced records of every shard to their owners.
+func (s *Store) sweep() (int, error) {
+	moved := 0
+	for _, collection := range s.cfg.Collections {
+		for _, c := range s.shardList() {
+			// Collect the misplaced IDs before moving any, as a shard
+			// may not take writes while it is being scanned.
+			var misplaced []string
+			err := c.scan(collection, true, func(id string, _ []byte) error {
+				if s.owner(id) != c {
+					misplaced = append(misplaced, id)
+				}
+				return nil
+			})
+			if err != nil {
+				return moved, err
+			}
+			for _, id := range misplaced {
+				select {
+				case <-s.stop:
+					return moved, errClosed
+				default:
+				}
+				ok, err := s.move(collection, id, c)
+				if err != nil {
+					return moved, fmt.Errorf("moving %s/%s from shard %s: %w", collection, id, c.id, err)
+				}
+				if ok {
+					moved++
+				}
+			}
+		}
+	}
+	return moved, nil
+}
+
+// move moves a record from shard from to its owner, and reports whether
+// it did. A copy the owner already has is newer, as every write goes to
+// the owner, so it is kept.
+func (s *Store) move(collection, id string, from *shardClient) (bool, error) {
+	unlock := s.lock(id)
+	defer unlock()
+	to := s.owner(id)
+	if to == from {
+		return false, nil
+	}
+	value, err := from.get(collection, id)
+	if err == store.ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if _, err := to.get(collection, id); err == store.ErrNotFound {
+		if err := to.put(collection, id, value); err != nil {
+			return false, err
+		}
+	} else if err != nil {
+		return false, err
+	}
+	if err := from.delete(collection, id); err != nil && err != store.ErrNotFound {
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *Store) lock(id string) (unlock func()) {
+	h := fnv.New32a()
+	h.Write([]byte(id))
+	mu := &s.locks[h.Sum32()%lockStripes]
+	mu.Lock()
+	return mu.Unlock
+}
+
+func (s *Store) owner(id string) *shardClient {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.shards[s.ring.Owner(id)]
+}
+
+func (s *Store) isRebalancing() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.rebalancing > 0
+}
+
+// shardList returns the shards in ID order.
+func (s *Store) shardList() []*shardClient {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := make([]*shardClient, 0, len(s.shards))
+	for _, c := range s.shards {
+		list = append(list, c)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].id < list[j].id })
+	return list
+}
+
+func (s *Store) Get(collection, id string) ([]byte, error) {
+	value, err := s.owner(id).get(collection, id)
+	if err != store.ErrNotFound || !s.isRebalancing() {
+		return value, err
+	}
+
+	// The record may not have been moved to its owner yet.
+	unlock := s.lock(id)
+	defer unlock()
+	owner := s.owner(id)
+	if value, err := owner.get(collection, id); err != store.ErrNotFound {
+		return value, err
+	}
+	for _, c := range s.shardList() {
+		if c == owner {
+			continue
+		}
+		value, err := c.get(collection, id)
+		if err == store.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := owner.put(collection, id, value); err != nil {
+			return nil, err
+		}
+		if err := c.delete(collection, id); err != nil && err != store.ErrNotFound {
+			return nil, err
+		}
+		return value, nil
+	}
+	return nil, store.ErrNotFound
+}
+
+func (s *Store) Put(collection, id string, value []byte) error {
+	unlock := s.lock(id)
+	defer unlock()
+	return s.owner(id).put(collection, id, value)
+}
+
+// Delete deletes the record from its owner and, while rebalancing, from
+// the other shards too, so that the record is not moved back.
+func (s *Store) Delete(collection, id string) error {
+	unlock := s.lock(id)
+	defer unlock()
+	owner := s.owner(id)
+	err := owner.delete(collection, id)
+	if (err != nil && err != store.ErrNotFound) || !s.isRebalancing() {
+		return err
+	}
+	for _, c := range s.shardList() {
+		if c == owner {
+			continue
+		}
+		switch cerr := c.delete(collection, id); cerr {
+		case nil:
+			err = nil
+		case store.ErrNotFound:
+		default:
+			return cerr
+		}
+	}
+	return err
+}
+
+// Scan merges the scans of every shard. A record found on several shards
+// while being moved is passed to fn once, as kept by its owner.
+func (s *Store) Scan(collection string, fn func(id string, value []byte) error) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	type head struct {
+		shard  *shardClient
+		stream pb.Shard_ScanRecordsClient
+		record *pb.Record
+	}
+	var heads []*head
+	for _, c := range s.shardList() {
+		stream, err := c.client.ScanRecords(ctx, &pb.ScanRecordsRequest{Collection: collection})
+		if err != nil {
+			return c.err(err)
+		}
+		heads = append(heads, &head{shard: c, stream: stream})
+	}
+	next := func(h *head) error {
+		record, err := h.stream.Recv()
+		if err == io.EOF {
+			h.record = nil
+			return nil
+		}
+		if err != nil {
+			return h.shard.err(err)
+		}
+		h.record = record
+		return nil
+	}
+	for _, h := range heads {
+		if err := next(h); err != nil {
+			return err
+		}
+	}
+	for {
+		id := ""
+		found := false
+		for _, h := range heads {
+			if h.record != nil && (!found || h.record.Id < id) {
+				id, found = h.record.Id, true
+			}
+		}
+		if !found {
+			return nil
+		}
+		owner := s.owner(id)
+		var value []byte
+		picked := false
+		for _, h := range heads {
+			if h.record == nil || h.record.Id != id {
+				continue
+			}
+			if !picked || h.shard == owner {
+				value, picked = h.record.Value, true
+			}
+			if err := next(h); err != nil {
+				return err
+			}
+		}
+		if err := fn(id, value); err != nil {
+			return err
+		}
+	}
+}
+
+// Flush does nothing: the shards flush their own stores.
+func (s *Store) Flush() error { return nil }
+
+// Close stops rebalancing and closes the connections to the shards.
+func (s *Store) Close() error {
+	close(s.stop)
+	s.wg.Wait()
+	s.closeShards()
+	return nil
+}
+
+func (s *Store) closeShards() {
+	for _, c := range s.shards {
+		c.conn.Close()
+	}
+}
+
+// shardClient calls a shard, converting its errors to store errors.
+type shardClient struct {
+	id      string
+	conn    *grpc.ClientConn
+	client  pb.ShardClient
+	timeout time.Duration
+}
+
+func (c *shardClient) get(collection, id string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	record, err := c.client.GetRecord(ctx, &pb.RecordKey{Collection: collection, Id: id})
+	if err != nil {
+		return nil, c.err(err)
+	}
+	return record.Value, nil
+}
+
+func (c *shardClient) put(collection, id string, value []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	_, err := c.client.PutRecord(ctx, &pb.Record{Collection: collection, Id: id, Value: value})
+	return c.err(err)
+}
+
+func (c *shardClient) delete(collection, id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	_, err := c.client.DeleteRecord(ctx, &pb.RecordKey{Collection: collection, Id: id})
+	return c.err(err)
+}
+
+func (c *shardClient) scan(collection string, keysOnly bool, fn func(id string, value []byte) error) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := c.client.ScanRecords(ctx, &pb.ScanRecordsRequest{Collection: collection, KeysOnly: keysOnly})
+	if err != nil {
+		return c.err(err)
+	}
+	for {
+		record, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return c.err(err)
+		}
+		if err := fn(record.Id, record.Value); err != nil {
+			return err
+		}
+	}
+}
+
+// err converts an error of a call to the shard: NotFound to
+// store.ErrNotFound, and the codes worth retrying to store.ErrUnavailable.
+func (c *shardClient) err(err error) error {
+	if err == nil {
+		return nil
+	}
+	st := status.Convert(err)
+	switch st.Code() {
+	case codes.NotFound:
+		return store.ErrNotFound
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted:
+		return fmt.Errorf("shard %s: %s: %w", c.id, st.Message(), store.ErrUnavailable)
+	}
+	return fmt.Errorf("shard %s: %s", c.id, st.Message())
+}
@@ -0,0 +1,234 @@
+package shard
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"github.com/marcoc22/tutorial3/store"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// testShard serves the records of a memory store the way a BookInfo
+// server configured as a shard does.
+type testShard struct {
+	pb.UnimplementedShardServer
+	records *store.Memory
+}
+
+func (s *testShard) GetRecord(ctx context.Context, in *pb.RecordKey) (*pb.Record, error) {
+	value, err := s.records.Get(in.Collection, in.Id)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return &pb.Record{Collection: in.Collection, Id: in.Id, Value: value}, nil
+}
+
+func (s *testShard) PutRecord(ctx context.Context, in *pb.Record) (*pb.PutRecordResponse, error) {
+	return &pb.PutRecordResponse{}, s.records.Put(in.Collection, in.Id, in.Value)
+}
+
+func (s *testShard) DeleteRecord(ctx context.Context, in *pb.RecordKey) (*pb.DeleteRecordResponse, error) {
+	if err := s.records.Delete(in.Collection, in.Id); err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return &pb.DeleteRecordResponse{}, nil
+}
+
+func (s *testShard) ScanRecords(in *pb.ScanRecordsRequest, stream pb.Shard_ScanRecordsServer) error {
+	return s.records.Scan(in.Collection, func(id string, value []byte) error {
+		record := &pb.Record{Collection: in.Collection, Id: id}
+		if !in.KeysOnly {
+			record.Value = value
+		}
+		return stream.Send(record)
+	})
+}
+
+// startShards serves a memory store as a shard on loopback for each of
+// ids, and returns the stores and the addresses of the shards.
+func startShards(t *testing.T, ids ...string) (map[string]*store.Memory, map[string]string) {
+	t.Helper()
+	records := make(map[string]*store.Memory)
+	addrs := make(map[string]string)
+	for _, id := range ids {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := grpc.NewServer()
+		records[id] = store.NewMemory()
+		pb.RegisterShardServer(s, &testShard{records: records[id]})
+		go s.Serve(lis)
+		t.Cleanup(s.Stop)
+		addrs[id] = lis.Addr().String()
+	}
+	return records, addrs
+}
+
+func openStore(t *testing.T, shards map[string]string) *Store {
+	t.Helper()
+	s, err := Open(Config{
+		Shards:      shards,
+		DialOptions: []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())},
+		Collections: []string{"books"},
+		CallTimeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// waitRebalanced waits for the rebalancings of s to finish.
+func waitRebalanced(t *testing.T, s *Store) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for s.isRebalancing() {
+		if time.Now().After(deadline) {
+			t.Fatal("still rebalancing after 10s")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// checkPlacement checks that every record of records is kept by its owner
+// on ring, and by no other shard.
+func checkPlacement(t *testing.T, records map[string]*store.Memory, ring *Ring, want int) {
+	t.Helper()
+	total := 0
+	for shard, m := range records {
+		m.Scan("books", func(id string, value []byte) error {
+			total++
+			if owner := ring.Owner(id); owner != shard {
+				t.Errorf("record %s is on shard %s, want %s", id, shard, owner)
+			}
+			return nil
+		})
+	}
+	if total != want {
+		t.Errorf("shards keep %d records, want %d", total, want)
+	}
+}
+
+func TestStoreRoutesToOwners(t *testing.T) {
+	records, addrs := startShards(t, "s1", "s2", "s3")
+	s := openStore(t, addrs)
+	for i := 0; i < 60; i++ {
+		if err := s.Put("books", fmt.Sprint(i), []byte(fmt.Sprint("book ", i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkPlacement(t, records, NewRing("s1", "s2", "s3"), 60)
+
+	value, err := s.Get("books", "7")
+	if err != nil || string(value) != "book 7" {
+		t.Errorf("Get(7) = %q, %v, want book 7", value, err)
+	}
+	if err := s.Delete("books", "7"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Get("books", "7"); err != store.ErrNotFound {
+		t.Errorf("Get after Delete = %v, want ErrNotFound", err)
+	}
+	if err := s.Delete("books", "7"); err != store.ErrNotFound {
+		t.Errorf("second Delete = %v, want ErrNotFound", err)
+	}
+}
+
+func TestStoreScanMerges(t *testing.T) {
+	records, addrs := startShards(t, "s1", "s2")
+	ring := NewRing("s1", "s2")
+	var ids []string
+	for i := 0; i < 20; i++ {
+		id := fmt.Sprintf("%02d", i)
+		ids = append(ids, id)
+		records[ring.Owner(id)].Put("books", id, []byte("owner's "+id))
+	}
+	// A record left behind by an interrupted move is on two shards: the
+	// scan passes the owner's copy, once.
+	stale := "05"
+	for shard, m := range records {
+		if shard != ring.Owner(stale) {
+			m.Put("books", stale, []byte("stale"))
+		}
+	}
+	s := openStore(t, addrs)
+
+	var got []string
+	err := s.Scan("books", func(id string, value []byte) error {
+		if string(value) != "owner's "+id {
+			t.Errorf("record %s = %q, want the owner's copy", id, value)
+		}
+		got = append(got, id)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(got) != fmt.Sprint(ids) {
+		t.Errorf("scanned %v, want %v", got, ids)
+	}
+
+	// Scans stop at the first error of fn.
+	stop := fmt.Errorf("stop")
+	n := 0
+	if err := s.Scan("books", func(id string, value []byte) error { n++; return stop }); err != stop || n != 1 {
+		t.Errorf("Scan stopped by fn = %v after %d records, want stop after 1", err, n)
+	}
+}
+
+func TestStoreRebalances(t *testing.T) {
+	records, addrs := startShards(t, "s1", "s2", "s3")
+	s := openStore(t, map[string]string{"s1": addrs["s1"], "s2": addrs["s2"]})
+	const n = 200
+	for i := 0; i < n; i++ {
+		if err := s.Put("books", fmt.Sprint(i), []byte(fmt.Sprint(i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkPlacement(t, records, NewRing("s1", "s2"), n)
+
+	if err := s.AddShard("s3", addrs["s3"]); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddShard("s3", addrs["s3"]); err == nil {
+		t.Error("adding s3 twice succeeded")
+	}
+	// Every record stays readable while it is being moved.
+	for i := 0; i < n; i++ {
+		if value, err := s.Get("books", fmt.Sprint(i)); err != nil || string(value) != fmt.Sprint(i) {
+			t.Fatalf("Get(%d) while rebalancing = %q, %v", i, value, err)
+		}
+	}
+	waitRebalanced(t, s)
+	checkPlacement(t, records, NewRing("s1", "s2", "s3"), n)
+	if records["s3"].Scan("books", func(string, []byte) error { return fmt.Errorf("found") }) == nil {
+		t.Error("no record moved to the new shard s3")
+	}
+}
+
+func TestStoreRebalanceFinishesInterruptedMove(t *testing.T) {
+	records, addrs := startShards(t, "s1", "s2")
+	ring := NewRing("s1", "s2")
+	// Records a previous router left on the wrong shard.
+	for i := 0; i < 20; i++ {
+		id := fmt.Sprint(i)
+		wrong := "s1"
+		if ring.Owner(id) == "s1" {
+			wrong = "s2"
+		}
+		records[wrong].Put("books", id, []byte(id))
+	}
+	s := openStore(t, addrs)
+	s.Rebalance()
+	waitRebalanced(t, s)
+	checkPlacement(t, records, ring, 20)
+}
@@ -0,0 +1,219 @@
+package store
+
+import (
+	"container/list"
+	"sync"
+)
+
+// CacheStats are the counters and sizes of a Cache.
+type CacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Entries   int
+	Bytes     int64
+}
+
+// Cache is a Store keeping the most recently used records of another
+// store in memory. Writes go through to the underlying store before the
+// cache is updated, one at a time for each record so that the cache ends
+// up holding the last of them, and concurrent misses for the same record
+// share a single read of the underlying store. Values are copied in and
+// out, so callers may keep or change them. Scans are not cached.
+type Cache struct {
+	backend    Store
+	maxEntries int
+	maxBytes   int64
+
+	mu      sync.Mutex
+	entries map[cacheKey]*list.Element
+	// order holds the entries, most recently used first.
+	order *list.List
+	bytes int64
+	// loads are the reads of the underlying store in progress.
+	loads map[cacheKey]*cacheLoad
+	// writes are the locks of the records being written.
+	writes map[cacheKey]*cacheWrite
+	stats  CacheStats
+}
+
+type cacheKey struct {
+	collection, id string
+}
+
+type cacheEntry struct {
+	key   cacheKey
+	value []byte
+}
+
+// cacheLoad is a read of the underlying store shared by the Gets missing
+// the same record. done is closed once value and err are set.
+type cacheLoad struct {
+	done  chan struct{}
+	value []byte
+	err   error
+	// stale is set when the record is written during the read, whose
+	// result must then not be cached.
+	stale bool
+}
+
+// cacheWrite serializes the writes of a record. users counts the writes
+// holding or waiting for mu; the last of them removes it from the cache.
+type cacheWrite struct {
+	mu    sync.Mutex
+	users int
+}
+
+// NewCache returns a cache in front of backend holding at most maxEntries
+// records and maxBytes bytes of values. A zero limit is no limit, but one
+// of them must be set.
+func NewCache(backend Store, maxEntries int, maxBytes int64) *Cache {
+	return &Cache{
+		backend:    backend,
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		entries:    make(map[cacheKey]*list.Element),
+		order:      list.New(),
+		loads:      make(map[cacheKey]*cacheLoad),
+		writes:     make(map[cacheKey]*cacheWrite),
+	}
+}
+
+func (c *Cache) Get(collection, id string) ([]byte, error) {
+	key := cacheKey{collection, id}
+	c.mu.Lock()
+	if e, ok := c.entries[key]; ok {
+		c.order.MoveToFront(e)
+		c.stats.Hits++
+		value := e.Value.(*cacheEntry).value
+		c.mu.Unlock()
+		return append([]byte(nil), value...), nil
+	}
+	c.stats.Misses++
+	if load, ok := c.loads[key]; ok {
+		c.mu.Unlock()
+		<-load.done
+		if load.err != nil {
+			return nil, load.err
+		}
+		return append([]byte(nil), load.value...), nil
+	}
+	load := &cacheLoad{done: make(chan struct{})}
+	c.loads[key] = load
+	c.mu.Unlock()
+
+	value, err := c.backend.Get(collection, id)
+	if err == nil {
+		value = append([]byte(nil), value...)
+	}
+	c.mu.Lock()
+	delete(c.loads, key)
+	if err == nil && !load.stale {
+		c.add(key, value)
+	}
+	c.mu.Unlock()
+	load.value, load.err = value, err
+	close(load.done)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(nil), value...), nil
+}
+
+// Put writes value to the underlying store, then caches it. If the write
+// fails the record is dropped from the cache, as its state is unknown.
+func (c *Cache) Put(collection, id string, value []byte) error {
+	key := cacheKey{collection, id}
+	defer c.lockWrites(key)()
+	err := c.backend.Put(collection, id, value)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.remove(key)
+	if err != nil {
+		return err
+	}
+	c.add(key, append([]byte(nil), value...))
+	return nil
+}
+
+func (c *Cache) Delete(collection, id string) error {
+	key := cacheKey{collection, id}
+	defer c.lockWrites(key)()
+	err := c.backend.Delete(collection, id)
+	c.mu.Lock()
+	c.remove(key)
+	c.mu.Unlock()
+	return err
+}
+
+func (c *Cache) Scan(collection string, fn func(id string, value []byte) error) error {
+	return c.backend.Scan(collection, fn)
+}
+
+func (c *Cache) Flush() error { return c.backend.Flush() }
+
+func (c *Cache) Close() error { return c.backend.Close() }
+
+// Stats returns the cache counters and sizes.
+func (c *Cache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Entries = c.order.Len()
+	stats.Bytes = c.bytes
+	return stats
+}
+
+// lockWrites waits for the other writes of key to finish and returns the
+// function ending this one.
+func (c *Cache) lockWrites(key cacheKey) func() {
+	c.mu.Lock()
+	w, ok := c.writes[key]
+	if !ok {
+		w = &cacheWrite{}
+		c.writes[key] = w
+	}
+	w.users++
+	c.mu.Unlock()
+	w.mu.Lock()
+	return func() {
+		w.mu.Unlock()
+		c.mu.Lock()
+		if w.users--; w.users == 0 {
+			delete(c.writes, key)
+		}
+		c.mu.Unlock()
+	}
+}
+
+// add caches value under key, evicting the least recently used records
+// over the limits. Values larger than maxBytes are not cached. c.mu must
+// be held.
+func (c *Cache) add(key cacheKey, value []byte) {
+	if c.maxBytes > 0 && int64(len(value)) > c.maxBytes {
+		return
+	}
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, value: value})
+	c.bytes += int64(len(value))
+	for (c.maxEntries > 0 && c.order.Len() > c.maxEntries) || (c.maxBytes > 0 && c.bytes > c.maxBytes) {
+		c.drop(c.order.Back())
+		c.stats.Evictions++
+	}
+}
+
+// remove drops the record under key, and marks a read of it in progress
+// as stale. c.mu must be held.
+func (c *Cache) remove(key cacheKey) {
+	if load, ok := c.loads[key]; ok {
+		load.stale = true
+	}
+	if e, ok := c.entries[key]; ok {
+		c.drop(e)
+	}
+}
+
+func (c *Cache) drop(e *list.Element) {
+	entry := c.order.Remove(e).(*cacheEntry)
+	delete(c.entries, entry.key)
+	c.bytes -= int64(len(entry.value))
+}
@@ -0,0 +1,254 @@
+package store
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// hookedStore is a Memory store calling afterGet once a record is read
+// and afterPut once one is written, for tests to pause the calls.
+type hookedStore struct {
+	*Memory
+	afterGet func(id string)
+	afterPut func(id string, value []byte)
+}
+
+func (s *hookedStore) Get(collection, id string) ([]byte, error) {
+	value, err := s.Memory.Get(collection, id)
+	if s.afterGet != nil {
+		s.afterGet(id)
+	}
+	return value, err
+}
+
+func (s *hookedStore) Put(collection, id string, value []byte) error {
+	err := s.Memory.Put(collection, id, value)
+	if s.afterPut != nil {
+		s.afterPut(id, value)
+	}
+	return err
+}
+
+func mustGet(t *testing.T, s Store, id string) string {
+	t.Helper()
+	value, err := s.Get("books", id)
+	if err != nil {
+		t.Fatalf("Get(%s): %v", id, err)
+	}
+	return string(value)
+}
+
+func checkStats(t *testing.T, c *Cache, want CacheStats) {
+	t.Helper()
+	if got := c.Stats(); got != want {
+		t.Errorf("stats = %+v, want %+v", got, want)
+	}
+}
+
+func TestCacheHits(t *testing.T) {
+	backend := NewMemory()
+	backend.Put("books", "1", []byte("Dune"))
+	c := NewCache(backend, 10, 0)
+
+	if got := mustGet(t, c, "1"); got != "Dune" {
+		t.Errorf("first Get = %q, want Dune", got)
+	}
+	if got := mustGet(t, c, "1"); got != "Dune" {
+		t.Errorf("second Get = %q, want Dune", got)
+	}
+	checkStats(t, c, CacheStats{Hits: 1, Misses: 1, Entries: 1, Bytes: 4})
+
+	// Writes go through and are cached.
+	if err := c.Put("books", "2", []byte("Emma")); err != nil {
+		t.Fatal(err)
+	}
+	if got := mustGet(t, backend, "2"); got != "Emma" {
+		t.Errorf("backend holds %q, want Emma", got)
+	}
+	if got := mustGet(t, c, "2"); got != "Emma" {
+		t.Errorf("Get after Put = %q, want Emma", got)
+	}
+	if err := c.Delete("books", "1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Get("books", "1"); err != ErrNotFound {
+		t.Errorf("Get after Delete = %v, want ErrNotFound", err)
+	}
+	// Missing records are not cached.
+	if _, err := c.Get("books", "1"); err != ErrNotFound {
+		t.Errorf("second Get after Delete = %v, want ErrNotFound", err)
+	}
+	checkStats(t, c, CacheStats{Hits: 2, Misses: 3, Entries: 1, Bytes: 4})
+}
+
+func TestCacheCopiesValues(t *testing.T) {
+	c := NewCache(NewMemory(), 10, 0)
+	value := []byte("Dune")
+	if err := c.Put("books", "1", value); err != nil {
+		t.Fatal(err)
+	}
+	value[0] = 'R'
+	got, err := c.Get("books", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "Dune" {
+		t.Errorf("Get after changing the value put = %q, want Dune", got)
+	}
+	got[0] = 'T'
+	if got := mustGet(t, c, "1"); got != "Dune" {
+		t.Errorf("Get after changing the value got = %q, want Dune", got)
+	}
+}
+
+func TestCacheEvictsByCount(t *testing.T) {
+	c := NewCache(NewMemory(), 2, 0)
+	c.Put("books", "1", []byte("Dune"))
+	c.Put("books", "2", []byte("Emma"))
+	// Reading 1 makes 2 the least recently used.
+	mustGet(t, c, "1")
+	c.Put("books", "3", []byte("Momo"))
+	checkStats(t, c, CacheStats{Hits: 1, Evictions: 1, Entries: 2, Bytes: 8})
+
+	mustGet(t, c, "1")
+	mustGet(t, c, "3")
+	// 2 is read from the backend again, evicting 1.
+	mustGet(t, c, "2")
+	checkStats(t, c, CacheStats{Hits: 3, Misses: 1, Evictions: 2, Entries: 2, Bytes: 8})
+	mustGet(t, c, "1")
+	checkStats(t, c, CacheStats{Hits: 3, Misses: 2, Evictions: 3, Entries: 2, Bytes: 8})
+}
+
+func TestCacheEvictsByBytes(t *testing.T) {
+	c := NewCache(NewMemory(), 0, 10)
+	c.Put("books", "1", []byte("Dune"))
+	c.Put("books", "2", []byte("Emma"))
+	checkStats(t, c, CacheStats{Entries: 2, Bytes: 8})
+	c.Put("books", "3", []byte("Momo"))
+	checkStats(t, c, CacheStats{Evictions: 1, Entries: 2, Bytes: 8})
+
+	// A value over the limit is stored but not cached, and evicts nothing.
+	c.Put("books", "4", []byte("The Left Hand of Darkness"))
+	checkStats(t, c, CacheStats{Evictions: 1, Entries: 2, Bytes: 8})
+	if got := mustGet(t, c, "4"); got != "The Left Hand of Darkness" {
+		t.Errorf("Get of an uncached value = %q", got)
+	}
+	checkStats(t, c, CacheStats{Misses: 1, Evictions: 1, Entries: 2, Bytes: 8})
+
+	// Replacing a value updates the bytes held.
+	c.Put("books", "3", []byte("Momo 2"))
+	checkStats(t, c, CacheStats{Misses: 1, Evictions: 1, Entries: 2, Bytes: 10})
+}
+
+func TestCacheDropsStaleLoads(t *testing.T) {
+	read := make(chan struct{})
+	resume := make(chan struct{})
+	backend := &hookedStore{Memory: NewMemory()}
+	backend.Put("books", "1", []byte("Dune"))
+	backend.afterGet = func(id string) {
+		close(read)
+		<-resume
+	}
+	c := NewCache(backend, 10, 0)
+
+	got := make(chan string)
+	go func() {
+		value, _ := c.Get("books", "1")
+		got <- string(value)
+	}()
+	// The record changes after the backend read it for the Get.
+	<-read
+	backend.afterGet = nil
+	if err := c.Put("books", "1", []byte("Dune Messiah")); err != nil {
+		t.Fatal(err)
+	}
+	close(resume)
+	if value := <-got; value != "Dune" {
+		t.Errorf("Get racing the Put = %q, want the old Dune", value)
+	}
+	if value := mustGet(t, c, "1"); value != "Dune Messiah" {
+		t.Errorf("Get after the Put = %q, want Dune Messiah", value)
+	}
+}
+
+func TestCacheOrdersWrites(t *testing.T) {
+	written := make(chan struct{})
+	resume := make(chan struct{})
+	backend := &hookedStore{Memory: NewMemory()}
+	backend.afterPut = func(id string, value []byte) {
+		if string(value) == "first" {
+			close(written)
+			<-resume
+		}
+	}
+	c := NewCache(backend, 10, 0)
+
+	done := make(chan struct{})
+	go func() {
+		c.Put("books", "1", []byte("first"))
+		close(done)
+	}()
+	<-written
+	// The second Put must wait for the first to update the cache, or the
+	// cache would end up holding the value the backend no longer has.
+	second := make(chan struct{})
+	go func() {
+		c.Put("books", "1", []byte("second"))
+		close(second)
+	}()
+	select {
+	case <-second:
+		t.Error("second Put of the record finished before the first")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(resume)
+	<-done
+	<-second
+	if got, want := mustGet(t, c, "1"), mustGet(t, backend, "1"); got != want || got != "second" {
+		t.Errorf("cache holds %q and backend %q, want both second", got, want)
+	}
+}
+
+func TestCacheConcurrentWrites(t *testing.T) {
+	backend := NewMemory()
+	c := NewCache(backend, 3, 0)
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				id := fmt.Sprint(i % 5)
+				switch i % 3 {
+				case 0:
+					c.Put("books", id, []byte(fmt.Sprintf("%d-%d", w, i)))
+				case 1:
+					if value, err := c.Get("books", id); err == nil {
+						// Callers may change what they get.
+						for j := range value {
+							value[j] = 0
+						}
+					}
+				case 2:
+					c.Delete("books", id)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	for i := 0; i < 5; i++ {
+		id := fmt.Sprint(i)
+		want, wantErr := backend.Get("books", id)
+		got, err := c.Get("books", id)
+		if err != wantErr || !bytes.Equal(got, want) {
+			t.Errorf("record %s: cache has %q, %v, backend %q, %v", id, got, err, want, wantErr)
+		}
+	}
+	if stats := c.Stats(); stats.Entries > 3 {
+		t.Errorf("cache holds %d entries, over its limit of 3", stats.Entries)
+	}
+}
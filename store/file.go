@@ -0,0 +1,132 @@
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// File is a Store that keeps its records in memory and writes them to a
+// file on Flush. The file is replaced atomically, so a crash loses at most
+// the changes made since the last flush.
+type File struct {
+	*Memory
+	path string
+
+	mu    sync.Mutex
+	dirty bool
+}
+
+// fileRecord is one line of the store file.
+type fileRecord struct {
+	Collection string `json:"collection"`
+	ID         string `json:"id"`
+	Value      []byte `json:"value"`
+}
+
+// OpenFile loads the store kept in path, which is created on the first
+// flush if it does not exist yet.
+func OpenFile(path string) (*File, error) {
+	if path == "" {
+		return nil, fmt.Errorf("store: file backend needs a path")
+	}
+	f := &File{Memory: NewMemory(), path: path}
+	in, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+
+	dec := json.NewDecoder(bufio.NewReader(in))
+	for dec.More() {
+		var r fileRecord
+		if err := dec.Decode(&r); err != nil {
+			return nil, fmt.Errorf("store: corrupt file %s: %v", path, err)
+		}
+		f.Memory.Put(r.Collection, r.ID, r.Value)
+	}
+	return f, nil
+}
+
+func (f *File) Put(collection, id string, value []byte) error {
+	if err := f.Memory.Put(collection, id, value); err != nil {
+		return err
+	}
+	f.markDirty()
+	return nil
+}
+
+func (f *File) Delete(collection, id string) error {
+	if err := f.Memory.Delete(collection, id); err != nil {
+		return err
+	}
+	f.markDirty()
+	return nil
+}
+
+func (f *File) markDirty() {
+	f.mu.Lock()
+	f.dirty = true
+	f.mu.Unlock()
+}
+
+// Flush writes the records to a temporary file next to the store file and
+// renames it into place.
+func (f *File) Flush() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.dirty {
+		return nil
+	}
+	// Clear the flag first so that changes racing with the snapshot below
+	// are written by the next flush.
+	f.dirty = false
+
+	tmp, err := ioutil.TempFile(filepath.Dir(f.path), filepath.Base(f.path)+".tmp")
+	if err != nil {
+		f.dirty = true
+		return err
+	}
+	err = f.write(tmp)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), f.path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		f.dirty = true
+	}
+	return err
+}
+
+func (f *File) write(out *os.File) error {
+	w := bufio.NewWriter(out)
+	enc := json.NewEncoder(w)
+	f.Memory.mu.RLock()
+	for collection, records := range f.Memory.collections {
+		for id, value := range records {
+			if err := enc.Encode(fileRecord{Collection: collection, ID: id, Value: value}); err != nil {
+				f.Memory.mu.RUnlock()
+				return err
+			}
+		}
+	}
+	f.Memory.mu.RUnlock()
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return out.Sync()
+}
+
+func (f *File) Close() error {
+	return f.Flush()
+}
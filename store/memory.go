@@ -0,0 +1,74 @@
+package store
+
+import (
+	"sort"
+	"sync"
+)
+
+// Memory is a Store that only lives as long as the process.
+type Memory struct {
+	mu          sync.RWMutex
+	collections map[string]map[string][]byte
+}
+
+// NewMemory returns an empty in-memory store.
+func NewMemory() *Memory {
+	return &Memory{collections: make(map[string]map[string][]byte)}
+}
+
+func (m *Memory) Get(collection, id string) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	value, ok := m.collections[collection][id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return value, nil
+}
+
+func (m *Memory) Put(collection, id string, value []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	records, ok := m.collections[collection]
+	if !ok {
+		records = make(map[string][]byte)
+		m.collections[collection] = records
+	}
+	records[id] = append([]byte(nil), value...)
+	return nil
+}
+
+func (m *Memory) Delete(collection, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.collections[collection][id]; !ok {
+		return ErrNotFound
+	}
+	delete(m.collections[collection], id)
+	return nil
+}
+
+// Scan works on a snapshot of the collection, so fn may modify the store.
+func (m *Memory) Scan(collection string, fn func(id string, value []byte) error) error {
+	m.mu.RLock()
+	records := m.collections[collection]
+	ids := make([]string, 0, len(records))
+	values := make(map[string][]byte, len(records))
+	for id, value := range records {
+		ids = append(ids, id)
+		values[id] = value
+	}
+	m.mu.RUnlock()
+
+	sort.Strings(ids)
+	for _, id := range ids {
+		if err := fn(id, values[id]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Memory) Flush() error { return nil }
+
+func (m *Memory) Close() error { return nil }
@@ -0,0 +1,67 @@
+// Package store persists the records served by BookInfo.
+package store
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrNotFound is returned when a record does not exist.
+var ErrNotFound = errors.New("store: record not found")
+
+// ErrUnavailable is returned, possibly wrapped, when a store cannot take
+// writes for now, for instance while a replicated store has no leader.
+// Retrying later may succeed.
+var ErrUnavailable = errors.New("store: unavailable")
+
+// Store keeps serialized records grouped in collections and keyed by ID.
+// Implementations are safe for concurrent use.
+type Store interface {
+	// Get returns the record stored under id, or ErrNotFound.
+	Get(collection, id string) ([]byte, error)
+	// Put creates or replaces the record stored under id.
+	Put(collection, id string, value []byte) error
+	// Delete removes the record stored under id, or returns ErrNotFound.
+	Delete(collection, id string) error
+	// Scan calls fn for every record of collection in ID order and stops
+	// at the first error fn returns. Some stores, such as a sharded one,
+	// do not take writes while they are being scanned: a caller writing
+	// what it scans collects the records first.
+	Scan(collection string, fn func(id string, value []byte) error) error
+	// Flush makes every change so far durable.
+	Flush() error
+	// Close flushes the store and releases its resources.
+	Close() error
+}
+
+// Open returns a store for backend, which is either "memory" or "file".
+// The file backend keeps its data in path.
+func Open(backend, path string) (Store, error) {
+	switch backend {
+	case "", "memory":
+		return NewMemory(), nil
+	case "file":
+		return OpenFile(path)
+	}
+	return nil, fmt.Errorf("store: unknown backend %q", backend)
+}
+
+// GetMessage reads the record stored under id into m.
+func GetMessage(s Store, collection, id string, m proto.Message) error {
+	data, err := s.Get(collection, id)
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(data, m)
+}
+
+// PutMessage stores m under id.
+func PutMessage(s Store, collection, id string, m proto.Message) error {
+	data, err := proto.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return s.Put(collection, id, data)
+}
@@ -0,0 +1,67 @@
+// Package tracing configures OpenTelemetry tracing for the BookInfo server
+// and client.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Setup installs a global tracer provider for service that exports spans
+// to dest, which is either "stdout" or a file path prefixed with "file:".
+// Spans are written as one JSON document each, suitable for offline
+// analysis. An empty dest leaves tracing disabled.
+//
+// The returned function flushes pending spans and must be called before
+// the process exits.
+func Setup(service, dest string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{}))
+	if dest == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var out io.Writer = os.Stdout
+	var file *os.File
+	if strings.HasPrefix(dest, "file:") {
+		f, err := os.OpenFile(strings.TrimPrefix(dest, "file:"),
+			os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, err
+		}
+		out, file = f, f
+	} else if dest != "stdout" {
+		return nil, fmt.Errorf("unsupported trace destination %q: want stdout or file:<path>", dest)
+	}
+
+	exporter, err := stdouttrace.New(stdouttrace.WithWriter(out))
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", service))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if file != nil {
+			if cerr := file.Close(); err == nil {
+				err = cerr
+			}
+		}
+		return err
+	}, nil
+}
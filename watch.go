@@ -0,0 +1,126 @@
+package main
+
+import (
+	"log"
+	"sync"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// watchBuffer is how many changes a watcher may fall behind by before
+// its stream is ended.
+const watchBuffer = 256
+
+// changeFeed fans the changes made to the books out to the WatchBooks
+// streams.
+type changeFeed struct {
+	mu       sync.Mutex
+	watchers map[chan *pb.BookChange]bool
+	closed   bool
+}
+
+func newChangeFeed() *changeFeed {
+	return &changeFeed{watchers: make(map[chan *pb.BookChange]bool)}
+}
+
+// subscribe returns a channel receiving every change published from now
+// on. The channel is closed when the watcher falls behind, or the feed is
+// closed.
+func (f *changeFeed) subscribe() chan *pb.BookChange {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ch := make(chan *pb.BookChange, watchBuffer)
+	if f.closed {
+		close(ch)
+		return ch
+	}
+	f.watchers[ch] = true
+	return ch
+}
+
+func (f *changeFeed) unsubscribe(ch chan *pb.BookChange) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.watchers[ch] {
+		delete(f.watchers, ch)
+		close(ch)
+	}
+}
+
+// publish sends change to every watcher without blocking, dropping the
+// watchers whose buffers are full.
+func (f *changeFeed) publish(change *pb.BookChange) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for ch := range f.watchers {
+		select {
+		case ch <- change:
+		default:
+			delete(f.watchers, ch)
+			close(ch)
+		}
+	}
+}
+
+// close ends every watch, so that shutdown does not wait for them.
+func (f *changeFeed) close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+	for ch := range f.watchers {
+		delete(f.watchers, ch)
+		close(ch)
+	}
+}
+
+// publishChange publishes a change made by a handler, unless the store
+// is replicated and the change will be published once applied.
+func (s *server) publishChange(change *pb.BookChange) {
+	if !s.replicated {
+		s.changes.publish(change)
+	}
+}
+
+// publishMutation publishes the change to a book a replicated store has
+// applied. It is a replication.Observer.
+func (s *server) publishMutation(m *pb.Mutation, existed bool) {
+	if m.Collection != booksCollection {
+		return
+	}
+	if m.Delete {
+		s.changes.publish(&pb.BookChange{Type: pb.ChangeType_DELETED, Id: m.Id})
+		return
+	}
+	book := &pb.Book{}
+	if err := proto.Unmarshal(m.Value, book); err != nil {
+		log.Printf("Not publishing change to corrupt Book %s: %v", m.Id, err)
+		return
+	}
+	change := &pb.BookChange{Type: pb.ChangeType_ADDED, Id: m.Id, Book: book}
+	if existed {
+		change.Type = pb.ChangeType_UPDATED
+	}
+	s.changes.publish(change)
+}
+
+func (s *server) WatchBooks(in *pb.WatchBooksRequest, stream pb.BookInfo_WatchBooksServer) error {
+	changes := s.changes.subscribe()
+	defer s.changes.unsubscribe(changes)
+	ctx := stream.Context()
+	for {
+		select {
+		case change, ok := <-changes:
+			if !ok {
+				return status.Errorf(codes.Unavailable, "Watch ended; changes may have been missed.")
+			}
+			if err := stream.Send(change); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		}
+	}
+}
@@ -0,0 +1,266 @@
+// Package webhook delivers events to an HTTP endpoint. Each event is
+// POSTed as JSON, signed with an HMAC of the body so that the receiver
+// can check it came from the server. Failed deliveries are retried with
+// exponential backoff and jitter; events that still cannot be delivered
+// are handed to a dead letter function to be kept and redelivered later.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// The headers of a delivery. SignatureHeader holds "sha256=" and the hex
+// HMAC-SHA256 of the body keyed with the secret. IDHeader is the same for
+// every attempt and redelivery of an event, so receivers can drop
+// duplicates.
+const (
+	SignatureHeader = "X-Signature-256"
+	EventHeader     = "X-Event-Type"
+	IDHeader        = "X-Delivery-ID"
+)
+
+// Event is a notification to deliver.
+type Event struct {
+	ID   string
+	Type string
+	// Body is the JSON document POSTed.
+	Body []byte
+}
+
+// Failure is an event given up on.
+type Failure struct {
+	Event
+	Attempts int
+	Err      error
+}
+
+// Config tunes a Dispatcher. Zero fields take the defaults of
+// DefaultConfig.
+type Config struct {
+	URL    string
+	Secret string
+	// MaxAttempts is the most times an event is sent.
+	MaxAttempts int
+	// InitialBackoff is the longest wait before the first retry; each
+	// retry may wait twice as long as the previous one, up to MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// Timeout bounds each attempt.
+	Timeout time.Duration
+	// Workers is how many events are delivered at once.
+	Workers int
+	// QueueSize is how many events can wait for a worker. Events sent to
+	// a full queue are dead-lettered.
+	QueueSize int
+	Client    *http.Client
+	// DeadLetter is called with every event given up on. It may be
+	// called concurrently.
+	DeadLetter func(Failure)
+}
+
+// DefaultConfig returns the defaults of the Config fields.
+func DefaultConfig() Config {
+	return Config{
+		MaxAttempts:    5,
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+		Timeout:        10 * time.Second,
+		Workers:        4,
+		QueueSize:      256,
+		Client:         http.DefaultClient,
+		DeadLetter:     func(Failure) {},
+	}
+}
+
+// Dispatcher delivers events in the background.
+type Dispatcher struct {
+	config Config
+	queue  chan Event
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	// mu guards closed, so that Send never writes to a closed queue.
+	mu     sync.RWMutex
+	closed bool
+}
+
+// New starts a Dispatcher delivering to c.URL.
+func New(c Config) *Dispatcher {
+	def := DefaultConfig()
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = def.MaxAttempts
+	}
+	if c.InitialBackoff <= 0 {
+		c.InitialBackoff = def.InitialBackoff
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = def.MaxBackoff
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = def.Timeout
+	}
+	if c.Workers <= 0 {
+		c.Workers = def.Workers
+	}
+	if c.QueueSize <= 0 {
+		c.QueueSize = def.QueueSize
+	}
+	if c.Client == nil {
+		c.Client = def.Client
+	}
+	if c.DeadLetter == nil {
+		c.DeadLetter = def.DeadLetter
+	}
+	d := &Dispatcher{config: c, queue: make(chan Event, c.QueueSize)}
+	d.ctx, d.cancel = context.WithCancel(context.Background())
+	for i := 0; i < c.Workers; i++ {
+		d.wg.Add(1)
+		go d.work()
+	}
+	return d
+}
+
+// Send queues e for delivery.
+func (d *Dispatcher) Send(e Event) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.closed {
+		d.config.DeadLetter(Failure{Event: e, Err: errors.New("webhook: dispatcher closed")})
+		return
+	}
+	select {
+	case d.queue <- e:
+	default:
+		d.config.DeadLetter(Failure{Event: e, Err: errors.New("webhook: queue full")})
+	}
+}
+
+// Close stops the deliveries. Events still queued or waiting to be
+// retried are dead-lettered. Closing again does nothing.
+func (d *Dispatcher) Close() {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return
+	}
+	d.closed = true
+	close(d.queue)
+	d.mu.Unlock()
+	d.cancel()
+	d.wg.Wait()
+}
+
+func (d *Dispatcher) work() {
+	defer d.wg.Done()
+	for e := range d.queue {
+		d.deliver(e)
+	}
+}
+
+// deliver sends e until it is accepted, fails for good or runs out of
+// attempts, and dead-letters it unless accepted.
+func (d *Dispatcher) deliver(e Event) {
+	var err error
+	attempts := 0
+	for attempts < d.config.MaxAttempts {
+		if d.ctx.Err() != nil {
+			if err == nil {
+				err = errors.New("webhook: dispatcher closed")
+			}
+			break
+		}
+		attempts++
+		var retryAfter time.Duration
+		var permanent bool
+		retryAfter, permanent, err = d.post(e)
+		if err == nil {
+			return
+		}
+		if permanent || attempts == d.config.MaxAttempts {
+			break
+		}
+		wait := d.backoff(attempts - 1)
+		if retryAfter > wait {
+			wait = retryAfter
+		}
+		select {
+		case <-time.After(wait):
+		case <-d.ctx.Done():
+		}
+	}
+	d.config.DeadLetter(Failure{Event: e, Attempts: attempts, Err: err})
+}
+
+// post makes one attempt at delivering e. It returns how long the
+// receiver asked to wait before retrying, if it did, and whether the
+// failure is one retrying cannot fix.
+func (d *Dispatcher) post(e Event) (retryAfter time.Duration, permanent bool, err error) {
+	ctx, cancel := context.WithTimeout(d.ctx, d.config.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.config.URL, bytes.NewReader(e.Body))
+	if err != nil {
+		return 0, true, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(d.config.Secret, e.Body))
+	req.Header.Set(EventHeader, e.Type)
+	req.Header.Set(IDHeader, e.ID)
+	resp, err := d.config.Client.Do(req)
+	if err != nil {
+		return 0, false, err
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return 0, false, nil
+	}
+	err = fmt.Errorf("webhook: %s answered %s", d.config.URL, resp.Status)
+	if secs, perr := strconv.Atoi(resp.Header.Get("Retry-After")); perr == nil && secs > 0 {
+		retryAfter = time.Duration(secs) * time.Second
+	}
+	switch {
+	case resp.StatusCode == http.StatusRequestTimeout, resp.StatusCode == http.StatusTooManyRequests:
+		return retryAfter, false, err
+	case resp.StatusCode >= 400 && resp.StatusCode < 500:
+		return 0, true, err
+	}
+	return retryAfter, false, err
+}
+
+// backoff returns how long to wait before the retry following retries
+// earlier ones: a random time below the exponential bound ("full
+// jitter").
+func (d *Dispatcher) backoff(retries int) time.Duration {
+	bound := float64(d.config.InitialBackoff) * math.Pow(2, float64(retries))
+	if bound > float64(d.config.MaxBackoff) {
+		bound = float64(d.config.MaxBackoff)
+	}
+	return time.Duration(rand.Int63n(int64(bound) + 1))
+}
+
+// Sign returns the SignatureHeader value of body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is the SignatureHeader value of body,
+// comparing in constant time.
+func Verify(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
@@ -0,0 +1,358 @@
+package webhook
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const secret = "s3cret"
+
+// receiver is an endpoint answering each delivery with the next of its
+// responses, and then with 200 OK.
+type receiver struct {
+	t *testing.T
+
+	mu        sync.Mutex
+	responses []response
+	attempts  []time.Time
+	ids       []string
+	delivered []*http.Request
+	bodies    [][]byte
+	// arrived signals every request as it arrives.
+	arrived chan struct{}
+	// hold, if set, keeps requests waiting until it is closed or the
+	// sender gives up.
+	hold chan struct{}
+}
+
+type response struct {
+	status     int
+	retryAfter string
+}
+
+func newReceiver(t *testing.T, responses ...response) (*receiver, *httptest.Server) {
+	r := &receiver{t: t, responses: responses, arrived: make(chan struct{}, 100)}
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	return r, srv
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		r.t.Errorf("reading delivery: %v", err)
+	}
+	r.mu.Lock()
+	r.attempts = append(r.attempts, time.Now())
+	r.ids = append(r.ids, req.Header.Get(IDHeader))
+	resp := response{status: http.StatusOK}
+	if len(r.responses) > 0 {
+		resp, r.responses = r.responses[0], r.responses[1:]
+	}
+	if resp.status < 300 {
+		r.delivered = append(r.delivered, req)
+		r.bodies = append(r.bodies, body)
+	}
+	hold := r.hold
+	r.mu.Unlock()
+	r.arrived <- struct{}{}
+	if hold != nil {
+		select {
+		case <-hold:
+		case <-req.Context().Done():
+			return
+		}
+	}
+	if resp.retryAfter != "" {
+		w.Header().Set("Retry-After", resp.retryAfter)
+	}
+	w.WriteHeader(resp.status)
+}
+
+func (r *receiver) attemptCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.attempts)
+}
+
+// deadLetters collects the failures a Dispatcher gives up on.
+type deadLetters struct {
+	mu       sync.Mutex
+	failures []Failure
+}
+
+func (d *deadLetters) add(f Failure) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.failures = append(d.failures, f)
+}
+
+func (d *deadLetters) list() []Failure {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]Failure(nil), d.failures...)
+}
+
+// newDispatcher delivers to srv with short backoffs, closing the
+// Dispatcher when the test ends.
+func newDispatcher(t *testing.T, srv *httptest.Server, c Config) (*Dispatcher, *deadLetters) {
+	dead := &deadLetters{}
+	c.URL = srv.URL
+	c.Secret = secret
+	if c.InitialBackoff == 0 {
+		c.InitialBackoff = time.Millisecond
+	}
+	if c.MaxBackoff == 0 {
+		c.MaxBackoff = 10 * time.Millisecond
+	}
+	c.DeadLetter = dead.add
+	d := New(c)
+	t.Cleanup(d.Close)
+	return d, dead
+}
+
+// flushID is the ID of the event flush sends.
+const flushID = "flush"
+
+// flush waits until d, which must have a single worker, is done with the
+// events sent before, by sending one more and waiting for it to arrive.
+func flush(t *testing.T, d *Dispatcher, r *receiver) {
+	t.Helper()
+	d.Send(Event{ID: flushID, Body: []byte("{}")})
+	settle(t, "the flush", func() bool {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return len(r.ids) > 0 && r.ids[len(r.ids)-1] == flushID
+	})
+}
+
+// settle waits until done reports true, failing the test if that takes
+// too long.
+func settle(t *testing.T, what string, done func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for !done() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	body := []byte(`{"id":"1"}`)
+	// The HMAC-SHA256 of the body keyed with the secret, as computed by
+	// `printf '{"id":"1"}' | openssl dgst -sha256 -hmac s3cret`.
+	signature := "sha256=06988fa1cf02b8383043f7f2735f723f7bb350950d9214409cde13490d6a6373"
+	if got := Sign(secret, body); got != signature {
+		t.Errorf("Sign = %q, want %q", got, signature)
+	}
+	tests := []struct {
+		name      string
+		secret    string
+		body      string
+		signature string
+		want      bool
+	}{
+		{"signed", secret, `{"id":"1"}`, signature, true},
+		{"body changed", secret, `{"id":"2"}`, signature, false},
+		{"other secret", "other", `{"id":"1"}`, signature, false},
+		{"no prefix", secret, `{"id":"1"}`, strings.TrimPrefix(signature, "sha256="), false},
+		{"upper case", secret, `{"id":"1"}`, strings.ToUpper(signature), false},
+		{"empty", secret, `{"id":"1"}`, "", false},
+	}
+	for _, tt := range tests {
+		if got := Verify(tt.secret, []byte(tt.body), tt.signature); got != tt.want {
+			t.Errorf("%s: Verify = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDeliver(t *testing.T) {
+	r, srv := newReceiver(t)
+	d, dead := newDispatcher(t, srv, Config{Workers: 1})
+	body := []byte(`{"id":"e1","type":"hold.ready"}`)
+	d.Send(Event{ID: "e1", Type: "hold.ready", Body: body})
+	flush(t, d, r)
+
+	if got := r.attemptCount(); got != 2 {
+		t.Fatalf("%d attempts before the flush, want 1", got-1)
+	}
+	req := r.delivered[0]
+	if req.Method != http.MethodPost || req.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("delivered as %s %s, want a POST of application/json", req.Method, req.Header.Get("Content-Type"))
+	}
+	if got := req.Header.Get(EventHeader); got != "hold.ready" {
+		t.Errorf("%s = %q, want hold.ready", EventHeader, got)
+	}
+	if got := req.Header.Get(IDHeader); got != "e1" {
+		t.Errorf("%s = %q, want e1", IDHeader, got)
+	}
+	if string(r.bodies[0]) != string(body) {
+		t.Errorf("body = %s, want %s", r.bodies[0], body)
+	}
+	if !Verify(secret, r.bodies[0], req.Header.Get(SignatureHeader)) {
+		t.Errorf("%s %q does not verify", SignatureHeader, req.Header.Get(SignatureHeader))
+	}
+	if failures := dead.list(); len(failures) != 0 {
+		t.Errorf("delivered event dead-lettered: %v", failures)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses []response
+	}{
+		{"server errors", []response{{status: 500}, {status: 502}, {status: 503}}},
+		{"request timeout", []response{{status: http.StatusRequestTimeout}}},
+		{"too many requests", []response{{status: http.StatusTooManyRequests}, {status: http.StatusTooManyRequests}}},
+	}
+	for _, tt := range tests {
+		r, srv := newReceiver(t, tt.responses...)
+		d, dead := newDispatcher(t, srv, Config{MaxAttempts: 5, Workers: 1})
+		d.Send(Event{ID: "e1", Type: "hold.ready", Body: []byte("{}")})
+		flush(t, d, r)
+		// The retries keep the delivery ID, so the receiver can drop
+		// duplicates.
+		want := make([]string, len(tt.responses)+1)
+		for i := range want {
+			want[i] = "e1"
+		}
+		if got := r.ids[:len(r.ids)-1]; strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("%s: attempts of %v, want %v", tt.name, got, want)
+		}
+		if failures := dead.list(); len(failures) != 0 {
+			t.Errorf("%s: delivered event dead-lettered: %v", tt.name, failures)
+		}
+	}
+}
+
+// TestRetryAfter checks that a retry waits as long as the receiver asks,
+// even past the backoff.
+func TestRetryAfter(t *testing.T) {
+	for _, code := range []int{http.StatusTooManyRequests, http.StatusServiceUnavailable} {
+		r, srv := newReceiver(t, response{status: code, retryAfter: "1"})
+		d, _ := newDispatcher(t, srv, Config{})
+		d.Send(Event{ID: "e1", Body: []byte("{}")})
+		settle(t, "the retry", func() bool { return r.attemptCount() == 2 })
+		d.Close()
+		if wait := r.attempts[1].Sub(r.attempts[0]); wait < time.Second {
+			t.Errorf("%d with Retry-After: 1 retried after %v, want at least 1s", code, wait)
+		}
+	}
+}
+
+func TestNoRetry(t *testing.T) {
+	for _, code := range []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusGone} {
+		r, srv := newReceiver(t, response{status: code})
+		d, dead := newDispatcher(t, srv, Config{MaxAttempts: 5})
+		d.Send(Event{ID: "e1", Body: []byte("{}")})
+		settle(t, "the dead letter", func() bool { return len(dead.list()) == 1 })
+		d.Close()
+		if got := r.attemptCount(); got != 1 {
+			t.Errorf("%d: %d attempts, want 1", code, got)
+		}
+		if f := dead.list()[0]; f.ID != "e1" || f.Attempts != 1 || !strings.Contains(f.Err.Error(), http.StatusText(code)) {
+			t.Errorf("%d: dead letter %s after %d attempts: %v", code, f.ID, f.Attempts, f.Err)
+		}
+	}
+}
+
+func TestDeadLetterAfterMaxAttempts(t *testing.T) {
+	responses := make([]response, 10)
+	for i := range responses {
+		responses[i] = response{status: http.StatusInternalServerError}
+	}
+	r, srv := newReceiver(t, responses...)
+	d, dead := newDispatcher(t, srv, Config{MaxAttempts: 3})
+	event := Event{ID: "e1", Type: "hold.expired", Body: []byte(`{"id":"e1"}`)}
+	d.Send(event)
+	settle(t, "the dead letter", func() bool { return len(dead.list()) == 1 })
+	d.Close()
+
+	if got := r.attemptCount(); got != 3 {
+		t.Errorf("%d attempts, want 3", got)
+	}
+	f := dead.list()[0]
+	if f.Event.ID != event.ID || f.Type != event.Type || string(f.Body) != string(event.Body) {
+		t.Errorf("dead-lettered %+v, want %+v", f.Event, event)
+	}
+	if f.Attempts != 3 || !strings.Contains(f.Err.Error(), "500") {
+		t.Errorf("dead letter after %d attempts: %v, want 3 attempts ending in 500", f.Attempts, f.Err)
+	}
+}
+
+// TestClose checks that Close gives up on the event being delivered and
+// those queued behind it, and that events sent after it are
+// dead-lettered at once.
+func TestClose(t *testing.T) {
+	r, srv := newReceiver(t)
+	r.hold = make(chan struct{})
+	defer close(r.hold)
+	d, dead := newDispatcher(t, srv, Config{Workers: 1, QueueSize: 2})
+	d.Send(Event{ID: "in flight", Body: []byte("{}")})
+	<-r.arrived
+	d.Send(Event{ID: "queued 1", Body: []byte("{}")})
+	d.Send(Event{ID: "queued 2", Body: []byte("{}")})
+	d.Send(Event{ID: "queue full", Body: []byte("{}")})
+
+	d.Close()
+	d.Send(Event{ID: "after close", Body: []byte("{}")})
+
+	failures := make(map[string]Failure)
+	for _, f := range dead.list() {
+		failures[f.ID] = f
+	}
+	tests := []struct {
+		id           string
+		wantAttempts int
+		wantErr      string
+	}{
+		{"in flight", 1, "context canceled"},
+		{"queued 1", 0, "closed"},
+		{"queued 2", 0, "closed"},
+		{"queue full", 0, "queue full"},
+		{"after close", 0, "closed"},
+	}
+	for _, tt := range tests {
+		f, ok := failures[tt.id]
+		if !ok {
+			t.Errorf("%s: not dead-lettered", tt.id)
+			continue
+		}
+		if f.Attempts != tt.wantAttempts || !strings.Contains(f.Err.Error(), tt.wantErr) {
+			t.Errorf("%s: dead letter after %d attempts: %v, want %d attempts and %q", tt.id, f.Attempts, f.Err, tt.wantAttempts, tt.wantErr)
+		}
+	}
+	if len(failures) != len(tests) {
+		t.Errorf("got %d dead letters, want %d", len(failures), len(tests))
+	}
+	if got := r.attemptCount(); got != 1 {
+		t.Errorf("%d attempts, want only the one in flight", got)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	d := &Dispatcher{config: Config{InitialBackoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond}}
+	for retries, bound := range []time.Duration{10, 20, 40, 50, 50} {
+		bound *= time.Millisecond
+		var longest time.Duration
+		for i := 0; i < 1000; i++ {
+			wait := d.backoff(retries)
+			if wait < 0 || wait > bound {
+				t.Fatalf("backoff(%d) = %v, want at most %v", retries, wait, bound)
+			}
+			longest = max(longest, wait)
+		}
+		// The waits are spread up to the bound, not fixed.
+		if longest < bound/2 {
+			t.Errorf("backoff(%d) waited at most %v of %v in 1000 tries", retries, longest, bound)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gofrs/uuid"
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"github.com/marcoc22/tutorial3/citation"
+	"github.com/marcoc22/tutorial3/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// worksCollection is the store collection works are kept in.
+const worksCollection = "works"
+
+// worksNamespace is the namespace of the name based UUIDs of works.
+var worksNamespace = uuid.NewV5(uuid.NamespaceURL, "https://github.com/marcoc22/tutorial3/works")
+
+// workKey returns the normalized title and first author that books of
+// the same work share, or "" if book has no title.
+func workKey(book *pb.Book) string {
+	title := normalizeTitle(book.Title)
+	if title == "" {
+		return ""
+	}
+	return title + "\x00" + normalizeName(firstAuthor(book.Author))
+}
+
+// workID returns the ID of the work with key. Deriving it from the key
+// lets every server, replica or shard find a book's work with a single
+// read and agree on the ID of a work they create at the same time.
+func workID(key string) string {
+	return uuid.NewV5(worksNamespace, key).String()
+}
+
+// normalizeTitle folds the case, punctuation and spacing of a title.
+func normalizeTitle(title string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}), " ")
+}
+
+// firstAuthor returns the name of the first author in Author text, given
+// name first.
+func firstAuthor(author string) string {
+	names := citation.Authors(author)
+	if len(names) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(names[0].Given + " " + names[0].Family)
+}
+
+func (s *server) getWork(id string) (*pb.Work, error) {
+	work := &pb.Work{}
+	if err := store.GetMessage(s.store, worksCollection, id, work); err != nil {
+		return nil, recordStatus(err, "Work", id)
+	}
+	return work, nil
+}
+
+func (s *server) putWork(work *pb.Work) error {
+	if strings.TrimSpace(work.Title) == "" {
+		return status.Errorf(codes.InvalidArgument, "Work title is required.")
+	}
+	if err := store.PutMessage(s.store, worksCollection, work.Id, work); err != nil {
+		return recordStatus(err, "Work", work.Id)
+	}
+	return nil
+}
+
+// assignWork checks that the work book refers to exists or, if it refers
+// to none, puts it in the work of its title and first author, creating
+// the work if needed. A book without a title is left out of works. s.mu
+// must be held.
+func (s *server) assignWork(book *pb.Book) error {
+	if book.WorkId != "" {
+		_, err := s.getWork(book.WorkId)
+		return referenceError(err)
+	}
+	key := workKey(book)
+	if key == "" {
+		return nil
+	}
+	id := workID(key)
+	_, err := s.getWork(id)
+	if status.Code(err) == codes.NotFound {
+		err = s.putWork(&pb.Work{
+			Id:     id,
+			Title:  strings.Join(strings.Fields(book.Title), " "),
+			Author: firstAuthor(book.Author),
+		})
+	}
+	if err != nil {
+		return err
+	}
+	book.WorkId = id
+	return nil
+}
+
+// releaseWork deletes the work with id once no book is in it. Failing to
+// is only logged: the book change that left the work empty has been made
+// and an empty work does no harm. s.mu must be held.
+func (s *server) releaseWork(id string) {
+	if id == "" || s.counts.inWork(id) > 0 {
+		return
+	}
+	if err := s.store.Delete(worksCollection, id); err != nil && err != store.ErrNotFound {
+		log.Printf("Cannot delete empty work %s: %v", id, err)
+	}
+}
+
+// editionNumber reads the number of an edition from text such as "9th",
+// "2nd ed." or "Second", reporting whether there was one.
+func editionNumber(edition string) (int, bool) {
+	if n, ok := firstNumber(edition); ok {
+		return n, true
+	}
+	for _, word := range strings.Fields(strings.ToLower(edition)) {
+		if n, ok := ordinals[strings.Trim(word, ".,")]; ok {
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+var ordinals = map[string]int{
+	"first": 1, "second": 2, "third": 3, "fourth": 4, "fifth": 5,
+	"sixth": 6, "seventh": 7, "eighth": 8, "ninth": 9, "tenth": 10,
+	"eleventh": 11, "twelfth": 12,
+}
+
+var number = regexp.MustCompile(`\d+`)
+
+// firstNumber returns the first run of digits in s.
+func firstNumber(s string) (int, bool) {
+	n, err := strconv.Atoi(number.FindString(s))
+	return n, err == nil
+}
+
+// sortEditions sorts the books of a work in order.
+func sortEditions(books []*pb.Book, order pb.EditionOrder) {
+	edition := func(b *pb.Book) (int, bool) { return editionNumber(b.Edition) }
+	year := func(b *pb.Book) (int, bool) { return firstNumber(b.Copyright) }
+	keys := []func(*pb.Book) (int, bool){edition, year}
+	if order == pb.EditionOrder_COPYRIGHT_YEAR {
+		keys = []func(*pb.Book) (int, bool){year, edition}
+	}
+	sort.SliceStable(books, func(i, j int) bool {
+		for _, key := range keys {
+			a, aok := key(books[i])
+			b, bok := key(books[j])
+			if aok != bok {
+				return aok
+			}
+			if a != b {
+				return a < b
+			}
+		}
+		return books[i].Id < books[j].Id
+	})
+}
+
+func (s *server) GetWork(ctx context.Context, in *pb.WorkID) (*pb.Work, error) {
+	return s.getWork(in.Value)
+}
+
+// UpdateWork changes the title and author of a work. Books keep joining
+// it by the title and author it was created for.
+func (s *server) UpdateWork(ctx context.Context, in *pb.Work) (*pb.Work, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.getWork(in.Id); err != nil {
+		return nil, err
+	}
+	if err := s.putWork(in); err != nil {
+		return nil, err
+	}
+	return in, status.New(codes.OK, "").Err()
+}
+
+func (s *server) ListWorks(in *pb.ListWorksRequest, stream pb.BookInfo_ListWorksServer) error {
+	query := strings.ToLower(in.Query)
+	err := s.store.Scan(worksCollection, func(id string, value []byte) error {
+		work := &pb.Work{}
+		if err := proto.Unmarshal(value, work); err != nil {
+			return status.Errorf(codes.DataLoss, "Work %s is corrupt: %v", id, err)
+		}
+		if !strings.Contains(strings.ToLower(work.Title), query) &&
+			!strings.Contains(strings.ToLower(work.Author), query) {
+			return nil
+		}
+		return stream.Send(work)
+	})
+	if _, ok := status.FromError(err); !ok {
+		return status.Errorf(codes.Internal, "Error while listing works: %v", err)
+	}
+	return err
+}
+
+func (s *server) ListEditions(in *pb.ListEditionsRequest, stream pb.BookInfo_ListEditionsServer) error {
+	if _, err := s.getWork(in.WorkId); err != nil {
+		return err
+	}
+	var books []*pb.Book
+	err := s.scanBooks(func(book *pb.Book) error {
+		if book.WorkId == in.WorkId {
+			books = append(books, book)
+		}
+		return nil
+	})
+	if _, ok := status.FromError(err); !ok {
+		return status.Errorf(codes.Internal, "Error while listing books: %v", err)
+	}
+	if err != nil {
+		return err
+	}
+	sortEditions(books, in.Order)
+	for _, book := range books {
+		if err := stream.Send(book); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GroupWorks puts the titled books without a work into the works of
+// their titles and first authors, creating works as needed.
+func (s *server) GroupWorks(ctx context.Context, in *pb.GroupWorksRequest) (*pb.GroupWorksSummary, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ungrouped []*pb.Book
+	err := s.scanBooks(func(book *pb.Book) error {
+		if book.WorkId == "" && workKey(book) != "" {
+			ungrouped = append(ungrouped, book)
+		}
+		return nil
+	})
+	if _, ok := status.FromError(err); !ok {
+		return nil, status.Errorf(codes.Internal, "Error while listing books: %v", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &pb.GroupWorksSummary{}
+	seen := make(map[string]bool)
+	for _, book := range ungrouped {
+		id := workID(workKey(book))
+		if !seen[id] {
+			seen[id] = true
+			_, err := s.getWork(id)
+			if status.Code(err) == codes.NotFound {
+				summary.WorksCreated++
+			} else if err != nil {
+				return nil, err
+			}
+		}
+		summary.BooksGrouped++
+		if in.DryRun {
+			continue
+		}
+		if err := s.assignWork(book); err != nil {
+			return nil, err
+		}
+		if err := s.putBook(ctx, book); err != nil {
+			return nil, err
+		}
+		s.publishChange(&pb.BookChange{Type: pb.ChangeType_UPDATED, Id: book.Id, Book: book})
+	}
+	return summary, status.New(codes.OK, "").Err()
+}
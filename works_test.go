@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/marcoc22/tutorial3/booksapp"
+	"github.com/marcoc22/tutorial3/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestWorkReleasedWithItsLastBook(t *testing.T) {
+	backend := store.NewMemory()
+	counted, counts, err := countBooks(backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newServer(counted, counts)
+	ctx := context.Background()
+	var ids []string
+	for _, b := range []*pb.Book{
+		{Title: "Dune", Author: "Frank Herbert", Edition: "1st"},
+		{Title: "DUNE", Author: "frank  herbert", Edition: "2nd"},
+	} {
+		id, err := s.AddBook(ctx, b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id.Value)
+	}
+	first, err := s.GetBook(ctx, &pb.BookID{Value: ids[0]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	work := first.WorkId
+	if work == "" || counts.inWork(work) != 2 {
+		t.Fatalf("work %q holds %d books, want both editions", work, counts.inWork(work))
+	}
+
+	if _, err := s.DeleteBook(ctx, &pb.BookID{Value: ids[0]}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GetWork(ctx, &pb.WorkID{Value: work}); err != nil {
+		t.Errorf("work with a book left: %v", err)
+	}
+
+	// Moving the last book to another work releases the first.
+	if _, err := s.UpdateBook(ctx, &pb.Book{Id: ids[1], Title: "Children of Dune", Author: "Frank Herbert"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GetWork(ctx, &pb.WorkID{Value: work}); status.Code(err) != codes.NotFound {
+		t.Errorf("work without books = %v, want NotFound", err)
+	}
+
+	// A restarted server counts the books of every work again.
+	_, counts, err = countBooks(backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := s.GetBook(ctx, &pb.BookID{Value: ids[1]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := counts.inWork(second.WorkId); n != 1 {
+		t.Errorf("recounted work holds %d books, want 1", n)
+	}
+}